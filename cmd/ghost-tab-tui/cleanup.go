@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/process"
+	"github.com/jackuait/ghost-tab/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Terminate a tmux session and its process trees",
+	Long:  "Sends SIGTERM to every process in the session's panes (children first), waits for the grace period, SIGKILLs survivors and kills the session. Prints which PIDs were terminated and which needed escalation as JSON.",
+	RunE:  runCleanup,
+}
+
+var (
+	cleanupSession string
+	cleanupGrace   time.Duration
+)
+
+func init() {
+	cleanupCmd.Flags().StringVar(&cleanupSession, "session", "", "tmux session name")
+	cleanupCmd.MarkFlagRequired("session")
+	cleanupCmd.Flags().DurationVar(&cleanupGrace, "grace", process.DefaultGrace, "How long to wait after SIGTERM before sending SIGKILL")
+	rootCmd.AddCommand(cleanupCmd)
+}
+
+func runCleanup(cmd *cobra.Command, args []string) error {
+	report, err := session.Cleanup(session.NewTmux(), cleanupSession, cleanupGrace)
+	if err != nil {
+		return fmt.Errorf("cleaning up session %s: %w", cleanupSession, err)
+	}
+
+	result := map[string]interface{}{
+		"session":    cleanupSession,
+		"terminated": report.Terminated,
+		"escalated":  report.Escalated,
+	}
+
+	jsonOutput, _ := json.Marshal(result)
+	fmt.Println(string(jsonOutput))
+
+	return nil
+}
@@ -2,13 +2,25 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jackuait/ghost-tab/internal/aitools"
+	"github.com/jackuait/ghost-tab/internal/config"
+	"github.com/jackuait/ghost-tab/internal/history"
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/notify"
+	"github.com/jackuait/ghost-tab/internal/process"
+	"github.com/jackuait/ghost-tab/internal/statusline"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func TestRootCmd_HasAIToolFlag(t *testing.T) {
@@ -31,6 +43,12 @@ func TestRootCmd_SubcommandRegistered(t *testing.T) {
 		"settings-menu",
 		"main-menu",
 		"multi-select-ai-tool",
+		"launch",
+		"cleanup",
+		"project",
+		"history",
+		"discover",
+		"tools",
 	}
 
 	for _, name := range subcommands {
@@ -83,6 +101,11 @@ func TestMainMenuCmd_HasAllFlags(t *testing.T) {
 		{"ghost-display", "animated"},
 		{"tab-title", "full"},
 		{"update-version", ""},
+		{"persist-session", "off"},
+		{"collapsed-groups", ""},
+		{"project-sort", "manual"},
+		{"history-file", ""},
+		{"git-status-cache", ""},
 	}
 
 	for _, f := range flags {
@@ -287,3 +310,1079 @@ func TestSelectProjectCmd_ProjectsFileFlagRequired(t *testing.T) {
 		t.Error("Expected --projects-file to be marked as required")
 	}
 }
+
+func TestLaunchCmd_HasFlags(t *testing.T) {
+	cmd, _, _ := rootCmd.Find([]string{"launch"})
+	for _, name := range []string{"project", "name", "session", "baseline-file", "layout", "layouts-file", "persist", "attach", "projects-file", "history-file", "profile", "agent"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected --%s flag on launch", name)
+		}
+	}
+}
+
+func TestRunLaunch_MissingProjectDir(t *testing.T) {
+	rootCmd.SetArgs([]string{"launch", "--project", "/nonexistent/project/dir"})
+	err := rootCmd.Execute()
+	if err == nil {
+		t.Error("Expected error for nonexistent project directory")
+	}
+}
+
+func TestRunLaunch_UnknownLayout(t *testing.T) {
+	dir := t.TempDir()
+	rootCmd.SetArgs([]string{"launch", "--project", dir, "--layout", "does-not-exist"})
+	err := rootCmd.Execute()
+	launchLayout = ""
+	if err == nil || !strings.Contains(err.Error(), "unknown layout") {
+		t.Errorf("Expected unknown layout error, got: %v", err)
+	}
+}
+
+func TestRunLaunch_UnknownProfile(t *testing.T) {
+	dir := t.TempDir()
+	rootCmd.SetArgs([]string{"launch", "--project", dir, "--ai-tool", "claude", "--profile", "turbo"})
+	err := rootCmd.Execute()
+	launchProfile = ""
+	if err == nil || !strings.Contains(err.Error(), `no launch profile "turbo"`) {
+		t.Errorf("Expected unknown profile error, got: %v", err)
+	}
+}
+
+func TestCleanupCmd_HasFlags(t *testing.T) {
+	cmd, _, _ := rootCmd.Find([]string{"cleanup"})
+	if cmd.Flags().Lookup("session") == nil {
+		t.Error("Expected --session flag on cleanup")
+	}
+	grace := cmd.Flags().Lookup("grace")
+	if grace == nil {
+		t.Fatal("Expected --grace flag on cleanup")
+	}
+	if grace.DefValue != "300ms" {
+		t.Errorf("Expected --grace default 300ms, got %q", grace.DefValue)
+	}
+}
+
+func TestRunProjectAdd_MigratesAndAppends(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	os.WriteFile(file, []byte("old:/tmp/old\n"), 0644)
+
+	rootCmd.SetArgs([]string{"project", "add", "--projects-file", file, "--name", "new app", "--path", "/tmp/new:app"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("project add: %v", err)
+	}
+
+	projects, err := models.LoadProjects(file)
+	if err != nil {
+		t.Fatalf("LoadProjects: %v", err)
+	}
+	if len(projects) != 2 || projects[1].Name != "new app" || projects[1].Path != "/tmp/new:app" {
+		t.Errorf("unexpected projects: %+v", projects)
+	}
+	if _, err := os.Stat(file + models.LegacyBackupSuffix); err != nil {
+		t.Errorf("expected legacy backup: %v", err)
+	}
+}
+
+func TestLookupProject(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "projects")
+	models.WriteProjectsFile(file, &models.ProjectsFile{Projects: []models.Project{
+		{Name: "api", Path: "/srv/api", AIArgs: []string{"--verbose"}, Env: map[string]string{"B": "2", "A": "1"}},
+	}})
+	launchProjectsFile = file
+	defer func() { launchProjectsFile = "" }()
+
+	p, err := lookupProject("/srv/api", "api")
+	if err != nil {
+		t.Fatalf("lookupProject: %v", err)
+	}
+	if len(p.AIArgs) != 1 || p.AIArgs[0] != "--verbose" {
+		t.Errorf("expected project AI args, got %v", p.AIArgs)
+	}
+	if got := strings.Join(projectEnv(p), " "); got != "A=1 B=2" {
+		t.Errorf("projectEnv: got %q, want sorted pairs", got)
+	}
+
+	// Worktrees live elsewhere but share the project name.
+	wt, _ := lookupProject("/srv/api-feature", "api")
+	if wt.Path != "/srv/api-feature" || len(wt.AIArgs) != 1 {
+		t.Errorf("expected name match with worktree path, got %+v", wt)
+	}
+
+	other, _ := lookupProject("/srv/other", "other")
+	if other.Name != "other" || len(other.AIArgs) != 0 {
+		t.Errorf("expected bare project for unknown path, got %+v", other)
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	claude := aitools.Defaults().Get("claude")
+	tests := []struct {
+		name, flag, project string
+		want                []string
+	}{
+		{"none", "", "", nil},
+		{"project default", "", "yolo", []string{"--dangerously-skip-permissions"}},
+		{"flag wins", "resume", "yolo", []string{"--continue"}},
+		{"flag clears project default", "default", "yolo", nil},
+		{"project default for another tool", "", "full-auto", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := resolveProfile(claude, tt.flag, tt.project)
+			if err != nil {
+				t.Fatalf("resolveProfile: %v", err)
+			}
+			if strings.Join(p.Args, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("args = %v, want %v", p.Args, tt.want)
+			}
+		})
+	}
+
+	if _, err := resolveProfile(claude, "full-auto", ""); err == nil || !strings.Contains(err.Error(), `no launch profile "full-auto"`) {
+		t.Errorf("expected an unknown profile error, got %v", err)
+	}
+}
+
+func TestBuildAgents(t *testing.T) {
+	project := models.Project{Name: "api", Path: "/srv/api", Profile: "full-auto"}
+	wt := t.TempDir()
+
+	tool, agents, err := buildAgents([]string{"claude", "codex=" + wt}, "/srv/api", project, []string{"--verbose"})
+	if err != nil {
+		t.Fatalf("buildAgents: %v", err)
+	}
+	if tool.Name != "claude" || len(agents) != 2 {
+		t.Fatalf("got tool %q and %d agents", tool.Name, len(agents))
+	}
+	if a := agents[0]; a.Name != "Claude Code" || a.Dir != "/srv/api" || a.Color != "209" ||
+		strings.Join(a.Argv[1:], " ") != "--verbose" {
+		t.Errorf("claude agent = %+v", a)
+	}
+	if a := agents[1]; a.Dir != wt || strings.Join(a.Argv[1:], " ") != "--full-auto --cd "+wt {
+		t.Errorf("codex agent should run in the worktree with the project's profile, got %+v", a)
+	}
+
+	// Without --agent, the --ai-tool tool runs in the project directory.
+	tool, agents, _ = buildAgents(nil, "/srv/api", models.Project{}, nil)
+	if tool.Name != aiToolFlag || len(agents) != 1 || agents[0].Dir != "/srv/api" {
+		t.Errorf("default agent: tool %q, agents %+v", tool.Name, agents)
+	}
+
+	if _, _, err := buildAgents([]string{"clippy"}, "/srv/api", project, nil); err == nil || !strings.Contains(err.Error(), `unknown AI tool "clippy"`) {
+		t.Errorf("expected unknown tool error, got %v", err)
+	}
+	if _, _, err := buildAgents([]string{"codex=/nonexistent/wt"}, "/srv/api", project, nil); err == nil {
+		t.Error("expected an error for a missing agent directory")
+	}
+}
+
+func TestRecordLaunch(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history.jsonl")
+	launchHistoryFile = file
+	defer func() { launchHistoryFile = "" }()
+
+	launchErr := errors.New("tmux exited")
+	err := recordLaunch(history.Entry{Project: "api", Path: "/srv/api", AITool: "codex", Session: "dev-api-1"}, func() error {
+		return launchErr
+	})
+	if err != launchErr {
+		t.Errorf("expected the launch error to be returned, got %v", err)
+	}
+
+	entries, _ := history.NewStore(file).Load()
+	if len(entries) != 1 {
+		t.Fatalf("expected one history entry, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Project != "api" || e.AITool != "codex" || e.Session != "dev-api-1" || e.EndedAt == nil {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestRunHistory_JSON(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history.jsonl")
+	store := history.NewStore(file)
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i, name := range []string{"api", "web", "api"} {
+		e, _ := store.Start(history.Entry{Project: name, Path: "/srv/" + name, StartedAt: base.Add(time.Duration(i) * time.Hour)})
+		store.Finish(e.ID, e.StartedAt.Add(10*time.Minute))
+	}
+
+	rootCmd.SetArgs([]string{"history", "--history-file", file, "--json", "--limit", "2"})
+	defer func() { historyJSON, historyLimit = false, 20 }()
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	var result []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(result))
+	}
+	if result[0]["project"] != "api" || result[1]["project"] != "web" {
+		t.Errorf("expected newest first, got %v", result)
+	}
+	if result[0]["duration"] != float64(600) {
+		t.Errorf("expected duration in seconds, got %v", result[0]["duration"])
+	}
+}
+
+func TestDiscoverCmd_HasFlags(t *testing.T) {
+	cmd, _, _ := rootCmd.Find([]string{"discover"})
+	for _, name := range []string{"projects-file", "root", "max-depth", "list"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected --%s flag on discover", name)
+		}
+	}
+	if def := cmd.Flags().Lookup("max-depth").DefValue; def != "3" {
+		t.Errorf("Expected --max-depth default 3, got %q", def)
+	}
+}
+
+func TestRunDiscover_List(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "src")
+	for _, repo := range []string{"api", "web"} {
+		os.MkdirAll(filepath.Join(root, repo, ".git"), 0755)
+	}
+	file := filepath.Join(dir, "projects")
+	os.WriteFile(file, []byte("api:"+filepath.Join(root, "api")+"\n"), 0644)
+
+	rootCmd.SetArgs([]string{"discover", "--projects-file", file, "--root", root, "--list"})
+	defer func() { discoverList, discoverRoots = false, models.DefaultScanRoots }()
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	var result []discoveredProject
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	want := []discoveredProject{
+		{Name: "api", Path: filepath.Join(root, "api"), Known: true},
+		{Name: "web", Path: filepath.Join(root, "web"), Known: false},
+	}
+	if len(result) != len(want) {
+		t.Fatalf("expected %d repos, got %+v", len(want), result)
+	}
+	for i := range want {
+		if result[i] != want[i] {
+			t.Errorf("result[%d] = %+v, want %+v", i, result[i], want[i])
+		}
+	}
+}
+
+func TestImportProjects_Appends(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	os.WriteFile(file, []byte("old:/tmp/old\n"), 0644)
+
+	err := importProjects([]models.Project{{Name: "api", Path: "/src/api"}, {Name: "web", Path: "/src/web"}}, file)
+	if err != nil {
+		t.Fatalf("importProjects: %v", err)
+	}
+	projects, err := models.LoadProjects(file)
+	if err != nil {
+		t.Fatalf("LoadProjects: %v", err)
+	}
+	if len(projects) != 3 || projects[1].Name != "api" || projects[2].Path != "/src/web" {
+		t.Errorf("unexpected projects: %+v", projects)
+	}
+}
+
+func TestRootCmd_HasAIToolsFileFlag(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("ai-tools-file")
+	if flag == nil {
+		t.Fatal("Expected --ai-tools-file persistent flag to be registered")
+	}
+	if !strings.HasSuffix(flag.DefValue, filepath.Join("ghost-tab", "ai-tools.toml")) {
+		t.Errorf("unexpected default %q", flag.DefValue)
+	}
+}
+
+func TestRunTools_Installed(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+	os.Mkdir(bin, 0755)
+	for _, name := range []string{"codex", "aider"} {
+		os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755)
+	}
+	t.Setenv("PATH", bin)
+	file := filepath.Join(dir, "ai-tools.toml")
+	os.WriteFile(file, []byte("[[tool]]\nname = \"aider\"\n"), 0644)
+
+	prev := aitools.Current()
+	defer func() { aitools.Use(prev); toolsInstalled, aiToolsFile = false, defaultAIToolsFile() }()
+	rootCmd.SetArgs([]string{"tools", "--installed", "--ai-tools-file", file})
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("tools: %v", err)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	if buf.String() != "codex\naider\n" {
+		t.Errorf("expected installed tools in registry order, got %q", buf.String())
+	}
+}
+
+func TestRunTools_ReportsBrokenFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ai-tools.toml")
+	os.WriteFile(file, []byte("[[tool]]\nname = \"x\"\nproject_dir = \"env\"\n"), 0644)
+
+	prev := aitools.Current()
+	defer func() { aitools.Use(prev); aiToolsFile, aiToolsErr = defaultAIToolsFile(), nil }()
+	rootCmd.SetArgs([]string{"tools", "--ai-tools-file", file})
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+
+	oldErr := os.Stderr
+	os.Stderr, _ = os.Open(os.DevNull)
+	err := rootCmd.Execute()
+	os.Stderr = oldErr
+	if err == nil || !strings.Contains(err.Error(), "project_dir must be") {
+		t.Errorf("expected the file's error, got %v", err)
+	}
+	if len(aitools.Current().Tools()) != 4 {
+		t.Error("expected the built-in tools to stay in use")
+	}
+}
+
+func TestWriteToolsTable(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	var buf bytes.Buffer
+	writeToolsTable(&buf, models.DetectAITools())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "NAME") || !strings.Contains(lines[0], "VERSION") {
+		t.Fatalf("expected header and four rows, got %q", buf.String())
+	}
+	if !strings.Contains(lines[2], "Codex CLI") || !strings.HasSuffix(lines[2], "not installed") {
+		t.Errorf("unexpected row: %q", lines[2])
+	}
+
+	buf.Reset()
+	writeToolsTable(&buf, []models.AITool{
+		{Name: "claude", DisplayName: "Claude Code", Command: "claude", Installed: true, Status: models.ToolOutdated, Version: "0.2.0", MinVersion: "1.0.0"},
+		{Name: "codex", DisplayName: "Codex CLI", Command: "codex", Installed: true, Status: models.ToolBroken, Error: "exit status 1"},
+	})
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[1], "0.2.0") || !strings.HasSuffix(lines[1], "outdated (needs 1.0.0+)") {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], " - ") || !strings.HasSuffix(lines[2], "broken: exit status 1") {
+		t.Errorf("unexpected row: %q", lines[2])
+	}
+}
+
+func TestRunTools_JSON(t *testing.T) {
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\necho '0.9.0 (Claude Code)'\n"), 0755)
+	os.WriteFile(filepath.Join(bin, "codex"), []byte("#!/bin/sh\nexit 1\n"), 0755)
+	t.Setenv("PATH", bin)
+
+	prev := aitools.Current()
+	defer func() { aitools.Use(prev); toolsJSON, aiToolsFile = false, defaultAIToolsFile() }()
+	rootCmd.SetArgs([]string{"tools", "--json", "--ai-tools-file", filepath.Join(bin, "missing.toml")})
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("tools: %v", err)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	var result []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if len(result) != 4 {
+		t.Fatalf("expected 4 tools, got %d", len(result))
+	}
+	claude, codex, copilot := result[0], result[1], result[2]
+	if claude["version"] != "0.9.0" || claude["min_version"] != "1.0.0" || claude["status"] != "outdated" {
+		t.Errorf("claude = %v", claude)
+	}
+	if codex["status"] != "broken" || codex["installed"] != true || codex["error"] == nil {
+		t.Errorf("codex = %v", codex)
+	}
+	if copilot["status"] != "missing" || copilot["installed"] != false {
+		t.Errorf("copilot = %v", copilot)
+	}
+}
+
+func TestWriteHistoryTable(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	end := start.Add(75 * time.Minute)
+	entries := []history.Entry{
+		{Project: "api", Path: "/srv/api", AITool: "claude", StartedAt: start, EndedAt: &end},
+		{Project: "web", Path: "/srv/web", AITool: "codex", StartedAt: start},
+	}
+
+	var buf bytes.Buffer
+	writeHistoryTable(&buf, entries)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "STARTED") {
+		t.Fatalf("expected header and two rows, got %q", buf.String())
+	}
+	if !strings.Contains(lines[1], "2026-03-01 09:00") || !strings.Contains(lines[1], "1h15m") {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], " - ") {
+		t.Errorf("unfinished session should show -, got %q", lines[2])
+	}
+
+	buf.Reset()
+	writeHistoryTable(&buf, nil)
+	if !strings.Contains(buf.String(), "No sessions") {
+		t.Errorf("expected empty message, got %q", buf.String())
+	}
+}
+
+func TestRunStatusline(t *testing.T) {
+	dir := t.TempDir()
+	stdin := filepath.Join(dir, "status.json")
+	os.WriteFile(stdin, []byte(`{"workspace":{"current_dir":"`+dir+`"}}`), 0644)
+
+	oldIn, oldOut := os.Stdin, os.Stdout
+	os.Stdin, _ = os.Open(stdin)
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	rootCmd.SetArgs([]string{"statusline"})
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdin, os.Stdout = oldIn, oldOut
+	if err != nil {
+		t.Fatalf("statusline: %v", err)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	if !strings.HasPrefix(buf.String(), "\033[01;36m"+filepath.Base(dir)+"\033[00m") || strings.HasSuffix(buf.String(), "\n") {
+		t.Errorf("unexpected status line %q", buf.String())
+	}
+}
+
+func TestRunStatusline_InvalidJSON(t *testing.T) {
+	stdin := filepath.Join(t.TempDir(), "status.json")
+	os.WriteFile(stdin, []byte(`current_dir: /tmp`), 0644)
+
+	oldIn := os.Stdin
+	os.Stdin, _ = os.Open(stdin)
+	defer func() { os.Stdin = oldIn }()
+	rootCmd.SetArgs([]string{"statusline"})
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid status JSON") {
+		t.Errorf("expected an invalid JSON error, got %v", err)
+	}
+}
+
+func TestRunStatusline_Preview(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "statusline.toml")
+	os.WriteFile(file, []byte("segments = [\"model\", \"branch\"]\nseparator = \" / \"\n\n[colors]\nmodel = \"00\"\nbranch = \"00\"\n"), 0644)
+
+	defer func() { statuslinePreview, statuslineConfig = false, statusline.DefaultConfigFile() }()
+	rootCmd.SetArgs([]string{"statusline", "--preview", "--config", file})
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("statusline --preview: %v", err)
+	}
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	if buf.String() != "\033[00mOpus\033[00m / \033[00mmain\033[00m\n" {
+		t.Errorf("unexpected preview %q", buf.String())
+	}
+}
+
+func TestRunStatusline_PreviewReportsBrokenConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "statusline.toml")
+	os.WriteFile(file, []byte("segments = [\"weather\"]\n"), 0644)
+
+	defer func() { statuslinePreview, statuslineConfig = false, statusline.DefaultConfigFile() }()
+	rootCmd.SetArgs([]string{"statusline", "--preview", "--config", file})
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown segment") {
+		t.Errorf("expected the config error, got %v", err)
+	}
+}
+
+func TestWriteMemoryBreakdown(t *testing.T) {
+	procs := process.ParsePS(`  100     1  1048576 claude
+  101   100   409600 npm
+  103   101   204800 node
+  102   100    10240 zsh
+`)
+	commandLine := func(pid int) string {
+		if pid == 101 {
+			return "npm exec @modelcontextprotocol/server-github " + strings.Repeat("--flag ", 20)
+		}
+		return ""
+	}
+
+	var buf bytes.Buffer
+	writeMemoryBreakdown(&buf, procs, 100, 1, commandLine)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "PID") {
+		t.Fatalf("expected header, total and one row, got %q", buf.String())
+	}
+	if !strings.Contains(lines[1], "1.5G") || !strings.HasSuffix(lines[1], "claude (total)") {
+		t.Errorf("unexpected total: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "600M") || !strings.Contains(lines[2], "server-github") || !strings.HasSuffix(lines[2], "…") {
+		t.Errorf("unexpected row: %q", lines[2])
+	}
+}
+
+func TestRunStatusline_BreakdownNeedsClaude(t *testing.T) {
+	defer func() { statuslineBreakdown, statuslinePID = false, 0 }()
+	rootCmd.SetArgs([]string{"statusline", "--breakdown", "--pid", "999999999"})
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "no process with PID") {
+		t.Errorf("expected a missing process error, got %v", err)
+	}
+}
+
+// runSoundsWith runs the sounds command against a fake backend and returns
+// its output.
+func runSoundsWith(t *testing.T, fake *notify.Fake, args ...string) (string, error) {
+	t.Helper()
+	prev := soundBackend
+	soundBackend = func() notify.Backend { return fake }
+	defer func() {
+		soundBackend = prev
+		soundsJSON, soundsPlay, soundsHookCommand = false, "", ""
+		soundsCmd.Flags().Lookup("hook-command").Changed = false
+	}()
+	rootCmd.SetArgs(append([]string{"sounds"}, args...))
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String(), err
+}
+
+var freedesktopSounds = []notify.Sound{
+	{Name: "bell", Path: "/usr/share/sounds/freedesktop/stereo/bell.oga"},
+	{Name: "complete", Path: "/usr/share/sounds/freedesktop/stereo/complete.oga"},
+}
+
+func TestRunSounds_lists_sounds(t *testing.T) {
+	out, err := runSoundsWith(t, &notify.Fake{SoundList: freedesktopSounds})
+	if err != nil {
+		t.Fatalf("sounds: %v", err)
+	}
+	for _, want := range []string{"NAME", "PATH", "complete", "/usr/share/sounds/freedesktop/stereo/bell.oga"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunSounds_play(t *testing.T) {
+	fake := &notify.Fake{SoundList: freedesktopSounds}
+	if _, err := runSoundsWith(t, fake, "--play", "bell"); err != nil {
+		t.Fatalf("sounds --play: %v", err)
+	}
+	if len(fake.Played) != 1 || fake.Played[0].Name != "bell" {
+		t.Errorf("played %v, want bell", fake.Played)
+	}
+	if _, err := runSoundsWith(t, fake, "--play", "Bottle"); err == nil {
+		t.Error("expected an error for a sound that is not installed")
+	}
+}
+
+func TestRunSounds_hook_command_falls_back_to_default(t *testing.T) {
+	out, err := runSoundsWith(t, &notify.Fake{SoundList: freedesktopSounds}, "--hook-command", "Bottle")
+	if err != nil {
+		t.Fatalf("sounds --hook-command: %v", err)
+	}
+	if want := "fake-play /usr/share/sounds/freedesktop/stereo/complete.oga &\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestRunSounds_no_sounds(t *testing.T) {
+	if _, err := runSoundsWith(t, &notify.Fake{}); err == nil {
+		t.Error("expected an error when no sounds are found")
+	}
+}
+
+func TestSoundNameOrDefault(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"", ""},
+		{"bell", "bell"},
+		{"Bottle", "complete"},
+	}
+	for _, tt := range tests {
+		if got := soundNameOrDefault(tt.name, freedesktopSounds); got != tt.want {
+			t.Errorf("soundNameOrDefault(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := soundNameOrDefault("Bottle", nil); got != "Bottle" {
+		t.Errorf("with no sounds = %q, want the name unchanged", got)
+	}
+}
+
+// fakePane records what the notify command does to Claude's pane.
+type fakePane struct {
+	focused bool
+	bells   int
+	titles  []string
+}
+
+func (p *fakePane) Focused() bool               { return p.focused }
+func (p *fakePane) Bell() error                 { p.bells++; return nil }
+func (p *fakePane) SetTitle(title string) error { p.titles = append(p.titles, title); return nil }
+
+// detached collects the events the hook in runNotifyWith handed to the sinks.
+var detached []notify.Event
+
+// runNotifyWith runs the notify command on payload with a fake backend and
+// pane, and returns its output.
+func runNotifyWith(t *testing.T, payload string, backend *notify.Fake, pane *fakePane, args ...string) (string, error) {
+	t.Helper()
+	detached = nil
+	dir := t.TempDir()
+	stdin := filepath.Join(dir, "hook.json")
+	os.WriteFile(stdin, []byte(payload), 0644)
+
+	prevDeliver := deliverDetached
+	deliverDetached = func(e notify.Event) error {
+		detached = append(detached, e)
+		return nil
+	}
+	prevBackend, prevPane := soundBackend, notifyPane
+	soundBackend = func() notify.Backend { return backend }
+	notifyPane = func() notify.Pane {
+		if pane == nil {
+			return nil
+		}
+		return pane
+	}
+	t.Setenv("GHOST_TAB_PROJECT", "my-app")
+	t.Setenv("GHOST_TAB_TITLE", "my-app · claude")
+	defer func() {
+		soundBackend, notifyPane, deliverDetached = prevBackend, prevPane, prevDeliver
+		notifyDryRun, notifyTest, notifyDeliver, notifyConfig = false, false, false, notify.DefaultConfigFile()
+		notifySoundFile = filepath.Join(filepath.Dir(notify.DefaultConfigFile()), "claude-features.json")
+	}()
+	rootCmd.SetArgs(append([]string{"notify",
+		"--config", filepath.Join(dir, "notify.toml"),
+		"--sound-file", filepath.Join(dir, "claude-features.json"),
+	}, args...))
+
+	oldIn, oldOut := os.Stdin, os.Stdout
+	os.Stdin, _ = os.Open(stdin)
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdin, os.Stdout = oldIn, oldOut
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String(), err
+}
+
+func TestRunNotify_Stop_when_away(t *testing.T) {
+	transcript := filepath.Join(t.TempDir(), "t.jsonl")
+	os.WriteFile(transcript, []byte(`{"type":"assistant","message":{"content":[{"type":"text","text":"Tests pass."}]}}`+"\n"), 0644)
+	backend := &notify.Fake{SoundList: []notify.Sound{{Name: "Bottle", Path: "/s/Bottle.aiff"}}}
+	pane := &fakePane{}
+
+	_, err := runNotifyWith(t, `{"hook_event_name":"Stop","cwd":"/tmp/x","transcript_path":"`+transcript+`"}`, backend, pane)
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(backend.Played) != 1 || backend.Played[0].Name != "Bottle" {
+		t.Errorf("played %v, want Bottle", backend.Played)
+	}
+	want := []notify.Notification{{Title: "Claude Code · my-app", Body: "Tests pass."}}
+	if !reflect.DeepEqual(backend.Notifications, want) {
+		t.Errorf("notifications = %v, want %v", backend.Notifications, want)
+	}
+	if pane.bells != 1 {
+		t.Errorf("rang the bell %d times, want 1", pane.bells)
+	}
+	if !reflect.DeepEqual(pane.titles, []string{"● my-app · claude"}) {
+		t.Errorf("titles = %q", pane.titles)
+	}
+}
+
+func TestRunNotify_Stop_when_focused_only_plays_sound(t *testing.T) {
+	backend := &notify.Fake{SoundList: []notify.Sound{{Name: "complete", Path: "/s/complete.oga"}}}
+	pane := &fakePane{focused: true}
+
+	if _, err := runNotifyWith(t, `{"hook_event_name":"Stop"}`, backend, pane); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	// Bottle is not installed, so the platform default plays instead
+	if len(backend.Played) != 1 || backend.Played[0].Name != "complete" {
+		t.Errorf("played %v, want complete", backend.Played)
+	}
+	if len(backend.Notifications) != 0 || pane.bells != 0 || len(pane.titles) != 0 {
+		t.Errorf("expected only a sound, got notifications %v, %d bells, titles %q",
+			backend.Notifications, pane.bells, pane.titles)
+	}
+}
+
+func TestRunNotify_UserPromptSubmit_resets_title(t *testing.T) {
+	pane := &fakePane{focused: true}
+	if _, err := runNotifyWith(t, `{"hook_event_name":"UserPromptSubmit"}`, &notify.Fake{}, pane); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if !reflect.DeepEqual(pane.titles, []string{"my-app · claude"}) {
+		t.Errorf("titles = %q", pane.titles)
+	}
+}
+
+func TestRunNotify_dry_run_with_config(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "notify.toml")
+	os.WriteFile(cfgFile, []byte("[projects.my-app.Notification]\nactions = [\"bell\", \"desktop\"]\n"), 0644)
+	backend := &notify.Fake{}
+
+	out, err := runNotifyWith(t, `{"hook_event_name":"Notification","message":"Needs permission"}`,
+		backend, nil, "--dry-run", "--config", cfgFile)
+	if err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if out != "bell\ndesktop\n" {
+		t.Errorf("output = %q, want bell and desktop", out)
+	}
+	if len(backend.Notifications) != 0 {
+		t.Errorf("dry run showed notifications: %v", backend.Notifications)
+	}
+}
+
+func TestRunNotify_Stop_sends_to_sinks(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "notify.toml")
+	events := filepath.Join(dir, "events.jsonl")
+	os.WriteFile(cfgFile, []byte("[[sinks]]\ntype = \"file\"\npath = \""+events+"\"\n"), 0644)
+
+	if _, err := runNotifyWith(t, `{"hook_event_name":"Stop","session_id":"abc"}`, &notify.Fake{}, nil, "--config", cfgFile); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(detached) != 1 {
+		t.Fatalf("handed %d events to the sinks, want 1", len(detached))
+	}
+	got := detached[0]
+	if got.Event != "Stop" || got.Title != "Claude Code · my-app" || got.Message != "Claude finished" || got.SessionID != "abc" {
+		t.Errorf("event = %+v", got)
+	}
+	if _, err := os.Stat(events); err == nil {
+		t.Error("the hook delivered to the sink itself instead of leaving it to --deliver")
+	}
+
+	payload, _ := json.Marshal(got)
+	if _, err := runNotifyWith(t, string(payload), &notify.Fake{}, nil, "--deliver", "--config", cfgFile); err != nil {
+		t.Fatalf("notify --deliver: %v", err)
+	}
+	data, err := os.ReadFile(events)
+	if err != nil {
+		t.Fatalf("sink not written: %v", err)
+	}
+	var written notify.Event
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("event %s: %v", data, err)
+	}
+	if written.SessionID != "abc" || written.Message != "Claude finished" {
+		t.Errorf("written event = %+v", written)
+	}
+}
+
+func TestRunNotify_deliver_reports_failed_sinks(t *testing.T) {
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	cfgFile := filepath.Join(t.TempDir(), "notify.toml")
+	os.WriteFile(cfgFile, []byte("[[sinks]]\ntype = \"command\"\ncommand = \"exit 1\"\nattempts = 1\n"), 0644)
+
+	_, err := runNotifyWith(t, `{"event":"Stop"}`, &notify.Fake{}, nil, "--deliver", "--config", cfgFile)
+	if err == nil || !strings.Contains(err.Error(), "command") {
+		t.Errorf("expected the failed sink to be reported, got %v", err)
+	}
+}
+
+func TestRunNotify_sinks_muted_in_features(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "notify.toml")
+	features := filepath.Join(dir, "features.json")
+	events := filepath.Join(dir, "events.jsonl")
+	os.WriteFile(cfgFile, []byte("[[sinks]]\ntype = \"file\"\npath = \""+events+"\"\n"), 0644)
+	os.WriteFile(features, []byte(`{"sinks": false}`), 0644)
+
+	if _, err := runNotifyWith(t, `{"hook_event_name":"Stop"}`, &notify.Fake{}, nil,
+		"--config", cfgFile, "--sound-file", features); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(detached) != 0 {
+		t.Errorf("muted sinks were handed %v", detached)
+	}
+}
+
+func TestRunNotify_test_uses_the_stand_in(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "notify.toml")
+	events := filepath.Join(dir, "events.jsonl")
+	os.WriteFile(cfgFile, []byte(`[[sinks]]
+type = "webhook"
+url = "https://hooks.example.invalid/services/T000"
+format = "slack"
+headers = { Authorization = "Bearer xyz" }
+
+[[sinks]]
+type = "file"
+path = "`+events+`"
+`), 0644)
+
+	out, err := runNotifyWith(t, ``, &notify.Fake{}, nil, "--test", "--config", cfgFile)
+	if err != nil {
+		t.Fatalf("notify --test: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"✓ webhook https://hooks.example.invalid/services/T000",
+		"POST /",
+		"Authorization: Bearer xyz",
+		`{"text": "Claude Code · my-app: All tests pass. Ready for review."}`,
+		"✓ file " + events,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(events); err != nil {
+		t.Errorf("file sink not written: %v", err)
+	}
+}
+
+func TestRunNotify_test_without_sinks(t *testing.T) {
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	if _, err := runNotifyWith(t, ``, &notify.Fake{}, nil, "--test"); err == nil || !strings.Contains(err.Error(), "no sinks configured") {
+		t.Errorf("expected a no sinks error, got %v", err)
+	}
+}
+
+func TestRunNotify_InvalidJSON(t *testing.T) {
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	if _, err := runNotifyWith(t, `Stop`, &notify.Fake{}, nil); err == nil || !strings.Contains(err.Error(), "invalid hook JSON") {
+		t.Errorf("expected an invalid JSON error, got %v", err)
+	}
+}
+
+func TestRunNotify_Install(t *testing.T) {
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	defer func() { notifyInstall = false }()
+
+	out, err := runNotifyWith(t, `{}`, &notify.Fake{}, nil, "--install", "--settings", settings)
+	if err != nil {
+		t.Fatalf("notify --install: %v", err)
+	}
+	if out != "added\n" {
+		t.Errorf("output = %q, want added", out)
+	}
+	data, _ := os.ReadFile(settings)
+	if !strings.Contains(string(data), `"command": "ghost-tab-tui notify"`) {
+		t.Errorf("settings missing the notify hook:\n%s", data)
+	}
+}
+
+func TestRunNotify_Uninstall(t *testing.T) {
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	os.WriteFile(settings, []byte(`{"hooks": {"Stop": [{"hooks": [{"type": "command", "command": "echo done"}]}]}}`), 0644)
+	defer func() { notifyInstall, notifyUninstall = false, false }()
+
+	if _, err := runNotifyWith(t, `{}`, &notify.Fake{}, nil, "--install", "--settings", settings); err != nil {
+		t.Fatalf("notify --install: %v", err)
+	}
+	notifyInstall = false
+	out, err := runNotifyWith(t, `{}`, &notify.Fake{}, nil, "--uninstall", "--settings", settings)
+	if err != nil {
+		t.Fatalf("notify --uninstall: %v", err)
+	}
+	if out != "removed\n" {
+		t.Errorf("output = %q, want removed", out)
+	}
+	data, _ := os.ReadFile(settings)
+	if strings.Contains(string(data), "ghost-tab-tui notify") || !strings.Contains(string(data), "echo done") {
+		t.Errorf("expected only the notify hooks removed:\n%s", data)
+	}
+
+	if out, _ := runNotifyWith(t, `{}`, &notify.Fake{}, nil, "--uninstall", "--settings", settings); out != "not_found\n" {
+		t.Errorf("second uninstall = %q, want not_found", out)
+	}
+}
+
+// runHooksWith runs a hooks subcommand on the settings file and returns its
+// output. Flags are reset afterwards, including whether they were set.
+func runHooksWith(t *testing.T, settings string, args ...string) (string, error) {
+	t.Helper()
+	defer func() {
+		for _, c := range hooksCmd.Commands() {
+			c.Flags().VisitAll(func(f *pflag.Flag) {
+				f.Value.Set(f.DefValue)
+				f.Changed = false
+			})
+		}
+	}()
+	rootCmd.SetArgs(append(append([]string{"hooks"}, args...), "--settings", settings))
+
+	oldOut := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := rootCmd.Execute()
+	w.Close()
+	os.Stdout = oldOut
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String(), err
+}
+
+func TestRunHooks_add_list_remove(t *testing.T) {
+	settings := filepath.Join(t.TempDir(), "settings.json")
+
+	for _, args := range [][]string{
+		{"add", "--event", "PreToolUse", "--matcher", "Bash", "--command", "check-bash", "--timeout", "30"},
+		{"add", "--event", "Stop", "--command", "afplay Bottle.aiff &"},
+	} {
+		if out, err := runHooksWith(t, settings, args...); err != nil || out != "added\n" {
+			t.Fatalf("hooks %v = %q, %v", args, out, err)
+		}
+	}
+	if out, _ := runHooksWith(t, settings, "add", "--event", "Stop", "--command", "afplay Bottle.aiff &"); out != "exists\n" {
+		t.Errorf("adding again = %q, want exists", out)
+	}
+
+	out, err := runHooksWith(t, settings, "list")
+	if err != nil {
+		t.Fatalf("hooks list: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "EVENT") ||
+		!strings.Contains(lines[1], "Bash") || !strings.Contains(lines[2], "Stop  ") || !strings.Contains(lines[2], " - ") {
+		t.Errorf("hooks list =\n%s", out)
+	}
+
+	out, _ = runHooksWith(t, settings, "list", "--json", "--event", "PreToolUse")
+	var hooks []config.Hook
+	if err := json.Unmarshal([]byte(out), &hooks); err != nil {
+		t.Fatalf("hooks list --json: %v\n%s", err, out)
+	}
+	want := []config.Hook{{Event: "PreToolUse", Matcher: "Bash", Command: "check-bash", Timeout: 30}}
+	if !reflect.DeepEqual(hooks, want) {
+		t.Errorf("hooks = %+v, want %+v", hooks, want)
+	}
+
+	if out, _ := runHooksWith(t, settings, "remove", "--event", "Stop", "--contains", "afplay "); out != "removed\n" {
+		t.Errorf("remove = %q, want removed", out)
+	}
+	if out, _ := runHooksWith(t, settings, "remove", "--command", "check-bash", "--matcher", "Edit"); out != "not_found\n" {
+		t.Errorf("remove with another matcher = %q, want not_found", out)
+	}
+	if out, _ := runHooksWith(t, settings, "list", "--json"); out != `[{"event":"PreToolUse","matcher":"Bash","command":"check-bash","timeout":30}]`+"\n" {
+		t.Errorf("after remove = %s", out)
+	}
+}
+
+func TestRunHooks_remove_ghost_tab_sound(t *testing.T) {
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	for _, command := range []string{"paplay /usr/share/sounds/bell.oga &", "cd ~/proj && paplay ~/done.wav"} {
+		if _, err := runHooksWith(t, settings, "add", "--event", "Stop", "--command", command); err != nil {
+			t.Fatalf("hooks add %q: %v", command, err)
+		}
+	}
+
+	if out, err := runHooksWith(t, settings, "remove", "--event", "Stop", "--ghost-tab-sound"); err != nil || out != "removed\n" {
+		t.Fatalf("remove --ghost-tab-sound = %q, %v", out, err)
+	}
+	if out, _ := runHooksWith(t, settings, "list", "--json"); out != `[{"event":"Stop","command":"cd ~/proj \u0026\u0026 paplay ~/done.wav"}]`+"\n" {
+		t.Errorf("after remove = %s", out)
+	}
+}
+
+func TestRunHooks_add_rejects_matcher_for_Stop(t *testing.T) {
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	_, err := runHooksWith(t, settings, "add", "--event", "Stop", "--matcher", "Bash", "--command", "x")
+	if err == nil || !strings.Contains(err.Error(), "don't take a matcher") {
+		t.Errorf("expected a matcher error, got %v", err)
+	}
+}
+
+func TestRunHooks_refuses_invalid_settings(t *testing.T) {
+	rootCmd.SetErr(io.Discard)
+	defer rootCmd.SetErr(nil)
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	content := "{\n  \"model\": \"opus\",\n  \"theme\": dark\n}\n"
+	os.WriteFile(settings, []byte(content), 0644)
+
+	_, err := runHooksWith(t, settings, "add", "--event", "Stop", "--command", "ding")
+	if err == nil || !strings.Contains(err.Error(), "line 3, column 12") {
+		t.Errorf("expected a parse error at line 3, column 12, got %v", err)
+	}
+	if data, _ := os.ReadFile(settings); string(data) != content {
+		t.Errorf("settings should be left alone, got\n%s", data)
+	}
+}
+
+func TestRunHooks_migrate(t *testing.T) {
+	settings := filepath.Join(t.TempDir(), "settings.json")
+	os.WriteFile(settings, []byte(`{"hooks": {"Notification": [{"matcher": "idle_prompt", "hooks": [{"type": "command", "command": "aplay -q /tmp/ding.wav &"}]}]}}`), 0644)
+
+	if out, err := runHooksWith(t, settings, "migrate"); err != nil || out != "1\n" {
+		t.Fatalf("hooks migrate = %q, %v", out, err)
+	}
+	if out, _ := runHooksWith(t, settings, "list", "--json"); out != `[{"event":"Stop","command":"aplay -q /tmp/ding.wav \u0026"}]`+"\n" {
+		t.Errorf("after migrate = %s", out)
+	}
+}
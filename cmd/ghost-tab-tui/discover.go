@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/tui"
+	"github.com/jackuait/ghost-tab/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var discoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find git repositories under scan roots and import them",
+	Long: `Scans the given root directories for git repositories and shows them in a
+checklist, with projects already in the projects file marked. The chosen
+repositories are appended to the projects file. Returns result as JSON.`,
+	RunE: runDiscover,
+}
+
+var (
+	discoverProjectsFile string
+	discoverRoots        []string
+	discoverMaxDepth     int
+	discoverList         bool
+)
+
+func init() {
+	discoverCmd.Flags().StringVar(&discoverProjectsFile, "projects-file", "", "Path to projects file")
+	discoverCmd.MarkFlagRequired("projects-file")
+	discoverCmd.Flags().StringSliceVar(&discoverRoots, "root", models.DefaultScanRoots, "Directory to scan (repeatable or comma-separated)")
+	discoverCmd.Flags().IntVar(&discoverMaxDepth, "max-depth", models.DefaultScanDepth, "Directory levels below each root to search")
+	discoverCmd.Flags().BoolVar(&discoverList, "list", false, "Print discovered repositories as JSON without prompting")
+	rootCmd.AddCommand(discoverCmd)
+}
+
+// discoveredProject is a repository in --list output.
+type discoveredProject struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Known bool   `json:"known"`
+}
+
+func runDiscover(cmd *cobra.Command, args []string) error {
+	if _, err := models.MigrateProjectsFile(discoverProjectsFile); err != nil {
+		return fmt.Errorf("failed to migrate projects file: %w", err)
+	}
+	var existing []models.Project
+	pf, err := models.ReadProjectsFile(discoverProjectsFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	} else {
+		existing = pf.Projects
+	}
+
+	found := models.DiscoverProjects(discoverRoots, discoverMaxDepth)
+	model := tui.NewImportSelect(found, existing)
+
+	if discoverList {
+		known := model.Known()
+		out := make([]discoveredProject, len(found))
+		for i, p := range found {
+			out[i] = discoveredProject{Name: p.Name, Path: p.Path, Known: known[i]}
+		}
+		jsonOutput, _ := json.Marshal(out)
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+
+	tui.ApplyTheme(tui.ThemeForTool(aiToolFlag))
+
+	ttyOpts, cleanup, err := util.TUITeaOptions()
+	if err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	defer cleanup()
+
+	opts := append([]tea.ProgramOption{tea.WithAltScreen()}, ttyOpts...)
+	p := tea.NewProgram(model, opts...)
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+
+	result := finalModel.(tui.ImportSelectModel).Result()
+	if result == nil || !result.Confirmed {
+		fmt.Println(`{"confirmed":false}`)
+		return nil
+	}
+
+	if err := importProjects(result.Projects, discoverProjectsFile); err != nil {
+		return err
+	}
+
+	output := map[string]interface{}{
+		"confirmed": true,
+		"imported":  result.Projects,
+		"count":     len(result.Projects),
+	}
+	jsonOutput, _ := json.Marshal(output)
+	fmt.Println(string(jsonOutput))
+
+	return nil
+}
+
+// importProjects appends each project to the projects file in order.
+func importProjects(projects []models.Project, file string) error {
+	for _, p := range projects {
+		if err := tui.AppendProject(p, file); err != nil {
+			return fmt.Errorf("failed to add project %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
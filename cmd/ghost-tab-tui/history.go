@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent project launches",
+	Long:  "Prints recently launched sessions, newest first, as a table or (with --json) as a JSON array.",
+	RunE:  runHistory,
+}
+
+var (
+	historyFile    string
+	historyLimit   int
+	historyProject string
+	historyJSON    bool
+)
+
+func init() {
+	historyCmd.Flags().StringVar(&historyFile, "history-file", "", "Path to history file (default: the ghost-tab config directory)")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Maximum number of sessions to show (0 = all)")
+	historyCmd.Flags().StringVar(&historyProject, "project", "", "Only show launches of this project")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	path := historyFile
+	if path == "" {
+		path = history.DefaultPath()
+	}
+	entries, err := history.NewStore(path).Load()
+	if err != nil {
+		return err
+	}
+
+	if historyProject != "" {
+		var filtered []history.Entry
+		for _, e := range entries {
+			if e.Project == historyProject {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+	recent := history.Recent(entries, historyLimit)
+
+	if historyJSON {
+		result := make([]map[string]interface{}, 0, len(recent))
+		for _, e := range recent {
+			item := map[string]interface{}{
+				"project":    e.Project,
+				"path":       e.Path,
+				"ai_tool":    e.AITool,
+				"session":    e.Session,
+				"started_at": e.StartedAt,
+				"ended_at":   e.EndedAt,
+				"duration":   int64(e.Duration().Seconds()),
+			}
+			result = append(result, item)
+		}
+		jsonOutput, _ := json.Marshal(result)
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+
+	writeHistoryTable(os.Stdout, recent)
+	return nil
+}
+
+// writeHistoryTable prints entries as an aligned table.
+func writeHistoryTable(out io.Writer, entries []history.Entry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No sessions recorded yet.")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tPROJECT\tAI TOOL\tDURATION\tPATH")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			e.StartedAt.Local().Format("2006-01-02 15:04"),
+			e.Project,
+			e.AITool,
+			formatDuration(e),
+			shortenHome(e.Path),
+		)
+	}
+	w.Flush()
+}
+
+// formatDuration renders a session's length compactly ("1h05m", "12m",
+// "40s"); sessions without a recorded end show "-".
+func formatDuration(e history.Entry) string {
+	if e.EndedAt == nil {
+		return "-"
+	}
+	d := e.Duration().Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+// shortenHome replaces the $HOME prefix of path with ~.
+func shortenHome(path string) string {
+	if home := os.Getenv("HOME"); home != "" && len(path) >= len(home) && path[:len(home)] == home {
+		return "~" + path[len(home):]
+	}
+	return path
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jackuait/ghost-tab/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage the hooks in Claude's settings",
+	Long: `Lists, adds and removes the command hooks in Claude's settings file for
+its hook events: PreToolUse, PostToolUse, Stop, SubagentStop, Notification,
+UserPromptSubmit and SessionStart. PreToolUse, PostToolUse, Notification and
+SessionStart hooks can be narrowed with a matcher. Settings and hooks
+Ghost Tab doesn't manage are left as they are.`,
+}
+
+var hooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the hooks",
+	Args:  cobra.NoArgs,
+	RunE:  runHooksList,
+}
+
+var hooksAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a hook",
+	Long: `Adds a command hook for an event and prints "added", or "exists" when the
+event and matcher already run the command.`,
+	Args: cobra.NoArgs,
+	RunE: runHooksAdd,
+}
+
+var hooksRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove hooks",
+	Long: `Removes the hooks running the command given by --command, every hook
+whose command contains the text given by --contains, or, with
+--ghost-tab-sound, the sound hooks Ghost Tab wrote (a sound player run in
+the background), optionally only for one event and matcher. Prints
+"removed" or "not_found".`,
+	Args: cobra.NoArgs,
+	RunE: runHooksRemove,
+}
+
+var hooksMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move hooks registered by older Ghost Tab versions",
+	Long: `Moves hooks from where older Ghost Tab versions registered them, such as
+the sound hook on Notification's idle_prompt, to the event Ghost Tab uses
+now. Hooks Ghost Tab didn't write are left alone. Prints how many hooks
+were moved.`,
+	Args: cobra.NoArgs,
+	RunE: runHooksMigrate,
+}
+
+var (
+	hooksSettings string
+	hooksJSON     bool
+	hooksEvent    string
+	hooksMatcher  string
+	hooksCommand  string
+	hooksContains string
+	hooksTimeout  int
+	hooksSound    bool
+)
+
+func init() {
+	home, _ := os.UserHomeDir()
+	hooksCmd.PersistentFlags().StringVar(&hooksSettings, "settings", filepath.Join(home, ".claude", "settings.json"), "Path to Claude's settings file")
+
+	hooksListCmd.Flags().BoolVar(&hooksJSON, "json", false, "Output as JSON")
+	hooksListCmd.Flags().StringVar(&hooksEvent, "event", "", "Only list hooks for this event")
+
+	hooksAddCmd.Flags().StringVar(&hooksEvent, "event", "", "Hook event, e.g. Stop or PreToolUse")
+	hooksAddCmd.MarkFlagRequired("event")
+	hooksAddCmd.Flags().StringVar(&hooksMatcher, "matcher", "", "Matcher, e.g. a tool name such as Bash")
+	hooksAddCmd.Flags().StringVar(&hooksCommand, "command", "", "Shell command to run")
+	hooksAddCmd.MarkFlagRequired("command")
+	hooksAddCmd.Flags().IntVar(&hooksTimeout, "timeout", 0, "Timeout in seconds (0 for Claude's default)")
+
+	hooksRemoveCmd.Flags().StringVar(&hooksEvent, "event", "", "Only remove hooks for this event")
+	hooksRemoveCmd.Flags().StringVar(&hooksMatcher, "matcher", "", "Only remove hooks with this matcher")
+	hooksRemoveCmd.Flags().StringVar(&hooksCommand, "command", "", "Remove hooks running exactly this command")
+	hooksRemoveCmd.Flags().StringVar(&hooksContains, "contains", "", "Remove hooks whose command contains this text")
+	hooksRemoveCmd.Flags().BoolVar(&hooksSound, "ghost-tab-sound", false, "Remove the sound hooks Ghost Tab wrote")
+	hooksRemoveCmd.MarkFlagsOneRequired("command", "contains", "ghost-tab-sound")
+	hooksRemoveCmd.MarkFlagsMutuallyExclusive("command", "contains", "ghost-tab-sound")
+
+	hooksCmd.AddCommand(hooksListCmd, hooksAddCmd, hooksRemoveCmd, hooksMigrateCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
+
+func runHooksList(cmd *cobra.Command, args []string) error {
+	hooks, err := config.ListHooks(hooksSettings)
+	if err != nil {
+		return err
+	}
+	if hooksEvent != "" {
+		var filtered []config.Hook
+		for _, h := range hooks {
+			if h.Event == hooksEvent {
+				filtered = append(filtered, h)
+			}
+		}
+		hooks = filtered
+	}
+	if hooksJSON {
+		if hooks == nil {
+			hooks = []config.Hook{}
+		}
+		jsonOutput, _ := json.Marshal(hooks)
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+	writeHooksTable(os.Stdout, hooks)
+	return nil
+}
+
+// writeHooksTable writes one aligned row per hook, "-" for no matcher.
+func writeHooksTable(out io.Writer, hooks []config.Hook) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EVENT\tMATCHER\tCOMMAND")
+	for _, h := range hooks {
+		matcher := h.Matcher
+		if matcher == "" {
+			matcher = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", h.Event, matcher, h.Command)
+	}
+	w.Flush()
+}
+
+func runHooksAdd(cmd *cobra.Command, args []string) error {
+	result, err := config.AddHook(hooksSettings, config.Hook{
+		Event:   hooksEvent,
+		Matcher: hooksMatcher,
+		Command: hooksCommand,
+		Timeout: hooksTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func runHooksRemove(cmd *cobra.Command, args []string) error {
+	matchMatcher := cmd.Flags().Changed("matcher")
+	removed, err := config.RemoveHooks(hooksSettings, func(h config.Hook) bool {
+		if hooksEvent != "" && h.Event != hooksEvent {
+			return false
+		}
+		if matchMatcher && h.Matcher != hooksMatcher {
+			return false
+		}
+		switch {
+		case hooksSound:
+			return config.IsSoundHookCommand(h.Command)
+		case hooksContains != "":
+			return strings.Contains(h.Command, hooksContains)
+		}
+		return h.Command == hooksCommand
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		fmt.Println(config.HookNotFound)
+		return nil
+	}
+	fmt.Println(config.HookRemoved)
+	return nil
+}
+
+func runHooksMigrate(cmd *cobra.Command, args []string) error {
+	moved, err := config.MigrateHooks(hooksSettings)
+	if err != nil {
+		return err
+	}
+	fmt.Println(moved)
+	return nil
+}
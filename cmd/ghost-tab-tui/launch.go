@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/aitools"
+	"github.com/jackuait/ghost-tab/internal/history"
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/session"
+	"github.com/jackuait/ghost-tab/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var launchCmd = &cobra.Command{
+	Use:   "launch [-- ai-args...]",
+	Short: "Launch the tmux session for a project",
+	Long:  "Creates the project's tmux session from its layout (default: lazygit / AI tool / broot / shell) and attaches to it. Arguments after -- are passed to the AI tool. With several --agent flags the AI tools run side by side, each in its own pane.",
+	RunE:  runLaunch,
+}
+
+var (
+	launchProject      string
+	launchName         string
+	launchSession      string
+	launchBaselineFile string
+	launchLayout       string
+	launchLayoutsFile  string
+	launchPersist      bool
+	launchAttach       string
+	launchProjectsFile string
+	launchHistoryFile  string
+	launchProfile      string
+	launchAgents       []string
+	launchTabTitle     string
+)
+
+func init() {
+	launchCmd.Flags().StringVar(&launchProject, "project", "", "Path to the project directory")
+	launchCmd.MarkFlagRequired("project")
+	launchCmd.Flags().StringVar(&launchName, "name", "", "Project name (default: directory name)")
+	launchCmd.Flags().StringVar(&launchSession, "session", "", "tmux session name (default: dev-<name>-<pid>)")
+	launchCmd.Flags().StringVar(&launchBaselineFile, "baseline-file", "", "Session baseline file exported as GHOST_TAB_BASELINE_FILE")
+	launchCmd.Flags().StringVar(&launchLayout, "layout", "", "Layout name (default: the project's layout, then \"default\")")
+	launchCmd.Flags().StringVar(&launchLayoutsFile, "layouts-file", "", "Path to layouts JSON file")
+	launchCmd.Flags().BoolVar(&launchPersist, "persist", false, "Keep the session running after the window closes")
+	launchCmd.Flags().StringVar(&launchAttach, "attach", "", "Attach to this running session instead of creating one")
+	launchCmd.Flags().StringVar(&launchProjectsFile, "projects-file", "", "Projects file with per-project settings (AI args, env, layout)")
+	launchCmd.Flags().StringVar(&launchHistoryFile, "history-file", "", "Record the launch and its duration in this history file")
+	launchCmd.Flags().StringVar(&launchProfile, "profile", "", "AI tool launch profile, or \"default\" for none (default: the project's profile)")
+	launchCmd.Flags().StringArrayVar(&launchAgents, "agent", nil, "AI tool to run, as tool or tool=dir; repeat to run several side by side (default: --ai-tool in the project directory)")
+	launchCmd.Flags().StringVar(&launchTabTitle, "tab-title", "", "Tab title exported as GHOST_TAB_TITLE, restored by the notify hook")
+	rootCmd.AddCommand(launchCmd)
+}
+
+// resolveCommand returns the absolute path of name if it is on PATH,
+// otherwise name itself.
+func resolveCommand(name string) string {
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	return name
+}
+
+func runLaunch(cmd *cobra.Command, args []string) error {
+	// Keep running when the terminal window closes so the end of the
+	// session is still recorded; tmux exits on its own.
+	signal.Ignore(syscall.SIGHUP)
+
+	projectDir, err := filepath.Abs(util.ExpandPath(launchProject))
+	if err != nil {
+		return fmt.Errorf("resolving project path: %w", err)
+	}
+
+	name := launchName
+	if name == "" {
+		name = filepath.Base(projectDir)
+	}
+
+	if launchAttach != "" {
+		entry := history.Entry{Project: name, Path: projectDir, AITool: aiToolFlag, Session: launchAttach}
+		return recordLaunch(entry, func() error {
+			return session.Attach(session.NewTmux(), launchAttach)
+		})
+	}
+
+	if err := util.ValidatePath(projectDir); err != nil {
+		return err
+	}
+
+	sessionName := launchSession
+	if sessionName == "" {
+		sessionName = session.SessionName(name, os.Getpid())
+	}
+
+	project, err := lookupProject(projectDir, name)
+	if err != nil {
+		return err
+	}
+	if launchLayout != "" {
+		project.Layout = launchLayout
+	}
+
+	layout, err := resolveLayout(project)
+	if err != nil {
+		return err
+	}
+
+	args = append(append([]string{}, project.AIArgs...), args...)
+	tool, agents, err := buildAgents(launchAgents, projectDir, project, args)
+	if err != nil {
+		return err
+	}
+	aiTool := aiToolFlag
+	if len(launchAgents) > 0 {
+		aiTool = tool.Name
+	}
+
+	env := []string{"PATH=" + os.Getenv("PATH"), "GHOST_TAB_PROJECT=" + name}
+	if launchTabTitle != "" {
+		env = append(env, "GHOST_TAB_TITLE="+launchTabTitle)
+	}
+	if launchBaselineFile != "" {
+		env = append(env, "GHOST_TAB_BASELINE_FILE="+launchBaselineFile)
+	}
+	env = append(env, projectEnv(project)...)
+
+	cfg := session.Config{
+		SessionName: sessionName,
+		ProjectName: name,
+		ProjectDir:  projectDir,
+		Agents:      agents,
+		ReadyPrompt: tool.ReadyPromptRegexp(),
+		LazygitCmd:  resolveCommand("lazygit"),
+		BrootCmd:    resolveCommand("broot"),
+		Env:         env,
+		Layout:      layout,
+		Persist:     launchPersist,
+	}
+
+	entry := history.Entry{Project: name, Path: projectDir, AITool: aiTool, Session: sessionName}
+	return recordLaunch(entry, func() error {
+		return session.Launch(session.NewTmux(), cfg)
+	})
+}
+
+// recordLaunch runs launch, recording it in the history file when one was
+// given. History is best effort: failing to write it never stops a launch.
+func recordLaunch(entry history.Entry, launch func() error) error {
+	if launchHistoryFile == "" {
+		return launch()
+	}
+	store := history.NewStore(launchHistoryFile)
+	started, err := store.Start(entry)
+	launchErr := launch()
+	if err == nil {
+		_ = store.Finish(started.ID, time.Now())
+	}
+	return launchErr
+}
+
+// resolveLayout picks the layout for a project: its own Layout if set,
+// otherwise its assignment in the layouts file, otherwise the default.
+func resolveLayout(project models.Project) (session.Layout, error) {
+	lf := session.LayoutFile{}
+	if launchLayoutsFile != "" {
+		var err error
+		if lf, err = session.LoadLayoutFile(launchLayoutsFile); err != nil {
+			return session.Layout{}, err
+		}
+	}
+	projects := []models.Project{project}
+	lf.Apply(projects)
+	return lf.Find(projects[0].Layout)
+}
+
+// lookupProject returns the projects file entry for the launched directory,
+// matched by path and then by name (worktrees live at other paths). Without
+// a projects file or a match, a bare project is returned.
+func lookupProject(projectDir, name string) (models.Project, error) {
+	bare := models.Project{Name: name, Path: projectDir}
+	if launchProjectsFile == "" {
+		return bare, nil
+	}
+	pf, err := models.ReadProjectsFile(launchProjectsFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return bare, nil
+		}
+		return bare, err
+	}
+	for _, p := range pf.Projects {
+		if filepath.Clean(util.ExpandPath(p.Path)) == projectDir {
+			p.Path = projectDir
+			return p, nil
+		}
+	}
+	for _, p := range pf.Projects {
+		if p.Name == name {
+			p.Path = projectDir
+			return p, nil
+		}
+	}
+	return bare, nil
+}
+
+// resolveProfile picks the launch profile: the one named on the command
+// line, which tool must define, otherwise the project's default when tool
+// defines it.
+func resolveProfile(tool aitools.Tool, flag, projectDefault string) (aitools.Profile, error) {
+	if flag != "" {
+		p, ok := tool.Profile(flag)
+		if !ok {
+			return p, fmt.Errorf("%s has no launch profile %q", tool.DisplayName, flag)
+		}
+		return p, nil
+	}
+	p, _ := tool.Profile(projectDefault)
+	return p, nil
+}
+
+// buildAgents resolves the AI tools to run: one per --agent spec ("tool" or
+// "tool=dir"), or the --ai-tool tool in the project directory. --profile
+// applies to the first agent; the others use the project's default profile
+// when they define it. The first agent's tool is returned too.
+func buildAgents(specs []string, projectDir string, project models.Project, args []string) (aitools.Tool, []session.Agent, error) {
+	var tools []aitools.Tool
+	var dirs []string
+	if len(specs) == 0 {
+		tool, ok := aitools.Current().Lookup(aiToolFlag)
+		if !ok {
+			tool = aitools.Current().Get(aitools.DefaultTool)
+		}
+		tools, dirs = []aitools.Tool{tool}, []string{projectDir}
+	}
+	for _, spec := range specs {
+		name, dir, _ := strings.Cut(spec, "=")
+		tool, ok := aitools.Current().Lookup(name)
+		if !ok {
+			return aitools.Tool{}, nil, fmt.Errorf("unknown AI tool %q", name)
+		}
+		if dir == "" {
+			dir = projectDir
+		} else {
+			abs, err := filepath.Abs(util.ExpandPath(dir))
+			if err != nil {
+				return aitools.Tool{}, nil, fmt.Errorf("resolving agent path: %w", err)
+			}
+			if err := util.ValidatePath(abs); err != nil {
+				return aitools.Tool{}, nil, err
+			}
+			dir = abs
+		}
+		tools, dirs = append(tools, tool), append(dirs, dir)
+	}
+
+	agents := make([]session.Agent, len(tools))
+	for i, tool := range tools {
+		flag := ""
+		if i == 0 {
+			flag = launchProfile
+		}
+		profile, err := resolveProfile(tool, flag, project.Profile)
+		if err != nil {
+			return aitools.Tool{}, nil, err
+		}
+		agents[i] = session.Agent{
+			Name:  tool.DisplayName,
+			Dir:   dirs[i],
+			Argv:  util.BuildAILaunchArgv(tool.Name, resolveCommand(tool.Command), dirs[i], profile, args),
+			Color: tool.Theme.Primary,
+		}
+	}
+	return tools[0], agents, nil
+}
+
+// projectEnv returns a project's environment variables as sorted KEY=VALUE
+// pairs.
+func projectEnv(project models.Project) []string {
+	keys := make([]string, 0, len(project.Env))
+	for k := range project.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := make([]string, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, k+"="+project.Env[k])
+	}
+	return env
+}
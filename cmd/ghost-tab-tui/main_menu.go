@@ -8,7 +8,10 @@ import (
 	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackuait/ghost-tab/internal/history"
 	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/notify"
+	"github.com/jackuait/ghost-tab/internal/session"
 	"github.com/jackuait/ghost-tab/internal/tui"
 	"github.com/jackuait/ghost-tab/internal/util"
 	"github.com/spf13/cobra"
@@ -31,6 +34,11 @@ var (
 	mainMenuSoundName    string
 	mainMenuSettingsFile string
 	mainMenuSoundFile    string
+	mainMenuPersist      string
+	mainMenuCollapsed    string
+	mainMenuProjectSort  string
+	mainMenuHistoryFile  string
+	mainMenuStatusCache  string
 )
 
 func init() {
@@ -45,6 +53,11 @@ func init() {
 	mainMenuCmd.Flags().StringVar(&mainMenuSoundName, "sound-name", "", "Sound name for notifications (empty = off)")
 	mainMenuCmd.Flags().StringVar(&mainMenuSettingsFile, "settings-file", "", "Path to settings file for persistence")
 	mainMenuCmd.Flags().StringVar(&mainMenuSoundFile, "sound-file", "", "Path to sound features JSON file for persistence")
+	mainMenuCmd.Flags().StringVar(&mainMenuPersist, "persist-session", "off", "Keep sessions running after the window closes (on, off)")
+	mainMenuCmd.Flags().StringVar(&mainMenuCollapsed, "collapsed-groups", "", "Comma-separated project groups to show collapsed")
+	mainMenuCmd.Flags().StringVar(&mainMenuProjectSort, "project-sort", "manual", "Project order (manual, recent, frequent)")
+	mainMenuCmd.Flags().StringVar(&mainMenuHistoryFile, "history-file", "", "Path to launch history file for recent/frequent ordering")
+	mainMenuCmd.Flags().StringVar(&mainMenuStatusCache, "git-status-cache", "", "Path to git status cache file shared between menus")
 	rootCmd.AddCommand(mainMenuCmd)
 }
 
@@ -53,6 +66,11 @@ func runMainMenu(cmd *cobra.Command, args []string) error {
 	// Bubbletea will detect TTY EOF and shut down gracefully instead.
 	signal.Ignore(syscall.SIGHUP)
 
+	// One-time upgrade of the legacy name:path file to the structured format.
+	if _, err := models.MigrateProjectsFile(mainMenuProjectsFile); err != nil {
+		return fmt.Errorf("failed to migrate projects file: %w", err)
+	}
+
 	projects, err := models.LoadProjects(mainMenuProjectsFile)
 	if err != nil {
 		return fmt.Errorf("failed to load projects: %w", err)
@@ -67,7 +85,20 @@ func runMainMenu(cmd *cobra.Command, args []string) error {
 
 	model := tui.NewMainMenu(projects, aiTools, mainMenuAITool, mainMenuGhostDisplay)
 	model.SetTabTitle(mainMenuTabTitle)
-	model.SetSoundName(mainMenuSoundName)
+	sounds := notify.Detect()
+	model.SetSoundBackend(sounds)
+	model.SetSoundName(soundNameOrDefault(mainMenuSoundName, sounds.Sounds()))
+	model.SetPersistSession(mainMenuPersist)
+	model.SetCollapsedGroups(strings.Split(mainMenuCollapsed, ","))
+	if mainMenuHistoryFile != "" {
+		// A broken history file only loses the ordering, not the menu
+		entries, _ := history.NewStore(mainMenuHistoryFile).Load()
+		model.SetProjectUsage(history.Summarize(entries))
+	}
+	model.SetProjectSort(mainMenuProjectSort)
+	model.SetLiveSessions(liveSessions(projects))
+	model.SetGitStatusCache(models.NewGitStatusCache(mainMenuStatusCache, models.DefaultGitStatusTTL))
+	model.SetCheckAITools(true)
 	model.SetProjectsFile(mainMenuProjectsFile)
 	if mainMenuAIToolFile != "" {
 		model.SetAIToolFile(mainMenuAIToolFile)
@@ -108,3 +139,33 @@ func runMainMenu(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// liveSessions returns the running Ghost Tab tmux sessions of each project.
+// Returns nil when no tmux server is running.
+func liveSessions(projects []models.Project) map[string][]string {
+	names, err := session.NewTmux().ListSessions()
+	if err != nil {
+		return nil
+	}
+	live := make(map[string][]string)
+	for _, p := range projects {
+		live[p.Name] = session.ProjectSessions(names, p.Name)
+	}
+	return live
+}
+
+// soundNameOrDefault returns name if it is one of sounds. A sound that is
+// not installed here, such as the macOS default on Linux, is replaced by
+// the platform's default sound so the menu starts on something that plays.
+func soundNameOrDefault(name string, sounds []notify.Sound) string {
+	if name == "" {
+		return ""
+	}
+	if _, ok := notify.FindSound(sounds, name); ok {
+		return name
+	}
+	if s, ok := notify.DefaultSound(sounds); ok {
+		return s.Name
+	}
+	return name
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackuait/ghost-tab/internal/aitools"
 	"github.com/jackuait/ghost-tab/internal/models"
 	"github.com/jackuait/ghost-tab/internal/tui"
 	"github.com/jackuait/ghost-tab/internal/util"
@@ -25,7 +26,13 @@ func init() {
 func runMultiSelectAITool(cmd *cobra.Command, args []string) error {
 	tui.ApplyTheme(tui.ThemeForTool(aiToolFlag))
 
-	tools := models.DetectAITools()
+	// The installer can only install the built-in tools
+	var tools []models.AITool
+	for _, t := range models.DetectAITools() {
+		if aitools.IsBuiltin(t.Name) {
+			tools = append(tools, t)
+		}
+	}
 
 	model := tui.NewMultiSelect(tools)
 
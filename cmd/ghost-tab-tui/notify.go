@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/config"
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/notify"
+	"github.com/jackuait/ghost-tab/internal/session"
+	"github.com/jackuait/ghost-tab/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// notifyHookCommand is the command Claude's hooks run.
+const notifyHookCommand = "ghost-tab-tui notify"
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Handle a Claude hook event with sounds and notifications",
+	Long: `Reads the JSON payload of a Claude hook (Stop, SubagentStop, Notification
+or UserPromptSubmit) from stdin and decides what to do from the event, the
+project, whether Claude's tmux pane is focused and the time of day:
+
+  sound    play the sound chosen in the settings menu
+  desktop  show a desktop notification with Claude's last message
+  bell     ring the bell in Claude's tmux pane
+  title    mark the tab title with ● until the next prompt
+  sinks    send the event to the webhooks, commands and files configured
+           as [[sinks]], retrying failures with backoff. This happens in a
+           background process, so the hook returns straight away
+
+The rules come from the config file. A broken config file is reported on
+stderr and the defaults are used. Failing actions are reported on stderr
+but never fail the hook.
+
+With --dry-run, prints the actions instead of running them. With --install,
+registers this command for Claude's Stop, Notification and UserPromptSubmit
+hooks in the settings file and prints "added" or "exists". With --uninstall,
+removes those hooks again and prints "removed" or "not_found".
+
+With --test, sends a sample event to every configured sink without reading
+stdin. Webhooks post to a local HTTP stand-in instead of their URL, which
+prints the request it got, so templates can be checked without pinging
+anyone; command and file sinks run for real. Fails if the config file
+can't be loaded or a sink fails.`,
+	Args: cobra.NoArgs,
+	RunE: runNotify,
+}
+
+var (
+	notifyConfig    string
+	notifySoundFile string
+	notifyDryRun    bool
+	notifyInstall   bool
+	notifyUninstall bool
+	notifySettings  string
+	notifyTest      bool
+	notifyDeliver   bool
+)
+
+// notifyPane returns Claude's tmux pane, or nil outside tmux. Tests
+// replace it.
+var notifyPane = func() notify.Pane {
+	id := os.Getenv("TMUX_PANE")
+	if id == "" {
+		return nil
+	}
+	return notify.TmuxPane{Tmux: session.NewTmux(), ID: id}
+}
+
+func init() {
+	home, _ := os.UserHomeDir()
+	notifyCmd.Flags().StringVar(&notifyConfig, "config", notify.DefaultConfigFile(), "Path to the notification config (TOML)")
+	notifyCmd.Flags().StringVar(&notifySoundFile, "sound-file", filepath.Join(util.ConfigDir(), "claude-features.json"), "Path to the sound features JSON file")
+	notifyCmd.Flags().BoolVar(&notifyDryRun, "dry-run", false, "Print the actions instead of running them")
+	notifyCmd.Flags().BoolVar(&notifyInstall, "install", false, "Register the notify hooks in Claude's settings")
+	notifyCmd.Flags().BoolVar(&notifyUninstall, "uninstall", false, "Remove the notify hooks from Claude's settings")
+	notifyCmd.Flags().BoolVar(&notifyTest, "test", false, "Send a sample event to the sinks, webhooks to a local stand-in")
+	notifyCmd.Flags().BoolVar(&notifyDeliver, "deliver", false, "Deliver the event JSON on stdin to the sinks (run by the hook)")
+	notifyCmd.Flags().MarkHidden("deliver")
+	notifyCmd.Flags().StringVar(&notifySettings, "settings", filepath.Join(home, ".claude", "settings.json"), "Claude settings file for --install and --uninstall")
+	rootCmd.AddCommand(notifyCmd)
+}
+
+func runNotify(cmd *cobra.Command, args []string) error {
+	if notifyInstall {
+		result, err := config.AddNotifyHooks(notifySettings, notifyHookCommand)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	}
+	if notifyUninstall {
+		result, err := config.RemoveNotifyHooks(notifySettings, notifyHookCommand)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result)
+		return nil
+	}
+
+	cfg, err := notify.LoadConfig(notifyConfig)
+	if notifyTest {
+		if err != nil {
+			return err
+		}
+		return testSinks(os.Stdout, cfg.Sinks)
+	}
+	if notifyDeliver {
+		if err != nil {
+			return err
+		}
+		return deliver(os.Stdin, cfg.Sinks)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ghost-tab-tui: ignoring notification config: %v\n", err)
+	}
+	in, err := notify.ParseHookInput(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("invalid hook JSON: %w", err)
+	}
+
+	pane := notifyPane()
+	project := in.Project(os.Getenv("GHOST_TAB_PROJECT"))
+	actions := cfg.Decide(in.Event, notify.Context{
+		Project: project,
+		Focused: pane != nil && pane.Focused(),
+		Now:     time.Now(),
+	})
+	if notifyDryRun {
+		for _, a := range actions {
+			fmt.Println(a)
+		}
+		return nil
+	}
+
+	h := hookActions{
+		backend: soundBackend(),
+		pane:    pane,
+		in:      in,
+		project: project,
+		title:   os.Getenv("GHOST_TAB_TITLE"),
+		sinks:   cfg.Sinks,
+	}
+	for _, a := range actions {
+		if err := h.run(a); err != nil {
+			fmt.Fprintf(os.Stderr, "ghost-tab-tui: notify: %s: %v\n", a, err)
+		}
+	}
+	return nil
+}
+
+// hookActions runs the actions chosen for a hook event.
+type hookActions struct {
+	backend notify.Backend
+	pane    notify.Pane // nil outside tmux
+	in      notify.HookInput
+	project string
+	title   string // the tab title, exported by launch as GHOST_TAB_TITLE
+	sinks   []notify.Sink
+}
+
+func (h hookActions) run(action string) error {
+	switch action {
+	case notify.ActionSound:
+		name := notify.SoundSetting(notifySoundFile)
+		if name == "" {
+			return nil
+		}
+		sounds := h.backend.Sounds()
+		s, ok := notify.FindSound(sounds, name)
+		if !ok {
+			s, ok = notify.DefaultSound(sounds)
+		}
+		if !ok {
+			return fmt.Errorf("no sounds found (sound backend: %s)", h.backend.Name())
+		}
+		return h.backend.Play(s)
+	case notify.ActionDesktop:
+		return h.backend.Notify(h.notificationTitle(), h.notificationBody())
+	case notify.ActionBell:
+		if h.pane == nil {
+			return nil
+		}
+		return h.pane.Bell()
+	case notify.ActionTitle:
+		if h.pane == nil || h.title == "" {
+			return nil
+		}
+		return h.pane.SetTitle(notify.TitleMark + h.title)
+	case notify.ActionResetTitle:
+		if h.pane == nil || h.title == "" {
+			return nil
+		}
+		return h.pane.SetTitle(h.title)
+	case notify.ActionSinks:
+		if !notify.SinksEnabled(notifySoundFile) {
+			return nil
+		}
+		return h.sendToSinks()
+	}
+	return fmt.Errorf("unknown action")
+}
+
+// sendToSinks hands the event to the sinks that take it. Delivery runs in
+// a detached process so that retries against a slow or unreachable sink
+// never hold up Claude, which waits for its hooks.
+func (h hookActions) sendToSinks() error {
+	e := notify.Event{
+		Event:     h.in.Event,
+		Project:   h.project,
+		Title:     h.notificationTitle(),
+		Message:   h.notificationBody(),
+		Cwd:       h.in.Cwd,
+		SessionID: h.in.SessionID,
+		Time:      time.Now(),
+	}
+	for _, s := range h.sinks {
+		if s.Wants(e.Event) {
+			return deliverDetached(e)
+		}
+	}
+	return nil
+}
+
+// deliverDetached starts "ghost-tab-tui notify --deliver" in a session of
+// its own with the event on stdin, and returns without waiting for it.
+// Tests replace it.
+var deliverDetached = func(e notify.Event) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	// The event fits in the pipe's buffer, so it is written before the
+	// child starts and nothing has to stay behind to feed it.
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	w.Close()
+
+	cmd := exec.Command(exe, "notify", "--deliver", "--config", notifyConfig)
+	cmd.Stdin = r
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// deliver sends the event on stdin to every sink that takes it, all at
+// once, retrying failures. It backs --deliver.
+func deliver(in io.Reader, sinks []notify.Sink) error {
+	var e notify.Event
+	if err := json.NewDecoder(in).Decode(&e); err != nil {
+		return fmt.Errorf("invalid event JSON: %w", err)
+	}
+	var wanted []notify.Sink
+	for _, s := range sinks {
+		if s.Wants(e.Event) {
+			wanted = append(wanted, s)
+		}
+	}
+	errs := make([]error, len(wanted))
+	var wg sync.WaitGroup
+	for i, s := range wanted {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := notify.Deliver(context.Background(), s, e, sinkSleep); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.Name(), err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// sinkSleep waits between sink retries. Tests replace it.
+var sinkSleep = time.Sleep
+
+// testSinks sends a sample event to each sink and reports how it went.
+// Webhooks post to a local stand-in, which prints what it received.
+func testSinks(out io.Writer, sinks []notify.Sink) error {
+	if len(sinks) == 0 {
+		return fmt.Errorf("no sinks configured in %s", notifyConfig)
+	}
+	standIn, err := startStandIn()
+	if err != nil {
+		return err
+	}
+	defer standIn.Close()
+
+	e := notify.SampleEvent(time.Now())
+	failed := 0
+	for _, s := range sinks {
+		var err error
+		webhook, isWebhook := s.(*notify.WebhookSink)
+		if isWebhook {
+			ctx, cancel := context.WithTimeout(context.Background(), s.Retry().Timeout)
+			err = webhook.SendTo(ctx, standIn.URL, e)
+			cancel()
+		} else {
+			err = notify.Deliver(context.Background(), s, e, sinkSleep)
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "✗ %s: %v\n", s.Name(), err)
+			continue
+		}
+		fmt.Fprintf(out, "✓ %s\n", s.Name())
+		if isWebhook {
+			fmt.Fprint(out, standIn.Last())
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sinks failed", failed, len(sinks))
+	}
+	return nil
+}
+
+// standIn is a local HTTP server that accepts webhook posts and keeps the
+// last one to show.
+type standIn struct {
+	URL    string
+	server *http.Server
+	mu     sync.Mutex
+	last   string
+}
+
+func startStandIn() (*standIn, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("starting the webhook stand-in: %w", err)
+	}
+	s := &standIn{URL: "http://" + ln.Addr().String() + "/"}
+	s.server = &http.Server{Handler: http.HandlerFunc(s.record)}
+	go s.server.Serve(ln)
+	return s, nil
+}
+
+// record keeps the request as it will be shown: request line, the headers
+// a sink may set, then the body.
+func (s *standIn) record(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %s %s\n", r.Method, r.URL.Path)
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		switch name {
+		case "Accept-Encoding", "Content-Length", "User-Agent":
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s: %s\n", name, r.Header.Get(name))
+	}
+	fmt.Fprintf(&b, "  %s\n", body)
+	s.mu.Lock()
+	s.last = b.String()
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+// Last returns the last request received, formatted for the terminal.
+func (s *standIn) Last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Close stops the server.
+func (s *standIn) Close() error {
+	return s.server.Close()
+}
+
+// notificationTitle names the tool and project, e.g. "Claude Code · my-app".
+func (h hookActions) notificationTitle() string {
+	title := models.DisplayName("claude")
+	if h.project != "" {
+		title += " · " + h.project
+	}
+	return title
+}
+
+// notificationBody says why Claude wants attention: the Notification's
+// message, or Claude's last message when it finished.
+func (h hookActions) notificationBody() string {
+	switch h.in.Event {
+	case notify.EventNotification:
+		if h.in.Message != "" {
+			return h.in.Message
+		}
+		return "Claude needs your attention"
+	case notify.EventSubagentStop:
+		return "A subagent finished"
+	}
+	if msg := notify.LastAssistantMessage(h.in.TranscriptPath); msg != "" {
+		return msg
+	}
+	return "Claude finished"
+}
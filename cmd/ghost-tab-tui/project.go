@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage the projects file",
+	Long:  "Non-interactive operations on the structured projects file, for use from shell scripts.",
+}
+
+var projectAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a project to the projects file",
+	Long:  "Appends a project to the projects file, migrating a legacy name:path file first. Returns result as JSON.",
+	RunE:  runProjectAdd,
+}
+
+var (
+	projectProjectsFile string
+	projectName         string
+	projectPath         string
+)
+
+func init() {
+	projectCmd.PersistentFlags().StringVar(&projectProjectsFile, "projects-file", "", "Path to projects file")
+	projectCmd.MarkPersistentFlagRequired("projects-file")
+	projectAddCmd.Flags().StringVar(&projectName, "name", "", "Project name")
+	projectAddCmd.MarkFlagRequired("name")
+	projectAddCmd.Flags().StringVar(&projectPath, "path", "", "Project path")
+	projectAddCmd.MarkFlagRequired("path")
+	projectCmd.AddCommand(projectAddCmd)
+	rootCmd.AddCommand(projectCmd)
+}
+
+func runProjectAdd(cmd *cobra.Command, args []string) error {
+	if _, err := models.MigrateProjectsFile(projectProjectsFile); err != nil {
+		return fmt.Errorf("failed to migrate projects file: %w", err)
+	}
+	if err := tui.AppendProject(models.Project{Name: projectName, Path: projectPath}, projectProjectsFile); err != nil {
+		return fmt.Errorf("failed to add project: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"added": true,
+		"name":  projectName,
+		"path":  projectPath,
+	}
+
+	jsonOutput, _ := json.Marshal(result)
+	fmt.Println(string(jsonOutput))
+
+	return nil
+}
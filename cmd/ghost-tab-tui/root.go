@@ -1,15 +1,48 @@
 package main
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
 
-var aiToolFlag string
+	"github.com/jackuait/ghost-tab/internal/aitools"
+	"github.com/jackuait/ghost-tab/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	aiToolFlag  string
+	aiToolsFile string
+	aiToolsErr  error // why aiToolsFile could not be loaded, if it couldn't
+)
 
 var rootCmd = &cobra.Command{
-	Use:   "ghost-tab-tui",
-	Short: "Interactive TUI components for Ghost Tab",
-	Long:  "Provides terminal UI components for Ghost Tab project selector, AI tool picker, and settings menu.",
+	Use:              "ghost-tab-tui",
+	Short:            "Interactive TUI components for Ghost Tab",
+	Long:             "Provides terminal UI components for Ghost Tab project selector, AI tool picker, and settings menu.",
+	PersistentPreRun: loadAITools,
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&aiToolFlag, "ai-tool", "claude", "AI tool for theming")
+	rootCmd.PersistentFlags().StringVar(&aiToolsFile, "ai-tools-file", defaultAIToolsFile(), "Path to user-defined AI tools (TOML)")
+}
+
+// defaultAIToolsFile returns the path of the user's tool definitions,
+// ${XDG_CONFIG_HOME:-~/.config}/ghost-tab/ai-tools.toml.
+func defaultAIToolsFile() string {
+	return filepath.Join(util.ConfigDir(), aitools.FileName)
+}
+
+// loadAITools registers the user's AI tools. A broken file must not lock
+// the user out of the menu, so it is reported and the built-in tools are
+// used instead.
+func loadAITools(cmd *cobra.Command, args []string) {
+	r, err := aitools.Load(aiToolsFile)
+	aiToolsErr = err
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ghost-tab-tui: ignoring AI tools file: %v\n", err)
+		return
+	}
+	aitools.Use(r)
 }
@@ -29,6 +29,11 @@ func init() {
 func runSelectProject(cmd *cobra.Command, args []string) error {
 	tui.ApplyTheme(tui.ThemeForTool(aiToolFlag))
 
+	// One-time upgrade of the legacy name:path file to the structured format.
+	if _, err := models.MigrateProjectsFile(projectsFile); err != nil {
+		return fmt.Errorf("failed to migrate projects file: %w", err)
+	}
+
 	projects, err := models.LoadProjects(projectsFile)
 	if err != nil {
 		return fmt.Errorf("failed to load projects: %w", err)
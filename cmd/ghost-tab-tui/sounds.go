@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jackuait/ghost-tab/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+var soundsCmd = &cobra.Command{
+	Use:   "sounds",
+	Short: "List, play and build hooks for notification sounds",
+	Long: `Lists the notification sounds found in the platform's sound directories
+that the installed player can play: /System/Library/Sounds and
+~/Library/Sounds with afplay on macOS, the freedesktop sound themes under
+$XDG_DATA_HOME/sounds and $XDG_DATA_DIRS/sounds with paplay, pw-play or
+aplay on Linux.
+
+With --play, plays the named sound. With --hook-command, prints the shell
+command Claude's Stop hook runs to play the named sound; a sound that is
+not installed is replaced by the platform's default.`,
+	Args: cobra.NoArgs,
+	RunE: runSounds,
+}
+
+var (
+	soundsJSON        bool
+	soundsPlay        string
+	soundsHookCommand string
+)
+
+// soundBackend returns the backend the sounds command uses. Tests replace it.
+var soundBackend = func() notify.Backend { return notify.Detect() }
+
+func init() {
+	soundsCmd.Flags().BoolVar(&soundsJSON, "json", false, "Output as JSON")
+	soundsCmd.Flags().StringVar(&soundsPlay, "play", "", "Play the named sound")
+	soundsCmd.Flags().StringVar(&soundsHookCommand, "hook-command", "", "Print the hook command that plays the named sound")
+	rootCmd.AddCommand(soundsCmd)
+}
+
+func runSounds(cmd *cobra.Command, args []string) error {
+	backend := soundBackend()
+	sounds := backend.Sounds()
+	if len(sounds) == 0 {
+		return fmt.Errorf("no notification sounds found (sound backend: %s)", backend.Name())
+	}
+	switch {
+	case soundsPlay != "":
+		s, ok := notify.FindSound(sounds, soundsPlay)
+		if !ok {
+			return fmt.Errorf("unknown sound %q", soundsPlay)
+		}
+		return backend.Play(s)
+	case cmd.Flags().Changed("hook-command"):
+		s, ok := notify.FindSound(sounds, soundsHookCommand)
+		if !ok {
+			s, _ = notify.DefaultSound(sounds)
+		}
+		fmt.Println(backend.SoundCommand(s))
+		return nil
+	case soundsJSON:
+		jsonOutput, _ := json.Marshal(sounds)
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+	writeSoundsTable(os.Stdout, sounds)
+	return nil
+}
+
+// writeSoundsTable writes one aligned row per sound.
+func writeSoundsTable(out io.Writer, sounds []notify.Sound) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPATH")
+	for _, s := range sounds {
+		fmt.Fprintf(w, "%s\t%s\n", s.Name, s.Path)
+	}
+	w.Flush()
+}
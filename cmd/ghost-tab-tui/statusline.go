@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jackuait/ghost-tab/internal/process"
+	"github.com/jackuait/ghost-tab/internal/statusline"
+	"github.com/spf13/cobra"
+)
+
+var statuslineCmd = &cobra.Command{
+	Use:   "statusline",
+	Short: "Print the Claude Code status line",
+	Long: `Reads the status JSON Claude Code writes to stdin and prints the status
+line: repository, branch, lines changed since the session baseline in
+GHOST_TAB_BASELINE_FILE, context window usage from the transcript and the
+memory of Claude's process tree.
+
+The segments, their order and colors come from the config file; see
+"statusline --preview". A broken config file is reported on stderr and the
+default line is printed.
+
+With --preview, renders the line for sample data instead of reading stdin,
+and fails if the config file can't be loaded.
+
+With --breakdown, lists what makes up the memory segment instead: each
+process Claude started (MCP servers, language servers, shells) with
+everything under it, heaviest first. Run it from a shell inside Claude,
+or pass Claude's PID with --pid.`,
+	Args: cobra.NoArgs,
+	RunE: runStatusline,
+}
+
+var (
+	statuslinePreview   bool
+	statuslineConfig    string
+	statuslineBreakdown bool
+	statuslinePID       int
+	statuslineTop       int
+)
+
+func init() {
+	statuslineCmd.Flags().BoolVar(&statuslinePreview, "preview", false, "Render the status line for sample data")
+	statuslineCmd.Flags().StringVar(&statuslineConfig, "config", statusline.DefaultConfigFile(), "Path to the status line config (TOML)")
+	statuslineCmd.Flags().BoolVar(&statuslineBreakdown, "breakdown", false, "List the heaviest processes under Claude")
+	statuslineCmd.Flags().IntVar(&statuslinePID, "pid", 0, "Claude's PID for --breakdown (default: the Claude this runs under)")
+	statuslineCmd.Flags().IntVar(&statuslineTop, "top", 10, "Number of processes --breakdown lists, 0 for all")
+	rootCmd.AddCommand(statuslineCmd)
+}
+
+func runStatusline(cmd *cobra.Command, args []string) error {
+	if statuslineBreakdown {
+		return runMemoryBreakdown()
+	}
+	cfg, err := statusline.LoadConfig(statuslineConfig)
+	if statuslinePreview {
+		if err != nil {
+			return err
+		}
+		fmt.Println(statusline.Preview(cfg))
+		return nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ghost-tab-tui: ignoring status line config: %v\n", err)
+	}
+
+	in, err := statusline.ParseInput(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("invalid status JSON: %w", err)
+	}
+	line := statusline.Collect(in, statusline.Options{
+		BaselineFile: os.Getenv("GHOST_TAB_BASELINE_FILE"),
+		PID:          os.Getppid(),
+		Segments:     cfg.Segments,
+	})
+	fmt.Print(line.Render(cfg))
+	return nil
+}
+
+// runMemoryBreakdown prints the memory of the Claude process tree by child.
+func runMemoryBreakdown() error {
+	procs, err := process.ReadSnapshot()
+	if err != nil {
+		return fmt.Errorf("failed to read processes: %w", err)
+	}
+	root := statuslinePID
+	if root == 0 {
+		root = procs.Ancestor(os.Getppid(), "claude")
+		if root == 0 {
+			return fmt.Errorf("not running under Claude; pass its PID with --pid")
+		}
+	}
+	if _, ok := procs[root]; !ok {
+		return fmt.Errorf("no process with PID %d", root)
+	}
+	writeMemoryBreakdown(os.Stdout, procs, root, statuslineTop, process.CommandLine)
+	return nil
+}
+
+// writeMemoryBreakdown writes the tree total of root and one aligned row
+// per child tree, naming processes with commandLine.
+func writeMemoryBreakdown(out io.Writer, procs process.Snapshot, root, top int, commandLine func(int) string) {
+	name := func(pid int) string {
+		if c := commandLine(pid); c != "" {
+			return truncate(c, breakdownCommandWidth)
+		}
+		return procs[pid].Comm
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PID\tMEMORY\tPROCS\tCOMMAND")
+	trees := procs.Breakdown(root, 0)
+	count := 1
+	for _, t := range trees {
+		count += t.Processes
+	}
+	fmt.Fprintf(w, "%d\t%s\t%d\t%s (total)\n", root, statusline.FormatMemoryKB(procs.TreeRSSKB(root)), count, procs[root].Comm)
+	if top > 0 && len(trees) > top {
+		trees = trees[:top]
+	}
+	for _, t := range trees {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", t.PID, statusline.FormatMemoryKB(t.RSSKB), t.Processes, name(t.PID))
+	}
+	w.Flush()
+}
+
+// breakdownCommandWidth is where --breakdown cuts off long command lines.
+const breakdownCommandWidth = 80
+
+// truncate shortens s to width runes, marking the cut with "…".
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jackuait/ghost-tab/internal/aitools"
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "List the registered AI tools",
+	Long: `Lists the built-in AI tools and those defined in the AI tools file with
+their display name, command, version and health. Each installed tool's
+version command is run with a timeout: a tool whose command fails or hangs
+is broken, one older than its min_version is outdated. Fails if the AI
+tools file can't be loaded, so it doubles as a check of that file.
+
+With --json, prints the same report as a JSON array for scripting.
+
+With --installed, prints only the names of installed tools, one per line,
+without running them. This never fails: a broken file falls back to the
+built-in tools.`,
+	RunE: runTools,
+}
+
+var (
+	toolsInstalled bool
+	toolsJSON      bool
+)
+
+func init() {
+	toolsCmd.Flags().BoolVar(&toolsInstalled, "installed", false, "Print only the names of installed tools")
+	toolsCmd.Flags().BoolVar(&toolsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(toolsCmd)
+}
+
+func runTools(cmd *cobra.Command, args []string) error {
+	if toolsInstalled {
+		for _, name := range aitools.Current().Installed() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	if aiToolsErr != nil {
+		return aiToolsErr
+	}
+	tools := models.DetectAITools()
+	if toolsJSON {
+		jsonOutput, _ := json.Marshal(tools)
+		fmt.Println(string(jsonOutput))
+		return nil
+	}
+	writeToolsTable(os.Stdout, tools)
+	return nil
+}
+
+// writeToolsTable writes one aligned row per tool.
+func writeToolsTable(out io.Writer, tools []models.AITool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDISPLAY NAME\tCOMMAND\tVERSION\tSTATUS")
+	for _, t := range tools {
+		version := t.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Name, t.DisplayName, t.Command, version, toolStatus(t))
+	}
+	w.Flush()
+}
+
+// toolStatus describes a tool's health for the STATUS column.
+func toolStatus(t models.AITool) string {
+	switch t.Status {
+	case models.ToolMissing:
+		return "not installed"
+	case models.ToolOutdated:
+		return fmt.Sprintf("outdated (needs %s+)", t.MinVersion)
+	case models.ToolBroken:
+		return "broken: " + t.Error
+	}
+	return "installed"
+}
@@ -80,21 +80,9 @@ for _gt_lib in "${_gt_libs[@]}"; do
 done
 unset _gt_libs _gt_lib
 
-TMUX_CMD="$(command -v tmux)"
-LAZYGIT_CMD="$(command -v lazygit)"
-BROOT_CMD="$(command -v broot)"
-CLAUDE_CMD="$(command -v claude)"
-CODEX_CMD="$(command -v codex)"
-COPILOT_CMD="$(command -v copilot)"
-OPENCODE_CMD="$(command -v opencode)"
-
 # AI tool preference
 AI_TOOL_PREF_FILE="${XDG_CONFIG_HOME:-$HOME/.config}/ghost-tab/ai-tool"
-AI_TOOLS_AVAILABLE=()
-[ -n "$CLAUDE_CMD" ] && AI_TOOLS_AVAILABLE+=("claude")
-[ -n "$CODEX_CMD" ] && AI_TOOLS_AVAILABLE+=("codex")
-[ -n "$COPILOT_CMD" ] && AI_TOOLS_AVAILABLE+=("copilot")
-[ -n "$OPENCODE_CMD" ] && AI_TOOLS_AVAILABLE+=("opencode")
+detect_ai_tools
 
 # Read saved preference, default to first available
 SELECTED_AI_TOOL=""
@@ -107,6 +95,9 @@ validate_ai_tool "$AI_TOOL_PREF_FILE"
 # Load user projects from config file if it exists
 PROJECTS_FILE="${XDG_CONFIG_HOME:-$HOME/.config}/ghost-tab/projects"
 
+# Launch history, used for recent/frequent project ordering
+HISTORY_FILE="${XDG_CONFIG_HOME:-$HOME/.config}/ghost-tab/history.jsonl"
+
 # Version update check (Homebrew only)
 # shellcheck disable=SC2034  # Used in sourced update.sh module
 UPDATE_CACHE="${XDG_CONFIG_HOME:-$HOME/.config}/ghost-tab/.update-check"
@@ -137,6 +128,13 @@ elif [ -z "$1" ]; then
           PROJECT_NAME="$_selected_project_name"
           # shellcheck disable=SC2154
           cd "$_selected_project_path" || exit 1
+          # Reattach to a running session instead of creating a new one
+          if [ -n "${_selected_project_session:-}" ]; then
+            set_tab_title "$PROJECT_NAME" "$SELECTED_AI_TOOL"
+            exec ghost-tab-tui launch --project "$(pwd)" --name "$PROJECT_NAME" \
+              --ai-tool "$SELECTED_AI_TOOL" --history-file "$HISTORY_FILE" \
+              --attach "$_selected_project_session"
+          fi
           break
           ;;
         plain-terminal)
@@ -176,51 +174,52 @@ if [ -f "$_settings_file" ]; then
 fi
 if [ "$_tab_title_setting" = "full" ]; then
   set_tab_title "$PROJECT_NAME" "$SELECTED_AI_TOOL"
+  _tab_title="$PROJECT_NAME · $SELECTED_AI_TOOL"
 else
   set_tab_title "$PROJECT_NAME"
+  _tab_title="$PROJECT_NAME"
 fi
 
-# Background watcher: switch to Claude pane once it's ready
-(
-  while true; do
-    sleep 0.5
-    content=$("$TMUX_CMD" capture-pane -t "$SESSION_NAME:0.1" -p 2>/dev/null)
-    # All three tools show a prompt character when ready
-    if echo "$content" | grep -qE '[>$❯]'; then
-      "$TMUX_CMD" select-pane -t "$SESSION_NAME:0.1"
-      break
-    fi
-  done
-) &
-WATCHER_PID=$!
-
-cleanup() {
-  cleanup_tmux_session "$SESSION_NAME" "$WATCHER_PID" "$TMUX_CMD"
-  rm -f "$GHOST_TAB_BASELINE_FILE"
-}
-trap cleanup EXIT HUP TERM INT
-
-# Build the AI tool launch command
-case "$SELECTED_AI_TOOL" in
-  codex|opencode)
-    AI_LAUNCH_CMD="$(build_ai_launch_cmd "$SELECTED_AI_TOOL" "$CLAUDE_CMD" "$CODEX_CMD" "$COPILOT_CMD" "$OPENCODE_CMD" "$PROJECT_DIR")"
-    ;;
-  *)
-    AI_LAUNCH_CMD="$(build_ai_launch_cmd "$SELECTED_AI_TOOL" "$CLAUDE_CMD" "$CODEX_CMD" "$COPILOT_CMD" "$OPENCODE_CMD" "$*")"
-    ;;
-esac
-
-"$TMUX_CMD" new-session -s "$SESSION_NAME" -e "PATH=$PATH" -e "GHOST_TAB_BASELINE_FILE=$GHOST_TAB_BASELINE_FILE" -c "$PROJECT_DIR" \
-  "$LAZYGIT_CMD; exec bash" \; \
-  set-option status-left " ⬡ ${PROJECT_NAME} " \; \
-  set-option status-left-style "fg=white,bg=colour236,bold" \; \
-  set-option status-style "bg=colour235" \; \
-  set-option status-right "" \; \
-  set-option exit-unattached on \; \
-  split-window -h -p 50 -c "$PROJECT_DIR" \
-  "$AI_LAUNCH_CMD; exec bash" \; \
-  select-pane -t 0 \; \
-  split-window -v -p 50 -c "$PROJECT_DIR" \
-  "trap exit TERM; while true; do $BROOT_CMD $PROJECT_DIR; done" \; \
-  split-window -v -p 30 -c "$PROJECT_DIR" \; \
-  select-pane -t 3
+# Persist mode keeps the session (and its baseline) alive after the window
+# closes so it can be reattached from the main menu.
+_persist_session="off"
+if [ -f "$_settings_file" ]; then
+  _saved_persist=$(grep '^persist_session=' "$_settings_file" 2>/dev/null | cut -d= -f2)
+  if [ -n "$_saved_persist" ]; then
+    _persist_session="$_saved_persist"
+  fi
+fi
+
+_launch_args=()
+if [ -n "${_selected_ai_profile:-}" ]; then
+  _launch_args+=("--profile" "$_selected_ai_profile")
+fi
+for _agent in ${_selected_agents[@]+"${_selected_agents[@]}"}; do
+  _launch_args+=("--agent" "$_agent")
+done
+if [ "$_persist_session" = "on" ]; then
+  _launch_args+=("--persist")
+else
+  cleanup() {
+    # TERM every pane's process tree, KILL survivors after the grace period
+    ghost-tab-tui cleanup --session "$SESSION_NAME" >/dev/null 2>&1
+    rm -f "$GHOST_TAB_BASELINE_FILE"
+  }
+  trap cleanup EXIT HUP TERM INT
+fi
+
+# Build and attach the tmux session using the project's layout (by default
+# lazygit, AI tool, broot and a spare shell). ghost-tab-tui also focuses the
+# AI pane once the tool shows its prompt.
+ghost-tab-tui launch \
+  --project "$PROJECT_DIR" \
+  --name "$PROJECT_NAME" \
+  --session "$SESSION_NAME" \
+  --ai-tool "$SELECTED_AI_TOOL" \
+  --baseline-file "$GHOST_TAB_BASELINE_FILE" \
+  --layouts-file "${XDG_CONFIG_HOME:-$HOME/.config}/ghost-tab/layouts.json" \
+  --projects-file "$PROJECTS_FILE" \
+  --history-file "$HISTORY_FILE" \
+  --tab-title "$_tab_title" \
+  "${_launch_args[@]}" \
+  -- "$@"
@@ -0,0 +1,377 @@
+// Package aitools is the registry of AI coding tools Ghost Tab can launch:
+// the built-in tools plus any defined by the user in ai-tools.toml.
+package aitools
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// How a tool is told which project to open.
+const (
+	ProjectDirCwd        = "cwd"        // started in the project directory only
+	ProjectDirFlag       = "flag"       // passed after ProjectDirFlag, e.g. --cd
+	ProjectDirPositional = "positional" // passed as the first argument
+)
+
+// DefaultReadyPrompt matches the prompt characters the built-in tools show
+// once they are ready for input.
+const DefaultReadyPrompt = `[>$❯]`
+
+// DefaultTool is used when no tool has been chosen.
+const DefaultTool = "claude"
+
+// DefaultVersionArgs are passed to a tool's command to print its version.
+var DefaultVersionArgs = []string{"--version"}
+
+// Theme is a tool's palette as ANSI 256-color numbers.
+type Theme struct {
+	Primary       string
+	Dim           string
+	Bright        string
+	Accent        string
+	Cap           string
+	DarkFeet      string
+	EyeWhite      string
+	EyePupil      string
+	SleepPrimary  string
+	SleepAccent   string
+	SleepDim      string
+	SleepDarkFeet string
+	SleepCap      string
+	Text          string
+}
+
+// fields maps the ai-tools.toml key of each color to its field.
+func (t *Theme) fields() map[string]*string {
+	return map[string]*string{
+		"primary":         &t.Primary,
+		"dim":             &t.Dim,
+		"bright":          &t.Bright,
+		"accent":          &t.Accent,
+		"cap":             &t.Cap,
+		"dark_feet":       &t.DarkFeet,
+		"eye_white":       &t.EyeWhite,
+		"eye_pupil":       &t.EyePupil,
+		"sleep_primary":   &t.SleepPrimary,
+		"sleep_accent":    &t.SleepAccent,
+		"sleep_dim":       &t.SleepDim,
+		"sleep_dark_feet": &t.SleepDarkFeet,
+		"sleep_cap":       &t.SleepCap,
+		"text":            &t.Text,
+	}
+}
+
+// NoProfile names launching a tool with no profile arguments. It may be
+// given wherever a profile name is expected, e.g. to override a project's
+// default profile.
+const NoProfile = "default"
+
+// Profile is a named set of arguments a tool can be launched with, such as
+// claude's "yolo".
+type Profile struct {
+	Name string
+	Args []string
+}
+
+// Tool describes how to detect, launch and present an AI tool.
+type Tool struct {
+	Name        string // identifier stored in preferences, e.g. "claude"
+	DisplayName string
+	Command     string // binary looked up on PATH and launched
+	// ProjectDir is one of ProjectDirCwd, ProjectDirFlag or
+	// ProjectDirPositional. ProjectDirFlag names the flag for the latter.
+	ProjectDir     string
+	ProjectDirFlag string
+	// PassArgs appends the wrapper's extra arguments to the command.
+	PassArgs bool
+	// ReadyPrompt is a regexp matched against the tool's pane to tell when
+	// it is ready for input.
+	ReadyPrompt string
+	// Ghost names the built-in tool whose ghost art is drawn.
+	Ghost string
+	Theme Theme
+	// Profiles are the tool's launch profiles, in cycling order.
+	Profiles []Profile
+	// VersionArgs print the tool's version; nil means DefaultVersionArgs.
+	VersionArgs []string
+	// MinVersion is the oldest supported version, "" for any.
+	MinVersion string
+}
+
+// Profile returns the tool's profile called name. NoProfile and "" yield
+// an empty profile.
+func (t Tool) Profile(name string) (Profile, bool) {
+	if name == "" || name == NoProfile {
+		return Profile{Name: NoProfile}, true
+	}
+	for _, p := range t.Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// ProfileNames returns NoProfile followed by the tool's profile names.
+func (t Tool) ProfileNames() []string {
+	names := []string{NoProfile}
+	for _, p := range t.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// setProfile adds p, replacing a profile of the same name in place. The
+// slice is copied first since tools share it with the built-ins.
+func (t *Tool) setProfile(p Profile) {
+	t.Profiles = append([]Profile(nil), t.Profiles...)
+	for i := range t.Profiles {
+		if t.Profiles[i].Name == p.Name {
+			t.Profiles[i] = p
+			return
+		}
+	}
+	t.Profiles = append(t.Profiles, p)
+}
+
+// VersionCommand returns the arguments that make the tool print its version.
+func (t Tool) VersionCommand() []string {
+	if t.VersionArgs == nil {
+		return DefaultVersionArgs
+	}
+	return t.VersionArgs
+}
+
+// Installed reports whether the tool's command is on PATH.
+func (t Tool) Installed() bool {
+	_, err := exec.LookPath(t.Command)
+	return err == nil
+}
+
+// ReadyPromptRegexp returns the compiled ReadyPrompt, or the default
+// prompt if it is empty or invalid.
+func (t Tool) ReadyPromptRegexp() *regexp.Regexp {
+	if re, err := regexp.Compile(t.ReadyPrompt); err == nil && t.ReadyPrompt != "" {
+		return re
+	}
+	return regexp.MustCompile(DefaultReadyPrompt)
+}
+
+// Validate checks that the tool can be launched and drawn.
+func (t Tool) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("tool has no name")
+	}
+	if t.Command == "" {
+		return fmt.Errorf("tool %q has no command", t.Name)
+	}
+	switch t.ProjectDir {
+	case ProjectDirCwd, ProjectDirPositional:
+	case ProjectDirFlag:
+		if t.ProjectDirFlag == "" {
+			return fmt.Errorf("tool %q passes the project dir as a flag but has no project_dir_flag", t.Name)
+		}
+	default:
+		return fmt.Errorf("tool %q: project_dir must be cwd, flag or positional, got %q", t.Name, t.ProjectDir)
+	}
+	if _, err := regexp.Compile(t.ReadyPrompt); err != nil {
+		return fmt.Errorf("tool %q: invalid ready_prompt: %w", t.Name, err)
+	}
+	if !IsBuiltin(t.Ghost) {
+		return fmt.Errorf("tool %q: ghost must name a built-in tool, got %q", t.Name, t.Ghost)
+	}
+	if t.MinVersion != "" && ParseVersion(t.MinVersion) != t.MinVersion {
+		return fmt.Errorf("tool %q: min_version must be a version number like 1.2.0, got %q", t.Name, t.MinVersion)
+	}
+	for _, p := range t.Profiles {
+		if p.Name == "" || p.Name == NoProfile {
+			return fmt.Errorf("tool %q: profiles need a name other than %q", t.Name, NoProfile)
+		}
+	}
+	for key, color := range t.Theme.fields() {
+		if n, err := strconv.Atoi(*color); err != nil || n < 0 || n > 255 {
+			return fmt.Errorf("tool %q: theme %s must be a 256-color number, got %q", t.Name, key, *color)
+		}
+	}
+	return nil
+}
+
+var builtins = []Tool{
+	{
+		Name:        "claude",
+		DisplayName: "Claude Code",
+		Command:     "claude",
+		ProjectDir:  ProjectDirCwd,
+		PassArgs:    true,
+		ReadyPrompt: DefaultReadyPrompt,
+		Ghost:       "claude",
+		Theme: Theme{
+			Primary: "209", Dim: "166", Bright: "208", Accent: "220", Cap: "223",
+			DarkFeet: "166", EyeWhite: "255", EyePupil: "232",
+			SleepPrimary: "166", SleepAccent: "178", SleepDim: "166", SleepDarkFeet: "94", SleepCap: "180",
+			Text: "223",
+		},
+		Profiles: []Profile{
+			{Name: "yolo", Args: []string{"--dangerously-skip-permissions"}},
+			{Name: "resume", Args: []string{"--continue"}},
+		},
+		MinVersion: "1.0.0",
+	},
+	{
+		Name:           "codex",
+		DisplayName:    "Codex CLI",
+		Command:        "codex",
+		ProjectDir:     ProjectDirFlag,
+		ProjectDirFlag: "--cd",
+		ReadyPrompt:    DefaultReadyPrompt,
+		Ghost:          "codex",
+		Theme: Theme{
+			Primary: "114", Dim: "71", Bright: "113", Accent: "78", Cap: "157",
+			DarkFeet: "71", EyeWhite: "255", EyePupil: "232",
+			SleepPrimary: "71", SleepAccent: "65", SleepDim: "71", SleepDarkFeet: "58", SleepCap: "114",
+			Text: "157",
+		},
+		Profiles: []Profile{
+			{Name: "full-auto", Args: []string{"--full-auto"}},
+		},
+	},
+	{
+		Name:        "copilot",
+		DisplayName: "Copilot CLI",
+		Command:     "copilot",
+		ProjectDir:  ProjectDirCwd,
+		ReadyPrompt: DefaultReadyPrompt,
+		Ghost:       "copilot",
+		Theme: Theme{
+			Primary: "141", Dim: "98", Bright: "140", Accent: "134", Cap: "183",
+			DarkFeet: "98", EyeWhite: "255", EyePupil: "232",
+			SleepPrimary: "98", SleepAccent: "96", SleepDim: "98", SleepDarkFeet: "60", SleepCap: "140",
+			Text: "183",
+		},
+	},
+	{
+		Name:        "opencode",
+		DisplayName: "OpenCode",
+		Command:     "opencode",
+		ProjectDir:  ProjectDirPositional,
+		ReadyPrompt: DefaultReadyPrompt,
+		Ghost:       "opencode",
+		Theme: Theme{
+			Primary: "250", Dim: "244", Bright: "255", Accent: "240", Cap: "252",
+			DarkFeet: "240", EyeWhite: "255", EyePupil: "238",
+			SleepPrimary: "244", SleepAccent: "234", SleepDim: "236", SleepDarkFeet: "232", SleepCap: "242",
+			Text: "252",
+		},
+	},
+}
+
+// IsBuiltin reports whether name is one of the tools Ghost Tab ships with.
+func IsBuiltin(name string) bool {
+	for _, t := range builtins {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Fallback returns the definition used for a tool that isn't registered:
+// launched by its name in the project directory with the extra arguments,
+// drawn as the default tool, without profiles or a minimum version.
+func Fallback(name string) Tool {
+	t := builtins[0]
+	t.Name = name
+	t.DisplayName = name
+	t.Command = name
+	t.Profiles = nil
+	t.MinVersion = ""
+	return t
+}
+
+// Registry is an ordered set of tools, keyed by name.
+type Registry struct {
+	tools []Tool
+}
+
+// Defaults returns a registry holding the built-in tools.
+func Defaults() *Registry {
+	return &Registry{tools: append([]Tool(nil), builtins...)}
+}
+
+// Tools returns the registered tools in order: built-ins first, then
+// user-defined tools in file order.
+func (r *Registry) Tools() []Tool {
+	return append([]Tool(nil), r.tools...)
+}
+
+// Names returns the registered tool names in order.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.tools))
+	for i, t := range r.tools {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Lookup returns the tool registered under name.
+func (r *Registry) Lookup(name string) (Tool, bool) {
+	for _, t := range r.tools {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Tool{}, false
+}
+
+// Get returns the tool registered under name, or Fallback(name).
+func (r *Registry) Get(name string) Tool {
+	if t, ok := r.Lookup(name); ok {
+		return t
+	}
+	return Fallback(name)
+}
+
+// Add registers t, replacing a tool of the same name in place.
+func (r *Registry) Add(t Tool) {
+	for i := range r.tools {
+		if r.tools[i].Name == t.Name {
+			r.tools[i] = t
+			return
+		}
+	}
+	r.tools = append(r.tools, t)
+}
+
+// Installed returns the names of the registered tools whose command is on
+// PATH, in registry order.
+func (r *Registry) Installed() []string {
+	var names []string
+	for _, t := range r.tools {
+		if t.Installed() {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+var current = Defaults()
+
+// Current returns the registry in use by this process. It holds the
+// built-in tools until Use is called.
+func Current() *Registry {
+	return current
+}
+
+// Use makes r the registry returned by Current. Call it once at startup,
+// before any lookups.
+func Use(r *Registry) {
+	current = r
+}
+
+// FileName is the user's tool definitions file in the ghost-tab config
+// directory. It can't be resolved here: util, which has the directory,
+// depends on this package.
+const FileName = "ai-tools.toml"
@@ -0,0 +1,215 @@
+package aitools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaults(t *testing.T) {
+	r := Defaults()
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"claude", "codex", "copilot", "opencode"}) {
+		t.Fatalf("Names() = %v", got)
+	}
+	for _, tool := range r.Tools() {
+		if err := tool.Validate(); err != nil {
+			t.Errorf("built-in %s: %v", tool.Name, err)
+		}
+	}
+	codex, _ := r.Lookup("codex")
+	if codex.ProjectDir != ProjectDirFlag || codex.ProjectDirFlag != "--cd" || codex.PassArgs {
+		t.Errorf("codex = %+v", codex)
+	}
+}
+
+func TestRegistry_GetFallsBack(t *testing.T) {
+	got := Defaults().Get("vim")
+	if got.Name != "vim" || got.DisplayName != "vim" || got.Command != "vim" {
+		t.Errorf("Get(vim) = %+v", got)
+	}
+	if got.ProjectDir != ProjectDirCwd || !got.PassArgs || got.Ghost != "claude" {
+		t.Errorf("fallback should launch like claude, got %+v", got)
+	}
+	if got.MinVersion != "" || len(got.Profiles) != 0 {
+		t.Errorf("fallback should not inherit claude's profiles or minimum version, got %+v", got)
+	}
+	if _, ok := Defaults().Lookup("vim"); ok {
+		t.Error("Lookup(vim) should miss")
+	}
+}
+
+func TestRegistry_Decode(t *testing.T) {
+	r := Defaults()
+	err := r.Decode([]byte(`
+[[tool]]
+name = "aider"
+display_name = "Aider"
+project_dir = "positional"
+ready_prompt = '(?m)^> $'
+ghost = "codex"
+
+[tool.theme]
+primary = 39
+text = "117"
+
+# Overrides only what it sets
+[[tool]]
+name = "claude"
+command = "claude-beta"
+
+[[tool]]
+name = "goose"
+command = "goose"
+project_dir = "flag"
+project_dir_flag = "--path"
+pass_args = false
+version_args = ["version"]
+min_version = "1.2.0"
+`))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"claude", "codex", "copilot", "opencode", "aider", "goose"}) {
+		t.Fatalf("Names() = %v", got)
+	}
+
+	aider, _ := r.Lookup("aider")
+	if aider.DisplayName != "Aider" || aider.Command != "aider" || aider.ProjectDir != ProjectDirPositional {
+		t.Errorf("aider = %+v", aider)
+	}
+	if aider.Theme.Primary != "39" || aider.Theme.Text != "117" || aider.Theme.Dim != "166" {
+		t.Errorf("aider theme should set primary and text over claude's palette, got %+v", aider.Theme)
+	}
+	if !aider.ReadyPromptRegexp().MatchString("chat\n> ") || aider.ReadyPromptRegexp().MatchString("$ ") {
+		t.Error("aider ready prompt not applied")
+	}
+
+	claude, _ := r.Lookup("claude")
+	if claude.Command != "claude-beta" || claude.DisplayName != "Claude Code" || !claude.PassArgs {
+		t.Errorf("claude override = %+v", claude)
+	}
+
+	goose, _ := r.Lookup("goose")
+	if goose.ProjectDirFlag != "--path" || goose.PassArgs {
+		t.Errorf("goose = %+v", goose)
+	}
+	if !reflect.DeepEqual(goose.VersionCommand(), []string{"version"}) || goose.MinVersion != "1.2.0" {
+		t.Errorf("goose version check = %v, min %q", goose.VersionCommand(), goose.MinVersion)
+	}
+	if !reflect.DeepEqual(aider.VersionCommand(), []string{"--version"}) {
+		t.Errorf("aider should use --version, got %v", aider.VersionCommand())
+	}
+}
+
+func TestRegistry_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no name", "[[tool]]\ncommand = \"x\"\n", "has no name"},
+		{"unknown key", "[[tool]]\nname = \"x\"\nargs = 1\n", `unknown key "tool.args"`},
+		{"bad mode", "[[tool]]\nname = \"x\"\nproject_dir = \"env\"\n", "project_dir must be cwd, flag or positional"},
+		{"flag without name", "[[tool]]\nname = \"x\"\nproject_dir = \"flag\"\n", "no project_dir_flag"},
+		{"bad regexp", "[[tool]]\nname = \"x\"\nready_prompt = \"(\"\n", "invalid ready_prompt"},
+		{"bad ghost", "[[tool]]\nname = \"x\"\nghost = \"clippy\"\n", "ghost must name a built-in tool"},
+		{"bad color", "[[tool]]\nname = \"x\"\ntheme = { primary = \"#ff0000\" }\n", "theme primary must be a 256-color number"},
+		{"wrong type", "[[tool]]\nname = \"x\"\npass_args = \"yes\"\n", `"tool.pass_args"): incompatible types`},
+		{"bad min version", "[[tool]]\nname = \"x\"\nmin_version = \"latest\"\n", "min_version must be a version number"},
+		{"bad version args", "[[tool]]\nname = \"x\"\nversion_args = \"-v\"\n", `"tool.version_args"): incompatible types`},
+		{"theme not a table", "[[tool]]\nname = \"x\"\ntheme = 39\n", "theme must be a table"},
+		{"unknown theme color", "[[tool]]\nname = \"x\"\ntheme = { glow = 39 }\n", `unknown theme color "glow"`},
+		{"stray table", "[tools]\n", `unknown key "tools"`},
+		{"syntax", "[[tool]]\nname = \"x\n", "line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Defaults().Decode([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_DecodeProfiles(t *testing.T) {
+	r := Defaults()
+	err := r.Decode([]byte(`
+[[tool]]
+name = "claude"
+
+[[tool.profile]]
+name = "yolo"
+args = ["--dangerously-skip-permissions", "--verbose"]
+
+[[tool.profile]]
+name = "opus"
+args = ["--model", "opus"]
+`))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	claude, _ := r.Lookup("claude")
+	if got := claude.ProfileNames(); !reflect.DeepEqual(got, []string{"default", "yolo", "resume", "opus"}) {
+		t.Errorf("ProfileNames() = %v", got)
+	}
+	if p, _ := claude.Profile("yolo"); !reflect.DeepEqual(p.Args, []string{"--dangerously-skip-permissions", "--verbose"}) {
+		t.Errorf("yolo should be replaced, got %v", p.Args)
+	}
+	if p, ok := claude.Profile(NoProfile); !ok || len(p.Args) != 0 {
+		t.Errorf("Profile(default) = %+v, %v", p, ok)
+	}
+	if _, ok := claude.Profile("nope"); ok {
+		t.Error("Profile(nope) should miss")
+	}
+	if builtin, _ := Defaults().Lookup("claude"); len(builtin.Profiles) != 2 || len(builtin.Profiles[0].Args) != 1 {
+		t.Errorf("built-in profiles were modified: %+v", builtin.Profiles)
+	}
+
+	err = Defaults().Decode([]byte("[[tool]]\nname = \"x\"\n[[tool.profile]]\nname = \"default\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "profiles need a name") {
+		t.Errorf("reserved profile name: error = %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	r, err := Load(filepath.Join(dir, "missing.toml"))
+	if err != nil || len(r.Tools()) != 4 {
+		t.Fatalf("missing file: %v, %d tools", err, len(r.Tools()))
+	}
+
+	path := filepath.Join(dir, "ai-tools.toml")
+	os.WriteFile(path, []byte("[[tool]]\nname = \"aider\"\n"), 0644)
+	r, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, ok := r.Lookup("aider"); !ok {
+		t.Error("expected aider to be registered")
+	}
+
+	os.WriteFile(path, []byte("[[tool]]\nname = 1\n"), 0644)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("expected error naming the file, got %v", err)
+	}
+}
+
+func TestRegistry_Installed(t *testing.T) {
+	bin := t.TempDir()
+	for _, name := range []string{"codex", "aider"} {
+		os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"), 0755)
+	}
+	t.Setenv("PATH", bin)
+
+	r := Defaults()
+	r.Add(Fallback("aider"))
+	r.Add(Fallback("goose"))
+	if got := r.Installed(); !reflect.DeepEqual(got, []string{"codex", "aider"}) {
+		t.Errorf("Installed() = %v", got)
+	}
+}
@@ -0,0 +1,151 @@
+package aitools
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Load returns the built-in tools extended by the [[tool]] entries of the
+// TOML file at path. A missing file yields the built-ins alone.
+//
+// An entry whose name matches a registered tool overrides only the keys it
+// sets, and its profiles replace those of the same name; a new tool starts
+// from Fallback(name), so it can be as short as
+//
+//	[[tool]]
+//	name = "aider"
+func Load(path string) (*Registry, error) {
+	r := Defaults()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Decode(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// toolsFile is the TOML form of an ai-tools file.
+type toolsFile struct {
+	Tool []toolFile `toml:"tool"`
+}
+
+// toolFile is a [[tool]] entry. Keys it leaves out are nil and keep the
+// tool's value.
+type toolFile struct {
+	Name           string        `toml:"name"`
+	DisplayName    *string       `toml:"display_name"`
+	Command        *string       `toml:"command"`
+	ProjectDir     *string       `toml:"project_dir"`
+	ProjectDirFlag *string       `toml:"project_dir_flag"`
+	PassArgs       *bool         `toml:"pass_args"`
+	ReadyPrompt    *string       `toml:"ready_prompt"`
+	Ghost          *string       `toml:"ghost"`
+	VersionArgs    *[]string     `toml:"version_args"`
+	MinVersion     *string       `toml:"min_version"`
+	Profiles       []profileFile `toml:"profile"`
+	Theme          themeColors   `toml:"theme"`
+}
+
+// profileFile is a [[tool.profile]] entry.
+type profileFile struct {
+	Name string   `toml:"name"`
+	Args []string `toml:"args"`
+}
+
+// Decode adds the [[tool]] entries of a TOML document to r.
+func (r *Registry) Decode(data []byte) error {
+	var f toolsFile
+	md, err := toml.Decode(string(data), &f)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		if len(undecoded[0]) == 1 {
+			return fmt.Errorf("unknown key %q, expected [[tool]] entries", undecoded[0].String())
+		}
+		return fmt.Errorf("unknown key %q", undecoded[0].String())
+	}
+
+	for _, entry := range f.Tool {
+		if entry.Name == "" {
+			return fmt.Errorf("[[tool]] entry has no name")
+		}
+		t := r.Get(entry.Name)
+		if err := entry.apply(&t); err != nil {
+			return err
+		}
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		r.Add(t)
+	}
+	return nil
+}
+
+// apply sets the keys the entry gives on t.
+func (f toolFile) apply(t *Tool) error {
+	for dst, src := range map[*string]*string{
+		&t.DisplayName:    f.DisplayName,
+		&t.Command:        f.Command,
+		&t.ProjectDir:     f.ProjectDir,
+		&t.ProjectDirFlag: f.ProjectDirFlag,
+		&t.ReadyPrompt:    f.ReadyPrompt,
+		&t.Ghost:          f.Ghost,
+		&t.MinVersion:     f.MinVersion,
+	} {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	if f.PassArgs != nil {
+		t.PassArgs = *f.PassArgs
+	}
+	if f.VersionArgs != nil {
+		t.VersionArgs = *f.VersionArgs
+	}
+	for _, p := range f.Profiles {
+		t.setProfile(Profile{Name: p.Name, Args: p.Args})
+	}
+	fields := t.Theme.fields()
+	for key, color := range f.Theme {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("tool %q: unknown theme color %q", t.Name, key)
+		}
+		*field = color
+	}
+	return nil
+}
+
+// themeColors are the colors of a [tool.theme] table by key. Colors may be
+// written as numbers or strings.
+type themeColors map[string]string
+
+// UnmarshalTOML implements toml.Unmarshaler.
+func (c *themeColors) UnmarshalTOML(value any) error {
+	table, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("theme must be a table")
+	}
+	*c = themeColors{}
+	for key, v := range table {
+		switch v := v.(type) {
+		case int64:
+			(*c)[key] = strconv.FormatInt(v, 10)
+		case string:
+			(*c)[key] = v
+		default:
+			return fmt.Errorf("theme %s must be a 256-color number", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package aitools
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionRegex finds a dotted version number such as 1.0.42 or
+// v0.2.0-beta.1 in a tool's --version output.
+var versionRegex = regexp.MustCompile(`\d+(?:\.\d+)+(?:-[0-9A-Za-z.-]+)?`)
+
+// ParseVersion returns the first version number in output, or "" if there
+// is none.
+func ParseVersion(output string) string {
+	return versionRegex.FindString(output)
+}
+
+// CompareVersions compares two versions as returned by ParseVersion,
+// returning -1, 0 or 1. Missing components count as 0, and a pre-release
+// (1.0.0-beta) sorts before its release.
+func CompareVersions(a, b string) int {
+	a, preA, _ := strings.Cut(a, "-")
+	b, preB, _ := strings.Cut(b, "-")
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,42 @@
+package aitools
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"1.0.42 (Claude Code)\n", "1.0.42"},
+		{"codex-cli 0.20.0", "0.20.0"},
+		{"opencode v0.3.1-beta.2", "0.3.1-beta.2"},
+		{"GitHub Copilot CLI 0.0.328.\nRun 'copilot --help'", "0.0.328"},
+		{"version 2", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ParseVersion(tt.output); got != tt.want {
+			t.Errorf("ParseVersion(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1.0.10", "1.0.9", 1},
+		{"0.9.99", "1.0.0", -1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
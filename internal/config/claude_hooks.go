@@ -0,0 +1,411 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Claude hook events.
+const (
+	HookPreToolUse       = "PreToolUse"
+	HookPostToolUse      = "PostToolUse"
+	HookStop             = "Stop"
+	HookSubagentStop     = "SubagentStop"
+	HookNotification     = "Notification"
+	HookUserPromptSubmit = "UserPromptSubmit"
+	HookSessionStart     = "SessionStart"
+)
+
+// HookEvents lists the Claude hook events, in the order hooks are listed.
+var HookEvents = []string{
+	HookPreToolUse, HookPostToolUse, HookStop, HookSubagentStop,
+	HookNotification, HookUserPromptSubmit, HookSessionStart,
+}
+
+// MatcherEvents are the events whose hooks can be narrowed by a matcher:
+// tool names such as "Bash" or "Edit|Write" for the tool events, the kind
+// of notification (e.g. "idle_prompt") and how the session started
+// (startup, resume, clear or compact).
+var MatcherEvents = []string{HookPreToolUse, HookPostToolUse, HookNotification, HookSessionStart}
+
+// Hook is one command hook in Claude's settings.
+//
+// In the file, hooks are grouped by event, then by matcher:
+//
+//	"hooks": {
+//	  "PreToolUse": [
+//	    {"matcher": "Bash", "hooks": [{"type": "command", "command": "..."}]}
+//	  ]
+//	}
+type Hook struct {
+	Event   string `json:"event"`
+	Matcher string `json:"matcher,omitempty"` // empty matches everything
+	Command string `json:"command"`
+	Timeout int    `json:"timeout,omitempty"` // seconds, 0 for Claude's default
+}
+
+// Validate reports whether Claude can run h.
+func (h Hook) Validate() error {
+	if !slices.Contains(HookEvents, h.Event) {
+		return fmt.Errorf("unknown hook event %q, expected one of %s", h.Event, strings.Join(HookEvents, ", "))
+	}
+	if strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("hook command is empty")
+	}
+	if h.Matcher != "" && !slices.Contains(MatcherEvents, h.Event) {
+		return fmt.Errorf("%s hooks don't take a matcher", h.Event)
+	}
+	if h.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	return nil
+}
+
+// ListHooks returns the command hooks in the Claude settings file, by
+// event in the order of HookEvents, then in file order. Events Ghost Tab
+// doesn't know come last. A missing file has no hooks.
+func ListHooks(path string) ([]Hook, error) {
+	settings, _, err := readSettingsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	hooksObj, _ := settings["hooks"].(map[string]interface{})
+	var hooks []Hook
+	walkHooks(hooksObj, func(h Hook, entry, hook map[string]interface{}) {
+		hooks = append(hooks, h)
+	})
+	return hooks, nil
+}
+
+// AddHook adds h to the Claude settings file, next to the hooks with the
+// same event and matcher. Creates the file if it doesn't exist. Returns
+// HookExists when that event and matcher already run the command.
+func AddHook(path string, h Hook) (HookResult, error) {
+	if err := h.Validate(); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("creating parent directories: %w", err)
+	}
+
+	settings, _, err := readSettingsFile(path)
+	if err != nil {
+		return 0, err
+	}
+	hooksObj, ok := settings["hooks"].(map[string]interface{})
+	if !ok {
+		hooksObj = make(map[string]interface{})
+		settings["hooks"] = hooksObj
+	}
+	if !addHook(hooksObj, h) {
+		return HookExists, nil
+	}
+
+	if err := writeSettingsFile(path, settings); err != nil {
+		return 0, err
+	}
+	return HookAdded, nil
+}
+
+// UpdateHook replaces the hook matching old's event, matcher and command
+// with h. A hook that keeps its event and matcher is changed in place, so
+// its position and any settings Ghost Tab doesn't know survive. Returns
+// HookNotFound when no hook matches old.
+func UpdateHook(path string, old, h Hook) (HookResult, error) {
+	if err := h.Validate(); err != nil {
+		return 0, err
+	}
+	settings, _, err := readSettingsFile(path)
+	if err != nil {
+		return 0, err
+	}
+	hooksObj, _ := settings["hooks"].(map[string]interface{})
+
+	var found map[string]interface{}
+	duplicate := false
+	walkHooks(hooksObj, func(existing Hook, entry, hook map[string]interface{}) {
+		if found == nil && sameHook(existing, old) {
+			found = hook
+		}
+		duplicate = duplicate || (!sameHook(existing, old) && sameHook(existing, h))
+	})
+	if found == nil {
+		return HookNotFound, nil
+	}
+
+	if h.Event == old.Event && h.Matcher == old.Matcher && !duplicate {
+		found["command"] = h.Command
+		if h.Timeout > 0 {
+			found["timeout"] = h.Timeout
+		} else {
+			delete(found, "timeout")
+		}
+	} else {
+		removeHooks(settings, func(existing Hook) bool { return sameHook(existing, old) })
+		hooksObj, ok := settings["hooks"].(map[string]interface{})
+		if !ok {
+			hooksObj = make(map[string]interface{})
+			settings["hooks"] = hooksObj
+		}
+		addHook(hooksObj, h)
+	}
+
+	if err := writeSettingsFile(path, settings); err != nil {
+		return 0, err
+	}
+	return HookUpdated, nil
+}
+
+// RemoveHooks removes the hooks in the Claude settings file that match
+// selects, then any matcher groups, events and "hooks" object left empty.
+// Returns how many hooks were removed.
+func RemoveHooks(path string, match func(Hook) bool) (int, error) {
+	settings, _, err := readSettingsFile(path)
+	if err != nil {
+		return 0, err
+	}
+	removed := removeHooks(settings, match)
+	if removed == 0 {
+		return 0, nil
+	}
+	if err := writeSettingsFile(path, settings); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// hookMigrations move hooks from where older Ghost Tab versions registered
+// them. The sound hook used to run on Notification's idle_prompt, a minute
+// after Claude went quiet; it now runs on Stop, as soon as Claude finishes.
+// Only commands matched by ghostTab are moved, so hooks the user added
+// there stay put.
+var hookMigrations = []struct {
+	from, to Hook
+	ghostTab func(command string) bool
+}{
+	{from: Hook{Event: HookNotification, Matcher: "idle_prompt"}, to: Hook{Event: HookStop}, ghostTab: IsSoundHookCommand},
+}
+
+// soundHookPlayers are the players the sound hooks Ghost Tab writes start
+// with.
+var soundHookPlayers = []string{"afplay ", "paplay ", "pw-play ", "aplay -q "}
+
+// IsSoundHookCommand reports whether command is a sound hook written by
+// Ghost Tab: a player and a sound file, run in the background.
+func IsSoundHookCommand(command string) bool {
+	if !strings.HasSuffix(command, " &") {
+		return false
+	}
+	for _, player := range soundHookPlayers {
+		if strings.HasPrefix(command, player) {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateHooks moves Ghost Tab's hooks in the Claude settings file from
+// the places older versions registered them to where they belong now,
+// dropping ones already there. Returns how many hooks were moved.
+func MigrateHooks(path string) (int, error) {
+	settings, _, err := readSettingsFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, m := range hookMigrations {
+		var old []Hook
+		moved += removeHooks(settings, func(h Hook) bool {
+			if h.Event == m.from.Event && h.Matcher == m.from.Matcher && m.ghostTab(h.Command) {
+				old = append(old, h)
+				return true
+			}
+			return false
+		})
+		if len(old) == 0 {
+			continue
+		}
+		hooksObj, ok := settings["hooks"].(map[string]interface{})
+		if !ok {
+			hooksObj = make(map[string]interface{})
+			settings["hooks"] = hooksObj
+		}
+		for _, h := range old {
+			h.Event, h.Matcher = m.to.Event, m.to.Matcher
+			addHook(hooksObj, h)
+		}
+	}
+	if moved == 0 {
+		return 0, nil
+	}
+
+	if err := writeSettingsFile(path, settings); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// sameHook reports whether a and b are the same command for the same event
+// and matcher, whatever their timeouts.
+func sameHook(a, b Hook) bool {
+	return a.Event == b.Event && a.Matcher == b.Matcher && a.Command == b.Command
+}
+
+// hookEventNames returns the events in a "hooks" object in listing order.
+func hookEventNames(hooksObj map[string]interface{}) []string {
+	var names, unknown []string
+	for _, event := range HookEvents {
+		if _, ok := hooksObj[event]; ok {
+			names = append(names, event)
+		}
+	}
+	for event := range hooksObj {
+		if !slices.Contains(HookEvents, event) {
+			unknown = append(unknown, event)
+		}
+	}
+	sort.Strings(unknown)
+	return append(names, unknown...)
+}
+
+// walkHooks calls fn for each command hook in a "hooks" object, with the
+// matcher group and hook objects it was read from. Entries that aren't
+// shaped like hooks are skipped.
+func walkHooks(hooksObj map[string]interface{}, fn func(h Hook, entry, hook map[string]interface{})) {
+	for _, event := range hookEventNames(hooksObj) {
+		entries, _ := hooksObj[event].([]interface{})
+		for _, item := range entries {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			hooks, _ := entry["hooks"].([]interface{})
+			for _, hi := range hooks {
+				hook, ok := hi.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if h, ok := hookFrom(event, entry, hook); ok {
+					fn(h, entry, hook)
+				}
+			}
+		}
+	}
+}
+
+// hookFrom reads a command hook. It reports false for other kinds of hook.
+func hookFrom(event string, entry, hook map[string]interface{}) (Hook, bool) {
+	command, ok := hook["command"].(string)
+	if !ok {
+		return Hook{}, false
+	}
+	h := Hook{Event: event, Command: command}
+	h.Matcher, _ = entry["matcher"].(string)
+	if timeout, ok := hook["timeout"].(float64); ok {
+		h.Timeout = int(timeout)
+	}
+	return h, true
+}
+
+// addHook adds h to the matcher group for its event and matcher, creating
+// the group if needed. It reports false when the group already runs the
+// command.
+func addHook(hooksObj map[string]interface{}, h Hook) bool {
+	hook := map[string]interface{}{
+		"type":    "command",
+		"command": h.Command,
+	}
+	if h.Timeout > 0 {
+		hook["timeout"] = h.Timeout
+	}
+
+	entries, _ := hooksObj[h.Event].([]interface{})
+	for _, item := range entries {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if matcher, _ := entry["matcher"].(string); matcher != h.Matcher {
+			continue
+		}
+		hooks, _ := entry["hooks"].([]interface{})
+		for _, hi := range hooks {
+			if existing, ok := hi.(map[string]interface{}); ok && existing["command"] == h.Command {
+				return false
+			}
+		}
+		entry["hooks"] = append(hooks, hook)
+		return true
+	}
+
+	entry := map[string]interface{}{"hooks": []interface{}{hook}}
+	if h.Matcher != "" {
+		entry["matcher"] = h.Matcher
+	}
+	hooksObj[h.Event] = append(entries, entry)
+	return true
+}
+
+// removeHooks removes the hooks match selects from settings, along with
+// the groups, events and "hooks" object they leave empty. Returns how many
+// hooks were removed.
+func removeHooks(settings map[string]interface{}, match func(Hook) bool) int {
+	hooksObj, ok := settings["hooks"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	removed := 0
+	for _, event := range hookEventNames(hooksObj) {
+		entries, ok := hooksObj[event].([]interface{})
+		if !ok {
+			continue
+		}
+		before := removed
+		keptEntries := []interface{}{}
+		for _, item := range entries {
+			entry, ok := item.(map[string]interface{})
+			hooks, hasHooks := entry["hooks"].([]interface{})
+			if !ok || !hasHooks {
+				keptEntries = append(keptEntries, item)
+				continue
+			}
+			kept := []interface{}{}
+			for _, hi := range hooks {
+				if hook, ok := hi.(map[string]interface{}); ok {
+					if h, ok := hookFrom(event, entry, hook); ok && match(h) {
+						removed++
+						continue
+					}
+				}
+				kept = append(kept, hi)
+			}
+			if len(kept) == len(hooks) {
+				keptEntries = append(keptEntries, entry)
+				continue
+			}
+			if len(kept) > 0 {
+				entry["hooks"] = kept
+				keptEntries = append(keptEntries, entry)
+			}
+		}
+		if removed == before {
+			continue
+		}
+		if len(keptEntries) > 0 {
+			hooksObj[event] = keptEntries
+		} else {
+			delete(hooksObj, event)
+		}
+	}
+
+	if removed > 0 && len(hooksObj) == 0 {
+		delete(settings, "hooks")
+	}
+	return removed
+}
@@ -0,0 +1,874 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeSettings writes a settings file for a hooks test and returns its path.
+func writeSettings(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func mustListHooks(t *testing.T, path string) []Hook {
+	t.Helper()
+	hooks, err := ListHooks(path)
+	if err != nil {
+		t.Fatalf("ListHooks: %v", err)
+	}
+	return hooks
+}
+
+const hooksFixture = `{
+  "model": "opus",
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "echo done"}]}
+    ],
+    "CustomEvent": [
+      {"hooks": [{"type": "command", "command": "echo custom"}]}
+    ],
+    "PreToolUse": [
+      {
+        "matcher": "Bash",
+        "hooks": [
+          {"type": "command", "command": "check-bash", "timeout": 30},
+          {"type": "prompt", "prompt": "Is this safe?"}
+        ]
+      }
+    ]
+  }
+}`
+
+func TestHook_Validate(t *testing.T) {
+	tests := []struct {
+		hook Hook
+		want string
+	}{
+		{Hook{Event: "Stop", Command: "echo"}, ""},
+		{Hook{Event: "PreToolUse", Matcher: "Edit|Write", Command: "echo"}, ""},
+		{Hook{Event: "SessionStart", Matcher: "resume", Command: "echo"}, ""},
+		{Hook{Event: "Done", Command: "echo"}, "unknown hook event"},
+		{Hook{Event: "Stop", Command: "  "}, "command is empty"},
+		{Hook{Event: "Stop", Matcher: "x", Command: "echo"}, "don't take a matcher"},
+		{Hook{Event: "Stop", Command: "echo", Timeout: -1}, "timeout"},
+	}
+	for _, tt := range tests {
+		err := tt.hook.Validate()
+		if tt.want == "" && err != nil {
+			t.Errorf("Validate(%+v) = %v, want nil", tt.hook, err)
+		}
+		if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.hook, err, tt.want)
+		}
+	}
+}
+
+func TestListHooks(t *testing.T) {
+	t.Run("lists command hooks in event order", func(t *testing.T) {
+		got := mustListHooks(t, writeSettings(t, hooksFixture))
+		want := []Hook{
+			{Event: "PreToolUse", Matcher: "Bash", Command: "check-bash", Timeout: 30},
+			{Event: "Stop", Command: "echo done"},
+			{Event: "CustomEvent", Command: "echo custom"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ListHooks = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("missing file has no hooks", func(t *testing.T) {
+		if got := mustListHooks(t, filepath.Join(t.TempDir(), "settings.json")); len(got) != 0 {
+			t.Errorf("ListHooks = %+v", got)
+		}
+	})
+}
+
+func TestAddHook(t *testing.T) {
+	t.Run("joins the group with the same matcher", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		result, err := AddHook(path, Hook{Event: "PreToolUse", Matcher: "Bash", Command: "log-bash"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+		data, _ := os.ReadFile(path)
+		if n := strings.Count(string(data), `"matcher"`); n != 1 {
+			t.Errorf("expected one Bash group, found %d matchers:\n%s", n, data)
+		}
+		for _, want := range []string{`"model": "opus"`, "Is this safe?", "log-bash"} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("file should contain %q:\n%s", want, data)
+			}
+		}
+	})
+
+	t.Run("new matcher gets its own group", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		if _, err := AddHook(path, Hook{Event: "PreToolUse", Matcher: "Edit", Command: "check-edit", Timeout: 5}); err != nil {
+			t.Fatal(err)
+		}
+		hooks := mustListHooks(t, path)
+		want := Hook{Event: "PreToolUse", Matcher: "Edit", Command: "check-edit", Timeout: 5}
+		if hooks[1] != want {
+			t.Errorf("hooks[1] = %+v, want %+v", hooks[1], want)
+		}
+	})
+
+	t.Run("reports exists without writing", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		result, err := AddHook(path, Hook{Event: "Stop", Command: "echo done"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != HookExists {
+			t.Errorf("expected HookExists, got %v", result)
+		}
+		if data, _ := os.ReadFile(path); string(data) != hooksFixture {
+			t.Error("file should not change when the hook exists")
+		}
+	})
+
+	t.Run("rejects invalid hooks", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.json")
+		if _, err := AddHook(path, Hook{Event: "Stop", Matcher: "Bash", Command: "x"}); err == nil {
+			t.Error("expected an error")
+		}
+		if _, err := os.Stat(path); err == nil {
+			t.Error("file should not be created for an invalid hook")
+		}
+	})
+
+	t.Run("writes ampersands as is", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.json")
+		if _, err := AddHook(path, Hook{Event: "Stop", Command: "afplay Bottle.aiff &"}); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(path)
+		if !strings.Contains(string(data), "afplay Bottle.aiff &") {
+			t.Errorf("command should be written unescaped:\n%s", data)
+		}
+	})
+}
+
+func TestUpdateHook(t *testing.T) {
+	t.Run("changes the command in place", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		old := Hook{Event: "PreToolUse", Matcher: "Bash", Command: "check-bash"}
+		result, err := UpdateHook(path, old, Hook{Event: "PreToolUse", Matcher: "Bash", Command: "check-bash --strict"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != HookUpdated {
+			t.Errorf("expected HookUpdated, got %v", result)
+		}
+		hooks := mustListHooks(t, path)
+		if hooks[0] != (Hook{Event: "PreToolUse", Matcher: "Bash", Command: "check-bash --strict"}) {
+			t.Errorf("hooks[0] = %+v", hooks[0])
+		}
+		if data, _ := os.ReadFile(path); !strings.Contains(string(data), "Is this safe?") {
+			t.Error("other hooks in the group should survive")
+		}
+	})
+
+	t.Run("moves to another event", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		if _, err := UpdateHook(path, Hook{Event: "Stop", Command: "echo done"}, Hook{Event: "SubagentStop", Command: "echo done"}); err != nil {
+			t.Fatal(err)
+		}
+		for _, h := range mustListHooks(t, path) {
+			if h.Event == "Stop" {
+				t.Errorf("Stop hook left behind: %+v", h)
+			}
+		}
+		data, _ := os.ReadFile(path)
+		if !strings.Contains(string(data), `"SubagentStop"`) || strings.Contains(string(data), `"Stop"`) {
+			t.Errorf("expected the hook under SubagentStop only:\n%s", data)
+		}
+	})
+
+	t.Run("reports not found", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		result, err := UpdateHook(path, Hook{Event: "Stop", Command: "nope"}, Hook{Event: "Stop", Command: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != HookNotFound {
+			t.Errorf("expected HookNotFound, got %v", result)
+		}
+	})
+}
+
+func TestRemoveHooks(t *testing.T) {
+	t.Run("drops emptied groups, events and hooks", func(t *testing.T) {
+		path := writeSettings(t, `{
+  "model": "opus",
+  "hooks": {
+    "Stop": [{"hooks": [{"type": "command", "command": "afplay Bottle.aiff &"}]}]
+  }
+}`)
+		removed, err := RemoveHooks(path, func(h Hook) bool { return strings.HasPrefix(h.Command, "afplay ") })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if removed != 1 {
+			t.Errorf("removed %d, want 1", removed)
+		}
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), "hooks") || !strings.Contains(string(data), "opus") {
+			t.Errorf("expected only the model left:\n%s", data)
+		}
+	})
+
+	t.Run("keeps the rest of a group", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		if _, err := RemoveHooks(path, func(h Hook) bool { return h.Command == "check-bash" }); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(path)
+		for _, want := range []string{`"matcher": "Bash"`, "Is this safe?", "echo done", "echo custom"} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("file should contain %q:\n%s", want, data)
+			}
+		}
+	})
+
+	t.Run("leaves the file alone when nothing matches", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		removed, err := RemoveHooks(path, func(Hook) bool { return false })
+		if err != nil || removed != 0 {
+			t.Fatalf("RemoveHooks = %d, %v", removed, err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != hooksFixture {
+			t.Error("file should not change")
+		}
+	})
+}
+
+func TestMigrateHooks(t *testing.T) {
+	t.Run("moves idle_prompt hooks to Stop", func(t *testing.T) {
+		path := writeSettings(t, `{
+  "hooks": {
+    "Notification": [
+      {"matcher": "idle_prompt", "hooks": [{"type": "command", "command": "afplay Bottle.aiff &"}]},
+      {"matcher": "permission_prompt", "hooks": [{"type": "command", "command": "echo permission"}]}
+    ]
+  }
+}`)
+		moved, err := MigrateHooks(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if moved != 1 {
+			t.Errorf("moved %d, want 1", moved)
+		}
+		want := []Hook{
+			{Event: "Stop", Command: "afplay Bottle.aiff &"},
+			{Event: "Notification", Matcher: "permission_prompt", Command: "echo permission"},
+		}
+		if got := mustListHooks(t, path); !reflect.DeepEqual(got, want) {
+			t.Errorf("hooks = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("drops hooks already on Stop", func(t *testing.T) {
+		path := writeSettings(t, `{
+  "hooks": {
+    "Notification": [{"matcher": "idle_prompt", "hooks": [{"type": "command", "command": "paplay /usr/share/sounds/bell.oga &"}]}],
+    "Stop": [{"hooks": [{"type": "command", "command": "paplay /usr/share/sounds/bell.oga &"}]}]
+  }
+}`)
+		if _, err := MigrateHooks(path); err != nil {
+			t.Fatal(err)
+		}
+		want := []Hook{{Event: "Stop", Command: "paplay /usr/share/sounds/bell.oga &"}}
+		if got := mustListHooks(t, path); !reflect.DeepEqual(got, want) {
+			t.Errorf("hooks = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("leaves other idle_prompt hooks alone", func(t *testing.T) {
+		content := `{
+  "hooks": {
+    "Notification": [
+      {"matcher": "idle_prompt", "hooks": [
+        {"type": "command", "command": "notify-send 'Claude is waiting'"},
+        {"type": "command", "command": "afplay ~/Library/Sounds/Ping.aiff &"}
+      ]}
+    ]
+  }
+}`
+		path := writeSettings(t, content)
+		moved, err := MigrateHooks(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if moved != 1 {
+			t.Errorf("moved %d, want 1", moved)
+		}
+		want := []Hook{
+			{Event: "Stop", Command: "afplay ~/Library/Sounds/Ping.aiff &"},
+			{Event: "Notification", Matcher: "idle_prompt", Command: "notify-send 'Claude is waiting'"},
+		}
+		if got := mustListHooks(t, path); !reflect.DeepEqual(got, want) {
+			t.Errorf("hooks = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("nothing to move", func(t *testing.T) {
+		path := writeSettings(t, hooksFixture)
+		if moved, err := MigrateHooks(path); err != nil || moved != 0 {
+			t.Errorf("MigrateHooks = %d, %v", moved, err)
+		}
+	})
+}
+
+// --- AddHook settings file tests (ported from settings-json.bats add_sound_notification_hook) ---
+
+func TestAddHook_settings_file(t *testing.T) {
+	defaultCmd := "afplay /System/Library/Sounds/Bottle.aiff &"
+	soundHook := Hook{Event: HookStop, Command: defaultCmd}
+
+	t.Run("adds hook to empty settings", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(data)
+		if !strings.Contains(content, `"Stop"`) {
+			t.Error("file should contain the Stop event")
+		}
+		if !strings.Contains(content, "Bottle.aiff") {
+			t.Error("file should contain 'Bottle.aiff'")
+		}
+
+		// Verify valid JSON
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Errorf("file should be valid JSON: %v", err)
+		}
+	})
+
+	t.Run("skips when already exists", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		existing := `{
+  "hooks": {
+    "Stop": [
+      {
+        "hooks": [{"type": "command", "command": "afplay /System/Library/Sounds/Bottle.aiff &"}]
+      }
+    ]
+  }
+}`
+		if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookExists {
+			t.Errorf("expected HookExists, got %v", result)
+		}
+	})
+
+	t.Run("creates file when missing", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "new-settings.json")
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			t.Error("file should have been created")
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"Stop"`) {
+			t.Error("file should contain the Stop event")
+		}
+	})
+
+	// --- Malformed JSON tests ---
+
+	t.Run("refuses malformed JSON", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			content string
+			want    string
+		}{
+			{"missing closing brace", `{"foo": "bar"`, "line 1, column 14"},
+			{"missing quotes", `{foo: bar}`, "line 1, column 2"},
+			{"missing commas", "{\n  \"foo\": \"bar\"\n  \"baz\": \"qux\"\n}", "line 3, column 3"},
+			{"completely corrupted", "not even json at all!!!", "line 1, column 1"},
+			{"binary", string([]byte{0x00, 0x01, 0x02, 0x03, 0x04}), "line 1, column 1"},
+			{"not an object", `["foo"]`, "expected a JSON object"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				dir := t.TempDir()
+				path := filepath.Join(dir, "settings.json")
+
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				_, err := AddHook(path, soundHook)
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Fatalf("expected an error with %q, got %v", tt.want, err)
+				}
+
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != tt.content {
+					t.Errorf("malformed file should be left alone, got %q", data)
+				}
+			})
+		}
+	})
+
+	t.Run("handles malformed JSON - trailing comma", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte(`{"foo": "bar",}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"Stop"`) {
+			t.Error("file should contain the Stop event")
+		}
+	})
+
+	t.Run("handles Windows line endings", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{\r\n  \"foo\": \"bar\"\r\n}\r\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"Stop"`) {
+			t.Error("file should contain the Stop event")
+		}
+	})
+
+	// --- Empty and whitespace files ---
+
+	t.Run("handles empty file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte(""), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"Stop"`) {
+			t.Error("file should contain the Stop event")
+		}
+	})
+
+	t.Run("handles file with only whitespace", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("   \n\n  \t\t  \n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"Stop"`) {
+			t.Error("file should contain the Stop event")
+		}
+	})
+
+	// --- Special characters ---
+
+	t.Run("handles command with special shell characters", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		specialCmd := `echo "test $VAR & && || ; | > < ( )"`
+		result, err := AddHook(path, Hook{Event: HookStop, Command: specialCmd})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(data)
+		if !strings.Contains(content, `"command":`) {
+			t.Error("file should contain '\"command\":'")
+		}
+		if !strings.Contains(content, "echo") {
+			t.Error("file should contain 'echo'")
+		}
+
+		// Verify valid JSON
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Errorf("file should be valid JSON: %v", err)
+		}
+	})
+
+	t.Run("handles command with quotes and newlines", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := `echo 'single' "double"`
+		result, err := AddHook(path, Hook{Event: HookStop, Command: cmd})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// JSON will have the quotes escaped
+		if !strings.Contains(string(data), "echo 'single'") {
+			t.Error("file should contain the single-quoted part of the command")
+		}
+	})
+
+	// --- Permission denied ---
+
+	t.Run("handles read-only file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{}"), 0444); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chmod(path, 0644)
+
+		_, err := AddHook(path, soundHook)
+		if err == nil {
+			t.Error("expected error for read-only file, got nil")
+		}
+	})
+
+	// --- Large files ---
+
+	t.Run("handles large JSON file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		// Build a JSON file with 1000+ entries
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for i := 1; i <= 1000; i++ {
+			if i > 1 {
+				sb.WriteString(",\n")
+			}
+			sb.WriteString(fmt.Sprintf("  \"key%d\": \"value%d\"", i, i))
+		}
+		sb.WriteString("\n}")
+
+		if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(data)
+		if !strings.Contains(content, `"Stop"`) {
+			t.Error("file should contain the Stop event")
+		}
+		if !strings.Contains(content, "key1000") {
+			t.Error("file should preserve existing key1000")
+		}
+	})
+
+	// --- Concurrent writes ---
+
+	t.Run("handles concurrent writes to same file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, errs[0] = AddHook(path, soundHook)
+		}()
+		go func() {
+			defer wg.Done()
+			_, errs[1] = AddHook(path, soundHook)
+		}()
+		wg.Wait()
+
+		// At least one should succeed
+		anySuccess := errs[0] == nil || errs[1] == nil
+		if !anySuccess {
+			t.Errorf("at least one concurrent write should succeed, got errors: %v, %v", errs[0], errs[1])
+		}
+
+		// File should be valid JSON
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Errorf("resulting file should be valid JSON: %v", err)
+		}
+	})
+
+	// --- Hook structure verification ---
+
+	t.Run("creates correct hook structure", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Fatalf("file should be valid JSON: %v", err)
+		}
+
+		// Verify structure: hooks -> Stop -> [0] without a matcher
+		hooks, ok := parsed["hooks"].(map[string]interface{})
+		if !ok {
+			t.Fatal("expected 'hooks' to be an object")
+		}
+		stopList, ok := hooks["Stop"].([]interface{})
+		if !ok {
+			t.Fatal("expected 'hooks.Stop' to be an array")
+		}
+		if len(stopList) != 1 {
+			t.Fatalf("expected 1 Stop entry, got %d", len(stopList))
+		}
+		entry, ok := stopList[0].(map[string]interface{})
+		if !ok {
+			t.Fatal("expected Stop entry to be an object")
+		}
+		if _, ok := entry["matcher"]; ok {
+			t.Errorf("expected no matcher, got %v", entry["matcher"])
+		}
+		hooksList, ok := entry["hooks"].([]interface{})
+		if !ok {
+			t.Fatal("expected entry.hooks to be an array")
+		}
+		if len(hooksList) != 1 {
+			t.Fatalf("expected 1 hook, got %d", len(hooksList))
+		}
+		hook, ok := hooksList[0].(map[string]interface{})
+		if !ok {
+			t.Fatal("expected hook to be an object")
+		}
+		if hook["type"] != "command" {
+			t.Errorf("expected hook type 'command', got %v", hook["type"])
+		}
+		if hook["command"] != defaultCmd {
+			t.Errorf("expected hook command %q, got %v", defaultCmd, hook["command"])
+		}
+	})
+
+	t.Run("creates parent directories if needed", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "subdir", "nested", "settings.json")
+
+		result, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != HookAdded {
+			t.Errorf("expected HookAdded, got %v", result)
+		}
+
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			t.Error("file should have been created")
+		}
+	})
+
+	t.Run("preserves existing keys when adding hook", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		existing := `{"permissions": {"allow": ["Bash"]}, "apiKey": "test123"}`
+		if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Fatalf("file should be valid JSON: %v", err)
+		}
+
+		if _, ok := parsed["hooks"]; !ok {
+			t.Error("should have hooks key")
+		}
+		if _, ok := parsed["permissions"]; !ok {
+			t.Error("should preserve permissions key")
+		}
+		if _, ok := parsed["apiKey"]; !ok {
+			t.Error("should preserve apiKey key")
+		}
+	})
+
+	t.Run("file ends with newline", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := AddHook(path, soundHook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(string(data), "\n") {
+			t.Error("file should end with a newline")
+		}
+	})
+}
@@ -1,11 +1,10 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
 )
 
 // MergeResult indicates what MergeStatusLine did.
@@ -33,7 +32,7 @@ func (r MergeResult) String() string {
 	}
 }
 
-// HookResult indicates what AddSoundHook did.
+// HookResult indicates what a hook operation such as AddHook did.
 type HookResult int
 
 const (
@@ -41,6 +40,12 @@ const (
 	HookAdded HookResult = iota
 	// HookExists means the hook was already present — no changes made.
 	HookExists
+	// HookUpdated means an existing hook was changed.
+	HookUpdated
+	// HookRemoved means one or more hooks were removed.
+	HookRemoved
+	// HookNotFound means no hook matched — no changes made.
+	HookNotFound
 )
 
 func (r HookResult) String() string {
@@ -49,6 +54,12 @@ func (r HookResult) String() string {
 		return "added"
 	case HookExists:
 		return "exists"
+	case HookUpdated:
+		return "updated"
+	case HookRemoved:
+		return "removed"
+	case HookNotFound:
+		return "not_found"
 	default:
 		return "unknown"
 	}
@@ -85,28 +96,15 @@ func MergeStatusLine(path string, statusLine map[string]interface{}) (MergeResul
 	return StatusLineCreated, nil
 }
 
-// AddSoundHook adds a sound notification hook to the Claude settings file
-// if it doesn't already exist. Creates the file if it doesn't exist.
-// The hookCommand is the shell command to run for the notification.
-//
-// The hook structure follows the Claude Code settings format:
-//
-//	{
-//	  "hooks": {
-//	    "Notification": [
-//	      {
-//	        "matcher": "idle_prompt",
-//	        "hooks": [
-//	          {
-//	            "type": "command",
-//	            "command": "<hookCommand>"
-//	          }
-//	        ]
-//	      }
-//	    ]
-//	  }
-//	}
-func AddSoundHook(path string, hookCommand string) (HookResult, error) {
+// NotifyHookEvents are the Claude hook events AddNotifyHooks registers the
+// notify command for: finished turns and requests for attention, plus
+// prompts so the tab title mark can be cleared.
+var NotifyHookEvents = []string{"Stop", "Notification", "UserPromptSubmit"}
+
+// AddNotifyHooks registers hookCommand for each of NotifyHookEvents in the
+// Claude settings file, skipping events that already run it. Creates the
+// file if it doesn't exist. Returns HookExists when nothing was added.
+func AddNotifyHooks(path string, hookCommand string) (HookResult, error) {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return 0, fmt.Errorf("creating parent directories: %w", err)
 	}
@@ -116,93 +114,40 @@ func AddSoundHook(path string, hookCommand string) (HookResult, error) {
 		return 0, err
 	}
 
-	// Get or create "hooks" object
 	hooksObj, ok := settings["hooks"].(map[string]interface{})
 	if !ok {
 		hooksObj = make(map[string]interface{})
 		settings["hooks"] = hooksObj
 	}
 
-	// Get or create "Notification" array
-	var notifList []interface{}
-	if existing, ok := hooksObj["Notification"].([]interface{}); ok {
-		notifList = existing
-	}
-
-	// Check if idle_prompt hook already exists
-	for _, item := range notifList {
-		entry, ok := item.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if entry["matcher"] == "idle_prompt" {
-			return HookExists, nil
+	result := HookExists
+	for _, event := range NotifyHookEvents {
+		if addHook(hooksObj, Hook{Event: event, Command: hookCommand}) {
+			result = HookAdded
 		}
 	}
-
-	// Build the new hook entry
-	newEntry := map[string]interface{}{
-		"matcher": "idle_prompt",
-		"hooks": []interface{}{
-			map[string]interface{}{
-				"type":    "command",
-				"command": hookCommand,
-			},
-		},
+	if result == HookExists {
+		return HookExists, nil
 	}
 
-	notifList = append(notifList, newEntry)
-	hooksObj["Notification"] = notifList
-
 	if err := writeSettingsFile(path, settings); err != nil {
 		return 0, err
 	}
-
 	return HookAdded, nil
 }
 
-// readSettingsFile reads and parses a JSON settings file.
-// Returns the parsed map, whether the file existed, and any error.
-// If the file doesn't exist or contains invalid JSON, returns an empty map.
-// Returns an error only for permission/IO issues that prevent reading.
-func readSettingsFile(path string) (map[string]interface{}, bool, error) {
-	data, err := os.ReadFile(path)
+// RemoveNotifyHooks removes the hooks running exactly hookCommand from
+// each of NotifyHookEvents in the Claude settings file, leaving every other
+// hook alone. Returns HookNotFound when there were none.
+func RemoveNotifyHooks(path string, hookCommand string) (HookResult, error) {
+	removed, err := RemoveHooks(path, func(h Hook) bool {
+		return h.Command == hookCommand && slices.Contains(NotifyHookEvents, h.Event)
+	})
 	if err != nil {
-		if os.IsNotExist(err) {
-			return make(map[string]interface{}), false, nil
-		}
-		return nil, false, fmt.Errorf("reading settings file: %w", err)
-	}
-
-	// Try to parse the JSON
-	settings := make(map[string]interface{})
-	content := strings.TrimSpace(string(data))
-	if content == "" {
-		return settings, true, nil
-	}
-
-	if err := json.Unmarshal([]byte(content), &settings); err != nil {
-		// Malformed JSON: start fresh (matches bash/python behavior)
-		return make(map[string]interface{}), true, nil
-	}
-
-	return settings, true, nil
-}
-
-// writeSettingsFile marshals the settings map and writes it to the file
-// with 2-space indentation and a trailing newline.
-func writeSettingsFile(path string, settings map[string]interface{}) error {
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling JSON: %w", err)
+		return 0, err
 	}
-
-	// Append trailing newline (matches python json.dump behavior)
-	data = append(data, '\n')
-
-	if err := os.WriteFile(path, data, 0644); err != nil {
-		return fmt.Errorf("writing settings file: %w", err)
+	if removed == 0 {
+		return HookNotFound, nil
 	}
-
-	return nil
+	return HookRemoved, nil
 }
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
-	"sync"
 	"testing"
 )
 
@@ -249,20 +249,15 @@ func TestMergeStatusLine(t *testing.T) {
 	})
 }
 
-// --- AddSoundHook tests (ported from settings-json.bats add_sound_notification_hook) ---
+// --- AddNotifyHooks tests ---
 
-func TestAddSoundHook(t *testing.T) {
-	defaultCmd := "afplay /System/Library/Sounds/Bottle.aiff &"
+func TestAddNotifyHooks(t *testing.T) {
+	const cmd = "ghost-tab-tui notify"
 
-	t.Run("adds hook to empty settings", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
+	t.Run("adds hook to every event", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "claude", "settings.json")
 
-		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
+		result, err := AddNotifyHooks(path, cmd)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -274,32 +269,32 @@ func TestAddSoundHook(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		content := string(data)
-		if !strings.Contains(content, "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-		if !strings.Contains(content, "Bottle.aiff") {
-			t.Error("file should contain 'Bottle.aiff'")
+		var parsed struct {
+			Hooks map[string][]struct {
+				Hooks []struct {
+					Type    string `json:"type"`
+					Command string `json:"command"`
+				} `json:"hooks"`
+			} `json:"hooks"`
 		}
-
-		// Verify valid JSON
-		var parsed map[string]interface{}
 		if err := json.Unmarshal(data, &parsed); err != nil {
-			t.Errorf("file should be valid JSON: %v", err)
+			t.Fatalf("file should be valid JSON: %v", err)
+		}
+		for _, event := range NotifyHookEvents {
+			entries := parsed.Hooks[event]
+			if len(entries) != 1 || len(entries[0].Hooks) != 1 || entries[0].Hooks[0].Command != cmd {
+				t.Errorf("%s entries = %+v", event, entries)
+			}
 		}
 	})
 
-	t.Run("skips when already exists", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
+	t.Run("keeps other hooks and settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.json")
 		existing := `{
+  "model": "opus",
   "hooks": {
-    "Notification": [
-      {
-        "matcher": "idle_prompt",
-        "hooks": [{"type": "command", "command": "afplay /System/Library/Sounds/Bottle.aiff &"}]
-      }
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "echo done"}]}
     ]
   }
 }`
@@ -307,570 +302,70 @@ func TestAddSoundHook(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookExists {
-			t.Errorf("expected HookExists, got %v", result)
-		}
-	})
-
-	t.Run("creates file when missing", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "new-settings.json")
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			t.Error("file should have been created")
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	// --- Malformed JSON tests ---
-
-	t.Run("handles malformed JSON - missing closing brace", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte(`{"foo": "bar"`), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		// Verify resulting file is valid JSON
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		var parsed map[string]interface{}
-		if err := json.Unmarshal(data, &parsed); err != nil {
-			t.Errorf("resulting file should be valid JSON: %v", err)
-		}
-	})
-
-	t.Run("handles malformed JSON - missing quotes", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte(`{foo: bar}`), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
+		if _, err := AddNotifyHooks(path, cmd); err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	t.Run("handles malformed JSON - trailing comma", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte(`{"foo": "bar",}`), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	t.Run("handles malformed JSON - missing commas", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte(`{"foo": "bar" "baz": "qux"}`), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	t.Run("handles completely corrupted JSON", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("not even json at all!!!"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	t.Run("handles binary file", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte{0x00, 0x01, 0x02, 0x03, 0x04}, 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	t.Run("handles Windows line endings", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("{\r\n  \"foo\": \"bar\"\r\n}\r\n"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	// --- Empty and whitespace files ---
-
-	t.Run("handles empty file", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte(""), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	t.Run("handles file with only whitespace", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("   \n\n  \t\t  \n"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.Contains(string(data), "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-	})
-
-	// --- Special characters ---
-
-	t.Run("handles command with special shell characters", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		specialCmd := `echo "test $VAR & && || ; | > < ( )"`
-		result, err := AddSoundHook(path, specialCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
+		data, _ := os.ReadFile(path)
 		content := string(data)
-		if !strings.Contains(content, `"command":`) {
-			t.Error("file should contain '\"command\":'")
-		}
-		if !strings.Contains(content, "echo") {
-			t.Error("file should contain 'echo'")
-		}
-
-		// Verify valid JSON
-		var parsed map[string]interface{}
-		if err := json.Unmarshal(data, &parsed); err != nil {
-			t.Errorf("file should be valid JSON: %v", err)
-		}
-	})
-
-	t.Run("handles command with quotes and newlines", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		cmd := `echo 'single' "double"`
-		result, err := AddSoundHook(path, cmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		// JSON will have the quotes escaped
-		if !strings.Contains(string(data), "echo 'single'") {
-			t.Error("file should contain the single-quoted part of the command")
-		}
-	})
-
-	// --- Permission denied ---
-
-	t.Run("handles read-only file", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("{}"), 0444); err != nil {
-			t.Fatal(err)
-		}
-		defer os.Chmod(path, 0644)
-
-		_, err := AddSoundHook(path, defaultCmd)
-		if err == nil {
-			t.Error("expected error for read-only file, got nil")
-		}
-	})
-
-	// --- Large files ---
-
-	t.Run("handles large JSON file", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		// Build a JSON file with 1000+ entries
-		var sb strings.Builder
-		sb.WriteString("{\n")
-		for i := 1; i <= 1000; i++ {
-			if i > 1 {
-				sb.WriteString(",\n")
+		for _, want := range []string{`"model": "opus"`, "echo done", cmd, "UserPromptSubmit"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("file should contain %q:\n%s", want, content)
 			}
-			sb.WriteString(fmt.Sprintf("  \"key%d\": \"value%d\"", i, i))
-		}
-		sb.WriteString("\n}")
-
-		if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		content := string(data)
-		if !strings.Contains(content, "idle_prompt") {
-			t.Error("file should contain 'idle_prompt'")
-		}
-		if !strings.Contains(content, "key1000") {
-			t.Error("file should preserve existing key1000")
-		}
-	})
-
-	// --- Concurrent writes ---
-
-	t.Run("handles concurrent writes to same file", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		var wg sync.WaitGroup
-		errs := make([]error, 2)
-
-		wg.Add(2)
-		go func() {
-			defer wg.Done()
-			_, errs[0] = AddSoundHook(path, defaultCmd)
-		}()
-		go func() {
-			defer wg.Done()
-			_, errs[1] = AddSoundHook(path, defaultCmd)
-		}()
-		wg.Wait()
-
-		// At least one should succeed
-		anySuccess := errs[0] == nil || errs[1] == nil
-		if !anySuccess {
-			t.Errorf("at least one concurrent write should succeed, got errors: %v, %v", errs[0], errs[1])
-		}
-
-		// File should be valid JSON
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		var parsed map[string]interface{}
-		if err := json.Unmarshal(data, &parsed); err != nil {
-			t.Errorf("resulting file should be valid JSON: %v", err)
 		}
 	})
 
-	// --- Hook structure verification ---
-
-	t.Run("creates correct hook structure", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+	t.Run("skips events that already run it", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.json")
+		if _, err := AddNotifyHooks(path, cmd); err != nil {
 			t.Fatal(err)
 		}
+		before, _ := os.ReadFile(path)
 
-		_, err := AddSoundHook(path, defaultCmd)
+		result, err := AddNotifyHooks(path, cmd)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		var parsed map[string]interface{}
-		if err := json.Unmarshal(data, &parsed); err != nil {
-			t.Fatalf("file should be valid JSON: %v", err)
-		}
-
-		// Verify structure: hooks -> Notification -> [0] -> matcher == "idle_prompt"
-		hooks, ok := parsed["hooks"].(map[string]interface{})
-		if !ok {
-			t.Fatal("expected 'hooks' to be an object")
-		}
-		notifList, ok := hooks["Notification"].([]interface{})
-		if !ok {
-			t.Fatal("expected 'hooks.Notification' to be an array")
-		}
-		if len(notifList) != 1 {
-			t.Fatalf("expected 1 notification entry, got %d", len(notifList))
-		}
-		entry, ok := notifList[0].(map[string]interface{})
-		if !ok {
-			t.Fatal("expected notification entry to be an object")
-		}
-		if entry["matcher"] != "idle_prompt" {
-			t.Errorf("expected matcher 'idle_prompt', got %v", entry["matcher"])
-		}
-		hooksList, ok := entry["hooks"].([]interface{})
-		if !ok {
-			t.Fatal("expected entry.hooks to be an array")
-		}
-		if len(hooksList) != 1 {
-			t.Fatalf("expected 1 hook, got %d", len(hooksList))
-		}
-		hook, ok := hooksList[0].(map[string]interface{})
-		if !ok {
-			t.Fatal("expected hook to be an object")
-		}
-		if hook["type"] != "command" {
-			t.Errorf("expected hook type 'command', got %v", hook["type"])
-		}
-		if hook["command"] != defaultCmd {
-			t.Errorf("expected hook command %q, got %v", defaultCmd, hook["command"])
-		}
-	})
-
-	t.Run("creates parent directories if needed", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "subdir", "nested", "settings.json")
-
-		result, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		if result != HookAdded {
-			t.Errorf("expected HookAdded, got %v", result)
-		}
-
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			t.Error("file should have been created")
-		}
-	})
-
-	t.Run("preserves existing keys when adding hook", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		existing := `{"permissions": {"allow": ["Bash"]}, "apiKey": "test123"}`
-		if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
-			t.Fatal(err)
-		}
-
-		_, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		var parsed map[string]interface{}
-		if err := json.Unmarshal(data, &parsed); err != nil {
-			t.Fatalf("file should be valid JSON: %v", err)
-		}
-
-		if _, ok := parsed["hooks"]; !ok {
-			t.Error("should have hooks key")
-		}
-		if _, ok := parsed["permissions"]; !ok {
-			t.Error("should preserve permissions key")
+		if result != HookExists {
+			t.Errorf("expected HookExists, got %v", result)
 		}
-		if _, ok := parsed["apiKey"]; !ok {
-			t.Error("should preserve apiKey key")
+		after, _ := os.ReadFile(path)
+		if string(before) != string(after) {
+			t.Error("file should not change when every hook exists")
 		}
 	})
+}
 
-	t.Run("file ends with newline", func(t *testing.T) {
-		dir := t.TempDir()
-		path := filepath.Join(dir, "settings.json")
-
-		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
-			t.Fatal(err)
-		}
+func TestRemoveNotifyHooks(t *testing.T) {
+	const cmd = "ghost-tab-tui notify"
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{
+  "hooks": {
+    "Stop": [{"hooks": [{"type": "command", "command": "echo done"}]}],
+    "PreToolUse": [{"matcher": "Bash", "hooks": [{"type": "command", "command": "ghost-tab-tui notify"}]}]
+  }
+}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddNotifyHooks(path, cmd); err != nil {
+		t.Fatal(err)
+	}
 
-		_, err := AddSoundHook(path, defaultCmd)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
+	result, err := RemoveNotifyHooks(path, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != HookRemoved {
+		t.Errorf("expected HookRemoved, got %v", result)
+	}
+	want := []Hook{
+		{Event: "PreToolUse", Matcher: "Bash", Command: cmd},
+		{Event: "Stop", Command: "echo done"},
+	}
+	if got := mustListHooks(t, path); !reflect.DeepEqual(got, want) {
+		t.Errorf("hooks = %+v, want %+v", got, want)
+	}
 
-		data, err := os.ReadFile(path)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !strings.HasSuffix(string(data), "\n") {
-			t.Error("file should end with a newline")
-		}
-	})
+	if result, err := RemoveNotifyHooks(path, cmd); err != nil || result != HookNotFound {
+		t.Errorf("second removal = %v, %v; want HookNotFound", result, err)
+	}
 }
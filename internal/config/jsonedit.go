@@ -0,0 +1,756 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSyntaxError reports where a JSON file stops being valid.
+type JSONSyntaxError struct {
+	Line   int // 1-based
+	Column int // 1-based, in characters
+	Msg    string
+}
+
+func (e *JSONSyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// jsonNode is a JSON value parsed with its position in the source, so an
+// edit can rewrite just the parts of a file that changed. Comments and
+// trailing commas, which editors allow in settings files, are accepted.
+type jsonNode struct {
+	kind       byte // '{', '[' or 's' for strings, numbers, booleans and null
+	start, end int  // the value is src[start:end]
+	members    []jsonMember
+	elems      []*jsonNode
+	scalar     interface{}
+}
+
+// jsonMember is a key and value of an object; src[start:value.end] is the
+// whole member.
+type jsonMember struct {
+	key   string
+	start int
+	value *jsonNode
+}
+
+// value returns the node as encoding/json would decode it.
+func (n *jsonNode) value() interface{} {
+	switch n.kind {
+	case '{':
+		m := make(map[string]interface{}, len(n.members))
+		for _, mem := range n.members {
+			m[mem.key] = mem.value.value()
+		}
+		return m
+	case '[':
+		a := make([]interface{}, len(n.elems))
+		for i, e := range n.elems {
+			a[i] = e.value()
+		}
+		return a
+	}
+	return n.scalar
+}
+
+// parseJSON parses src, returning nil for a document with nothing but
+// whitespace and comments.
+func parseJSON(src []byte) (*jsonNode, error) {
+	p := &jsonParser{src: src}
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	if p.pos == len(src) {
+		return nil, nil
+	}
+	n, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(src) {
+		return nil, p.errorf("unexpected %s after the end of the document", p.describe())
+	}
+	return n, nil
+}
+
+type jsonParser struct {
+	src []byte
+	pos int
+}
+
+func (p *jsonParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+func (p *jsonParser) errorAt(pos int, format string, args ...interface{}) error {
+	before := p.src[:pos]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return &JSONSyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// describe names the character at the current position for messages.
+func (p *jsonParser) describe() string {
+	if p.pos >= len(p.src) {
+		return "end of file"
+	}
+	r, _ := utf8.DecodeRune(p.src[p.pos:])
+	return fmt.Sprintf("%q", r)
+}
+
+// skip moves past whitespace and comments.
+func (p *jsonParser) skip() error {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case bytes.HasPrefix(p.src[p.pos:], []byte("//")):
+			end := bytes.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+			} else {
+				p.pos += end
+			}
+		case bytes.HasPrefix(p.src[p.pos:], []byte("/*")):
+			end := bytes.Index(p.src[p.pos+2:], []byte("*/"))
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *jsonParser) parseValue() (*jsonNode, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of file, expected a value")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"':
+		return p.parseString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	}
+	for word, v := range map[string]interface{}{"true": true, "false": false, "null": nil} {
+		if bytes.HasPrefix(p.src[p.pos:], []byte(word)) {
+			n := &jsonNode{kind: 's', start: p.pos, end: p.pos + len(word), scalar: v}
+			p.pos = n.end
+			return n, nil
+		}
+	}
+	return nil, p.errorf("unexpected %s, expected a value", p.describe())
+}
+
+func (p *jsonParser) parseObject() (*jsonNode, error) {
+	n := &jsonNode{kind: '{', start: p.pos}
+	p.pos++
+	for {
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '}' {
+			break
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+			return nil, p.errorf("unexpected %s, expected a key in quotes or '}'", p.describe())
+		}
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return nil, p.errorf("unexpected %s, expected ':' after the key", p.describe())
+		}
+		p.pos++
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		n.members = append(n.members, jsonMember{key: key.scalar.(string), start: key.start, value: value})
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != '}' {
+			return nil, p.errorf("unexpected %s, expected ',' or '}'", p.describe())
+		}
+		break
+	}
+	p.pos++
+	n.end = p.pos
+	return n, nil
+}
+
+func (p *jsonParser) parseArray() (*jsonNode, error) {
+	n := &jsonNode{kind: '[', start: p.pos}
+	p.pos++
+	for {
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ']' {
+			break
+		}
+		elem, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		n.elems = append(n.elems, elem)
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ']' {
+			return nil, p.errorf("unexpected %s, expected ',' or ']'", p.describe())
+		}
+		break
+	}
+	p.pos++
+	n.end = p.pos
+	return n, nil
+}
+
+func (p *jsonParser) parseString() (*jsonNode, error) {
+	start := p.pos
+	i := p.pos + 1
+	for i < len(p.src) && p.src[i] != '"' {
+		if p.src[i] == '\\' {
+			i++
+		}
+		if i < len(p.src) && p.src[i] == '\n' {
+			return nil, p.errorAt(start, "unterminated string")
+		}
+		i++
+	}
+	if i >= len(p.src) {
+		return nil, p.errorAt(start, "unterminated string")
+	}
+	var s string
+	if err := json.Unmarshal(p.src[start:i+1], &s); err != nil {
+		return nil, p.errorAt(start, "invalid string")
+	}
+	p.pos = i + 1
+	return &jsonNode{kind: 's', start: start, end: p.pos, scalar: s}, nil
+}
+
+func (p *jsonParser) parseNumber() (*jsonNode, error) {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte("+-0123456789.eE", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	var f float64
+	if err := json.Unmarshal(p.src[start:p.pos], &f); err != nil {
+		return nil, p.errorAt(start, "invalid number %s", p.src[start:p.pos])
+	}
+	return &jsonNode{kind: 's', start: start, end: p.pos, scalar: f}, nil
+}
+
+// editJSON returns src changed to hold v. Only the values that differ are
+// rewritten: key order, indentation, comments and line endings elsewhere
+// are kept. New values are indented like the rest of the file. An empty
+// src gets v indented with two spaces. Fails, with the line and column,
+// when src isn't valid JSON.
+func editJSON(src []byte, v interface{}) ([]byte, error) {
+	v, err := normalizeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseJSON(src)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		text, err := formatJSON(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return []byte(text + "\n"), nil
+	}
+
+	p := &jsonPatcher{src: src, unit: detectIndent(src), newline: "\n"}
+	if bytes.Contains(src, []byte("\r\n")) {
+		p.newline = "\r\n"
+	}
+	if err := p.diff(root, v, false); err != nil {
+		return nil, err
+	}
+	out := p.apply()
+	if !bytes.HasSuffix(out, []byte("\n")) {
+		out = append(out, p.newline...)
+	}
+	return out, nil
+}
+
+// normalizeJSON round-trips v through encoding/json so its numbers and
+// containers have the types jsonNode.value returns.
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling JSON: %w", err)
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// leadingKeys come first in the objects formatJSON writes, in this order,
+// so new hooks and status lines read like Claude's documentation: the type
+// of a hook before its command, the matcher of a group before its hooks.
+var leadingKeys = []string{"type", "matcher"}
+
+// objectKeys returns the keys of m in the order they are written: those in
+// leadingKeys first, then the rest sorted.
+func objectKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for _, k := range leadingKeys {
+		if _, ok := m[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	rest := make([]string, 0, len(m)-len(keys))
+	for k := range m {
+		if !slices.Contains(leadingKeys, k) {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// formatJSON marshals v with keys in objectKeys order, characters such as
+// & left as they are, and lines after the first prefixed with prefix. An
+// empty unit gives a single line spaced the way people write one, e.g.
+// {"a": [1, 2]}.
+func formatJSON(v interface{}, prefix, unit string) (string, error) {
+	var parts []string
+	var open, close string
+	switch val := v.(type) {
+	case map[string]interface{}:
+		open, close = "{", "}"
+		for _, k := range objectKeys(val) {
+			key, _ := formatJSON(k, "", "")
+			value, err := formatJSON(val[k], prefix+unit, unit)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, key+": "+value)
+		}
+	case []interface{}:
+		open, close = "[", "]"
+		for _, e := range val {
+			text, err := formatJSON(e, prefix+unit, unit)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, text)
+		}
+	default:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err != nil {
+			return "", fmt.Errorf("marshaling JSON: %w", err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	}
+
+	switch {
+	case len(parts) == 0:
+		return open + close, nil
+	case unit == "":
+		return open + strings.Join(parts, ", ") + close, nil
+	}
+	indent := "\n" + prefix + unit
+	return open + indent + strings.Join(parts, ","+indent) + "\n" + prefix + close, nil
+}
+
+// detectIndent returns the indentation of the first indented line, two
+// spaces if there is none.
+func detectIndent(src []byte) string {
+	for _, line := range strings.Split(string(src), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && trimmed != "\r" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+// jsonEdit replaces src[start:end] with text.
+type jsonEdit struct {
+	start, end int
+	text       string
+}
+
+type jsonPatcher struct {
+	src     []byte
+	unit    string // one level of indentation
+	newline string
+	edits   []jsonEdit
+}
+
+// apply returns src with the edits made. Edits don't overlap; at the same
+// position, a removal goes before an insertion.
+func (p *jsonPatcher) apply() []byte {
+	sort.Slice(p.edits, func(i, j int) bool {
+		if p.edits[i].start != p.edits[j].start {
+			return p.edits[i].start > p.edits[j].start
+		}
+		return p.edits[i].end > p.edits[j].end
+	})
+	out := append([]byte(nil), p.src...)
+	for _, e := range p.edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out
+}
+
+// lineIndent returns the whitespace at the start of the line holding pos.
+func (p *jsonPatcher) lineIndent(pos int) string {
+	lineStart := bytes.LastIndexByte(p.src[:pos], '\n') + 1
+	i := lineStart
+	for i < len(p.src) && (p.src[i] == ' ' || p.src[i] == '\t') {
+		i++
+	}
+	return string(p.src[lineStart:i])
+}
+
+// startsLine reports whether only whitespace comes before pos on its line.
+func (p *jsonPatcher) startsLine(pos int) bool {
+	lineStart := bytes.LastIndexByte(p.src[:pos], '\n') + 1
+	return len(bytes.TrimLeft(p.src[lineStart:pos], " \t")) == 0
+}
+
+// isCompact reports whether container n is written on one line. Empty
+// containers aren't: what is added to them gets lines of its own.
+func (p *jsonPatcher) isCompact(n *jsonNode) bool {
+	if len(n.members) == 0 && len(n.elems) == 0 {
+		return false
+	}
+	return !bytes.Contains(p.src[n.start:n.end], []byte("\n"))
+}
+
+// restOfLine returns where the line goes on after an item ending at pos:
+// past its comma, if it has one, and a comment after it, then the newline.
+// It reports false if anything else follows on the line.
+func (p *jsonPatcher) restOfLine(pos int) (end int, comma, ok bool) {
+	skipSpace := func() {
+		for pos < len(p.src) && (p.src[pos] == ' ' || p.src[pos] == '\t' || p.src[pos] == '\r') {
+			pos++
+		}
+	}
+	skipSpace()
+	if pos < len(p.src) && p.src[pos] == ',' {
+		comma = true
+		pos++
+		skipSpace()
+	}
+	if bytes.HasPrefix(p.src[pos:], []byte("//")) {
+		if i := bytes.IndexByte(p.src[pos:], '\n'); i >= 0 {
+			pos += i
+		}
+	}
+	if pos < len(p.src) && p.src[pos] == '\n' {
+		return pos + 1, comma, true
+	}
+	return 0, false, false
+}
+
+// commaAfter returns the position of the comma after an item ending at
+// pos, or -1 if there is none.
+func (p *jsonPatcher) commaAfter(pos int) int {
+	q := &jsonParser{src: p.src, pos: pos}
+	if q.skip() != nil || q.pos >= len(p.src) || p.src[q.pos] != ',' {
+		return -1
+	}
+	return q.pos
+}
+
+// format marshals v to go at indentation indent, on one line if compact.
+func (p *jsonPatcher) format(v interface{}, indent string, compact bool) (string, error) {
+	unit := p.unit
+	if compact {
+		unit = ""
+	}
+	text, err := formatJSON(v, indent, unit)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(text, "\n", p.newline), nil
+}
+
+// diff records the edits that turn node n into v. compact says n sits in
+// a container written on one line.
+func (p *jsonPatcher) diff(n *jsonNode, v interface{}, compact bool) error {
+	if reflect.DeepEqual(n.value(), v) {
+		return nil
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if n.kind == '{' {
+			return p.diffObject(n, val)
+		}
+	case []interface{}:
+		if n.kind == '[' {
+			return p.diffArray(n, val)
+		}
+	}
+	text, err := p.format(v, p.lineIndent(n.start), compact)
+	if err != nil {
+		return err
+	}
+	p.edits = append(p.edits, jsonEdit{n.start, n.end, text})
+	return nil
+}
+
+// jsonItem is an object member or array element: src[start:end] is all of
+// it, node its value.
+type jsonItem struct {
+	start, end int
+	node       *jsonNode
+}
+
+func (p *jsonPatcher) diffObject(n *jsonNode, v map[string]interface{}) error {
+	compact := p.isCompact(n)
+	items := make([]jsonItem, len(n.members))
+	keep := make([]bool, len(n.members))
+	seen := map[string]bool{}
+	for i, m := range n.members {
+		items[i] = jsonItem{m.start, m.value.end, m.value}
+		_, keep[i] = v[m.key]
+		seen[m.key] = true
+	}
+	// A repeated key is read as its last value, so only that one is edited.
+	last := map[string]int{}
+	for i, m := range n.members {
+		last[m.key] = i
+	}
+	for i, m := range n.members {
+		if keep[i] && last[m.key] == i {
+			if err := p.diff(m.value, v[m.key], compact); err != nil {
+				return err
+			}
+		}
+	}
+
+	var added []string
+	for _, key := range objectKeys(v) {
+		if !seen[key] {
+			added = append(added, key)
+		}
+	}
+	indent := p.itemIndent(n, items)
+	adds := make([]string, len(added))
+	for i, key := range added {
+		k, _ := formatJSON(key, "", "")
+		value, err := p.format(v[key], indent, compact)
+		if err != nil {
+			return err
+		}
+		adds[i] = k + ": " + value
+	}
+	return p.editItems(n, items, keep, map[int][]string{len(items) - 1: adds}, compact)
+}
+
+func (p *jsonPatcher) diffArray(n *jsonNode, v []interface{}) error {
+	compact := p.isCompact(n)
+	old := make([]interface{}, len(n.elems))
+	items := make([]jsonItem, len(n.elems))
+	for i, e := range n.elems {
+		old[i] = e.value()
+		items[i] = jsonItem{e.start, e.end, e}
+	}
+
+	// Elements found unchanged in both, in order, are left alone. Between
+	// them, old and new elements are paired up and diffed in turn, so an
+	// element that changed keeps its formatting; the rest are removed or
+	// added there.
+	keep := make([]bool, len(items))
+	adds := map[int][]string{}
+	indent := p.itemIndent(n, items)
+	oi, ni := 0, 0
+	for _, match := range append(lcsPairs(old, v), [2]int{len(old), len(v)}) {
+		for ; oi < match[0] && ni < match[1]; oi, ni = oi+1, ni+1 {
+			keep[oi] = true
+			if err := p.diff(n.elems[oi], v[ni], compact); err != nil {
+				return err
+			}
+		}
+		// The remaining new elements go after the last element kept so far.
+		after := -1
+		for i := oi - 1; i >= 0; i-- {
+			if keep[i] {
+				after = i
+				break
+			}
+		}
+		for ; ni < match[1]; ni++ {
+			text, err := p.format(v[ni], indent, compact)
+			if err != nil {
+				return err
+			}
+			adds[after] = append(adds[after], text)
+		}
+		oi = match[0]
+		if match[0] < len(old) {
+			keep[oi] = true
+			oi, ni = oi+1, ni+1
+		}
+	}
+	return p.editItems(n, items, keep, adds, compact)
+}
+
+// lcsPairs returns the index pairs of a longest common subsequence of
+// equal elements of a and b.
+func lcsPairs(a, b []interface{}) [][2]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if reflect.DeepEqual(a[i], b[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case reflect.DeepEqual(a[i], b[j]):
+			pairs = append(pairs, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// itemIndent returns the indentation for items of container n: that of
+// its first item when it starts a line, one level deeper than n otherwise.
+func (p *jsonPatcher) itemIndent(n *jsonNode, items []jsonItem) string {
+	if len(items) > 0 && p.startsLine(items[0].start) {
+		return p.lineIndent(items[0].start)
+	}
+	return p.lineIndent(n.start) + p.unit
+}
+
+// editItems removes the items of container n that aren't kept and inserts
+// the texts in adds after the kept item at their index, or first for -1.
+// Separators, comments and whitespace around the other items stay put.
+func (p *jsonPatcher) editItems(n *jsonNode, items []jsonItem, keep []bool, adds map[int][]string, compact bool) error {
+	var kept []int
+	for i := range items {
+		if keep[i] {
+			kept = append(kept, i)
+		}
+	}
+	sep := "," + p.newline + p.itemIndent(n, items)
+	if compact {
+		sep = ", "
+	}
+
+	if len(kept) == 0 {
+		// Nothing of the old contents survives: write the container anew.
+		texts := adds[-1]
+		if len(items) > 0 {
+			texts = append(texts, adds[len(items)-1]...)
+		}
+		open, close := string(p.src[n.start]), string(p.src[n.end-1])
+		text := open + close
+		switch {
+		case len(texts) > 0 && compact && len(items) > 0:
+			text = open + strings.Join(texts, sep) + close
+		case len(texts) > 0:
+			indent := p.lineIndent(n.start) + p.unit
+			sep = "," + p.newline + indent
+			text = open + p.newline + indent + strings.Join(texts, sep) + p.newline + p.lineIndent(n.start) + close
+		}
+		p.edits = append(p.edits, jsonEdit{n.start, n.end, text})
+		return nil
+	}
+
+	// Runs of removed items on lines of their own go with those lines, and
+	// a comment after them on the last one. Other runs go with the
+	// separator before them, or the one after them at the front.
+	for i := 0; i < len(items); {
+		if keep[i] {
+			i++
+			continue
+		}
+		j := i
+		for j+1 < len(items) && !keep[j+1] {
+			j++
+		}
+		end, comma, ok := p.restOfLine(items[j].end)
+		switch {
+		case !compact && ok && p.startsLine(items[i].start):
+			start := bytes.LastIndexByte(p.src[:items[i].start], '\n') + 1
+			p.edits = append(p.edits, jsonEdit{start, end, ""})
+			if j == len(items)-1 && !comma && i > 0 {
+				// The item before is now last: drop its comma.
+				if c := p.commaAfter(items[i-1].end); c >= 0 {
+					p.edits = append(p.edits, jsonEdit{c, c + 1, ""})
+				}
+			}
+		case i > 0:
+			p.edits = append(p.edits, jsonEdit{items[i-1].end, items[j].end, ""})
+		default:
+			p.edits = append(p.edits, jsonEdit{items[0].start, items[j+1].start, ""})
+		}
+		i = j + 1
+	}
+
+	for after, texts := range adds {
+		if len(texts) == 0 {
+			continue
+		}
+		// Adds after a removed item go after the kept one before it.
+		for after >= 0 && !keep[after] {
+			after--
+		}
+		if after < 0 {
+			first := items[kept[0]]
+			p.edits = append(p.edits, jsonEdit{first.start, first.start, strings.Join(texts, sep) + sep})
+			continue
+		}
+		p.edits = append(p.edits, jsonEdit{items[after].end, items[after].end, sep + strings.Join(texts, sep)})
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEditJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		v    string // the new value, as JSON
+		want string
+	}{
+		{
+			name: "changes a value in place",
+			src:  "{\n  \"zebra\": 1,\n  \"apple\": 2\n}\n",
+			v:    `{"zebra": 1, "apple": 3}`,
+			want: "{\n  \"zebra\": 1,\n  \"apple\": 3\n}\n",
+		},
+		{
+			name: "keeps comments and trailing commas",
+			src:  "{\n  // theme\n  \"theme\": \"dark\", /* why */\n  \"model\": \"opus\",\n}\n",
+			v:    `{"theme": "light", "model": "opus"}`,
+			want: "{\n  // theme\n  \"theme\": \"light\", /* why */\n  \"model\": \"opus\",\n}\n",
+		},
+		{
+			name: "adds keys at the end, indented like the file",
+			src:  "{\n    \"zebra\": 1\n}\n",
+			v:    `{"zebra": 1, "hooks": {"Stop": []}}`,
+			want: "{\n    \"zebra\": 1,\n    \"hooks\": {\n        \"Stop\": []\n    }\n}\n",
+		},
+		{
+			name: "removes the first, middle and last keys",
+			src:  "{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3,\n  \"d\": 4,\n  \"e\": 5\n}\n",
+			v:    `{"b": 2, "d": 4}`,
+			want: "{\n  \"b\": 2,\n  \"d\": 4\n}\n",
+		},
+		{
+			name: "removes every key",
+			src:  "{\n  \"a\": 1\n}\n",
+			v:    `{}`,
+			want: "{}\n",
+		},
+		{
+			name: "fills an empty object",
+			src:  "{}\n",
+			v:    `{"a": [1]}`,
+			want: "{\n  \"a\": [\n    1\n  ]\n}\n",
+		},
+		{
+			name: "edits array elements without touching the others",
+			src:  "{\n  \"list\": [\n    {\"x\": 1},   // one\n    {\"x\": 2},\n    {\"x\": 3}\n  ]\n}\n",
+			v:    `{"list": [{"x": 1}, {"x": 3}, {"x": 4}]}`,
+			want: "{\n  \"list\": [\n    {\"x\": 1},   // one\n    {\"x\": 3},\n    {\n      \"x\": 4\n    }\n  ]\n}\n",
+		},
+		{
+			name: "changes a nested value in a one-line object",
+			src:  "{\"a\": {\"b\": 1, \"c\": 2}}",
+			v:    `{"a": {"b": 1, "c": 3, "d": [true]}}`,
+			want: "{\"a\": {\"b\": 1, \"c\": 3, \"d\": [true]}}\n",
+		},
+		{
+			name: "prepends to an array",
+			src:  "[\n  2,\n  3\n]\n",
+			v:    `[1, 2, 3]`,
+			want: "[\n  1,\n  2,\n  3\n]\n",
+		},
+		{
+			name: "keeps tabs and Windows line endings",
+			src:  "{\r\n\t\"a\": 1\r\n}\r\n",
+			v:    `{"a": 1, "b": {"c": "&"}}`,
+			want: "{\r\n\t\"a\": 1,\r\n\t\"b\": {\r\n\t\t\"c\": \"&\"\r\n\t}\r\n}\r\n",
+		},
+		{
+			name: "writes a blank file fresh",
+			src:  "  \n",
+			v:    `{"b": 1, "a": 2}`,
+			want: "{\n  \"a\": 2,\n  \"b\": 1\n}\n",
+		},
+		{
+			name: "writes type and matcher first in new objects",
+			src:  "{\n  \"Stop\": []\n}\n",
+			v:    `{"Stop": [{"hooks": [{"command": "x", "type": "command"}], "matcher": ""}]}`,
+			want: "{\n  \"Stop\": [\n    {\n      \"matcher\": \"\",\n      \"hooks\": [\n        {\n          \"type\": \"command\",\n          \"command\": \"x\"\n        }\n      ]\n    }\n  ]\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(tt.v), &v); err != nil {
+				t.Fatal(err)
+			}
+			got, err := editJSON([]byte(tt.src), v)
+			if err != nil {
+				t.Fatalf("editJSON: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("editJSON =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditJSON_unchanged(t *testing.T) {
+	src := "{\n  /* kept */ \"b\": [1, 2],\n  \"a\": {\"n\": 1.50}\n}"
+	got, err := editJSON([]byte(src), map[string]interface{}{
+		"a": map[string]interface{}{"n": 1.5},
+		"b": []interface{}{1, 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != src+"\n" {
+		t.Errorf("editJSON = %q, want the source unchanged", got)
+	}
+}
+
+func TestParseJSON_errors(t *testing.T) {
+	tests := []struct {
+		src        string
+		line, col  int
+		wantSubstr string
+	}{
+		{`{"a": 1`, 1, 8, "expected ',' or '}'"},
+		{"{\n  \"a\": 1\n  \"b\": 2\n}", 3, 3, "expected ',' or '}'"},
+		{"{\n  \"é\": tru\n}", 2, 8, "expected a value"},
+		{`{"a": [1, 2}`, 1, 12, "expected ',' or ']'"},
+		{`{"a": "x` + "\n" + `"}`, 1, 7, "unterminated string"},
+		{`{"a": 01}`, 1, 7, "invalid number"},
+		{"{} /* never closed", 1, 4, "unterminated comment"},
+		{`{} {}`, 1, 4, "after the end of the document"},
+	}
+	for _, tt := range tests {
+		_, err := parseJSON([]byte(tt.src))
+		var syntaxErr *JSONSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("parseJSON(%q) = %v, want a JSONSyntaxError", tt.src, err)
+			continue
+		}
+		if syntaxErr.Line != tt.line || syntaxErr.Column != tt.col {
+			t.Errorf("parseJSON(%q) at line %d, column %d, want %d, %d", tt.src, syntaxErr.Line, syntaxErr.Column, tt.line, tt.col)
+		}
+		if !strings.Contains(syntaxErr.Msg, tt.wantSubstr) {
+			t.Errorf("parseJSON(%q) = %q, want %q", tt.src, syntaxErr.Msg, tt.wantSubstr)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SettingsBackupDir is the directory, next to a settings file, that keeps
+// the versions of it Ghost Tab replaced.
+const SettingsBackupDir = "ghost-tab-backups"
+
+// settingsBackups is how many versions of each settings file are kept.
+const settingsBackups = 10
+
+// readSettingsFile reads and parses a JSON settings file, which may have
+// comments and trailing commas. Returns the parsed map and whether the
+// file existed. A missing or blank file reads as empty settings; one that
+// isn't valid JSON is an error naming the line and column, so it is never
+// replaced by settings that drop what the user wrote.
+func readSettingsFile(path string) (map[string]interface{}, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]interface{}), false, nil
+		}
+		return nil, false, fmt.Errorf("reading settings file: %w", err)
+	}
+
+	root, err := parseSettings(path, data)
+	if err != nil {
+		return nil, true, err
+	}
+	if root == nil {
+		return make(map[string]interface{}), true, nil
+	}
+	return root.value().(map[string]interface{}), true, nil
+}
+
+// parseSettings parses settings file data, returning nil for a blank file.
+func parseSettings(path string, data []byte) (*jsonNode, error) {
+	root, err := parseJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid JSON, leaving it alone: %w", path, err)
+	}
+	if root != nil && root.kind != '{' {
+		return nil, fmt.Errorf("%s is not valid settings, leaving it alone: expected a JSON object", path)
+	}
+	return root, nil
+}
+
+// writeSettingsFile saves settings to the file, changing only the values
+// that differ from what is there: key order, indentation and comments are
+// kept, and new keys are added at the end of their object. A missing or
+// blank file is written with 2-space indentation. Characters such as & in
+// hook commands are written as is, not escaped for HTML.
+//
+// The file is replaced atomically, after the version it replaces is copied
+// to SettingsBackupDir. A file that isn't valid JSON or isn't writable is
+// left alone and reported as an error.
+func writeSettingsFile(path string, settings map[string]interface{}) error {
+	// Write through a symlink, e.g. to a dotfiles repository, rather than
+	// replacing it.
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+
+	mode := os.FileMode(0644)
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+		if mode&0200 == 0 {
+			return fmt.Errorf("writing settings file: %s is read-only", path)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("writing settings file: %w", err)
+	}
+
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading settings file: %w", err)
+	}
+	if _, err := parseSettings(path, old); err != nil {
+		return err
+	}
+	data, err := editJSON(old, settings)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(old)) == 0 {
+		old = nil
+	}
+	if old != nil && bytes.Equal(data, old) {
+		return nil
+	}
+
+	if old != nil {
+		if err := backupSettingsFile(path, old); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(path, data, mode); err != nil {
+		return fmt.Errorf("writing settings file: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data through a temporary file in the
+// same directory, so readers never see a partly written file.
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// SettingsBackups returns the saved versions of the settings file at path,
+// oldest first.
+func SettingsBackups(path string) ([]string, error) {
+	dir := filepath.Join(filepath.Dir(path), SettingsBackupDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	prefix := filepath.Base(path) + "."
+	var backups []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			backups = append(backups, filepath.Join(dir, e.Name()))
+		}
+	}
+	// Timestamps are written so that names sort by age.
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// backupSettingsFile saves data, the current contents of the settings file
+// at path, as path's name plus a timestamp in SettingsBackupDir, then
+// removes all but the newest settingsBackups versions.
+func backupSettingsFile(path string, data []byte) error {
+	dir := filepath.Join(filepath.Dir(path), SettingsBackupDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("backing up settings file: %w", err)
+	}
+
+	name := filepath.Join(dir, filepath.Base(path)+"."+time.Now().Format("20060102-150405.000000"))
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	for i := 2; os.IsExist(err); i++ {
+		f, err = os.OpenFile(fmt.Sprintf("%s-%d", name, i), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	}
+	if err != nil {
+		return fmt.Errorf("backing up settings file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("backing up settings file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("backing up settings file: %w", err)
+	}
+
+	backups, err := SettingsBackups(path)
+	if err != nil {
+		return fmt.Errorf("backing up settings file: %w", err)
+	}
+	for len(backups) > settingsBackups {
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+	return nil
+}
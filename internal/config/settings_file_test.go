@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const orderedSettings = `{
+  // Set by hand.
+  "model": "opus",
+  "env": {"ZED": "1", "ALPHA": "2"},
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "echo done"}]}
+    ]
+  },
+  "alwaysThinkingEnabled": true,
+}
+`
+
+func TestWriteSettingsFile_keeps_order_and_comments(t *testing.T) {
+	path := writeSettings(t, orderedSettings)
+	if _, err := AddHook(path, Hook{Event: "Stop", Command: "echo again"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  // Set by hand.
+  "model": "opus",
+  "env": {"ZED": "1", "ALPHA": "2"},
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "echo done"}, {"type": "command", "command": "echo again"}]}
+    ]
+  },
+  "alwaysThinkingEnabled": true,
+}
+`
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("settings =\n%s\nwant\n%s", data, want)
+	}
+
+	if _, err := RemoveHooks(path, func(h Hook) bool { return h.Command == "echo again" }); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != orderedSettings {
+		t.Errorf("settings after removing the hook =\n%s\nwant\n%s", data, orderedSettings)
+	}
+}
+
+func TestWriteSettingsFile_backs_up(t *testing.T) {
+	path := writeSettings(t, orderedSettings)
+	if _, err := AddHook(path, Hook{Event: "Stop", Command: "echo again"}); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := SettingsBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("got %d backups, want 1", len(backups))
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != orderedSettings {
+		t.Errorf("backup =\n%s\nwant the previous settings", data)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if e.Name() != "settings.json" && e.Name() != SettingsBackupDir {
+			t.Errorf("unexpected file %s left next to the settings", e.Name())
+		}
+	}
+}
+
+func TestWriteSettingsFile_keeps_the_newest_backups(t *testing.T) {
+	path := writeSettings(t, "{}\n")
+	for i := 0; i < settingsBackups+3; i++ {
+		if err := writeSettingsFile(path, map[string]interface{}{"n": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := SettingsBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != settingsBackups {
+		t.Fatalf("got %d backups, want %d", len(backups), settingsBackups)
+	}
+	// The last write replaced n = settingsBackups+1.
+	if data, _ := os.ReadFile(backups[len(backups)-1]); !strings.Contains(string(data), `"n": 11`) {
+		t.Errorf("newest backup = %s", data)
+	}
+}
+
+func TestWriteSettingsFile_no_backup_without_changes(t *testing.T) {
+	path := writeSettings(t, hooksFixture)
+	if _, err := AddHook(path, Hook{Event: "Stop", Command: "echo done"}); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := SettingsBackups(path); len(backups) != 0 {
+		t.Errorf("got backups %v for an unchanged file", backups)
+	}
+}
+
+func TestWriteSettingsFile_keeps_mode_and_symlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "settings.json")
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeSettingsFile(path, map[string]interface{}{"model": "opus"}); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("settings.json should still be a symlink (%v)", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(target); !strings.Contains(string(data), "opus") {
+		t.Errorf("target = %s", data)
+	}
+}
+
+func TestWriteSettingsFile_refuses_invalid_files(t *testing.T) {
+	content := "{\n  \"model\": \"opus\"\n  \"theme\": \"dark\"\n}\n"
+	path := writeSettings(t, content)
+
+	_, err := AddHook(path, Hook{Event: "Stop", Command: "echo done"})
+	if err == nil || !strings.Contains(err.Error(), "line 3, column 3") || !strings.Contains(err.Error(), path) {
+		t.Fatalf("expected an error naming the file, line and column, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("invalid file should be left alone, got\n%s", data)
+	}
+	if backups, _ := SettingsBackups(path); len(backups) != 0 {
+		t.Errorf("got backups %v of an invalid file", backups)
+	}
+}
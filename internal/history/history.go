@@ -0,0 +1,249 @@
+// Package history records project launches so the menu can order projects
+// by recency or frequency and `ghost-tab-tui history` can list them.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// FileName is the history file's name in the ghost-tab config directory.
+const FileName = "history.jsonl"
+
+// DefaultPath returns the history file in the ghost-tab config directory,
+// ${XDG_CONFIG_HOME:-~/.config}/ghost-tab.
+func DefaultPath() string {
+	return filepath.Join(util.ConfigDir(), FileName)
+}
+
+// MaxEntries is how many launches are kept. Older ones are dropped when
+// the file is compacted.
+const MaxEntries = 500
+
+// Entry is one launch of a project.
+type Entry struct {
+	ID      string `json:"id"`
+	Project string `json:"project"`
+	// Path is the directory that was launched: the project itself or one
+	// of its worktrees.
+	Path      string     `json:"path"`
+	AITool    string     `json:"ai_tool,omitempty"`
+	Session   string     `json:"session,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+// Duration returns how long the session was attached, or 0 if it has not
+// ended (or its end was never recorded).
+func (e Entry) Duration() time.Duration {
+	if e.EndedAt == nil {
+		return 0
+	}
+	return e.EndedAt.Sub(e.StartedAt)
+}
+
+// endRecord is the line appended when a session ends. Full entries are
+// written at launch; parse merges the two by ID.
+type endRecord struct {
+	ID      string    `json:"id"`
+	EndedAt time.Time `json:"ended_at"`
+}
+
+// Store is the append-only history file. Each launch appends a line and
+// each session end appends another, so concurrent tabs never rewrite each
+// other's records. Appends share a lock that compaction takes exclusively,
+// so a record can't be appended to a file that is about to be replaced.
+type Store struct {
+	path string
+}
+
+// NewStore returns the store backed by the file at path.
+func NewStore(path string) Store {
+	return Store{path: path}
+}
+
+// Path returns the history file path.
+func (s Store) Path() string { return s.path }
+
+// Start records the beginning of a launch and returns the entry with its
+// ID and start time filled in.
+func (s Store) Start(e Entry) (Entry, error) {
+	if e.StartedAt.IsZero() {
+		e.StartedAt = time.Now()
+	}
+	if e.ID == "" {
+		e.ID = strconv.FormatInt(e.StartedAt.UnixNano(), 36) + "-" + strconv.Itoa(os.Getpid())
+	}
+	e.EndedAt = nil
+	if err := s.append(e); err != nil {
+		return e, err
+	}
+	return e, s.compact()
+}
+
+// Finish records the end of the launch with the given ID.
+func (s Store) Finish(id string, at time.Time) error {
+	return s.append(endRecord{ID: id, EndedAt: at})
+}
+
+// append writes v as one JSON line at the end of the file.
+func (s Store) append(v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	unlock, err := s.lock(syscall.LOCK_SH)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load returns all recorded launches, oldest first. A missing file is an
+// empty history. Malformed lines are skipped.
+func (s Store) Load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	return parse(data), nil
+}
+
+// parse merges start and end lines into entries in start order.
+func parse(data []byte) []Entry {
+	var entries []Entry
+	byID := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Entry
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.ID == "" {
+			continue
+		}
+		if i, ok := byID[r.ID]; ok {
+			if r.EndedAt != nil {
+				entries[i].EndedAt = r.EndedAt
+			}
+			continue
+		}
+		if r.StartedAt.IsZero() {
+			// End of a launch that was compacted away
+			continue
+		}
+		byID[r.ID] = len(entries)
+		entries = append(entries, r)
+	}
+	return entries
+}
+
+// lock takes a flock of the given kind on the file next to the history
+// file. The history file itself can't be locked because compaction
+// replaces it.
+func (s Store) lock(how int) (unlock func(), err error) {
+	f, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock history file: %w", err)
+	}
+	return func() { f.Close() }, nil
+}
+
+// compact rewrites the file with only the newest MaxEntries launches once
+// it has grown well past the limit.
+func (s Store) compact() error {
+	unlock, err := s.lock(syscall.LOCK_EX)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := s.Load()
+	if err != nil || len(entries) <= 2*MaxEntries {
+		return err
+	}
+	entries = entries[len(entries)-MaxEntries:]
+
+	var buf bytes.Buffer
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Recent returns up to n entries, newest first. n <= 0 means all.
+func Recent(entries []Entry, n int) []Entry {
+	recent := make([]Entry, len(entries))
+	copy(recent, entries)
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].StartedAt.After(recent[j].StartedAt)
+	})
+	if n > 0 && len(recent) > n {
+		recent = recent[:n]
+	}
+	return recent
+}
+
+// Usage summarises how a project has been used.
+type Usage struct {
+	Launches int
+	LastUsed time.Time
+}
+
+// Summarize returns the usage of each project, keyed by project name.
+func Summarize(entries []Entry) map[string]Usage {
+	usage := make(map[string]Usage)
+	for _, e := range entries {
+		u := usage[e.Project]
+		u.Launches++
+		if e.StartedAt.After(u.LastUsed) {
+			u.LastUsed = e.StartedAt
+		}
+		usage[e.Project] = u
+	}
+	return usage
+}
@@ -0,0 +1,196 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_StartAndFinish(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "ghost-tab", "history.jsonl"))
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	e, err := store.Start(Entry{Project: "api", Path: "/srv/api", AITool: "claude", Session: "dev-api-1", StartedAt: start})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if e.ID == "" {
+		t.Fatal("Start should assign an ID")
+	}
+
+	entries, _ := store.Load()
+	if len(entries) != 1 || entries[0].EndedAt != nil {
+		t.Fatalf("expected one open entry, got %+v", entries)
+	}
+
+	if err := store.Finish(e.ID, start.Add(90*time.Minute)); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	entries, _ = store.Load()
+	if len(entries) != 1 {
+		t.Fatalf("end record should merge into the launch, got %d entries", len(entries))
+	}
+	got := entries[0]
+	if got.Project != "api" || got.Path != "/srv/api" || got.AITool != "claude" || got.Session != "dev-api-1" {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+	if got.Duration() != 90*time.Minute {
+		t.Errorf("Duration = %v, want 90m", got.Duration())
+	}
+}
+
+func TestStore_LoadMissingFile(t *testing.T) {
+	entries, err := NewStore(filepath.Join(t.TempDir(), "nope.jsonl")).Load()
+	if err != nil || entries != nil {
+		t.Errorf("missing file should be empty history, got %v, %v", entries, err)
+	}
+}
+
+func TestParse_SkipsMalformedAndOrphanedLines(t *testing.T) {
+	data := strings.Join([]string{
+		`{"id":"a","project":"api","path":"/srv/api","started_at":"2026-01-02T10:00:00Z"}`,
+		`not json`,
+		`{"id":"gone","ended_at":"2026-01-02T09:00:00Z"}`,
+		`{"project":"no-id","started_at":"2026-01-02T10:00:00Z"}`,
+		`{"id":"b","project":"web","path":"/srv/web","started_at":"2026-01-02T11:00:00Z"}`,
+		`{"id":"a","ended_at":"2026-01-02T10:30:00Z"}`,
+	}, "\n")
+
+	entries := parse([]byte(data))
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].ID != "a" || entries[0].Duration() != 30*time.Minute {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].ID != "b" || entries[1].EndedAt != nil {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestStore_Compacts(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= 2*MaxEntries; i++ {
+		if _, err := store.Start(Entry{ID: time.Duration(i).String(), Project: "p", StartedAt: start.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+	}
+
+	entries, _ := store.Load()
+	if len(entries) != MaxEntries {
+		t.Fatalf("expected %d entries after compaction, got %d", MaxEntries, len(entries))
+	}
+	if want := start.Add(time.Duration(2*MaxEntries) * time.Minute); !entries[len(entries)-1].StartedAt.Equal(want) {
+		t.Errorf("newest entry should be kept, got %v", entries[len(entries)-1].StartedAt)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(store.Path()), ".history-*"))
+	if len(leftovers) != 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestStore_CompactKeepsConcurrentAppends(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Grow the file past the limit without compacting it.
+	var buf bytes.Buffer
+	for i := 0; i <= 20*MaxEntries; i++ {
+		data, _ := json.Marshal(Entry{ID: fmt.Sprintf("old-%d", i), Project: "p", StartedAt: start})
+		buf.Write(append(data, '\n'))
+	}
+	if err := os.WriteFile(store.Path(), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Other tabs keep launching and finishing until compaction is done,
+	// too few times for their launches to be compacted away.
+	done := make(chan struct{})
+	written := make([][]string, 4)
+	var wg sync.WaitGroup
+	for tab := range written {
+		wg.Add(1)
+		go func(tab int) {
+			defer wg.Done()
+			for i := 0; i < MaxEntries/len(written); i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				id := fmt.Sprintf("new-%d-%d", tab, i)
+				if err := store.append(Entry{ID: id, Project: "p", StartedAt: start.Add(time.Hour)}); err != nil {
+					t.Errorf("append: %v", err)
+					return
+				}
+				if err := store.Finish(id, start.Add(2*time.Hour)); err != nil {
+					t.Errorf("Finish: %v", err)
+					return
+				}
+				written[tab] = append(written[tab], id)
+				time.Sleep(100 * time.Microsecond)
+			}
+		}(tab)
+	}
+	err := store.compact()
+	close(done)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+
+	entries, _ := store.Load()
+	ended := map[string]bool{}
+	for _, e := range entries {
+		ended[e.ID] = e.EndedAt != nil
+	}
+	for _, ids := range written {
+		for _, id := range ids {
+			if !ended[id] {
+				t.Fatalf("launch %s or its end was lost during compaction", id)
+			}
+		}
+	}
+}
+
+func TestRecent(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{ID: "1", StartedAt: base},
+		{ID: "2", StartedAt: base.Add(2 * time.Hour)},
+		{ID: "3", StartedAt: base.Add(time.Hour)},
+	}
+
+	recent := Recent(entries, 2)
+	if len(recent) != 2 || recent[0].ID != "2" || recent[1].ID != "3" {
+		t.Errorf("Recent(2) = %+v", recent)
+	}
+	if all := Recent(entries, 0); len(all) != 3 || all[2].ID != "1" {
+		t.Errorf("Recent(0) should return everything newest first, got %+v", all)
+	}
+	if entries[0].ID != "1" {
+		t.Error("Recent should not reorder its input")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	usage := Summarize([]Entry{
+		{Project: "api", StartedAt: base.Add(time.Hour)},
+		{Project: "web", StartedAt: base},
+		{Project: "api", StartedAt: base},
+	})
+	if u := usage["api"]; u.Launches != 2 || !u.LastUsed.Equal(base.Add(time.Hour)) {
+		t.Errorf("api usage = %+v", u)
+	}
+	if u := usage["web"]; u.Launches != 1 {
+		t.Errorf("web usage = %+v", u)
+	}
+}
@@ -1,72 +1,140 @@
 package models
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/aitools"
+)
+
+// Health of an AI tool, as reported in AITool.Status.
+const (
+	ToolOK       = "ok"
+	ToolOutdated = "outdated"
+	ToolBroken   = "broken"
+	ToolMissing  = "missing"
 )
 
+// ToolCheckTimeout bounds how long a tool's version command may run.
+const ToolCheckTimeout = 3 * time.Second
+
 // AITool represents an AI coding assistant
 type AITool struct {
-	Name      string
-	Command   string
-	Installed bool
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Command     string `json:"command"`
+	Path        string `json:"path,omitempty"`
+	Installed   bool   `json:"installed"`
+	Version     string `json:"version,omitempty"`
+	MinVersion  string `json:"min_version,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Usable reports whether the tool can be launched: it is installed and
+// its version command didn't fail. Outdated tools are still usable.
+func (t AITool) Usable() bool {
+	return t.Installed && t.Status != ToolBroken
 }
 
 // String returns display string for AI tool
 func (t AITool) String() string {
-	displayName := t.Name
-	switch t.Name {
-	case "claude":
-		displayName = "Claude Code"
-	case "codex":
-		displayName = "Codex CLI"
-	case "copilot":
-		displayName = "Copilot CLI"
-	case "opencode":
-		displayName = "OpenCode"
+	name := DisplayName(t.Name)
+	switch {
+	case !t.Installed:
+		return name + " (not installed)"
+	case t.Status == ToolBroken:
+		return name + " (broken)"
+	case t.Status == ToolOutdated:
+		return fmt.Sprintf("%s %s ⚠ (needs %s+)", name, t.Version, t.MinVersion)
+	case t.Version != "":
+		return name + " " + t.Version + " ✓"
 	}
+	return name + " ✓"
+}
+
+// DetectAITools checks which of the registered AI tools are installed and
+// runs their version commands in parallel.
+func DetectAITools() []AITool {
+	return CheckTools(aitools.Current().Tools(), ToolCheckTimeout)
+}
 
-	if t.Installed {
-		return displayName + " ✓"
+// CheckTools runs CheckTool on each of tools in parallel, keeping their order.
+func CheckTools(tools []aitools.Tool, timeout time.Duration) []AITool {
+	results := make([]AITool, len(tools))
+	var wg sync.WaitGroup
+	for i, t := range tools {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = CheckTool(t, timeout)
+		}()
 	}
-	return displayName + " (not installed)"
+	wg.Wait()
+	return results
 }
 
-// DetectAITools checks which AI tools are installed
-func DetectAITools() []AITool {
-	tools := []AITool{
-		{Name: "claude", Command: "claude"},
-		{Name: "codex", Command: "codex"},
-		{Name: "copilot", Command: "gh copilot"},
-		{Name: "opencode", Command: "opencode"},
+// CheckTool looks t up on PATH and runs its version command. A tool whose
+// command exits non-zero or doesn't finish within timeout is broken; one
+// older than its minimum version is outdated. Output without a version
+// number isn't an error, since not every tool prints one.
+func CheckTool(t aitools.Tool, timeout time.Duration) AITool {
+	tool := AITool{
+		Name:        t.Name,
+		DisplayName: t.DisplayName,
+		Command:     t.Command,
+		MinVersion:  t.MinVersion,
+		Status:      ToolMissing,
 	}
+	path, err := exec.LookPath(t.Command)
+	if err != nil {
+		return tool
+	}
+	tool.Path = path
+	tool.Installed = true
 
-	for i := range tools {
-		tools[i].Installed = isCommandAvailable(tools[i].Command)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, t.VersionCommand()...)
+	// Don't wait on children that keep the output pipe open after a kill
+	cmd.WaitDelay = 100 * time.Millisecond
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		tool.Status = ToolBroken
+		tool.Error = versionError(ctx, err, out, timeout)
+		return tool
 	}
 
-	return tools
+	tool.Version = aitools.ParseVersion(string(out))
+	tool.Status = ToolOK
+	if tool.Version != "" && t.MinVersion != "" && aitools.CompareVersions(tool.Version, t.MinVersion) < 0 {
+		tool.Status = ToolOutdated
+	}
+	return tool
 }
 
-func isCommandAvailable(command string) bool {
-	_, err := exec.LookPath(command)
-	return err == nil
+// versionError describes why a version command failed, with the first
+// line of its output when there is one.
+func versionError(ctx context.Context, err error, out []byte, timeout time.Duration) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Sprintf("no response after %s", timeout)
+	}
+	msg := err.Error()
+	if line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n"); line != "" {
+		msg += ": " + line
+	}
+	return msg
 }
 
 // DisplayName returns the human-readable name for an AI tool identifier.
 // Unknown tools pass through unchanged.
 func DisplayName(tool string) string {
-	switch tool {
-	case "claude":
-		return "Claude Code"
-	case "codex":
-		return "Codex CLI"
-	case "copilot":
-		return "Copilot CLI"
-	case "opencode":
-		return "OpenCode"
-	default:
-		return tool
-	}
+	return aitools.Current().Get(tool).DisplayName
 }
 
 // CycleTool cycles through available tools. direction=1 for next, direction=-1 for prev.
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// DefaultScanDepth is how many directory levels below each scan root are
+// searched for repositories.
+const DefaultScanDepth = 3
+
+// DefaultScanRoots are the directories scanned when none are configured.
+// Only the ones that exist are used.
+var DefaultScanRoots = []string{"~/Projects", "~/projects", "~/code", "~/src", "~/work", "~/Developer"}
+
+// skipDirs are directories never descended into while scanning.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"Library":      true,
+}
+
+// ExistingScanRoots returns the roots that exist as directories, expanded
+// and deduplicated, in order.
+func ExistingScanRoots(roots []string) []string {
+	var existing []string
+	seen := map[string]bool{}
+	for _, root := range roots {
+		root = filepath.Clean(util.ExpandPath(strings.TrimSpace(root)))
+		if seen[root] {
+			continue
+		}
+		if info, err := os.Stat(root); err == nil && info.IsDir() {
+			seen[root] = true
+			existing = append(existing, root)
+		}
+	}
+	return existing
+}
+
+// DiscoverProjects finds git repositories under roots, searching at most
+// maxDepth levels below each root (the root itself is depth 0). A directory
+// containing a .git directory is a repository and is not searched further;
+// linked worktrees and submodules (a .git file) are skipped since they
+// belong to another project. Hidden directories and symlinks are not
+// followed. Results are sorted by path and named after their directory.
+func DiscoverProjects(roots []string, maxDepth int) []Project {
+	found := map[string]bool{}
+	for _, root := range ExistingScanRoots(roots) {
+		walkForRepos(root, 0, maxDepth, found)
+	}
+
+	paths := make([]string, 0, len(found))
+	for p := range found {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	projects := make([]Project, 0, len(paths))
+	for _, p := range paths {
+		projects = append(projects, Project{Name: filepath.Base(p), Path: p})
+	}
+	return projects
+}
+
+func walkForRepos(dir string, depth, maxDepth int, found map[string]bool) {
+	if info, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+		if info.IsDir() {
+			found[dir] = true
+		}
+		return
+	}
+	if depth >= maxDepth {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		// ReadDir reports symlinks as non-directories, so they are skipped
+		if !e.IsDir() || strings.HasPrefix(name, ".") || skipDirs[name] {
+			continue
+		}
+		walkForRepos(filepath.Join(dir, name), depth+1, maxDepth, found)
+	}
+}
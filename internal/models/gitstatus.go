@@ -0,0 +1,184 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitStatus is a snapshot of a working tree's git state.
+type GitStatus struct {
+	Branch  string `json:"branch"` // "(detached)" when HEAD is detached
+	Dirty   bool   `json:"dirty"`  // modified, staged or untracked files
+	Ahead   int    `json:"ahead"`  // commits not on the upstream branch
+	Behind  int    `json:"behind"` // upstream commits not on the branch
+	Stashes int    `json:"stashes"`
+}
+
+// ParseStatusPorcelainV2 parses the output of
+// `git status --porcelain=v2 --branch`. Stashes are not part of it and are
+// left at 0.
+func ParseStatusPorcelainV2(output string) GitStatus {
+	var s GitStatus
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "# branch.head "):
+			s.Branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			for _, field := range strings.Fields(strings.TrimPrefix(line, "# branch.ab ")) {
+				n, _ := strconv.Atoi(field[1:])
+				if field[0] == '+' {
+					s.Ahead = n
+				} else {
+					s.Behind = n
+				}
+			}
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			s.Dirty = true
+		}
+	}
+	return s
+}
+
+// ReadGitStatus runs git in dir and returns its status, including the
+// number of stash entries. dir may start with ~, as project paths do.
+// Git doesn't refresh the index, so the read never takes the index lock
+// from a git command the user is running.
+func ReadGitStatus(dir string) (GitStatus, error) {
+	out, err := gitCmd(dir, "--no-optional-locks", "status", "--porcelain=v2", "--branch").Output()
+	if err != nil {
+		return GitStatus{}, err
+	}
+	s := ParseStatusPorcelainV2(string(out))
+
+	stash, err := gitCmd(dir, "--no-optional-locks", "stash", "list").Output()
+	if err == nil {
+		for _, line := range strings.Split(string(stash), "\n") {
+			if line != "" {
+				s.Stashes++
+			}
+		}
+	}
+	return s, nil
+}
+
+// DefaultGitStatusTTL is how long a cached status is reused.
+const DefaultGitStatusTTL = 15 * time.Second
+
+type gitStatusCacheEntry struct {
+	Status    GitStatus `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// GitStatusCache keeps recent git statuses in a JSON file, so menus opened
+// in many tabs at once share one git run per directory. With an empty path
+// the cache only lives in memory. It is safe for concurrent use.
+type GitStatusCache struct {
+	path    string
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]gitStatusCacheEntry
+}
+
+// NewGitStatusCache returns a cache backed by the file at path whose
+// entries expire after ttl.
+func NewGitStatusCache(path string, ttl time.Duration) *GitStatusCache {
+	return &GitStatusCache{path: path, ttl: ttl, entries: map[string]gitStatusCacheEntry{}}
+}
+
+// Get returns the cached status of dir if it is younger than the TTL.
+func (c *GitStatusCache) Get(dir string) (GitStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	e, ok := c.entries[dir]
+	if !ok || time.Since(e.CheckedAt) >= c.ttl {
+		return GitStatus{}, false
+	}
+	return e.Status, true
+}
+
+// Put stores the status of dir and writes the cache file, merging entries
+// written meanwhile by other processes and dropping expired ones.
+func (c *GitStatusCache) Put(dir string, s GitStatus) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	c.entries[dir] = gitStatusCacheEntry{Status: s, CheckedAt: time.Now()}
+	for k, e := range c.entries {
+		if time.Since(e.CheckedAt) >= c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	return c.save()
+}
+
+// Status returns the status of dir from the cache, running git and caching
+// the result when it is missing or stale.
+func (c *GitStatusCache) Status(dir string) (GitStatus, error) {
+	if s, ok := c.Get(dir); ok {
+		return s, nil
+	}
+	s, err := ReadGitStatus(dir)
+	if err != nil {
+		return s, err
+	}
+	// A cache that can't be written only costs extra git runs
+	c.Put(dir, s)
+	return s, nil
+}
+
+// load merges the cache file into memory, keeping the newer entry of each
+// directory. A missing or unreadable file is ignored.
+func (c *GitStatusCache) load() {
+	if c.path == "" {
+		return
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+	var onDisk map[string]gitStatusCacheEntry
+	if json.Unmarshal(data, &onDisk) != nil {
+		return
+	}
+	for k, e := range onDisk {
+		if e.CheckedAt.After(c.entries[k].CheckedAt) {
+			c.entries[k] = e
+		}
+	}
+}
+
+func (c *GitStatusCache) save() error {
+	if c.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".git-status-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
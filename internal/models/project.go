@@ -2,16 +2,32 @@ package models
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"os"
 	"strings"
 )
 
 // Project represents a project entry
 type Project struct {
-	Name      string
-	Path      string
-	Worktrees []Worktree
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	AITool string `json:"ai_tool,omitempty"` // default AI tool, empty means the global preference
+	// AIArgs are extra arguments passed to the AI tool on launch.
+	AIArgs []string `json:"ai_args,omitempty"`
+	// Profile is the default launch profile (e.g. "yolo"), used when the
+	// launching AI tool defines a profile of that name.
+	Profile string `json:"profile,omitempty"`
+	Layout  string `json:"layout,omitempty"` // tmux layout name, empty means the default layout
+	// Env holds environment variables exported into the project's session.
+	Env map[string]string `json:"env,omitempty"`
+	// Group collects related projects under a collapsible header in the
+	// main menu. Empty means the project is listed above all groups.
+	Group     string     `json:"group,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	Notes     string     `json:"notes,omitempty"`
+	Worktrees []Worktree `json:"-"`
+	// Status is the project's git status, nil until it has been read.
+	Status *GitStatus `json:"-"`
 }
 
 // ParseProjectName extracts the project name from a "name:path" line.
@@ -36,16 +52,20 @@ func ParseProjectPath(line string) string {
 	return line[idx+1:]
 }
 
-// LoadProjects reads projects from file (name:path format)
+// LoadProjects reads projects from file. Both the structured JSON format
+// and the legacy name:path format are accepted.
 func LoadProjects(filepath string) ([]Project, error) {
-	file, err := os.Open(filepath)
+	pf, err := ReadProjectsFile(filepath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open projects file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
+	return pf.Projects, nil
+}
 
+// parseLegacyProjects parses the legacy name:path line format.
+func parseLegacyProjects(data []byte) ([]Project, error) {
 	var projects []Project
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
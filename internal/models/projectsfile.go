@@ -0,0 +1,125 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectsFileVersion is the version written to new projects files.
+const ProjectsFileVersion = 1
+
+// LegacyBackupSuffix is appended to the legacy name:path file's name when
+// it is migrated, so the original is kept next to the new file.
+const LegacyBackupSuffix = ".legacy"
+
+// ProjectsFile is the structured, versioned projects file.
+type ProjectsFile struct {
+	Version  int       `json:"version"`
+	Projects []Project `json:"projects"`
+	// legacy is true when the file was read from the name:path format.
+	legacy bool
+}
+
+// IsLegacy reports whether the file was read from the legacy name:path
+// format and still needs migrating.
+func (pf *ProjectsFile) IsLegacy() bool { return pf.legacy }
+
+// ReadProjectsFile reads a projects file in either format. The structured
+// format is JSON; anything else is parsed as legacy name:path lines.
+func ReadProjectsFile(path string) (*ProjectsFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open projects file: %w", err)
+	}
+
+	if !isStructured(data) {
+		projects, err := parseLegacyProjects(data)
+		if err != nil {
+			return nil, err
+		}
+		return &ProjectsFile{Version: ProjectsFileVersion, Projects: projects, legacy: true}, nil
+	}
+
+	var pf ProjectsFile
+	if err := json.Unmarshal(data, &pf); err != nil {
+		return nil, fmt.Errorf("failed to parse projects file: %w", err)
+	}
+	if pf.Version > ProjectsFileVersion {
+		return nil, fmt.Errorf("projects file version %d is newer than supported version %d", pf.Version, ProjectsFileVersion)
+	}
+	return &pf, nil
+}
+
+// isStructured reports whether data holds the JSON projects format.
+func isStructured(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// WriteProjectsFile writes pf as JSON, creating parent directories. The
+// file is written to a temporary file and renamed into place so a crash
+// never leaves a truncated projects file behind.
+func WriteProjectsFile(path string, pf *ProjectsFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	out := ProjectsFile{Version: ProjectsFileVersion, Projects: pf.Projects}
+	if out.Projects == nil {
+		out.Projects = []Project{}
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".projects-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// MigrateProjectsFile rewrites a legacy name:path projects file in the
+// structured format, keeping the original as path+LegacyBackupSuffix.
+// Returns true if a migration happened. Missing or already structured
+// files are left alone.
+func MigrateProjectsFile(path string) (bool, error) {
+	pf, err := ReadProjectsFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !pf.IsLegacy() {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path+LegacyBackupSuffix, data, 0644); err != nil {
+		return false, fmt.Errorf("backing up legacy projects file: %w", err)
+	}
+	if err := WriteProjectsFile(path, pf); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -1,14 +1,20 @@
 package models
 
 import (
+	"errors"
 	"os/exec"
+	"path/filepath"
 	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/util"
 )
 
 // Worktree represents a git worktree entry.
 type Worktree struct {
 	Path   string
 	Branch string
+	// Status is the worktree's git status, nil until it has been read.
+	Status *GitStatus
 }
 
 // ParseWorktreeListPorcelain parses the output of `git worktree list --porcelain`
@@ -59,10 +65,100 @@ func PopulateWorktrees(projects []Project) {
 // DetectWorktrees runs `git worktree list --porcelain` for the given path
 // and returns non-main worktrees. Returns nil on any error.
 func DetectWorktrees(projectPath string) []Worktree {
-	cmd := exec.Command("git", "-C", projectPath, "worktree", "list", "--porcelain")
-	out, err := cmd.Output()
+	out, err := gitCmd(projectPath, "worktree", "list", "--porcelain").Output()
 	if err != nil {
 		return nil
 	}
 	return ParseWorktreeListPorcelain(string(out))
 }
+
+// ListBranches returns the local branch names of the repository at
+// projectPath, sorted by name. Returns nil on any error.
+func ListBranches(projectPath string) []string {
+	out, err := gitCmd(projectPath, "for-each-ref", "--format=%(refname:short)", "--sort=refname", "refs/heads").Output()
+	if err != nil {
+		return nil
+	}
+	var branches []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches
+}
+
+// DefaultWorktreePath returns the directory suggested for a new worktree of
+// branch: a sibling of the project named <project>-<branch>, with slashes
+// in the branch name replaced so feature/login becomes feature-login.
+func DefaultWorktreePath(projectPath, branch string) string {
+	clean := filepath.Clean(util.ExpandPath(projectPath))
+	name := filepath.Base(clean) + "-" + strings.ReplaceAll(branch, "/", "-")
+	return filepath.Join(filepath.Dir(clean), name)
+}
+
+// AddWorktree creates a worktree of the repository at projectPath in path,
+// checking out branch. The branch is created from HEAD if it doesn't exist.
+func AddWorktree(projectPath, path, branch string) error {
+	args := []string{"worktree", "add"}
+	if branchExists(projectPath, branch) {
+		args = append(args, path, branch)
+	} else {
+		args = append(args, "-b", branch, path)
+	}
+	return runGit(projectPath, args...)
+}
+
+// RemoveWorktree removes the worktree at path from the repository at
+// projectPath. Without force, git refuses when the worktree has
+// uncommitted changes.
+func RemoveWorktree(projectPath, path string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	return runGit(projectPath, append(args, path)...)
+}
+
+// WorktreeChanges returns the number of modified and untracked files in
+// the worktree at path.
+func WorktreeChanges(path string) (int, error) {
+	out, err := gitCmd(path, "status", "--porcelain").Output()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func branchExists(projectPath, branch string) bool {
+	return gitCmd(projectPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+}
+
+// gitCmd returns the git command running args in dir. dir may start with
+// ~, as project paths do.
+func gitCmd(dir string, args ...string) *exec.Cmd {
+	dir = filepath.Clean(util.ExpandPath(dir))
+	return exec.Command("git", append([]string{"-C", dir}, args...)...)
+}
+
+// runGit runs git with args in dir, returning git's own message on
+// failure. Git prints progress before the error, so only the last line is
+// kept.
+func runGit(dir string, args ...string) error {
+	out, err := gitCmd(dir, args...).CombinedOutput()
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		msg := strings.TrimPrefix(lines[len(lines)-1], "fatal: ")
+		if msg == "" {
+			return err
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
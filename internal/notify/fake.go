@@ -0,0 +1,47 @@
+package notify
+
+import "github.com/jackuait/ghost-tab/internal/util"
+
+// Notification is a desktop notification recorded by Fake.
+type Notification struct {
+	Title string
+	Body  string
+}
+
+// Fake is a Backend for tests. It records what it is asked to play and
+// show instead of running anything.
+type Fake struct {
+	SoundList     []Sound
+	Played        []Sound
+	Notifications []Notification
+	Err           error // returned by Play and Notify when set
+}
+
+// Name implements Backend.
+func (f *Fake) Name() string { return "fake" }
+
+// Sounds implements Backend.
+func (f *Fake) Sounds() []Sound { return f.SoundList }
+
+// Play implements Backend.
+func (f *Fake) Play(s Sound) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Played = append(f.Played, s)
+	return nil
+}
+
+// Notify implements Backend.
+func (f *Fake) Notify(title, body string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Notifications = append(f.Notifications, Notification{Title: title, Body: body})
+	return nil
+}
+
+// SoundCommand implements Backend.
+func (f *Fake) SoundCommand(s Sound) string {
+	return "fake-play " + util.ShellQuote(s.Path) + " &"
+}
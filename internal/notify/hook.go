@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/transcript"
+)
+
+// Claude hook events the notify command handles.
+const (
+	EventStop             = "Stop"
+	EventSubagentStop     = "SubagentStop"
+	EventNotification     = "Notification"
+	EventUserPromptSubmit = "UserPromptSubmit"
+)
+
+// HookInput is the JSON payload Claude writes to a hook command's stdin.
+type HookInput struct {
+	SessionID      string `json:"session_id"`
+	TranscriptPath string `json:"transcript_path"`
+	Cwd            string `json:"cwd"`
+	Event          string `json:"hook_event_name"`
+	// Message is what a Notification event is about, e.g. "Claude needs
+	// your permission to use Bash".
+	Message string `json:"message"`
+}
+
+// ParseHookInput decodes a hook payload.
+func ParseHookInput(r io.Reader) (HookInput, error) {
+	var in HookInput
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return HookInput{}, err
+	}
+	if in.Event == "" {
+		return HookInput{}, fmt.Errorf("missing hook_event_name")
+	}
+	return in, nil
+}
+
+// Project returns the name of the project the hook fired in: the one
+// Ghost Tab launched the session for, otherwise the working directory's
+// name.
+func (in HookInput) Project(launched string) string {
+	if launched != "" {
+		return launched
+	}
+	if in.Cwd == "" {
+		return ""
+	}
+	return filepath.Base(in.Cwd)
+}
+
+// maxMessageRunes is how much of the last assistant message a desktop
+// notification shows.
+const maxMessageRunes = 200
+
+// messageEntry is the part of a transcript line that carries message text.
+type messageEntry struct {
+	Type        string `json:"type"`
+	IsSidechain bool   `json:"isSidechain"`
+	Message     struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// text returns the text blocks of the message joined by spaces.
+func (e messageEntry) text() string {
+	var s string
+	if json.Unmarshal(e.Message.Content, &s) == nil {
+		return s
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(e.Message.Content, &blocks) != nil {
+		return ""
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" && b.Text != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// LastAssistantMessage returns the text of the last main-chain assistant
+// message in the JSONL transcript at path, on one line and shortened for a
+// notification, or "" when there is none.
+func LastAssistantMessage(path string) string {
+	var text string
+	transcript.FindLast(path, func(line []byte) bool {
+		var e messageEntry
+		if json.Unmarshal(line, &e) != nil || e.IsSidechain || e.Type != "assistant" {
+			return false
+		}
+		text = e.text()
+		return strings.TrimSpace(text) != ""
+	})
+	return shorten(text, maxMessageRunes)
+}
+
+// shorten collapses whitespace and cuts s to n runes, ending with "…".
+func shorten(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
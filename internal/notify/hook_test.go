@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseHookInput(t *testing.T) {
+	in, err := ParseHookInput(strings.NewReader(`{
+		"session_id": "abc",
+		"transcript_path": "/tmp/t.jsonl",
+		"cwd": "/home/me/my-app",
+		"hook_event_name": "Notification",
+		"message": "Claude needs your permission to use Bash"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Event != EventNotification || in.TranscriptPath != "/tmp/t.jsonl" || in.Message == "" {
+		t.Errorf("input = %+v", in)
+	}
+	if got := in.Project(""); got != "my-app" {
+		t.Errorf("Project() = %q, want the directory name", got)
+	}
+	if got := in.Project("My App"); got != "My App" {
+		t.Errorf("Project(launched) = %q, want the launched name", got)
+	}
+}
+
+func TestParseHookInput_errors(t *testing.T) {
+	for _, payload := range []string{`not json`, `{"cwd": "/tmp"}`} {
+		if _, err := ParseHookInput(strings.NewReader(payload)); err == nil {
+			t.Errorf("expected an error for %s", payload)
+		}
+	}
+}
+
+func writeTranscript(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLastAssistantMessage(t *testing.T) {
+	path := writeTranscript(t,
+		`{"type":"user","message":{"role":"user","content":"fix the tests"}}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Looking at the tests."}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash"}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"All tests\n  pass now."},{"type":"text","text":"Done."}]}}`,
+		`{"type":"assistant","isSidechain":true,"message":{"content":[{"type":"text","text":"subagent chatter"}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","content":"ok"}]}}`,
+	)
+	if got := LastAssistantMessage(path); got != "All tests pass now. Done." {
+		t.Errorf("LastAssistantMessage() = %q", got)
+	}
+}
+
+func TestLastAssistantMessage_reads_past_first_window(t *testing.T) {
+	lines := []string{`{"type":"assistant","message":{"content":[{"type":"text","text":"early answer"}]}}`}
+	for i := 0; i < 2000; i++ {
+		lines = append(lines, fmt.Sprintf(`{"type":"user","message":{"content":"padding line %d %s"}}`, i, strings.Repeat("x", 40)))
+	}
+	if got := LastAssistantMessage(writeTranscript(t, lines...)); got != "early answer" {
+		t.Errorf("LastAssistantMessage() = %q", got)
+	}
+}
+
+func TestLastAssistantMessage_missing_or_empty(t *testing.T) {
+	if got := LastAssistantMessage(""); got != "" {
+		t.Errorf("no path = %q", got)
+	}
+	if got := LastAssistantMessage("/nonexistent/transcript.jsonl"); got != "" {
+		t.Errorf("missing file = %q", got)
+	}
+	if got := LastAssistantMessage(writeTranscript(t, `{"type":"user","message":{"content":"hi"}}`)); got != "" {
+		t.Errorf("no assistant message = %q", got)
+	}
+}
+
+func TestShorten(t *testing.T) {
+	if got := shorten("a  b\n c", 10); got != "a b c" {
+		t.Errorf("shorten() = %q", got)
+	}
+	if got := shorten("héllo wörld", 7); got != "héllo…" {
+		t.Errorf("shorten() = %q", got)
+	}
+}
@@ -0,0 +1,178 @@
+// Package notify plays notification sounds and shows desktop notifications
+// with whatever the platform provides: afplay and osascript on macOS,
+// PulseAudio, PipeWire or ALSA players and notify-send on Linux.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// AppName is the application name desktop notifications are shown under.
+const AppName = "Ghost Tab"
+
+// ErrUnavailable is returned when no installed program can do what was asked.
+var ErrUnavailable = errors.New("no supported program installed")
+
+// Backend plays notification sounds and shows desktop notifications.
+type Backend interface {
+	// Name describes the programs the backend uses, e.g. "paplay, notify-send".
+	Name() string
+	// Sounds lists the sounds the backend can play, sorted by name.
+	Sounds() []Sound
+	// Play starts playing s and returns without waiting for it to finish.
+	Play(s Sound) error
+	// Notify shows a desktop notification.
+	Notify(title, body string) error
+	// SoundCommand returns a shell command that plays s in the background,
+	// as written into Claude hooks. It is empty when s cannot be played.
+	SoundCommand(s Sound) string
+}
+
+// Player is a command-line program that plays sound files.
+type Player struct {
+	Command    string
+	Args       []string // placed before the file
+	Extensions []string // file types it can play
+}
+
+// Players are the sound players Detect looks for, in order of preference.
+var Players = []Player{
+	{Command: "afplay", Extensions: []string{".aiff", ".aif", ".caf", ".m4a", ".mp3", ".wav"}},
+	{Command: "paplay", Extensions: []string{".oga", ".ogg", ".flac", ".wav"}},
+	{Command: "pw-play", Extensions: []string{".oga", ".ogg", ".flac", ".wav"}},
+	{Command: "aplay", Args: []string{"-q"}, Extensions: []string{".wav"}},
+}
+
+// argv returns the command line that plays path.
+func (p Player) argv(path string) []string {
+	argv := append([]string{p.Command}, p.Args...)
+	return append(argv, path)
+}
+
+// Notifier is a command-line program that shows desktop notifications.
+type Notifier struct {
+	Command string
+	argv    func(title, body string) []string
+}
+
+// Notifiers are the notification programs Detect looks for, in order of
+// preference.
+var Notifiers = []Notifier{
+	{Command: "notify-send", argv: func(title, body string) []string {
+		// notify-send talks to the freedesktop notification service over D-Bus
+		return []string{"notify-send", "--app-name=" + AppName, title, body}
+	}},
+	{Command: "osascript", argv: func(title, body string) []string {
+		script := fmt.Sprintf("display notification %s with title %s",
+			appleScriptString(body), appleScriptString(title))
+		return []string{"osascript", "-e", script}
+	}},
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// System is the Backend that runs the programs installed on this machine.
+type System struct {
+	Player    *Player   // nil when no player is installed
+	Notifier  *Notifier // nil when no notifier is installed
+	SoundDirs []string  // searched in order; earlier directories win
+
+	sounds  []Sound
+	scanned bool
+}
+
+// Detect returns a backend using the first installed player and notifier,
+// with sounds found in the platform's sound directories.
+func Detect() *System {
+	home, _ := os.UserHomeDir()
+	return detect(exec.LookPath, SoundDirs(runtime.GOOS, home, os.Getenv))
+}
+
+func detect(lookPath func(string) (string, error), dirs []string) *System {
+	s := &System{SoundDirs: dirs}
+	for i := range Players {
+		if _, err := lookPath(Players[i].Command); err == nil {
+			s.Player = &Players[i]
+			break
+		}
+	}
+	for i := range Notifiers {
+		if _, err := lookPath(Notifiers[i].Command); err == nil {
+			s.Notifier = &Notifiers[i]
+			break
+		}
+	}
+	return s
+}
+
+// Name implements Backend.
+func (s *System) Name() string {
+	var names []string
+	if s.Player != nil {
+		names = append(names, s.Player.Command)
+	}
+	if s.Notifier != nil {
+		names = append(names, s.Notifier.Command)
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// Sounds implements Backend. The sound directories are scanned once.
+func (s *System) Sounds() []Sound {
+	if s.Player == nil {
+		return nil
+	}
+	if !s.scanned {
+		s.sounds = DiscoverSounds(s.SoundDirs, s.Player.Extensions)
+		s.scanned = true
+	}
+	return s.sounds
+}
+
+// Play implements Backend.
+func (s *System) Play(snd Sound) error {
+	if s.Player == nil {
+		return fmt.Errorf("play %s: %w", snd.Name, ErrUnavailable)
+	}
+	return start(s.Player.argv(snd.Path))
+}
+
+// Notify implements Backend.
+func (s *System) Notify(title, body string) error {
+	if s.Notifier == nil {
+		return fmt.Errorf("notify: %w", ErrUnavailable)
+	}
+	return start(s.Notifier.argv(title, body))
+}
+
+// SoundCommand implements Backend.
+func (s *System) SoundCommand(snd Sound) string {
+	if s.Player == nil || snd.Path == "" {
+		return ""
+	}
+	return util.ShellJoin(s.Player.argv(snd.Path)) + " &"
+}
+
+// start runs argv in the background and reaps it when it exits.
+func start(argv []string) error {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// installed returns a lookPath that finds only the named commands.
+func installed(commands ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, c := range commands {
+			if c == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestDetect_macOS(t *testing.T) {
+	s := detect(installed("afplay", "osascript"), nil)
+	if s.Player == nil || s.Player.Command != "afplay" {
+		t.Fatalf("player = %+v, want afplay", s.Player)
+	}
+	if s.Notifier == nil || s.Notifier.Command != "osascript" {
+		t.Fatalf("notifier = %+v, want osascript", s.Notifier)
+	}
+	if got := s.Name(); got != "afplay, osascript" {
+		t.Errorf("Name() = %q", got)
+	}
+}
+
+func TestDetect_prefers_PulseAudio_then_PipeWire_then_ALSA(t *testing.T) {
+	tests := []struct {
+		installed []string
+		want      string
+	}{
+		{[]string{"aplay", "pw-play", "paplay"}, "paplay"},
+		{[]string{"aplay", "pw-play"}, "pw-play"},
+		{[]string{"aplay"}, "aplay"},
+	}
+	for _, tt := range tests {
+		s := detect(installed(tt.installed...), nil)
+		if s.Player == nil || s.Player.Command != tt.want {
+			t.Errorf("with %v player = %+v, want %s", tt.installed, s.Player, tt.want)
+		}
+	}
+}
+
+func TestDetect_nothing_installed(t *testing.T) {
+	s := detect(installed(), nil)
+	if s.Name() != "none" {
+		t.Errorf("Name() = %q, want none", s.Name())
+	}
+	if s.Sounds() != nil {
+		t.Errorf("Sounds() = %v, want nil without a player", s.Sounds())
+	}
+	if err := s.Play(Sound{Name: "bell"}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Play() error = %v, want ErrUnavailable", err)
+	}
+	if err := s.Notify("t", "b"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Notify() error = %v, want ErrUnavailable", err)
+	}
+	if got := s.SoundCommand(Sound{Name: "bell", Path: "/x/bell.oga"}); got != "" {
+		t.Errorf("SoundCommand() = %q, want empty", got)
+	}
+}
+
+func TestSystem_Sounds_filters_by_player_extensions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"complete.oga", "bell.wav", "index.theme"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paplay := detect(installed("paplay"), []string{dir})
+	if got := SoundNames(paplay.Sounds()); !reflect.DeepEqual(got, []string{"bell", "complete"}) {
+		t.Errorf("paplay sounds = %v", got)
+	}
+	aplay := detect(installed("aplay"), []string{dir})
+	if got := SoundNames(aplay.Sounds()); !reflect.DeepEqual(got, []string{"bell"}) {
+		t.Errorf("aplay sounds = %v", got)
+	}
+}
+
+func TestSystem_SoundCommand(t *testing.T) {
+	tests := []struct {
+		player string
+		path   string
+		want   string
+	}{
+		{"afplay", "/System/Library/Sounds/Bottle.aiff", "afplay /System/Library/Sounds/Bottle.aiff &"},
+		{"paplay", "/usr/share/sounds/freedesktop/stereo/complete.oga", "paplay /usr/share/sounds/freedesktop/stereo/complete.oga &"},
+		{"aplay", "/home/me/My Sounds/ding.wav", "aplay -q '/home/me/My Sounds/ding.wav' &"},
+	}
+	for _, tt := range tests {
+		s := detect(installed(tt.player), nil)
+		if got := s.SoundCommand(Sound{Path: tt.path}); got != tt.want {
+			t.Errorf("%s: SoundCommand() = %q, want %q", tt.player, got, tt.want)
+		}
+	}
+}
+
+func TestNotifiers_argv(t *testing.T) {
+	send := Notifiers[0].argv("Claude is done", "my-app")
+	if !reflect.DeepEqual(send, []string{"notify-send", "--app-name=Ghost Tab", "Claude is done", "my-app"}) {
+		t.Errorf("notify-send argv = %q", send)
+	}
+	osa := Notifiers[1].argv(`say "hi"`, `back\slash`)
+	want := `display notification "back\\slash" with title "say \"hi\""`
+	if len(osa) != 3 || osa[2] != want {
+		t.Errorf("osascript argv = %q, want script %q", osa, want)
+	}
+}
+
+func TestFake_records_calls(t *testing.T) {
+	f := &Fake{SoundList: []Sound{{Name: "bell", Path: "/s/bell.oga"}}}
+	if err := f.Play(f.Sounds()[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Notify("title", "body"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Played) != 1 || f.Played[0].Name != "bell" {
+		t.Errorf("Played = %v", f.Played)
+	}
+	if !reflect.DeepEqual(f.Notifications, []Notification{{"title", "body"}}) {
+		t.Errorf("Notifications = %v", f.Notifications)
+	}
+	if got := f.SoundCommand(f.SoundList[0]); !strings.HasPrefix(got, "fake-play ") {
+		t.Errorf("SoundCommand() = %q", got)
+	}
+
+	f.Err = errors.New("boom")
+	if err := f.Play(f.SoundList[0]); err == nil || len(f.Played) != 1 {
+		t.Errorf("Play with Err set = %v, played %d", err, len(f.Played))
+	}
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/session"
+)
+
+// TitleMark is put before the tab title to show Claude is waiting.
+const TitleMark = "● "
+
+// Pane is the terminal pane Claude runs in.
+type Pane interface {
+	// Focused reports whether you are looking at the pane.
+	Focused() bool
+	// Bell rings the terminal bell in the pane.
+	Bell() error
+	// SetTitle sets the title of the terminal tab showing the pane.
+	SetTitle(title string) error
+}
+
+// TmuxPane is a pane of a tmux session, such as $TMUX_PANE.
+type TmuxPane struct {
+	Tmux session.Tmux
+	ID   string
+}
+
+// Focused reports whether the pane is the active one of the active window
+// and a client showing it has terminal focus. Terminals only report focus
+// with tmux's focus-events on, which Ghost Tab sessions set.
+func (p TmuxPane) Focused() bool {
+	active, err := p.Tmux.Output("display-message", "-p", "-t", p.ID, "#{pane_active}#{window_active}")
+	if err != nil {
+		return false
+	}
+	clients, err := p.Tmux.Output("list-clients", "-t", p.ID, "-F", "#{client_flags}")
+	if err != nil {
+		return false
+	}
+	return paneFocused(active, clients)
+}
+
+// paneFocused interprets the pane and client flags Focused reads.
+func paneFocused(active, clientFlags string) bool {
+	if strings.TrimSpace(active) != "11" {
+		return false
+	}
+	for _, flags := range strings.Split(clientFlags, "\n") {
+		for _, f := range strings.Split(flags, ",") {
+			if strings.TrimSpace(f) == "focused" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Bell writes BEL to the pane's terminal, which tmux flags on the window
+// and passes on to the terminal.
+func (p TmuxPane) Bell() error {
+	tty, err := p.Tmux.Output("display-message", "-p", "-t", p.ID, "#{pane_tty}")
+	if err != nil {
+		return err
+	}
+	return writeTTY(strings.TrimSpace(tty), "\a")
+}
+
+// SetTitle writes the title escape straight to the terminals attached to
+// the pane's session, as claude-wrapper.sh did before starting tmux.
+func (p TmuxPane) SetTitle(title string) error {
+	out, err := p.Tmux.Output("list-clients", "-t", p.ID, "-F", "#{client_tty}")
+	if err != nil {
+		return err
+	}
+	for _, tty := range strings.Fields(out) {
+		if err := writeTTY(tty, "\033]0;"+title+"\007"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTTY(path, s string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(s)
+	return err
+}
@@ -0,0 +1,22 @@
+package notify
+
+import "testing"
+
+func TestPaneFocused(t *testing.T) {
+	tests := []struct {
+		active, clients string
+		want            bool
+	}{
+		{"11\n", "attached,focused,UTF-8\n", true},
+		{"11\n", "attached,UTF-8\nattached,focused\n", true},
+		{"11\n", "attached,UTF-8\n", false},
+		{"10\n", "attached,focused\n", false},
+		{"01\n", "attached,focused\n", false},
+		{"11\n", "", false},
+	}
+	for _, tt := range tests {
+		if got := paneFocused(tt.active, tt.clients); got != tt.want {
+			t.Errorf("paneFocused(%q, %q) = %v, want %v", tt.active, tt.clients, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// Actions a hook event can trigger.
+const (
+	ActionSound   = "sound"   // play the chosen notification sound
+	ActionDesktop = "desktop" // show a desktop notification
+	ActionBell    = "bell"    // ring the bell in Claude's tmux pane
+	ActionTitle   = "title"   // mark the tab title until the next prompt
+	ActionSinks   = "sinks"   // send the event to the configured sinks
+	// ActionResetTitle removes the title mark. It is not configurable:
+	// every prompt submitted clears it.
+	ActionResetTitle = "reset-title"
+)
+
+// AllActions lists the actions a rule may name.
+var AllActions = []string{ActionSound, ActionDesktop, ActionBell, ActionTitle, ActionSinks}
+
+// silentActions may run during quiet hours.
+var silentActions = []string{ActionTitle, ActionResetTitle}
+
+// configEvents are the events a config file may set rules for.
+var configEvents = []string{EventStop, EventSubagentStop, EventNotification}
+
+// Rule chooses the actions for an event.
+type Rule struct {
+	// Actions run when Claude's pane is not focused.
+	Actions []string
+	// Focused run instead when it is, i.e. you are already looking at it.
+	Focused []string
+}
+
+// QuietHours is a daily time range, which may wrap past midnight.
+type QuietHours struct {
+	Start, End time.Duration // since midnight
+}
+
+// Contains reports whether t falls within the range.
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.Start == q.End {
+		return false
+	}
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if q.Start < q.End {
+		return now >= q.Start && now < q.End
+	}
+	return now >= q.Start || now < q.End
+}
+
+// Config decides what the notify command does for each hook event.
+type Config struct {
+	// Events holds the rule of each event.
+	Events map[string]Rule
+	// Projects overrides event rules for projects, by project name.
+	Projects map[string]map[string]Rule
+	// Quiet, when set, keeps all but the title mark silent.
+	Quiet *QuietHours
+	// Sinks receive events the sinks action runs for.
+	Sinks []Sink
+}
+
+// DefaultConfig sounds, notifies, rings, marks the title and tells the
+// sinks when Claude stops or needs you while you're elsewhere. When you're
+// looking at its pane, only a finished turn plays the sound, as the Stop
+// hook always has.
+func DefaultConfig() Config {
+	all := []string{ActionSound, ActionDesktop, ActionBell, ActionTitle, ActionSinks}
+	return Config{
+		Events: map[string]Rule{
+			EventStop:         {Actions: all, Focused: []string{ActionSound}},
+			EventNotification: {Actions: all},
+		},
+		Projects: map[string]map[string]Rule{},
+	}
+}
+
+// Context is what a decision depends on besides the event.
+type Context struct {
+	Project string
+	Focused bool
+	Now     time.Time
+}
+
+// Decide returns the actions to run for event.
+func (c Config) Decide(event string, ctx Context) []string {
+	if event == EventUserPromptSubmit {
+		return []string{ActionResetTitle}
+	}
+	rule, ok := c.Projects[ctx.Project][event]
+	if !ok {
+		rule = c.Events[event]
+	}
+	actions := rule.Actions
+	if ctx.Focused {
+		actions = rule.Focused
+	}
+	quiet := c.Quiet != nil && c.Quiet.Contains(ctx.Now)
+	var chosen []string
+	for _, a := range actions {
+		if quiet && !slices.Contains(silentActions, a) {
+			continue
+		}
+		if a == ActionSinks && !c.hasSinkFor(event) {
+			continue
+		}
+		chosen = append(chosen, a)
+	}
+	return chosen
+}
+
+// hasSinkFor reports whether any sink takes events of this kind.
+func (c Config) hasSinkFor(event string) bool {
+	for _, s := range c.Sinks {
+		if s.Wants(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultConfigFile returns the path of the user's notification settings,
+// ${XDG_CONFIG_HOME:-~/.config}/ghost-tab/notify.toml.
+func DefaultConfigFile() string {
+	return filepath.Join(util.ConfigDir(), "notify.toml")
+}
+
+// LoadConfig returns the default config changed by the TOML file at path.
+// A missing file yields the default. The file may set
+//
+//	quiet_hours = "22:00-08:00"   # only mark the title at night
+//
+//	[Stop]
+//	actions = ["sound", "title"]
+//	focused = []                  # when you're looking at Claude's pane
+//
+//	[projects.my-app.Notification]
+//	actions = ["desktop"]
+//
+//	[[sinks]]
+//	type = "webhook"              # or "command", "file"
+//	url = "https://hooks.slack.com/services/..."
+//	format = "slack"              # json, slack, discord, ntfy or a body template
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := cfg.Decode(data); err != nil {
+		return DefaultConfig(), fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// configFile is the TOML form of Config.
+type configFile struct {
+	QuietHours   *QuietHours                    `toml:"quiet_hours"`
+	Stop         ruleFile                       `toml:"Stop"`
+	SubagentStop ruleFile                       `toml:"SubagentStop"`
+	Notification ruleFile                       `toml:"Notification"`
+	Projects     map[string]map[string]ruleFile `toml:"projects"`
+	Sinks        []sinkFile                     `toml:"sinks"`
+}
+
+// ruleFile is the TOML form of Rule.
+type ruleFile struct {
+	Actions []string `toml:"actions"`
+	Focused []string `toml:"focused"`
+}
+
+// Decode applies the settings of a TOML document to c.
+func (c *Config) Decode(data []byte) error {
+	var f configFile
+	md, err := toml.Decode(string(data), &f)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		key := undecoded[0]
+		if len(key) == 1 {
+			return fmt.Errorf("unknown key %q, expected quiet_hours, projects, sinks or one of %v", key.String(), configEvents)
+		}
+		return fmt.Errorf("unknown key %q", key.String())
+	}
+
+	if f.QuietHours != nil {
+		c.Quiet = f.QuietHours
+	}
+	if md.IsDefined("sinks") {
+		sinks, err := decodeSinks(f.Sinks)
+		if err != nil {
+			return err
+		}
+		c.Sinks = sinks
+	}
+	// toml leaves a map alone when the value isn't a table; implicit
+	// tables, like projects in [projects.my-app.Stop], have no type
+	if t := md.Type("projects"); t != "" && t != "Hash" {
+		return fmt.Errorf("projects must be a table")
+	}
+	for name, events := range f.Projects {
+		if t := md.Type("projects", name); t != "" && t != "Hash" {
+			return fmt.Errorf("projects.%s must be a table", name)
+		}
+		rules := map[string]Rule{}
+		for event, rf := range events {
+			if !slices.Contains(configEvents, event) {
+				return fmt.Errorf("projects.%s: unknown event %q, expected one of %v", name, event, configEvents)
+			}
+			// A project's rule replaces the event's rule entirely: fields
+			// it leaves out are empty.
+			rule, err := rf.rule(fmt.Sprintf("projects.%s.%s", name, event))
+			if err != nil {
+				return err
+			}
+			rules[event] = rule
+		}
+		c.Projects[name] = rules
+	}
+	for event, rf := range map[string]ruleFile{EventStop: f.Stop, EventSubagentStop: f.SubagentStop, EventNotification: f.Notification} {
+		if !md.IsDefined(event) {
+			continue
+		}
+		rule, err := rf.rule(event)
+		if err != nil {
+			return err
+		}
+		// An event's rule only changes the fields the file sets.
+		base := c.Events[event]
+		if md.IsDefined(event, "actions") {
+			base.Actions = rule.Actions
+		}
+		if md.IsDefined(event, "focused") {
+			base.Focused = rule.Focused
+		}
+		c.Events[event] = base
+	}
+	return nil
+}
+
+// rule checks the actions of f, called name in errors.
+func (f ruleFile) rule(name string) (Rule, error) {
+	actions, err := checkActions(name+".actions", f.Actions)
+	if err != nil {
+		return Rule{}, err
+	}
+	focused, err := checkActions(name+".focused", f.Focused)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Actions: actions, Focused: focused}, nil
+}
+
+// checkActions rejects unknown actions and drops repeated ones.
+func checkActions(name string, actions []string) ([]string, error) {
+	if actions == nil {
+		return nil, nil
+	}
+	checked := []string{}
+	for _, action := range actions {
+		if !slices.Contains(AllActions, action) {
+			return nil, fmt.Errorf("%s: unknown action %s, expected one of %v", name, action, AllActions)
+		}
+		if !slices.Contains(checked, action) {
+			checked = append(checked, action)
+		}
+	}
+	return checked, nil
+}
+
+// ParseQuietHours parses a range like "22:00-08:00".
+func ParseQuietHours(s string) (*QuietHours, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return nil, fmt.Errorf("quiet_hours must look like \"22:00-08:00\", got %q", s)
+	}
+	from, err := parseClock(start)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseClock(end)
+	if err != nil {
+		return nil, err
+	}
+	return &QuietHours{Start: from, End: to}, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing quiet_hours.
+func (q *QuietHours) UnmarshalText(text []byte) error {
+	parsed, err := ParseQuietHours(string(text))
+	if err != nil {
+		return err
+	}
+	*q = *parsed
+	return nil
+}
+
+// parseClock parses "HH:MM" as the time since midnight.
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	h, m, ok := strings.Cut(s, ":")
+	hour, herr := strconv.Atoi(h)
+	minute, merr := strconv.Atoi(m)
+	if !ok || herr != nil || merr != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time %q in quiet_hours, expected HH:MM", s)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 1, 1, hour, minute, 0, 0, time.Local)
+}
+
+func TestDefaultConfig_Decide(t *testing.T) {
+	cfg := DefaultConfig()
+	all := []string{ActionSound, ActionDesktop, ActionBell, ActionTitle}
+	tests := []struct {
+		event   string
+		focused bool
+		want    []string
+	}{
+		{EventStop, false, all},
+		{EventStop, true, []string{ActionSound}},
+		{EventNotification, false, all},
+		{EventNotification, true, nil},
+		{EventSubagentStop, false, nil},
+		{EventUserPromptSubmit, true, []string{ActionResetTitle}},
+	}
+	for _, tt := range tests {
+		got := cfg.Decide(tt.event, Context{Focused: tt.focused, Now: at(12, 0)})
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Decide(%s, focused=%v) = %v, want %v", tt.event, tt.focused, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_Decide_quiet_hours_keep_only_the_title(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Quiet = &QuietHours{Start: 22 * time.Hour, End: 8 * time.Hour}
+
+	if got := cfg.Decide(EventStop, Context{Now: at(23, 30)}); !reflect.DeepEqual(got, []string{ActionTitle}) {
+		t.Errorf("at night = %v, want only the title", got)
+	}
+	if got := cfg.Decide(EventStop, Context{Now: at(12, 0)}); len(got) != 4 {
+		t.Errorf("at noon = %v, want every action", got)
+	}
+}
+
+func TestConfig_Decide_project_override(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Projects["my-app"] = map[string]Rule{EventStop: {Actions: []string{ActionBell}}}
+
+	if got := cfg.Decide(EventStop, Context{Project: "my-app"}); !reflect.DeepEqual(got, []string{ActionBell}) {
+		t.Errorf("my-app = %v, want [bell]", got)
+	}
+	if got := cfg.Decide(EventStop, Context{Project: "my-app", Focused: true}); len(got) != 0 {
+		t.Errorf("my-app focused = %v, want nothing", got)
+	}
+	if got := cfg.Decide(EventNotification, Context{Project: "my-app"}); len(got) != 4 {
+		t.Errorf("my-app Notification = %v, want the default rule", got)
+	}
+	if got := cfg.Decide(EventStop, Context{Project: "other"}); len(got) != 4 {
+		t.Errorf("other project = %v, want the default rule", got)
+	}
+}
+
+func TestQuietHours_Contains(t *testing.T) {
+	overnight := QuietHours{Start: 22 * time.Hour, End: 8 * time.Hour}
+	daytime := QuietHours{Start: 12*time.Hour + 30*time.Minute, End: 14 * time.Hour}
+	tests := []struct {
+		q    QuietHours
+		t    time.Time
+		want bool
+	}{
+		{overnight, at(22, 0), true},
+		{overnight, at(3, 0), true},
+		{overnight, at(8, 0), false},
+		{overnight, at(21, 59), false},
+		{daytime, at(12, 30), true},
+		{daytime, at(13, 59), true},
+		{daytime, at(14, 0), false},
+		{QuietHours{}, at(0, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Contains(tt.t); got != tt.want {
+			t.Errorf("%v.Contains(%s) = %v, want %v", tt.q, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestParseQuietHours(t *testing.T) {
+	q, err := ParseQuietHours("22:00 - 07:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Start != 22*time.Hour || q.End != 7*time.Hour+30*time.Minute {
+		t.Errorf("ParseQuietHours() = %+v", q)
+	}
+	for _, bad := range []string{"22:00", "25:00-08:00", "22:00-8", "ten-eight"} {
+		if _, err := ParseQuietHours(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestConfig_Decode(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.Decode([]byte(`
+quiet_hours = "23:00-07:00"
+
+[Stop]
+focused = []
+
+[SubagentStop]
+actions = ["title", "title"]
+
+[projects.my-app.Notification]
+actions = ["desktop"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Quiet == nil || cfg.Quiet.Start != 23*time.Hour {
+		t.Errorf("Quiet = %+v", cfg.Quiet)
+	}
+	if stop := cfg.Events[EventStop]; len(stop.Actions) != 5 || len(stop.Focused) != 0 {
+		t.Errorf("Stop = %+v, want default actions and no focused ones", stop)
+	}
+	if sub := cfg.Events[EventSubagentStop]; !reflect.DeepEqual(sub.Actions, []string{ActionTitle}) {
+		t.Errorf("SubagentStop = %+v", sub)
+	}
+	if n := cfg.Projects["my-app"][EventNotification]; !reflect.DeepEqual(n.Actions, []string{ActionDesktop}) {
+		t.Errorf("my-app Notification = %+v", n)
+	}
+}
+
+func TestConfig_Decode_errors(t *testing.T) {
+	tests := []struct{ doc, want string }{
+		{`volume = 3`, `unknown key "volume"`},
+		{`quiet_hours = 22`, `quiet_hours must look like "22:00-08:00"`},
+		{"[Stop]\nactions = [\"shout\"]", "unknown action shout"},
+		{"[Stop]\nwhen = []", `unknown key "Stop.when"`},
+		{"[projects.app.Start]\nactions = []", `unknown event "Start"`},
+		{`projects = { app = 1 }`, "projects.app must be a table"},
+		{`Stop = "sound"`, `"Stop"): type mismatch`},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		err := cfg.Decode([]byte(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Decode(%q) error = %v, want %q", tt.doc, err, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadConfig(filepath.Join(dir, "missing.toml"))
+	if err != nil || len(cfg.Events[EventStop].Actions) != 5 {
+		t.Errorf("missing file = %+v, %v; want the default", cfg, err)
+	}
+
+	bad := filepath.Join(dir, "bad.toml")
+	os.WriteFile(bad, []byte("[Stop]\nactions = [\"shout\"]\n"), 0644)
+	cfg, err = LoadConfig(bad)
+	if err == nil || !strings.Contains(err.Error(), bad) {
+		t.Errorf("error = %v, want it to name the file", err)
+	}
+	if len(cfg.Events[EventStop].Actions) != 5 {
+		t.Errorf("broken file should yield the default, got %+v", cfg)
+	}
+}
@@ -0,0 +1,452 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// Sink types a config file may declare.
+const (
+	SinkWebhook = "webhook" // POST a JSON body to a URL
+	SinkCommand = "command" // run a shell command with the event on stdin
+	SinkFile    = "file"    // append the event as a JSON line
+)
+
+// Defaults for sinks that don't set their own. Together they let Deliver
+// take about 31.5s on a sink that never answers, too long to wait for in a
+// hook: callers in a hook should deliver from a process of their own.
+const (
+	DefaultAttempts = 3
+	DefaultBackoff  = 500 * time.Millisecond
+	DefaultTimeout  = 10 * time.Second
+)
+
+// Event is what sinks are sent: one hook event, described for people.
+type Event struct {
+	Event     string    `json:"event"`
+	Project   string    `json:"project"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Cwd       string    `json:"cwd"`
+	SessionID string    `json:"session_id"`
+	Time      time.Time `json:"time"`
+}
+
+// Text returns the event as a single line, "Title: Message".
+func (e Event) Text() string {
+	if e.Message == "" {
+		return e.Title
+	}
+	return e.Title + ": " + e.Message
+}
+
+// SampleEvent returns the event sinks are sent by notify --test.
+func SampleEvent(now time.Time) Event {
+	return Event{
+		Event:     EventStop,
+		Project:   "my-app",
+		Title:     "Claude Code · my-app",
+		Message:   "All tests pass. Ready for review.",
+		Cwd:       "/home/me/my-app",
+		SessionID: "00000000-0000-0000-0000-000000000000",
+		Time:      now,
+	}
+}
+
+// Sink delivers events somewhere other than this machine's speaker and
+// screen.
+type Sink interface {
+	// Name describes the sink in messages, e.g. "webhook https://ntfy.sh".
+	Name() string
+	// Wants reports whether the sink takes events of this kind.
+	Wants(event string) bool
+	// Send delivers e once.
+	Send(ctx context.Context, e Event) error
+	// Retry returns how often and how patiently Send is tried.
+	Retry() RetryPolicy
+}
+
+// RetryPolicy retries a failing send with exponential backoff.
+type RetryPolicy struct {
+	Attempts int           // tries in total, at least 1
+	Backoff  time.Duration // wait before the second try, doubled after each
+	Timeout  time.Duration // limit on each try
+}
+
+// permanentError marks a failure that trying again won't fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Deliver sends e to s, retrying failures with backoff until the policy's
+// attempts run out or ctx is done. sleep waits between tries; tests pass
+// one that doesn't. The error says how many tries were made, and wraps
+// ctx's error when that cut the retries short.
+func Deliver(ctx context.Context, s Sink, e Event, sleep func(time.Duration)) error {
+	policy := s.Retry()
+	wait := policy.Backoff
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		tryCtx, cancel := context.WithTimeout(ctx, policy.Timeout)
+		err = s.Send(tryCtx, e)
+		cancel()
+		var permanent permanentError
+		if err == nil || errors.As(err, &permanent) {
+			return err
+		}
+		if attempt >= policy.Attempts || ctx.Err() != nil {
+			break
+		}
+		sleep(wait)
+		wait *= 2
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		err = fmt.Errorf("%w (%w)", err, ctxErr)
+	}
+	if attempt > 1 {
+		return fmt.Errorf("%w (after %d attempts)", err, attempt)
+	}
+	return err
+}
+
+// sinkBase holds the settings every sink type shares.
+type sinkBase struct {
+	events []string // empty means every event
+	retry  RetryPolicy
+}
+
+func (b sinkBase) Wants(event string) bool {
+	return len(b.events) == 0 || slices.Contains(b.events, event)
+}
+
+func (b sinkBase) Retry() RetryPolicy { return b.retry }
+
+// Webhook body templates for common services. Templates see the Event,
+// the sink's Topic, and a json function that quotes a value as JSON.
+var webhookFormats = map[string]string{
+	"json":    `{{json .Event}}`,
+	"slack":   `{"text": {{json .Event.Text}}}`,
+	"discord": `{"content": {{json .Event.Text}}}`,
+	"ntfy":    `{"topic": {{json .Topic}}, "title": {{json .Event.Title}}, "message": {{json .Event.Message}}, "tags": ["ghost"]}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// WebhookSink posts a templated JSON body to a URL, e.g. a Slack or
+// Discord incoming webhook or an ntfy server.
+type WebhookSink struct {
+	sinkBase
+	URL     string
+	Topic   string // for the ntfy format
+	Headers map[string]string
+	body    *template.Template
+	client  *http.Client
+}
+
+// Name implements Sink.
+func (w *WebhookSink) Name() string { return "webhook " + w.URL }
+
+// Body renders the JSON body sent for e.
+func (w *WebhookSink) Body(e Event) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Event Event
+		Topic string
+	}{e, w.Topic}
+	if err := w.body.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("body is not valid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// Send implements Sink. Server errors and rate limiting are retried,
+// other client errors are not.
+func (w *WebhookSink) Send(ctx context.Context, e Event) error {
+	return w.SendTo(ctx, w.URL, e)
+}
+
+// SendTo posts e as Send does, but to url.
+func (w *WebhookSink) SendTo(ctx context.Context, url string, e Event) error {
+	body, err := w.Body(e)
+	if err != nil {
+		return permanentError{err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ghost-tab")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	client := w.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return permanentError{err}
+}
+
+// CommandSink runs a shell command with the event as JSON on stdin and in
+// GHOST_TAB_NOTIFY_* environment variables.
+type CommandSink struct {
+	sinkBase
+	Command string
+}
+
+// Name implements Sink.
+func (c *CommandSink) Name() string { return "command " + c.Command }
+
+// Send implements Sink.
+func (c *CommandSink) Send(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return permanentError{err}
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(os.Environ(),
+		"GHOST_TAB_NOTIFY_EVENT="+e.Event,
+		"GHOST_TAB_NOTIFY_PROJECT="+e.Project,
+		"GHOST_TAB_NOTIFY_TITLE="+e.Title,
+		"GHOST_TAB_NOTIFY_MESSAGE="+e.Message,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if first, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n"); first != "" {
+			return fmt.Errorf("%w: %s", err, first)
+		}
+		return err
+	}
+	return nil
+}
+
+// FileSink appends each event to a file as a line of JSON.
+type FileSink struct {
+	sinkBase
+	Path string
+}
+
+// Name implements Sink.
+func (f *FileSink) Name() string { return "file " + f.Path }
+
+// Send implements Sink.
+func (f *FileSink) Send(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return permanentError{err}
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// sinkFile is the TOML form of a [[sinks]] entry, with the keys of every
+// sink type.
+type sinkFile struct {
+	Type     string    `toml:"type"`
+	Events   []string  `toml:"events"`
+	Attempts *int      `toml:"attempts"`
+	Backoff  *duration `toml:"backoff"`
+	Timeout  *duration `toml:"timeout"`
+
+	URL     string            `toml:"url"`
+	Format  string            `toml:"format"`
+	Body    *string           `toml:"body"`
+	Topic   string            `toml:"topic"`
+	Headers map[string]string `toml:"headers"`
+	Command string            `toml:"command"`
+	Path    string            `toml:"path"`
+}
+
+// duration is a positive time.Duration written like "500ms".
+type duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil || v <= 0 {
+		return fmt.Errorf("must be a duration like \"500ms\" or \"5s\", got %q", text)
+	}
+	*d = duration(v)
+	return nil
+}
+
+// decodeSinks builds the sinks of the [[sinks]] entries.
+func decodeSinks(files []sinkFile) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(files))
+	for i, f := range files {
+		s, err := f.sink()
+		if err != nil {
+			return nil, fmt.Errorf("sinks[%d]: %w", i, err)
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+// sinkKeys lists the keys each sink type accepts besides the shared ones.
+var sinkKeys = map[string][]string{
+	SinkWebhook: {"url", "format", "body", "topic", "headers"},
+	SinkCommand: {"command"},
+	SinkFile:    {"path"},
+}
+
+// typeKeys returns the keys of f only some sink types accept, in the
+// order of sinkFile.
+func (f sinkFile) typeKeys() []string {
+	var keys []string
+	for _, k := range []struct {
+		key string
+		set bool
+	}{
+		{"url", f.URL != ""}, {"format", f.Format != ""}, {"body", f.Body != nil},
+		{"topic", f.Topic != ""}, {"headers", f.Headers != nil},
+		{"command", f.Command != ""}, {"path", f.Path != ""},
+	} {
+		if k.set {
+			keys = append(keys, k.key)
+		}
+	}
+	return keys
+}
+
+func (f sinkFile) sink() (Sink, error) {
+	keys, ok := sinkKeys[f.Type]
+	if !ok {
+		return nil, fmt.Errorf("type must be one of %q, %q or %q", SinkWebhook, SinkCommand, SinkFile)
+	}
+	for _, key := range f.typeKeys() {
+		if !slices.Contains(keys, key) {
+			return nil, fmt.Errorf("unknown key %q for a %s sink", key, f.Type)
+		}
+	}
+	base, err := f.base()
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.Type {
+	case SinkWebhook:
+		return f.webhook(base)
+	case SinkCommand:
+		if strings.TrimSpace(f.Command) == "" {
+			return nil, fmt.Errorf("command sink needs a command")
+		}
+		return &CommandSink{sinkBase: base, Command: f.Command}, nil
+	default:
+		if f.Path == "" {
+			return nil, fmt.Errorf("file sink needs a path")
+		}
+		return &FileSink{sinkBase: base, Path: util.ExpandPath(f.Path)}, nil
+	}
+}
+
+func (f sinkFile) base() (sinkBase, error) {
+	base := sinkBase{retry: RetryPolicy{Attempts: DefaultAttempts, Backoff: DefaultBackoff, Timeout: DefaultTimeout}}
+	for _, event := range f.Events {
+		if !slices.Contains(configEvents, event) {
+			return base, fmt.Errorf("unknown event %s, expected one of %v", event, configEvents)
+		}
+		base.events = append(base.events, event)
+	}
+	if f.Attempts != nil {
+		if *f.Attempts < 1 || *f.Attempts > 10 {
+			return base, fmt.Errorf("attempts must be a number from 1 to 10")
+		}
+		base.retry.Attempts = *f.Attempts
+	}
+	if f.Backoff != nil {
+		base.retry.Backoff = time.Duration(*f.Backoff)
+	}
+	if f.Timeout != nil {
+		base.retry.Timeout = time.Duration(*f.Timeout)
+	}
+	return base, nil
+}
+
+func (f sinkFile) webhook(base sinkBase) (Sink, error) {
+	w := &WebhookSink{sinkBase: base, URL: f.URL, Topic: f.Topic, Headers: f.Headers}
+	if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+		return nil, fmt.Errorf("webhook sink needs an http:// or https:// url")
+	}
+
+	format := f.Format
+	var body string
+	switch {
+	case f.Body != nil && format != "":
+		return nil, fmt.Errorf("set either format or body, not both")
+	case f.Body != nil:
+		body = *f.Body
+	default:
+		if format == "" {
+			format = "json"
+		}
+		var ok bool
+		if body, ok = webhookFormats[format]; !ok {
+			return nil, fmt.Errorf("unknown format %q, expected json, slack, discord or ntfy", format)
+		}
+		if format == "ntfy" && w.Topic == "" {
+			return nil, fmt.Errorf("the ntfy format needs a topic")
+		}
+	}
+	tmpl, err := template.New("body").Funcs(templateFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("body: %w", err)
+	}
+	w.body = tmpl
+	// Catch templates that can't render valid JSON now, not at 3 a.m.
+	if _, err := w.Body(SampleEvent(time.Now())); err != nil {
+		return nil, fmt.Errorf("body: %w", err)
+	}
+	return w, nil
+}
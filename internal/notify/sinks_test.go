@@ -0,0 +1,286 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func noSleep(time.Duration) {}
+
+// decodeOneSink decodes a config holding a single [[sinks]] table.
+func decodeOneSink(t *testing.T, doc string) Sink {
+	t.Helper()
+	cfg := DefaultConfig()
+	if err := cfg.Decode([]byte("[[sinks]]\n" + doc)); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(cfg.Sinks) != 1 {
+		t.Fatalf("got %d sinks, want 1", len(cfg.Sinks))
+	}
+	return cfg.Sinks[0]
+}
+
+func sampleEvent() Event {
+	return SampleEvent(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func TestWebhookSink_formats(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want map[string]any
+	}{
+		{`format = "slack"`, map[string]any{"text": "Claude Code · my-app: All tests pass. Ready for review."}},
+		{`format = "discord"`, map[string]any{"content": "Claude Code · my-app: All tests pass. Ready for review."}},
+		{`format = "ntfy"` + "\ntopic = \"claude\"", map[string]any{
+			"topic": "claude", "title": "Claude Code · my-app",
+			"message": "All tests pass. Ready for review.", "tags": []any{"ghost"},
+		}},
+		{`body = '{"who": {{json .Event.Project}}, "what": {{json .Event.Event}}}'`, map[string]any{"who": "my-app", "what": "Stop"}},
+	}
+	for _, tt := range tests {
+		w := decodeOneSink(t, "type = \"webhook\"\nurl = \"https://example.com/hook\"\n"+tt.doc).(*WebhookSink)
+		body, err := w.Body(sampleEvent())
+		if err != nil {
+			t.Fatalf("%s: Body: %v", tt.doc, err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("%s: body %s: %v", tt.doc, body, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: body = %v, want %v", tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookSink_default_format_is_the_event(t *testing.T) {
+	w := decodeOneSink(t, `type = "webhook"`+"\n"+`url = "https://example.com/hook"`).(*WebhookSink)
+	body, err := w.Body(sampleEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Event
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, sampleEvent()) {
+		t.Errorf("event = %+v, want %+v", got, sampleEvent())
+	}
+}
+
+func TestWebhookSink_posts_with_headers(t *testing.T) {
+	var method, auth, contentType, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, auth, contentType = r.Method, r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		data := make([]byte, 1024)
+		n, _ := r.Body.Read(data)
+		body = string(data[:n])
+	}))
+	defer srv.Close()
+
+	s := decodeOneSink(t, `type = "webhook"
+url = "`+srv.URL+`"
+format = "slack"
+headers = { Authorization = "Bearer xyz" }`)
+	if err := Deliver(context.Background(), s, sampleEvent(), noSleep); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if method != http.MethodPost || auth != "Bearer xyz" || contentType != "application/json" {
+		t.Errorf("request = %s, Authorization %q, Content-Type %q", method, auth, contentType)
+	}
+	if !strings.Contains(body, `"text"`) {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestDeliver_retries_server_errors(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusTooManyRequests} {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls < 3 {
+				w.WriteHeader(status)
+			}
+		}))
+		var waits []time.Duration
+		s := decodeOneSink(t, `type = "webhook"
+url = "`+srv.URL+`"
+backoff = "100ms"`)
+		err := Deliver(context.Background(), s, sampleEvent(), func(d time.Duration) { waits = append(waits, d) })
+		srv.Close()
+		if err != nil {
+			t.Errorf("%d: Deliver: %v", status, err)
+		}
+		if calls != 3 {
+			t.Errorf("%d: %d calls, want 3", status, calls)
+		}
+		if want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}; !reflect.DeepEqual(waits, want) {
+			t.Errorf("%d: waits = %v, want %v", status, waits, want)
+		}
+	}
+}
+
+func TestDeliver_gives_up_after_the_attempts(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	s := decodeOneSink(t, `type = "webhook"
+url = "`+srv.URL+`"
+attempts = 2`)
+	err := Deliver(context.Background(), s, sampleEvent(), noSleep)
+	if err == nil || !strings.Contains(err.Error(), "down for maintenance") || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("err = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("%d calls, want 2", calls)
+	}
+}
+
+func TestDeliver_does_not_retry_client_errors(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	s := decodeOneSink(t, `type = "webhook"`+"\n"+`url = "`+srv.URL+`"`)
+	err := Deliver(context.Background(), s, sampleEvent(), noSleep)
+	if err == nil || strings.Contains(err.Error(), "attempts") {
+		t.Errorf("err = %v, want the 403 without an attempt count", err)
+	}
+	if calls != 1 {
+		t.Errorf("%d calls, want 1", calls)
+	}
+}
+
+func TestDeliver_stops_when_ctx_is_done(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	s := decodeOneSink(t, `type = "webhook"
+url = "`+srv.URL+`"
+attempts = 5`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sleeps := 0
+	err := Deliver(ctx, s, sampleEvent(), func(time.Duration) {
+		if sleeps++; sleeps == 2 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "down for maintenance") ||
+		!strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("err = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("%d calls, want 2", calls)
+	}
+}
+
+func TestCommandSink_gets_the_event(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	s := decodeOneSink(t, `type = "command"
+command = '{ cat; echo; echo "$GHOST_TAB_NOTIFY_EVENT $GHOST_TAB_NOTIFY_PROJECT"; } > "`+out+`"'`)
+	if err := Deliver(context.Background(), s, sampleEvent(), noSleep); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	stdin, env, _ := strings.Cut(string(data), "\n")
+	var got Event
+	if err := json.Unmarshal([]byte(stdin), &got); err != nil || got.Message != sampleEvent().Message {
+		t.Errorf("stdin = %s (%v)", stdin, err)
+	}
+	if env != "Stop my-app\n" {
+		t.Errorf("env = %q", env)
+	}
+}
+
+func TestCommandSink_reports_output_on_failure(t *testing.T) {
+	s := decodeOneSink(t, `type = "command"
+command = "echo no route to host >&2; exit 3"
+attempts = 1`)
+	err := Deliver(context.Background(), s, sampleEvent(), noSleep)
+	if err == nil || !strings.Contains(err.Error(), "no route to host") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestFileSink_appends_lines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log", "events.jsonl")
+	s := decodeOneSink(t, `type = "file"`+"\n"+`path = "`+path+`"`)
+	for i := 0; i < 2; i++ {
+		if err := Deliver(context.Background(), s, sampleEvent(), noSleep); err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), data)
+	}
+	var got Event
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil || got.Project != "my-app" {
+		t.Errorf("line = %s (%v)", lines[1], err)
+	}
+}
+
+func TestSink_events_filter(t *testing.T) {
+	s := decodeOneSink(t, `type = "file"
+path = "/tmp/x"
+events = ["Notification"]`)
+	if s.Wants(EventStop) || !s.Wants(EventNotification) {
+		t.Error("sink should only want Notification")
+	}
+	cfg := Config{Events: DefaultConfig().Events, Sinks: []Sink{s}}
+	if got := cfg.Decide(EventStop, Context{Now: at(12, 0)}); strings.Contains(strings.Join(got, ","), ActionSinks) {
+		t.Errorf("Decide(Stop) = %v, want no sinks action", got)
+	}
+	if got := cfg.Decide(EventNotification, Context{Now: at(12, 0)}); !strings.Contains(strings.Join(got, ","), ActionSinks) {
+		t.Errorf("Decide(Notification) = %v, want the sinks action", got)
+	}
+}
+
+func TestDecodeSinks_errors(t *testing.T) {
+	tests := []struct{ doc, want string }{
+		{`type = "pager"`, "type must be one of"},
+		{`type = "file"`, "needs a path"},
+		{`type = "command"`, "needs a command"},
+		{`type = "webhook"` + "\n" + `url = "ftp://x"`, "http:// or https://"},
+		{`type = "webhook"` + "\n" + `url = "https://x"` + "\n" + `format = "teams"`, `unknown format "teams"`},
+		{`type = "webhook"` + "\n" + `url = "https://x"` + "\n" + `format = "ntfy"`, "needs a topic"},
+		{`type = "webhook"` + "\n" + `url = "https://x"` + "\n" + `format = "slack"` + "\n" + `body = "{}"`, "not both"},
+		{`type = "webhook"` + "\n" + `url = "https://x"` + "\n" + `body = '{{.Event.Nope}}'`, "body:"},
+		{`type = "webhook"` + "\n" + `url = "https://x"` + "\n" + `body = '{{.Event.Title}}'`, "not valid JSON"},
+		{`type = "file"` + "\n" + `path = "/x"` + "\n" + `url = "https://x"`, `unknown key "url"`},
+		{`type = "file"` + "\n" + `path = "/x"` + "\n" + `retries = 2`, `unknown key "sinks.retries"`},
+		{`type = "file"` + "\n" + `path = "/x"` + "\n" + `attempts = 0`, "attempts must be"},
+		{`type = "file"` + "\n" + `path = "/x"` + "\n" + `backoff = "soon"`, `"sinks.backoff"): must be a duration`},
+		{`type = "file"` + "\n" + `path = "/x"` + "\n" + `events = ["Done"]`, "unknown event Done"},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		err := cfg.Decode([]byte("[[sinks]]\n" + tt.doc))
+		// Values toml rejects are named by key, the rest by sink
+		if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "sinks") {
+			t.Errorf("Decode(%q) error = %v, want %q", tt.doc, err, tt.want)
+		}
+	}
+}
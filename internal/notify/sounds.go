@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultSoundName is the sound used when none has been chosen.
+const DefaultSoundName = "Bottle"
+
+// fallbackSoundNames are tried in order when DefaultSoundName is not
+// installed, as on Linux where the freedesktop theme provides "complete".
+var fallbackSoundNames = []string{DefaultSoundName, "complete", "message", "bell"}
+
+// Sound is a notification sound installed on this system.
+type Sound struct {
+	Name string `json:"name"` // file name without extension, e.g. "Bottle"
+	Path string `json:"path"`
+}
+
+// SoundDirs returns the directories notification sounds are installed in
+// on goos, the user's own directories first.
+func SoundDirs(goos, home string, getenv func(string) string) []string {
+	if goos == "darwin" {
+		return []string{
+			filepath.Join(home, "Library", "Sounds"),
+			"/Library/Sounds",
+			"/System/Library/Sounds",
+		}
+	}
+	// Sound themes live under the XDG data directories, e.g.
+	// /usr/share/sounds/freedesktop/stereo/complete.oga
+	dataHome := getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	dataDirs := getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	dirs := []string{filepath.Join(dataHome, "sounds")}
+	for _, d := range strings.Split(dataDirs, ":") {
+		if d != "" {
+			dirs = append(dirs, filepath.Join(d, "sounds"))
+		}
+	}
+	return dirs
+}
+
+// DiscoverSounds finds the sound files with one of the given extensions in
+// dirs and their subdirectories. When two files share a name the one found
+// first wins. Missing directories are skipped. The result is sorted by name.
+func DiscoverSounds(dirs []string, extensions []string) []Sound {
+	seen := map[string]bool{}
+	var sounds []Sound
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			ext := filepath.Ext(d.Name())
+			if !hasExtension(extensions, ext) {
+				return nil
+			}
+			name := strings.TrimSuffix(d.Name(), ext)
+			if name == "" || seen[name] {
+				return nil
+			}
+			seen[name] = true
+			sounds = append(sounds, Sound{Name: name, Path: path})
+			return nil
+		})
+	}
+	sort.Slice(sounds, func(i, j int) bool {
+		a, b := strings.ToLower(sounds[i].Name), strings.ToLower(sounds[j].Name)
+		if a != b {
+			return a < b
+		}
+		return sounds[i].Name < sounds[j].Name
+	})
+	return sounds
+}
+
+func hasExtension(extensions []string, ext string) bool {
+	for _, e := range extensions {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindSound returns the sound called name.
+func FindSound(sounds []Sound, name string) (Sound, bool) {
+	for _, s := range sounds {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Sound{}, false
+}
+
+// DefaultSound returns the sound to use when none has been chosen or the
+// chosen one is not installed: DefaultSoundName or a common freedesktop
+// sound if present, otherwise the first sound. It reports false when there
+// are no sounds at all.
+func DefaultSound(sounds []Sound) (Sound, bool) {
+	for _, name := range fallbackSoundNames {
+		if s, ok := FindSound(sounds, name); ok {
+			return s, true
+		}
+	}
+	if len(sounds) == 0 {
+		return Sound{}, false
+	}
+	return sounds[0], true
+}
+
+// SoundNames returns the names of sounds, in order.
+func SoundNames(sounds []Sound) []string {
+	names := make([]string, len(sounds))
+	for i, s := range sounds {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// features is the part of {tool}-features.json notifications read.
+type features struct {
+	Sound     *bool  `json:"sound"`
+	SoundName string `json:"sound_name"`
+	Sinks     *bool  `json:"sinks"`
+}
+
+// readFeatures reads the features file at path. A missing or unreadable
+// file reads as empty, so everything is on with default settings.
+func readFeatures(path string) features {
+	var f features
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &f) != nil {
+		return features{}
+	}
+	return f
+}
+
+// SoundSetting returns the sound chosen in the features file at path
+// ({tool}-features.json), "" when sound is turned off. Like get_sound_name
+// in lib/notification-setup.sh, a missing or unreadable file means the
+// default sound.
+func SoundSetting(path string) string {
+	f := readFeatures(path)
+	if f.Sound != nil && !*f.Sound {
+		return ""
+	}
+	if f.SoundName == "" {
+		return DefaultSoundName
+	}
+	return f.SoundName
+}
+
+// SinksEnabled reports whether the features file at path leaves the
+// notification sinks on; "sinks": false mutes them.
+func SinksEnabled(path string) bool {
+	f := readFeatures(path)
+	return f.Sinks == nil || *f.Sinks
+}
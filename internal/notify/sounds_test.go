@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSounds(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSoundDirs_darwin(t *testing.T) {
+	got := SoundDirs("darwin", "/Users/me", func(string) string { return "" })
+	want := []string{"/Users/me/Library/Sounds", "/Library/Sounds", "/System/Library/Sounds"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SoundDirs(darwin) = %v, want %v", got, want)
+	}
+}
+
+func TestSoundDirs_linux_defaults(t *testing.T) {
+	got := SoundDirs("linux", "/home/me", func(string) string { return "" })
+	want := []string{"/home/me/.local/share/sounds", "/usr/local/share/sounds", "/usr/share/sounds"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SoundDirs(linux) = %v, want %v", got, want)
+	}
+}
+
+func TestSoundDirs_linux_uses_XDG_variables(t *testing.T) {
+	env := map[string]string{
+		"XDG_DATA_HOME": "/data/home",
+		"XDG_DATA_DIRS": "/opt/share::/usr/share",
+	}
+	got := SoundDirs("linux", "/home/me", func(k string) string { return env[k] })
+	want := []string{"/data/home/sounds", "/opt/share/sounds", "/usr/share/sounds"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SoundDirs = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverSounds_walks_themes_and_sorts(t *testing.T) {
+	dir := t.TempDir()
+	writeSounds(t, dir,
+		"freedesktop/stereo/complete.oga",
+		"freedesktop/stereo/bell.oga",
+		"freedesktop/index.theme",
+		"Zap.oga",
+	)
+	got := DiscoverSounds([]string{dir}, []string{".oga"})
+	if names := SoundNames(got); !reflect.DeepEqual(names, []string{"bell", "complete", "Zap"}) {
+		t.Fatalf("names = %v", names)
+	}
+	if want := filepath.Join(dir, "freedesktop/stereo/bell.oga"); got[0].Path != want {
+		t.Errorf("bell path = %q, want %q", got[0].Path, want)
+	}
+}
+
+func TestDiscoverSounds_earlier_directory_wins(t *testing.T) {
+	user, system := t.TempDir(), t.TempDir()
+	writeSounds(t, user, "bell.oga")
+	writeSounds(t, system, "bell.oga", "complete.oga")
+	got := DiscoverSounds([]string{user, filepath.Join(user, "missing"), system}, []string{".oga"})
+	if len(got) != 2 {
+		t.Fatalf("got %v, want 2 sounds", got)
+	}
+	if got[0].Path != filepath.Join(user, "bell.oga") {
+		t.Errorf("bell from %q, want the user directory", got[0].Path)
+	}
+}
+
+func TestDiscoverSounds_extension_is_case_insensitive(t *testing.T) {
+	dir := t.TempDir()
+	writeSounds(t, dir, "Glass.AIFF")
+	if got := SoundNames(DiscoverSounds([]string{dir}, []string{".aiff"})); !reflect.DeepEqual(got, []string{"Glass"}) {
+		t.Errorf("names = %v", got)
+	}
+}
+
+func TestDefaultSound(t *testing.T) {
+	mac := []Sound{{Name: "Basso"}, {Name: "Bottle"}, {Name: "Tink"}}
+	if s, _ := DefaultSound(mac); s.Name != "Bottle" {
+		t.Errorf("macOS default = %q, want Bottle", s.Name)
+	}
+	linux := []Sound{{Name: "bell"}, {Name: "complete"}, {Name: "message"}}
+	if s, _ := DefaultSound(linux); s.Name != "complete" {
+		t.Errorf("freedesktop default = %q, want complete", s.Name)
+	}
+	other := []Sound{{Name: "ding"}, {Name: "dong"}}
+	if s, _ := DefaultSound(other); s.Name != "ding" {
+		t.Errorf("default = %q, want the first sound", s.Name)
+	}
+	if _, ok := DefaultSound(nil); ok {
+		t.Error("DefaultSound(nil) reported a sound")
+	}
+}
+
+func TestFindSound(t *testing.T) {
+	sounds := []Sound{{Name: "bell", Path: "/s/bell.oga"}}
+	if s, ok := FindSound(sounds, "bell"); !ok || s.Path != "/s/bell.oga" {
+		t.Errorf("FindSound(bell) = %+v, %v", s, ok)
+	}
+	if _, ok := FindSound(sounds, "Bell"); ok {
+		t.Error("FindSound should match names exactly")
+	}
+}
+
+func TestSoundSetting(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct{ content, want string }{
+		{`{"sound": true, "sound_name": "Glass"}`, "Glass"},
+		{`{"sound_name": "complete"}`, "complete"},
+		{`{"sound": false, "sound_name": "Glass"}`, ""},
+		{`{"sound": true}`, DefaultSoundName},
+		{`not json`, DefaultSoundName},
+	}
+	for i, tt := range tests {
+		path := filepath.Join(dir, fmt.Sprintf("features-%d.json", i))
+		os.WriteFile(path, []byte(tt.content), 0644)
+		if got := SoundSetting(path); got != tt.want {
+			t.Errorf("SoundSetting(%s) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+	if got := SoundSetting(filepath.Join(dir, "missing.json")); got != DefaultSoundName {
+		t.Errorf("missing file = %q, want %q", got, DefaultSoundName)
+	}
+}
@@ -0,0 +1,126 @@
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultGrace is how long terminated processes get to exit before they
+// are killed.
+const DefaultGrace = 300 * time.Millisecond
+
+// pollInterval is how often survivors are checked during the grace period.
+const pollInterval = 10 * time.Millisecond
+
+// Report records what KillTrees did.
+type Report struct {
+	// Terminated lists every PID that was sent SIGTERM.
+	Terminated []int `json:"terminated"`
+	// Escalated lists the PIDs still running after the grace period that
+	// had to be sent SIGKILL.
+	Escalated []int `json:"escalated"`
+}
+
+// KillTrees terminates the process trees rooted at roots: every process
+// gets SIGTERM (children before parents), then up to grace to exit.
+// Survivors, including children forked during the grace period, are sent
+// SIGKILL and reported as escalated.
+func KillTrees(roots []int, grace time.Duration) (Report, error) {
+	report := Report{Terminated: []int{}, Escalated: []int{}}
+	if len(roots) == 0 {
+		return report, nil
+	}
+
+	snap, err := ReadSnapshot()
+	if err != nil {
+		return report, err
+	}
+	for _, pid := range collect(snap, roots) {
+		if syscall.Kill(pid, syscall.SIGTERM) == nil {
+			report.Terminated = append(report.Terminated, pid)
+		}
+	}
+
+	deadline := time.Now().Add(grace)
+	for anyAlive(report.Terminated) && time.Now().Before(deadline) {
+		time.Sleep(pollInterval)
+	}
+
+	// Re-read the snapshot so processes spawned after the first pass are
+	// caught too. Terminated processes still running are walked as roots of
+	// their own: one whose parent exited on SIGTERM has been reparented out
+	// of the original trees.
+	if snap, err = ReadSnapshot(); err != nil {
+		return report, err
+	}
+	survivors := append([]int(nil), roots...)
+	for _, pid := range report.Terminated {
+		if Alive(pid) {
+			survivors = append(survivors, pid)
+		}
+	}
+	for _, pid := range collect(snap, survivors) {
+		if !Alive(pid) {
+			continue
+		}
+		if syscall.Kill(pid, syscall.SIGKILL) == nil {
+			report.Escalated = append(report.Escalated, pid)
+		}
+	}
+	return report, nil
+}
+
+// collect returns the trees of all roots, deduplicated, in kill order.
+func collect(snap Snapshot, roots []int) []int {
+	var pids []int
+	seen := map[int]bool{}
+	for _, root := range roots {
+		for _, pid := range snap.Tree(root) {
+			if !seen[pid] {
+				seen[pid] = true
+				pids = append(pids, pid)
+			}
+		}
+	}
+	return pids
+}
+
+func anyAlive(pids []int) bool {
+	for _, pid := range pids {
+		if Alive(pid) {
+			return true
+		}
+	}
+	return false
+}
+
+// Alive reports whether pid is a running process. Zombies (exited but not
+// yet reaped by their parent) count as dead.
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	if err := syscall.Kill(pid, 0); err != nil && !errors.Is(err, syscall.EPERM) {
+		return false
+	}
+	return !isZombie(pid)
+}
+
+// isZombie checks the process state via /proc, falling back to ps.
+func isZombie(pid int) bool {
+	if data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "stat")); err == nil {
+		_, state, ok := parseStat(string(data), 1)
+		return ok && state == 'Z'
+	}
+	out, err := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(string(out)), "Z")
+}
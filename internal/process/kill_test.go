@@ -0,0 +1,217 @@
+package process
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// spawnTree starts a bash script and waits until it has at least want
+// processes in its tree (itself included). The returned command is reaped
+// when the test ends.
+func spawnTree(t *testing.T, script string, want int) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("bash", "-c", script)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting process tree: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		KillTrees([]int{cmd.Process.Pid}, 0)
+		<-done
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		snap, err := ReadSnapshot()
+		if err == nil && len(snap.Tree(cmd.Process.Pid)) >= want {
+			return cmd
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("process tree never reached %d processes", want)
+	return nil
+}
+
+func treeOf(t *testing.T, pid int) []int {
+	t.Helper()
+	snap, err := ReadSnapshot()
+	if err != nil {
+		t.Fatalf("ReadSnapshot: %v", err)
+	}
+	return snap.Tree(pid)
+}
+
+func TestKillTrees_NoRoots(t *testing.T) {
+	report, err := KillTrees(nil, time.Second)
+	if err != nil {
+		t.Fatalf("KillTrees: %v", err)
+	}
+	if len(report.Terminated) != 0 || len(report.Escalated) != 0 {
+		t.Errorf("expected empty report, got %+v", report)
+	}
+}
+
+func TestKillTrees_TerminatesWholeTree(t *testing.T) {
+	cmd := spawnTree(t, "sleep 300 & (sleep 300 & wait) & wait", 4)
+	pids := treeOf(t, cmd.Process.Pid)
+
+	report, err := KillTrees([]int{cmd.Process.Pid}, 2*time.Second)
+	if err != nil {
+		t.Fatalf("KillTrees: %v", err)
+	}
+
+	for _, pid := range pids {
+		if Alive(pid) {
+			t.Errorf("pid %d still alive after KillTrees", pid)
+		}
+	}
+	if len(report.Escalated) != 0 {
+		t.Errorf("expected no escalation for TERM-respecting tree, got %v", report.Escalated)
+	}
+	// The root may exit (and be reaped) on its own once its children are
+	// gone, so it is not necessarily signalled itself.
+	inTree := map[int]bool{}
+	for _, pid := range pids {
+		inTree[pid] = true
+	}
+	if len(report.Terminated) < len(pids)-1 {
+		t.Errorf("terminated: got %v, want at least the descendants of %v", report.Terminated, pids)
+	}
+	for _, pid := range report.Terminated {
+		if !inTree[pid] {
+			t.Errorf("terminated pid %d outside the tree %v", pid, pids)
+		}
+	}
+}
+
+func TestKillTrees_EscalatesTermIgnoringProcesses(t *testing.T) {
+	// Ignored signals are inherited, so both bash and sleep ignore TERM.
+	cmd := spawnTree(t, "trap '' TERM; sleep 300 & wait", 2)
+	pids := treeOf(t, cmd.Process.Pid)
+
+	start := time.Now()
+	report, err := KillTrees([]int{cmd.Process.Pid}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("KillTrees: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected to wait the grace period, returned after %v", elapsed)
+	}
+
+	// The sleep ignores TERM and must be escalated; bash then exits on its
+	// own as soon as the sleep it is waiting for is gone.
+	leaf := pids[0]
+	var found bool
+	for _, pid := range report.Escalated {
+		if pid == leaf {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("pid %d ignored TERM and should be reported as escalated (got %v)", leaf, report.Escalated)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for _, pid := range pids {
+		for Alive(pid) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if Alive(pid) {
+			t.Errorf("pid %d still alive after SIGKILL", pid)
+		}
+	}
+}
+
+func TestKillTrees_EscalatesOrphanedGrandchildren(t *testing.T) {
+	// The root and the middle bash exit on TERM; the subshell ignores it,
+	// as does its sleep, and is reparented once the middle bash is gone.
+	cmd := spawnTree(t, `bash -c '(trap "" TERM; sleep 300; :) & wait' & wait`, 4)
+	pids := treeOf(t, cmd.Process.Pid)
+
+	report, err := KillTrees([]int{cmd.Process.Pid}, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("KillTrees: %v", err)
+	}
+
+	leaf := pids[0]
+	var found bool
+	for _, pid := range report.Escalated {
+		if pid == leaf {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("orphaned pid %d ignored TERM and should be escalated (got %v)", leaf, report.Escalated)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for _, pid := range pids {
+		for Alive(pid) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if Alive(pid) {
+			t.Errorf("pid %d still alive after KillTrees", pid)
+		}
+	}
+}
+
+func TestKillTrees_ReturnsEarlyWhenTreeExits(t *testing.T) {
+	cmd := spawnTree(t, "sleep 300 & wait", 2)
+
+	start := time.Now()
+	if _, err := KillTrees([]int{cmd.Process.Pid}, 10*time.Second); err != nil {
+		t.Fatalf("KillTrees: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected early return once the tree exited, took %v", elapsed)
+	}
+}
+
+func TestKillTrees_MissingRoot(t *testing.T) {
+	report, err := KillTrees([]int{999999999}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("KillTrees: %v", err)
+	}
+	if len(report.Terminated) != 0 || len(report.Escalated) != 0 {
+		t.Errorf("expected nothing signalled for a missing pid, got %+v", report)
+	}
+}
+
+func TestAlive(t *testing.T) {
+	if Alive(0) || Alive(-1) {
+		t.Error("non-positive pids are never alive")
+	}
+	if Alive(999999999) {
+		t.Error("nonexistent pid should not be alive")
+	}
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("running true: %v", err)
+	}
+	if Alive(cmd.Process.Pid) {
+		t.Error("reaped process should not be alive")
+	}
+}
+
+func TestAlive_ZombieIsDead(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting true: %v", err)
+	}
+	defer cmd.Wait()
+
+	// Not reaped yet, so the process lingers as a zombie.
+	deadline := time.Now().Add(5 * time.Second)
+	for Alive(cmd.Process.Pid) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if Alive(cmd.Process.Pid) {
+		t.Error("zombie process should not count as alive")
+	}
+}
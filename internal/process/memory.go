@@ -0,0 +1,98 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ancestor returns the nearest process named comm among pid and its
+// parents, or 0 if there is none.
+func (s Snapshot) Ancestor(pid int, comm string) int {
+	// The depth bound guards against a snapshot with a cycle
+	for depth := 0; pid > 1 && depth < 64; depth++ {
+		proc, ok := s[pid]
+		if !ok {
+			return 0
+		}
+		if proc.Comm == comm {
+			return pid
+		}
+		pid = proc.PPID
+	}
+	return 0
+}
+
+// TreeRSSKB returns the resident memory of root and all its descendants
+// in kilobytes.
+func (s Snapshot) TreeRSSKB(root int) int64 {
+	kb, _ := s.tree(root, s.children())
+	return kb
+}
+
+// tree returns the resident memory and number of processes in the tree
+// under root.
+func (s Snapshot) tree(root int, children map[int][]int) (kb int64, count int) {
+	seen := map[int]bool{}
+	queue := []int{root}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		proc, ok := s[pid]
+		if !ok || seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		kb += proc.RSSKB
+		count++
+		queue = append(queue, children[pid]...)
+	}
+	return kb, count
+}
+
+// TreeMemory is the memory of one process and everything under it.
+type TreeMemory struct {
+	PID       int
+	Comm      string
+	RSSKB     int64
+	Processes int
+}
+
+// Breakdown returns the memory of each child of root with its own
+// descendants, heaviest first, so a heavy MCP or language server stands
+// out with the tools it spawned. top limits the result; 0 keeps all.
+func (s Snapshot) Breakdown(root, top int) []TreeMemory {
+	children := s.children()
+	var trees []TreeMemory
+	for _, pid := range children[root] {
+		kb, count := s.tree(pid, children)
+		trees = append(trees, TreeMemory{PID: pid, Comm: s[pid].Comm, RSSKB: kb, Processes: count})
+	}
+	sort.Slice(trees, func(i, j int) bool {
+		if trees[i].RSSKB != trees[j].RSSKB {
+			return trees[i].RSSKB > trees[j].RSSKB
+		}
+		return trees[i].PID < trees[j].PID
+	})
+	if top > 0 && len(trees) > top {
+		trees = trees[:top]
+	}
+	return trees
+}
+
+// CommandLine returns the full command line of pid for display, or "" if
+// it can't be read.
+func CommandLine(pid int) string {
+	id := strconv.Itoa(pid)
+	if data, err := os.ReadFile(filepath.Join(procRoot, id, "cmdline")); err == nil {
+		return strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+	}
+	out, err := exec.Command("ps", "-o", "args=", "-p", id).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
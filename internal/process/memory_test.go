@@ -0,0 +1,63 @@
+package process
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot_Ancestor(t *testing.T) {
+	snap := ParsePS(psSnapshot)
+	if got := snap.Ancestor(104, "claude"); got != 100 {
+		t.Errorf("Ancestor(104) = %d, want 100", got)
+	}
+	if got := snap.Ancestor(100, "claude"); got != 100 {
+		t.Errorf("a claude process is its own ancestor, got %d", got)
+	}
+	if got := snap.Ancestor(999, "claude"); got != 0 {
+		t.Errorf("unknown pid = %d", got)
+	}
+
+	loop := Snapshot{5: {PID: 5, PPID: 6, Comm: "a"}, 6: {PID: 6, PPID: 5, Comm: "b"}}
+	if got := loop.Ancestor(5, "claude"); got != 0 {
+		t.Errorf("cycle = %d", got)
+	}
+}
+
+func TestSnapshot_TreeRSSKB(t *testing.T) {
+	snap := ParsePS(psSnapshot)
+	// 51200 + 25600 + 10240 + 5120 + 512
+	if got := snap.TreeRSSKB(100); got != 92672 {
+		t.Errorf("TreeRSSKB(100) = %d, want 92672", got)
+	}
+	if got := snap.TreeRSSKB(103); got != 5120 {
+		t.Errorf("leaf = %d", got)
+	}
+	if got := snap.TreeRSSKB(999); got != 0 {
+		t.Errorf("missing = %d", got)
+	}
+}
+
+func TestSnapshot_Breakdown(t *testing.T) {
+	snap := ParsePS(`  100     1  51200 claude
+  101   100  25600 npm
+  103   101 204800 node
+  102   100  10240 zsh
+  104   102    512 bash
+  105   100  30000 gopls
+`)
+	got := snap.Breakdown(100, 0)
+	want := []TreeMemory{
+		{PID: 101, Comm: "npm", RSSKB: 230400, Processes: 2},
+		{PID: 105, Comm: "gopls", RSSKB: 30000, Processes: 1},
+		{PID: 102, Comm: "zsh", RSSKB: 10752, Processes: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Breakdown = %+v, want %+v", got, want)
+	}
+	if got := snap.Breakdown(100, 2); len(got) != 2 || got[1].PID != 105 {
+		t.Errorf("top 2 = %+v", got)
+	}
+	if got := snap.Breakdown(104, 0); len(got) != 0 {
+		t.Errorf("a leaf has no breakdown, got %+v", got)
+	}
+}
@@ -0,0 +1,156 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// procRoot is where the Linux process filesystem is mounted.
+var procRoot = "/proc"
+
+// Process is one row of a Snapshot.
+type Process struct {
+	PID   int
+	PPID  int
+	RSSKB int64
+	Comm  string
+}
+
+// Snapshot is the process table at one moment, by PID.
+type Snapshot map[int]Process
+
+// ReadSnapshot snapshots the running processes in one pass, walking /proc
+// when it is available (Linux) and falling back to a single `ps`
+// elsewhere (macOS).
+func ReadSnapshot() (Snapshot, error) {
+	if snap, err := readProcSnapshot(procRoot); err == nil {
+		return snap, nil
+	}
+	return readPSSnapshot()
+}
+
+// readProcSnapshot builds a Snapshot from the stat files under root.
+func readProcSnapshot(root string) (Snapshot, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	pageKB := int64(os.Getpagesize() / 1024)
+	snap := Snapshot{}
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, e.Name(), "stat"))
+		if err != nil {
+			// The process exited while we were walking.
+			continue
+		}
+		if p, _, ok := parseStat(string(data), pageKB); ok {
+			snap[p.PID] = p
+		}
+	}
+	if len(snap) == 0 {
+		return nil, fmt.Errorf("no processes found in %s", root)
+	}
+	return snap, nil
+}
+
+// parseStat parses /proc/<pid>/stat, returning the process and its state.
+// The command name is wrapped in parentheses and may itself contain spaces
+// or parentheses, so fields are read after the last ')'; rss is the 24th
+// field, in pages of pageKB.
+func parseStat(data string, pageKB int64) (p Process, state byte, ok bool) {
+	start := strings.IndexByte(data, '(')
+	end := strings.LastIndexByte(data, ')')
+	if start < 0 || end < start {
+		return Process{}, 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(data[:start]))
+	if err != nil {
+		return Process{}, 0, false
+	}
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 || len(fields[0]) != 1 {
+		return Process{}, 0, false
+	}
+	ppid, err1 := strconv.Atoi(fields[1])
+	rss, err2 := strconv.ParseInt(fields[21], 10, 64)
+	if err1 != nil || err2 != nil {
+		return Process{}, 0, false
+	}
+	p = Process{PID: pid, PPID: ppid, RSSKB: rss * pageKB, Comm: data[start+1 : end]}
+	return p, fields[0][0], true
+}
+
+// readPSSnapshot builds a Snapshot from `ps -axo pid=,ppid=,rss=,comm=`.
+func readPSSnapshot() (Snapshot, error) {
+	out, err := exec.Command("ps", "-axo", "pid=,ppid=,rss=,comm=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing processes: %w", err)
+	}
+	return ParsePS(string(out)), nil
+}
+
+// ParsePS parses `ps -axo pid=,ppid=,rss=,comm=` output. Commands keep
+// only their base name; malformed lines are skipped.
+func ParsePS(out string) Snapshot {
+	snap := Snapshot{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		rss, err3 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		comm := filepath.Base(strings.Join(fields[3:], " "))
+		snap[pid] = Process{PID: pid, PPID: ppid, RSSKB: rss, Comm: comm}
+	}
+	return snap
+}
+
+// children maps each PID to the PIDs of its children, in PID order.
+func (s Snapshot) children() map[int][]int {
+	children := map[int][]int{}
+	for _, p := range s {
+		if p.PID != p.PPID {
+			children[p.PPID] = append(children[p.PPID], p.PID)
+		}
+	}
+	for _, c := range children {
+		sort.Ints(c)
+	}
+	return children
+}
+
+// Tree returns pid and all of its descendants, deepest first, so that
+// children are signalled before their parents. pid itself is always
+// included last, even when it is not in the snapshot.
+func (s Snapshot) Tree(pid int) []int {
+	children := s.children()
+
+	var order []int
+	seen := map[int]bool{}
+	var walk func(int)
+	walk = func(p int) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		for _, c := range children[p] {
+			walk(c)
+		}
+		order = append(order, p)
+	}
+	walk(pid)
+	return order
+}
@@ -0,0 +1,171 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseStat(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      Process
+		wantState byte
+		wantOK    bool
+	}{
+		{"simple", "1234 (bash) S 1000 1234 1234 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0 250 0 0",
+			Process{PID: 1234, PPID: 1000, RSSKB: 1000, Comm: "bash"}, 'S', true},
+		{"spaces in comm", "42 (tmux: server) S 1 42 42 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0 3 0 0",
+			Process{PID: 42, PPID: 1, RSSKB: 12, Comm: "tmux: server"}, 'S', true},
+		{"parens in comm", "7 (a) b (c)) Z 3 7 7 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0 0 0 0",
+			Process{PID: 7, PPID: 3, Comm: "a) b (c)"}, 'Z', true},
+		{"no comm", "garbage", Process{}, 0, false},
+		{"bad pid", "x (a) S 1 2 3", Process{}, 0, false},
+		{"truncated", "9 (x) S 1 9 9 0", Process{}, 0, false},
+		{"bad ppid", "9 (x) S nope 9 9 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0 3 0 0", Process{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, state, ok := parseStat(tt.data, 4)
+			if ok != tt.wantOK || p != tt.want || state != tt.wantState {
+				t.Errorf("parseStat(%q) = (%+v, %q, %v), want (%+v, %q, %v)",
+					tt.data, p, state, ok, tt.want, tt.wantState, tt.wantOK)
+			}
+		})
+	}
+}
+
+const psSnapshot = `    1     0   1024 /sbin/launchd
+  100     1  51200 /Users/me/.local/bin/claude
+  101   100  25600 node
+  102   100  10240 /bin/zsh
+  103   101   5120 /usr/local/bin/some mcp server
+  104   102    512 bash
+  200     1  99999 claude
+  bad  line
+`
+
+func TestParsePS(t *testing.T) {
+	snap := ParsePS(psSnapshot)
+	if len(snap) != 7 {
+		t.Fatalf("expected 7 processes, got %d", len(snap))
+	}
+	if p := snap[100]; p.PPID != 1 || p.RSSKB != 51200 || p.Comm != "claude" {
+		t.Errorf("claude = %+v", p)
+	}
+	if p := snap[103]; p.Comm != "some mcp server" {
+		t.Errorf("command with spaces = %q", p.Comm)
+	}
+}
+
+// writeProcFS creates a fake proc filesystem holding procs.
+func writeProcFS(t *testing.T, procs []Process) string {
+	t.Helper()
+	root := t.TempDir()
+	pageKB := int64(os.Getpagesize() / 1024)
+	for _, p := range procs {
+		dir := filepath.Join(root, strconv.Itoa(p.PID))
+		os.Mkdir(dir, 0755)
+		stat := fmt.Sprintf("%d (%s) S %d 0 0 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 100 0 %d 0 0\n",
+			p.PID, p.Comm, p.PPID, p.RSSKB/pageKB)
+		os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644)
+	}
+	// Entries that aren't processes, and one that exited mid-read
+	os.Mkdir(filepath.Join(root, "self"), 0755)
+	os.Mkdir(filepath.Join(root, "99"), 0755)
+	return root
+}
+
+func TestReadProcSnapshot(t *testing.T) {
+	pageKB := int64(os.Getpagesize() / 1024)
+	want := Snapshot{
+		1:  {PID: 1, PPID: 0, RSSKB: 10 * pageKB, Comm: "init"},
+		20: {PID: 20, PPID: 1, RSSKB: 20 * pageKB, Comm: "bash"},
+		21: {PID: 21, PPID: 20, RSSKB: 500 * pageKB, Comm: "claude code"},
+	}
+	root := writeProcFS(t, []Process{want[1], want[20], want[21]})
+
+	snap, err := readProcSnapshot(root)
+	if err != nil {
+		t.Fatalf("readProcSnapshot: %v", err)
+	}
+	if !reflect.DeepEqual(snap, want) {
+		t.Errorf("got %+v, want %+v", snap, want)
+	}
+}
+
+func TestReadProcSnapshot_Empty(t *testing.T) {
+	if _, err := readProcSnapshot(t.TempDir()); err == nil {
+		t.Fatal("expected error for directory without processes")
+	}
+}
+
+func TestReadSnapshot_FallsBackToPS(t *testing.T) {
+	if _, err := exec.LookPath("ps"); err != nil {
+		t.Skip("ps not available")
+	}
+	prev := procRoot
+	procRoot = filepath.Join(t.TempDir(), "missing")
+	defer func() { procRoot = prev }()
+
+	snap, err := ReadSnapshot()
+	if err != nil {
+		t.Fatalf("ReadSnapshot: %v", err)
+	}
+	if p, ok := snap[os.Getpid()]; !ok || p.PPID != os.Getppid() {
+		t.Errorf("the ps snapshot should include this process, got %+v (present: %v)", p, ok)
+	}
+}
+
+// parents builds a Snapshot from a map of PID to parent PID.
+func parents(m map[int]int) Snapshot {
+	snap := Snapshot{}
+	for pid, ppid := range m {
+		snap[pid] = Process{PID: pid, PPID: ppid}
+	}
+	return snap
+}
+
+func TestSnapshot_Tree_ChildrenFirst(t *testing.T) {
+	snap := parents(map[int]int{
+		1:  0,
+		10: 1,
+		11: 10,
+		12: 10,
+		13: 11,
+		20: 1,
+	})
+	want := []int{13, 11, 12, 10}
+	if got := snap.Tree(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tree(10): got %v, want %v", got, want)
+	}
+}
+
+func TestSnapshot_Tree_UnknownPID(t *testing.T) {
+	if got := parents(map[int]int{1: 0}).Tree(555); !reflect.DeepEqual(got, []int{555}) {
+		t.Errorf("Tree of unknown pid: got %v, want [555]", got)
+	}
+}
+
+func TestSnapshot_Tree_Cycle(t *testing.T) {
+	// A corrupt snapshot must not recurse forever.
+	snap := parents(map[int]int{5: 6, 6: 5})
+	if got := snap.Tree(5); len(got) != 2 {
+		t.Errorf("expected both pids once, got %v", got)
+	}
+}
+
+func TestReadSnapshot_IncludesSelf(t *testing.T) {
+	snap, err := ReadSnapshot()
+	if err != nil {
+		t.Fatalf("ReadSnapshot: %v", err)
+	}
+	if p, ok := snap[os.Getpid()]; !ok || p.PPID != os.Getppid() || p.RSSKB == 0 {
+		t.Errorf("expected own pid with parent %d and some memory, got %+v (present: %v)", os.Getppid(), p, ok)
+	}
+}
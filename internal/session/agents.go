@@ -0,0 +1,90 @@
+package session
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// Agent is an AI tool running in a session.
+type Agent struct {
+	Name string // shown on the pane border when several agents run
+	// Dir is the directory the agent works in, e.g. a worktree. Empty
+	// means the project directory.
+	Dir  string
+	Argv []string // command and arguments, passed to the tool verbatim
+	// Color is the ANSI 256 color of the agent's pane border label.
+	Color string
+}
+
+// agentPaneFormat labels agent panes with their name in their color and
+// leaves other panes unlabelled. Commas inside the conditional are escaped
+// for tmux.
+const agentPaneFormat = "#{?@ghost-tab-agent,#[fg=colour#{@ghost-tab-color}#,bold] #{@ghost-tab-agent} #[default],}"
+
+// paneDir returns the directory a layout pane starts in: the first agent's
+// for the AI pane, otherwise the project directory.
+func (cfg Config) paneDir(p Pane) string {
+	if strings.Contains(p.Command, "{ai}") && len(cfg.Agents) > 0 {
+		return cfg.agentDir(cfg.Agents[0])
+	}
+	return cfg.ProjectDir
+}
+
+// agentArgs builds the tmux commands that start every agent after the
+// first. They split the AI pane into equal columns, left to right, and each
+// agent's pane border shows its name in its color; the layout's focus is
+// then restored. A layout without an AI pane opens every agent in its own
+// window instead. A single agent needs nothing beyond the layout.
+func (cfg Config) agentArgs() []string {
+	n := len(cfg.Agents)
+	if n <= 1 {
+		return nil
+	}
+
+	l := cfg.layout()
+	ai := l.AIPane()
+	var args []string
+	if ai < 0 {
+		for _, a := range cfg.Agents {
+			args = append(args, ";", "new-window", "-d", "-t", cfg.SessionName, "-n", a.Name,
+				"-c", cfg.agentDir(a), util.ShellJoin(a.Argv)+"; exec bash")
+		}
+		return args
+	}
+
+	for k := 1; k < n; k++ {
+		a := cfg.Agents[k]
+		// The new pane holds this agent and those still to come.
+		percent := 100 * (n - k) / (n - k + 1)
+		args = append(args, ";", "split-window", "-h", "-t", PaneTarget(cfg.SessionName, ai+k-1),
+			"-p", strconv.Itoa(percent), "-c", cfg.agentDir(a), util.ShellJoin(a.Argv)+"; exec bash")
+	}
+	for k, a := range cfg.Agents {
+		target := PaneTarget(cfg.SessionName, ai+k)
+		args = append(args,
+			";", "set-option", "-p", "-t", target, "@ghost-tab-agent", a.Name,
+			";", "set-option", "-p", "-t", target, "@ghost-tab-color", a.Color,
+		)
+	}
+	window := cfg.SessionName + ":0"
+	args = append(args,
+		";", "set-option", "-w", "-t", window, "pane-border-status", "top",
+		";", "set-option", "-w", "-t", window, "pane-border-format", agentPaneFormat,
+	)
+
+	focus := l.focusPane()
+	if focus > ai {
+		focus += n - 1
+	}
+	return append(args, ";", "select-pane", "-t", PaneTarget(cfg.SessionName, focus))
+}
+
+// agentDir returns the directory agent a starts in.
+func (cfg Config) agentDir(a Agent) string {
+	if a.Dir != "" {
+		return a.Dir
+	}
+	return cfg.ProjectDir
+}
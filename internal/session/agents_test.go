@@ -0,0 +1,102 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func multiAgentConfig() Config {
+	cfg := testConfig()
+	cfg.Env = nil
+	cfg.Agents = []Agent{
+		{Name: "Claude Code", Argv: []string{"/usr/bin/claude"}, Color: "209"},
+		{Name: "Codex CLI", Dir: "/home/user/my-app-feature", Argv: []string{"/usr/bin/codex", "--cd", "/home/user/my-app-feature"}, Color: "114"},
+		{Name: "OpenCode", Argv: []string{"/usr/bin/opencode", "/home/user/my-app"}, Color: "250"},
+	}
+	return cfg
+}
+
+func TestNewSessionArgs_MultipleAgents(t *testing.T) {
+	cmds := splitCommands(NewSessionArgs(multiAgentConfig()))
+
+	// The default layout's commands come first, then the agents.
+	expected := [][]string{
+		{"split-window", "-h", "-t", "dev-my-app-123:0.3", "-p", "66", "-c", "/home/user/my-app-feature",
+			"/usr/bin/codex --cd /home/user/my-app-feature; exec bash"},
+		{"split-window", "-h", "-t", "dev-my-app-123:0.4", "-p", "50", "-c", "/home/user/my-app",
+			"/usr/bin/opencode /home/user/my-app; exec bash"},
+		{"set-option", "-p", "-t", "dev-my-app-123:0.3", "@ghost-tab-agent", "Claude Code"},
+		{"set-option", "-p", "-t", "dev-my-app-123:0.3", "@ghost-tab-color", "209"},
+		{"set-option", "-p", "-t", "dev-my-app-123:0.4", "@ghost-tab-agent", "Codex CLI"},
+		{"set-option", "-p", "-t", "dev-my-app-123:0.4", "@ghost-tab-color", "114"},
+		{"set-option", "-p", "-t", "dev-my-app-123:0.5", "@ghost-tab-agent", "OpenCode"},
+		{"set-option", "-p", "-t", "dev-my-app-123:0.5", "@ghost-tab-color", "250"},
+		{"set-option", "-w", "-t", "dev-my-app-123:0", "pane-border-status", "top"},
+		{"set-option", "-w", "-t", "dev-my-app-123:0", "pane-border-format", agentPaneFormat},
+		{"select-pane", "-t", "dev-my-app-123:0.3"},
+	}
+	if len(cmds) < len(expected) {
+		t.Fatalf("expected at least %d tmux commands, got %q", len(expected), cmds)
+	}
+	got := cmds[len(cmds)-len(expected):]
+	for i := range expected {
+		if strings.Join(got[i], "\x00") != strings.Join(expected[i], "\x00") {
+			t.Errorf("command %d:\n got  %q\n want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestNewSessionArgs_FirstAgentDir(t *testing.T) {
+	cfg := testConfig()
+	cfg.Env = nil
+	cfg.Agents[0].Dir = "/home/user/my-app-wt"
+	cfg.Layout = Layout{Name: "ai", Panes: []Pane{{Command: "{ai}"}, {Command: "htop", Split: "horizontal"}}}
+
+	cmds := splitCommands(NewSessionArgs(cfg))
+	if got := strings.Join(cmds[0], " "); !strings.Contains(got, "-c /home/user/my-app-wt /usr/bin/claude --resume") {
+		t.Errorf("AI pane should start in the agent's directory: %s", got)
+	}
+	if got := strings.Join(cmds[len(cmds)-1], " "); !strings.Contains(got, "-c /home/user/my-app htop") {
+		t.Errorf("other panes should start in the project directory: %s", got)
+	}
+}
+
+func TestNewSessionArgs_AgentsRestoreFocusAfterAIPane(t *testing.T) {
+	cfg := multiAgentConfig()
+	cfg.Agents = cfg.Agents[:2]
+	cfg.Layout = Layout{Name: "ai", Panes: []Pane{
+		{Command: "{ai}"},
+		{Command: "htop", Split: "horizontal", Focus: true},
+	}}
+
+	cmds := splitCommands(NewSessionArgs(cfg))
+	// htop was pane 1 and moves right past the second agent.
+	if got := strings.Join(cmds[len(cmds)-1], " "); got != "select-pane -t dev-my-app-123:0.2" {
+		t.Errorf("last command = %q", got)
+	}
+}
+
+func TestNewSessionArgs_AgentsWithoutAIPaneOpenWindows(t *testing.T) {
+	cfg := multiAgentConfig()
+	cfg.Agents = cfg.Agents[:2]
+	cfg.Layout = Layout{Name: "plain", Panes: []Pane{{Command: "htop"}}}
+
+	cmds := splitCommands(NewSessionArgs(cfg))
+	expected := [][]string{
+		{"new-window", "-d", "-t", "dev-my-app-123", "-n", "Claude Code", "-c", "/home/user/my-app", "/usr/bin/claude; exec bash"},
+		{"new-window", "-d", "-t", "dev-my-app-123", "-n", "Codex CLI", "-c", "/home/user/my-app-feature",
+			"/usr/bin/codex --cd /home/user/my-app-feature; exec bash"},
+	}
+	got := cmds[len(cmds)-len(expected):]
+	for i := range expected {
+		if strings.Join(got[i], "\x00") != strings.Join(expected[i], "\x00") {
+			t.Errorf("command %d:\n got  %q\n want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestNewSessionArgs_SingleAgentAddsNothing(t *testing.T) {
+	if args := testConfig().agentArgs(); args != nil {
+		t.Errorf("agentArgs() = %q, want nil", args)
+	}
+}
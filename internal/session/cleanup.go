@@ -0,0 +1,36 @@
+package session
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/process"
+)
+
+// PanePIDs returns the PIDs of the processes running in every pane of a
+// session. Returns an error if the session does not exist.
+func (t Tmux) PanePIDs(sessionName string) ([]int, error) {
+	out, err := t.Output("list-panes", "-s", "-t", sessionName, "-F", "#{pane_pid}")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, line := range strings.Split(out, "\n") {
+		if pid, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// Cleanup terminates the process tree of every pane in the session,
+// escalating to SIGKILL for anything still running after grace, then
+// kills the session itself. A session that is already gone is not an
+// error; the report is simply empty.
+func Cleanup(t Tmux, sessionName string, grace time.Duration) (process.Report, error) {
+	pids, _ := t.PanePIDs(sessionName)
+	report, err := process.KillTrees(pids, grace)
+	_, _ = t.Output("kill-session", "-t", sessionName)
+	return report, err
+}
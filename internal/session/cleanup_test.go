@@ -0,0 +1,86 @@
+package session
+
+import (
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/process"
+)
+
+func TestPanePIDs(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `printf '101\n102\n\nbogus\n103\n'`)
+
+	pids, err := tmux.PanePIDs("dev-x-1")
+	if err != nil {
+		t.Fatalf("PanePIDs: %v", err)
+	}
+	if want := []int{101, 102, 103}; !reflect.DeepEqual(pids, want) {
+		t.Errorf("got %v, want %v", pids, want)
+	}
+	calls := tmuxCalls(t, logFile)
+	if got := strings.Join(calls[0], " "); got != "list-panes -s -t dev-x-1 -F #{pane_pid}" {
+		t.Errorf("unexpected tmux call: %q", got)
+	}
+}
+
+func TestPanePIDs_MissingSession(t *testing.T) {
+	tmux, _ := fakeTmux(t, `echo "can't find session" >&2; exit 1`)
+	if _, err := tmux.PanePIDs("nope"); err == nil {
+		t.Fatal("expected error for missing session")
+	}
+}
+
+func TestCleanup_KillsPaneTreesAndSession(t *testing.T) {
+	pane := exec.Command("bash", "-c", "sleep 300 & wait")
+	if err := pane.Start(); err != nil {
+		t.Fatalf("starting pane process: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		pane.Wait()
+		close(done)
+	}()
+	pid := pane.Process.Pid
+
+	tmux, logFile := fakeTmux(t, `[ "$1" = "list-panes" ] && echo `+strconv.Itoa(pid)+`; exit 0`)
+
+	report, err := Cleanup(tmux, "dev-x-1", time.Second)
+	if err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("pane process still running after Cleanup")
+	}
+	if len(report.Terminated) == 0 {
+		t.Error("expected terminated PIDs in report")
+	}
+
+	calls := tmuxCalls(t, logFile)
+	last := strings.Join(calls[len(calls)-1], " ")
+	if last != "kill-session -t dev-x-1" {
+		t.Errorf("expected final call to kill the session, got %q", last)
+	}
+}
+
+func TestCleanup_MissingSession(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `exit 1`)
+
+	report, err := Cleanup(tmux, "gone", process.DefaultGrace)
+	if err != nil {
+		t.Fatalf("Cleanup of missing session should not fail: %v", err)
+	}
+	if len(report.Terminated) != 0 || len(report.Escalated) != 0 {
+		t.Errorf("expected empty report, got %+v", report)
+	}
+	calls := tmuxCalls(t, logFile)
+	if calls[len(calls)-1][0] != "kill-session" {
+		t.Errorf("expected kill-session to still be attempted, got %v", calls)
+	}
+}
@@ -0,0 +1,254 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// DefaultLayoutName is the layout used when a project does not pick one.
+const DefaultLayoutName = "default"
+
+// Pane describes one tmux pane in a layout.
+//
+// Command is a shell command template; the placeholders {ai}, {lazygit},
+// {broot}, {dir} and {name} are replaced with shell-quoted words when the
+// session is built, so they must not be quoted again. An empty Command
+// opens a plain shell. Every pane after the first is created by splitting the
+// earlier pane at index From.
+type Pane struct {
+	Command string `json:"command,omitempty"`
+	Split   string `json:"split,omitempty"`   // "horizontal" or "vertical"
+	Percent int    `json:"percent,omitempty"` // size of the new pane, 0 = tmux default
+	From    int    `json:"from,omitempty"`
+	Focus   bool   `json:"focus,omitempty"`
+}
+
+// Layout is a named arrangement of panes.
+type Layout struct {
+	Name  string `json:"name"`
+	Panes []Pane `json:"panes"`
+}
+
+// LayoutFile is the on-disk layout configuration: layout definitions plus
+// the layout assigned to each project by name.
+type LayoutFile struct {
+	Layouts  []Layout          `json:"layouts"`
+	Projects map[string]string `json:"projects,omitempty"`
+}
+
+// DefaultLayout returns the built-in layout: lazygit top-left, the AI tool
+// on the right, broot middle-left and a spare shell bottom-left, with the
+// AI tool focused.
+func DefaultLayout() Layout {
+	return Layout{
+		Name: DefaultLayoutName,
+		Panes: []Pane{
+			{Command: "{lazygit}; exec bash"},
+			{Command: "{ai}; exec bash", Split: "horizontal", Percent: 50, From: 0, Focus: true},
+			{Command: "trap exit TERM; while true; do {broot} {dir}; done", Split: "vertical", Percent: 50, From: 0},
+			{Split: "vertical", Percent: 30, From: 2},
+		},
+	}
+}
+
+// LoadLayoutFile reads a layout file. A missing file is not an error and
+// yields an empty LayoutFile, so only the default layout is available.
+func LoadLayoutFile(path string) (LayoutFile, error) {
+	var lf LayoutFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lf, nil
+		}
+		return lf, fmt.Errorf("reading layout file: %w", err)
+	}
+	if err := json.Unmarshal(data, &lf); err != nil {
+		return lf, fmt.Errorf("parsing layout file: %w", err)
+	}
+	for _, l := range lf.Layouts {
+		if err := l.Validate(); err != nil {
+			return lf, err
+		}
+	}
+	return lf, nil
+}
+
+// Apply sets the Layout field of each project that has an assignment and
+// no layout of its own.
+func (lf LayoutFile) Apply(projects []models.Project) {
+	for i := range projects {
+		if projects[i].Layout != "" {
+			continue
+		}
+		if name, ok := lf.Projects[projects[i].Name]; ok {
+			projects[i].Layout = name
+		}
+	}
+}
+
+// Find returns the layout with the given name. An empty name or "default"
+// returns the built-in layout unless the file overrides it.
+func (lf LayoutFile) Find(name string) (Layout, error) {
+	if name == "" {
+		name = DefaultLayoutName
+	}
+	for _, l := range lf.Layouts {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	if name == DefaultLayoutName {
+		return DefaultLayout(), nil
+	}
+	return Layout{}, fmt.Errorf("unknown layout: %s", name)
+}
+
+// Validate checks that every pane can be built from the panes before it.
+func (l Layout) Validate() error {
+	if len(l.Panes) == 0 {
+		return fmt.Errorf("layout %q has no panes", l.Name)
+	}
+	focused := 0
+	for i, p := range l.Panes {
+		if p.Focus {
+			focused++
+		}
+		if i == 0 {
+			continue
+		}
+		if p.Split != "horizontal" && p.Split != "vertical" {
+			return fmt.Errorf("layout %q pane %d: split must be \"horizontal\" or \"vertical\"", l.Name, i)
+		}
+		if p.From < 0 || p.From >= i {
+			return fmt.Errorf("layout %q pane %d: from must refer to an earlier pane", l.Name, i)
+		}
+		if p.Percent < 0 || p.Percent > 99 {
+			return fmt.Errorf("layout %q pane %d: percent must be between 1 and 99", l.Name, i)
+		}
+	}
+	if focused > 1 {
+		return fmt.Errorf("layout %q focuses more than one pane", l.Name)
+	}
+	return nil
+}
+
+// tmuxOrder returns the tmux pane index of each declared pane once the
+// whole layout is built. tmux inserts a new pane directly after the pane
+// it was split from, so indices shift as panes are added.
+func (l Layout) tmuxOrder() []int {
+	var order []int // declared pane indices in tmux order
+	for i, p := range l.Panes {
+		if i == 0 {
+			order = append(order, 0)
+			continue
+		}
+		pos := 0
+		for j, d := range order {
+			if d == p.From {
+				pos = j + 1
+				break
+			}
+		}
+		order = append(order[:pos], append([]int{i}, order[pos:]...)...)
+	}
+	index := make([]int, len(l.Panes))
+	for pos, d := range order {
+		index[d] = pos
+	}
+	return index
+}
+
+// AIPane returns the final tmux pane index of the first pane running the
+// AI tool, or -1 if the layout has none.
+func (l Layout) AIPane() int {
+	index := l.tmuxOrder()
+	for i, p := range l.Panes {
+		if strings.Contains(p.Command, "{ai}") {
+			return index[i]
+		}
+	}
+	return -1
+}
+
+// focusPane returns the final tmux index of the pane the layout leaves
+// focused: the last pane marked Focus, otherwise the last pane created.
+func (l Layout) focusPane() int {
+	index := l.tmuxOrder()
+	focus := len(l.Panes) - 1
+	for i, p := range l.Panes {
+		if p.Focus {
+			focus = i
+		}
+	}
+	return index[focus]
+}
+
+// buildArgs renders the layout into tmux commands following new-session.
+// The first pane's command is returned separately because new-session
+// takes it as its trailing argument.
+func (l Layout) buildArgs(cfg Config) (first string, args []string) {
+	var ai Agent
+	if len(cfg.Agents) > 0 {
+		ai = cfg.Agents[0]
+	}
+	r := strings.NewReplacer(
+		"{ai}", util.ShellJoin(ai.Argv),
+		"{lazygit}", util.ShellQuote(cfg.LazygitCmd),
+		"{broot}", util.ShellQuote(cfg.BrootCmd),
+		"{dir}", util.ShellQuote(cfg.ProjectDir),
+		"{name}", util.ShellQuote(cfg.ProjectName),
+	)
+
+	// Track tmux indices while building so select-pane targets are correct
+	// at the moment each command runs.
+	var order []int
+	position := func(d int) int {
+		for pos, o := range order {
+			if o == d {
+				return pos
+			}
+		}
+		return -1
+	}
+
+	active := 0
+	for i, p := range l.Panes {
+		if i == 0 {
+			first = r.Replace(p.Command)
+			order = append(order, 0)
+			continue
+		}
+		if active != p.From {
+			args = append(args, ";", "select-pane", "-t", strconv.Itoa(position(p.From)))
+		}
+		flag := "-h"
+		if p.Split == "vertical" {
+			flag = "-v"
+		}
+		args = append(args, ";", "split-window", flag)
+		if p.Percent > 0 {
+			args = append(args, "-p", strconv.Itoa(p.Percent))
+		}
+		args = append(args, "-c", cfg.paneDir(p))
+		if p.Command != "" {
+			args = append(args, r.Replace(p.Command))
+		}
+
+		pos := position(p.From) + 1
+		order = append(order[:pos], append([]int{i}, order[pos:]...)...)
+		active = i
+	}
+
+	for i, p := range l.Panes {
+		if p.Focus && i != active {
+			args = append(args, ";", "select-pane", "-t", strconv.Itoa(position(i)))
+		}
+	}
+	return first, args
+}
@@ -0,0 +1,231 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackuait/ghost-tab/internal/models"
+)
+
+func TestDefaultLayout_IsValid(t *testing.T) {
+	if err := DefaultLayout().Validate(); err != nil {
+		t.Fatalf("default layout invalid: %v", err)
+	}
+}
+
+func TestDefaultLayout_AIPane(t *testing.T) {
+	// lazygit=0, broot=1, shell=2, AI=3 once tmux has inserted every split.
+	if got := DefaultLayout().AIPane(); got != 3 {
+		t.Errorf("AIPane: got %d, want 3", got)
+	}
+}
+
+func TestLayout_AIPane_None(t *testing.T) {
+	l := Layout{Name: "plain", Panes: []Pane{{Command: "htop"}}}
+	if got := l.AIPane(); got != -1 {
+		t.Errorf("AIPane: got %d, want -1", got)
+	}
+}
+
+func TestNewSessionArgs_CustomLayout(t *testing.T) {
+	cfg := testConfig()
+	cfg.Env = nil
+	cfg.Layout = Layout{
+		Name: "tests",
+		Panes: []Pane{
+			{Command: "{ai}; exec bash", Focus: true},
+			{Command: "npm test -- --watch", Split: "horizontal", Percent: 40, From: 0},
+			{Command: "tail -f {dir}/log/dev.log", Split: "vertical", From: 1},
+		},
+	}
+
+	cmds := splitCommands(NewSessionArgs(cfg))
+	expected := [][]string{
+		{"new-session", "-s", "dev-my-app-123", "-c", "/home/user/my-app", "/usr/bin/claude --resume; exec bash"},
+		{"set-option", "status-left", " ⬡ my-app "},
+		{"set-option", "status-left-style", "fg=white,bg=colour236,bold"},
+		{"set-option", "status-style", "bg=colour235"},
+		{"set-option", "status-right", ""},
+		{"set-option", "-t", "dev-my-app-123", "destroy-unattached", "on"},
+		{"set-option", "-s", "focus-events", "on"},
+		{"split-window", "-h", "-p", "40", "-c", "/home/user/my-app", "npm test -- --watch"},
+		{"split-window", "-v", "-c", "/home/user/my-app", "tail -f /home/user/my-app/log/dev.log"},
+		{"select-pane", "-t", "0"},
+	}
+
+	if len(cmds) != len(expected) {
+		t.Fatalf("expected %d tmux commands, got %d: %q", len(expected), len(cmds), cmds)
+	}
+	for i := range expected {
+		if strings.Join(cmds[i], "\x00") != strings.Join(expected[i], "\x00") {
+			t.Errorf("command %d:\n got  %q\n want %q", i, cmds[i], expected[i])
+		}
+	}
+	if got := cfg.Layout.AIPane(); got != 0 {
+		t.Errorf("AIPane: got %d, want 0", got)
+	}
+}
+
+func TestNewSessionArgs_SelectsSplitSourceByTmuxIndex(t *testing.T) {
+	cfg := testConfig()
+	cfg.Layout = Layout{
+		Name: "grid",
+		Panes: []Pane{
+			{Command: "a"},
+			{Command: "b", Split: "horizontal", From: 0},
+			{Command: "c", Split: "vertical", From: 0},
+			// b has moved from tmux index 1 to 2 after c was inserted.
+			{Command: "d", Split: "vertical", From: 1},
+		},
+	}
+
+	args := strings.Join(NewSessionArgs(cfg), " ")
+	if !strings.Contains(args, "; select-pane -t 2 ; split-window -v -c /home/user/my-app d") {
+		t.Errorf("expected split of b to target tmux index 2, got: %s", args)
+	}
+}
+
+func TestLayout_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout Layout
+		errMsg string
+	}{
+		{"no panes", Layout{Name: "x"}, "no panes"},
+		{"bad split", Layout{Name: "x", Panes: []Pane{{}, {Split: "diagonal"}}}, "split must be"},
+		{"from later pane", Layout{Name: "x", Panes: []Pane{{}, {Split: "vertical", From: 1}}}, "earlier pane"},
+		{"percent too big", Layout{Name: "x", Panes: []Pane{{}, {Split: "vertical", Percent: 100}}}, "percent"},
+		{"two focused", Layout{Name: "x", Panes: []Pane{{Focus: true}, {Split: "vertical", Focus: true}}}, "more than one"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.layout.Validate()
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadLayoutFile_Missing(t *testing.T) {
+	lf, err := LoadLayoutFile(filepath.Join(t.TempDir(), "layouts.json"))
+	if err != nil {
+		t.Fatalf("expected no error for missing file, got %v", err)
+	}
+	l, err := lf.Find("")
+	if err != nil {
+		t.Fatalf("Find default: %v", err)
+	}
+	if l.Name != DefaultLayoutName {
+		t.Errorf("expected default layout, got %q", l.Name)
+	}
+}
+
+func TestLoadLayoutFile_Valid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layouts.json")
+	content := `{
+  "layouts": [
+    {"name": "tests", "panes": [
+      {"command": "{ai}; exec bash", "focus": true},
+      {"command": "make watch", "split": "vertical", "percent": 30}
+    ]}
+  ],
+  "projects": {"api": "tests"}
+}`
+	os.WriteFile(path, []byte(content), 0644)
+
+	lf, err := LoadLayoutFile(path)
+	if err != nil {
+		t.Fatalf("LoadLayoutFile: %v", err)
+	}
+	l, err := lf.Find("tests")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(l.Panes) != 2 || l.Panes[1].Command != "make watch" {
+		t.Errorf("unexpected layout: %+v", l)
+	}
+
+	projects := []models.Project{
+		{Name: "api", Path: "/p/api"},
+		{Name: "web", Path: "/p/web"},
+		{Name: "cli", Path: "/p/cli", Layout: "custom"},
+	}
+	lf.Apply(projects)
+	if projects[0].Layout != "tests" {
+		t.Errorf("api layout: got %q, want %q", projects[0].Layout, "tests")
+	}
+	if projects[1].Layout != "" {
+		t.Errorf("web layout: got %q, want empty", projects[1].Layout)
+	}
+	if projects[2].Layout != "custom" {
+		t.Errorf("cli layout should be kept, got %q", projects[2].Layout)
+	}
+}
+
+func TestLoadLayoutFile_InvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layouts.json")
+	os.WriteFile(path, []byte("{not json"), 0644)
+	if _, err := LoadLayoutFile(path); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+func TestLoadLayoutFile_InvalidLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layouts.json")
+	os.WriteFile(path, []byte(`{"layouts":[{"name":"empty","panes":[]}]}`), 0644)
+	if _, err := LoadLayoutFile(path); err == nil {
+		t.Fatal("expected error for layout without panes")
+	}
+}
+
+func TestLayoutFile_FindUnknown(t *testing.T) {
+	if _, err := (LayoutFile{}).Find("nope"); err == nil {
+		t.Fatal("expected error for unknown layout")
+	}
+}
+
+func TestLayoutFile_OverridesDefault(t *testing.T) {
+	lf := LayoutFile{Layouts: []Layout{{Name: "default", Panes: []Pane{{Command: "{ai}"}}}}}
+	l, err := lf.Find("")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(l.Panes) != 1 {
+		t.Errorf("expected overridden default with 1 pane, got %d", len(l.Panes))
+	}
+}
+
+func TestNewSessionArgs_QuotesPlaceholders(t *testing.T) {
+	hostile := []string{
+		`/srv/a "quoted" dir`,
+		"/srv/$(touch pwned)/`id`",
+		"/srv/it's; rm -rf ~",
+		"/srv/new\nline & tab\t*",
+	}
+	for _, dir := range hostile {
+		cfg := testConfig()
+		cfg.Env = nil
+		cfg.ProjectDir = dir
+		cfg.ProjectName = filepath.Base(dir)
+		cfg.Agents = []Agent{{Argv: []string{"printf", "%s|", "--cd", dir}}}
+		cfg.Layout = Layout{Name: "echo", Panes: []Pane{{Command: "{ai}; printf '%s|' {dir} {name}"}}}
+
+		cmds := splitCommands(NewSessionArgs(cfg))
+		first := cmds[0][len(cmds[0])-1]
+		out, err := exec.Command("sh", "-c", first).Output()
+		if err != nil {
+			t.Fatalf("sh -c %q: %v", first, err)
+		}
+		want := "--cd|" + dir + "|" + dir + "|" + cfg.ProjectName + "|"
+		if string(out) != want {
+			t.Errorf("pane command %q\n printed %q\n want    %q", first, out, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"strconv"
+	"strings"
+)
+
+// sessionNameReplacer mirrors tmux's own session name sanitizing, which
+// turns "." and ":" into "_".
+var sessionNameReplacer = strings.NewReplacer(".", "_", ":", "_")
+
+// SessionName returns the tmux session name for a project launched by the
+// process with the given pid, matching "dev-${PROJECT_NAME}-$$" in
+// claude-wrapper.sh.
+func SessionName(projectName string, pid int) string {
+	return sessionPrefix(projectName) + strconv.Itoa(pid)
+}
+
+// sessionPrefix returns the session name prefix shared by every Ghost Tab
+// session of a project.
+func sessionPrefix(projectName string) string {
+	return "dev-" + sessionNameReplacer.Replace(projectName) + "-"
+}
+
+// ListSessions returns the names of all running tmux sessions.
+// Returns an error if the tmux server is not running.
+func (t Tmux) ListSessions() ([]string, error) {
+	out, err := t.Output("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
+// ProjectSessions filters session names down to the Ghost Tab sessions of
+// the given project, i.e. "dev-<name>-<pid>".
+func ProjectSessions(names []string, projectName string) []string {
+	prefix := sessionPrefix(projectName)
+	var matched []string
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if _, err := strconv.Atoi(name[len(prefix):]); err != nil {
+			continue
+		}
+		matched = append(matched, name)
+	}
+	return matched
+}
+
+// Attach attaches the current terminal to a running session and blocks
+// until the client detaches or the session ends.
+func Attach(t Tmux, sessionName string) error {
+	return t.Run("attach-session", "-t", sessionName)
+}
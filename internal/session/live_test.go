@@ -0,0 +1,88 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSessionName(t *testing.T) {
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"my-app", "dev-my-app-42"},
+		{"my.app", "dev-my_app-42"},
+		{"a:b", "dev-a_b-42"},
+	}
+	for _, tt := range tests {
+		if got := SessionName(tt.project, 42); got != tt.want {
+			t.Errorf("SessionName(%q) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestProjectSessions(t *testing.T) {
+	names := []string{
+		"dev-my-app-100",
+		"dev-my-app-200",
+		"dev-my-app-extra-300", // different project "my-app-extra"
+		"dev-my-app-abc",       // not a pid suffix
+		"dev-other-400",
+		"scratch",
+	}
+
+	got := ProjectSessions(names, "my-app")
+	want := []string{"dev-my-app-100", "dev-my-app-200"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := ProjectSessions(names, "my-app-extra"); len(got) != 1 || got[0] != "dev-my-app-extra-300" {
+		t.Errorf("my-app-extra: got %v", got)
+	}
+	if got := ProjectSessions(nil, "my-app"); len(got) != 0 {
+		t.Errorf("expected no sessions, got %v", got)
+	}
+}
+
+func TestProjectSessions_SanitizedName(t *testing.T) {
+	got := ProjectSessions([]string{"dev-my_app-1"}, "my.app")
+	if len(got) != 1 {
+		t.Errorf("expected tmux-sanitized name to match, got %v", got)
+	}
+}
+
+func TestTmux_ListSessions(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `printf 'dev-a-1\ndev-b-2\n\n'`)
+
+	names, err := tmux.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if strings.Join(names, ",") != "dev-a-1,dev-b-2" {
+		t.Errorf("got %v", names)
+	}
+
+	calls := tmuxCalls(t, logFile)
+	if strings.Join(calls[0], " ") != "list-sessions -F #{session_name}" {
+		t.Errorf("unexpected tmux call: %q", calls[0])
+	}
+}
+
+func TestTmux_ListSessions_NoServer(t *testing.T) {
+	tmux, _ := fakeTmux(t, `echo "no server running" >&2; exit 1`)
+	if _, err := tmux.ListSessions(); err == nil {
+		t.Fatal("expected error when tmux server is not running")
+	}
+}
+
+func TestAttach(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `exit 0`)
+	if err := Attach(tmux, "dev-a-1"); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	calls := tmuxCalls(t, logFile)
+	if strings.Join(calls[0], " ") != "attach-session -t dev-a-1" {
+		t.Errorf("unexpected tmux call: %q", calls[0])
+	}
+}
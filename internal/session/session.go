@@ -0,0 +1,132 @@
+package session
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/aitools"
+)
+
+// WatchInterval is how often the AI pane is polled for a ready prompt.
+const WatchInterval = 500 * time.Millisecond
+
+// readyPromptRegex matches the prompt characters the built-in AI tools
+// show once they are ready for input.
+var readyPromptRegex = regexp.MustCompile(aitools.DefaultReadyPrompt)
+
+// Config describes a Ghost Tab session.
+type Config struct {
+	SessionName string
+	ProjectName string
+	ProjectDir  string
+	// Agents are the AI tools to run. The first fills the layout's {ai}
+	// pane; any others open beside it (see agentArgs).
+	Agents []Agent
+	// ReadyPrompt matches the AI pane once its tool is ready for input.
+	// Nil means the built-in tools' prompt.
+	ReadyPrompt *regexp.Regexp
+	LazygitCmd  string
+	BrootCmd    string
+	// Env holds KEY=VALUE pairs exported into the session.
+	Env []string
+	// Layout arranges the panes. A layout without panes means DefaultLayout.
+	Layout Layout
+	// Persist keeps the session alive after its last client detaches, so it
+	// survives the terminal window closing and can be reattached later.
+	Persist bool
+}
+
+// layout returns the configured layout, falling back to DefaultLayout.
+func (cfg Config) layout() Layout {
+	if len(cfg.Layout.Panes) == 0 {
+		return DefaultLayout()
+	}
+	return cfg.Layout
+}
+
+// NewSessionArgs builds the tmux argument list that creates the session,
+// applies the Ghost Tab status bar styling and then builds the layout.
+// With the default layout this matches the tmux invocation that used to
+// live at the end of claude-wrapper.sh.
+func NewSessionArgs(cfg Config) []string {
+	first, layoutArgs := cfg.layout().buildArgs(cfg)
+
+	args := []string{"new-session", "-s", cfg.SessionName}
+	for _, kv := range cfg.Env {
+		args = append(args, "-e", kv)
+	}
+	args = append(args, "-c", cfg.paneDir(cfg.layout().Panes[0]))
+	if first != "" {
+		args = append(args, first)
+	}
+
+	args = append(args,
+		";", "set-option", "status-left", " ⬡ "+cfg.ProjectName+" ",
+		";", "set-option", "status-left-style", "fg=white,bg=colour236,bold",
+		";", "set-option", "status-style", "bg=colour235",
+		";", "set-option", "status-right", "",
+	)
+	// destroy-unattached is a session option, so a persistent session
+	// launched earlier on the same server keeps running when this one
+	// closes. exit-unattached would stop the whole server.
+	if !cfg.Persist {
+		args = append(args, ";", "set-option", "-t", cfg.SessionName, "destroy-unattached", "on")
+	}
+	args = append(args,
+		// Lets the notify hook tell whether the terminal has focus
+		";", "set-option", "-s", "focus-events", "on",
+	)
+	args = append(args, layoutArgs...)
+	return append(args, cfg.agentArgs()...)
+}
+
+// PaneTarget returns the tmux target for a pane in window 0 of a session.
+func PaneTarget(sessionName string, pane int) string {
+	return sessionName + ":0." + strconv.Itoa(pane)
+}
+
+// IsReadyPrompt reports whether captured pane content shows a ready prompt.
+func IsReadyPrompt(content string) bool {
+	return readyPromptRegex.MatchString(content)
+}
+
+// WatchAIPane polls the target pane until its content matches ready (the
+// default prompt when nil), then focuses it. Returns true if the pane was focused, false if stop was
+// closed first. Capture errors (e.g. the session does not exist yet) are
+// ignored and polling continues.
+func WatchAIPane(t Tmux, target string, ready *regexp.Regexp, interval time.Duration, stop <-chan struct{}) bool {
+	if ready == nil {
+		ready = readyPromptRegex
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return false
+		case <-ticker.C:
+			content, err := t.CapturePane(target)
+			if err != nil || !ready.MatchString(content) {
+				continue
+			}
+			_ = t.SelectPane(target)
+			return true
+		}
+	}
+}
+
+// Launch creates the session attached to the current terminal and blocks
+// until tmux exits. If the layout runs an AI tool, a background watcher
+// focuses its pane once it is ready.
+func Launch(t Tmux, cfg Config) error {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	if pane := cfg.layout().AIPane(); pane >= 0 {
+		go WatchAIPane(t, PaneTarget(cfg.SessionName, pane), cfg.ReadyPrompt, WatchInterval, stop)
+	}
+
+	return t.Run(NewSessionArgs(cfg)...)
+}
@@ -0,0 +1,310 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTmux writes a tmux stand-in script that appends each invocation's
+// arguments (one per line, followed by "--") to a log file. The script body
+// runs after logging, so tests can control output per subcommand.
+func fakeTmux(t *testing.T, body string) (Tmux, string) {
+	t.Helper()
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "tmux.log")
+	script := "#!/bin/bash\n" +
+		"for a in \"$@\"; do printf '%s\\n' \"$a\" >> " + "\"" + logFile + "\"" + "; done\n" +
+		"echo -- >> \"" + logFile + "\"\n" +
+		body + "\n"
+	path := filepath.Join(dir, "tmux")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("writing fake tmux: %v", err)
+	}
+	return Tmux{Path: path}, logFile
+}
+
+// tmuxCalls parses the fake tmux log into one argument slice per invocation.
+func tmuxCalls(t *testing.T, logFile string) [][]string {
+	t.Helper()
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		t.Fatalf("reading tmux log: %v", err)
+	}
+	var calls [][]string
+	var current []string
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if line == "--" {
+			calls = append(calls, current)
+			current = nil
+			continue
+		}
+		current = append(current, line)
+	}
+	return calls
+}
+
+func testConfig() Config {
+	return Config{
+		SessionName: "dev-my-app-123",
+		ProjectName: "my-app",
+		ProjectDir:  "/home/user/my-app",
+		Agents:      []Agent{{Name: "Claude Code", Argv: []string{"/usr/bin/claude", "--resume"}, Color: "209"}},
+		LazygitCmd:  "/usr/bin/lazygit",
+		BrootCmd:    "/usr/bin/broot",
+		Env:         []string{"PATH=/usr/bin", "GHOST_TAB_BASELINE_FILE=/tmp/baseline"},
+	}
+}
+
+// splitCommands splits a tmux argument list on ";" separators.
+func splitCommands(args []string) [][]string {
+	var cmds [][]string
+	var current []string
+	for _, a := range args {
+		if a == ";" {
+			cmds = append(cmds, current)
+			current = nil
+			continue
+		}
+		current = append(current, a)
+	}
+	return append(cmds, current)
+}
+
+func TestNewSessionArgs(t *testing.T) {
+	cmds := splitCommands(NewSessionArgs(testConfig()))
+
+	expected := [][]string{
+		{"new-session", "-s", "dev-my-app-123", "-e", "PATH=/usr/bin", "-e", "GHOST_TAB_BASELINE_FILE=/tmp/baseline",
+			"-c", "/home/user/my-app", "/usr/bin/lazygit; exec bash"},
+		{"set-option", "status-left", " ⬡ my-app "},
+		{"set-option", "status-left-style", "fg=white,bg=colour236,bold"},
+		{"set-option", "status-style", "bg=colour235"},
+		{"set-option", "status-right", ""},
+		{"set-option", "-t", "dev-my-app-123", "destroy-unattached", "on"},
+		{"set-option", "-s", "focus-events", "on"},
+		{"split-window", "-h", "-p", "50", "-c", "/home/user/my-app", "/usr/bin/claude --resume; exec bash"},
+		{"select-pane", "-t", "0"},
+		{"split-window", "-v", "-p", "50", "-c", "/home/user/my-app",
+			"trap exit TERM; while true; do /usr/bin/broot /home/user/my-app; done"},
+		{"split-window", "-v", "-p", "30", "-c", "/home/user/my-app"},
+		{"select-pane", "-t", "3"},
+	}
+
+	if len(cmds) != len(expected) {
+		t.Fatalf("expected %d tmux commands, got %d: %v", len(expected), len(cmds), cmds)
+	}
+	for i := range expected {
+		if strings.Join(cmds[i], "\x00") != strings.Join(expected[i], "\x00") {
+			t.Errorf("command %d:\n got  %q\n want %q", i, cmds[i], expected[i])
+		}
+	}
+}
+
+func TestNewSessionArgs_NoEnv(t *testing.T) {
+	cfg := testConfig()
+	cfg.Env = nil
+	args := NewSessionArgs(cfg)
+	for _, a := range args {
+		if a == "-e" {
+			t.Fatal("expected no -e flags when Env is empty")
+		}
+	}
+}
+
+func TestPaneTarget(t *testing.T) {
+	if got := PaneTarget("dev-x-1", 3); got != "dev-x-1:0.3" {
+		t.Errorf("got %q, want %q", got, "dev-x-1:0.3")
+	}
+}
+
+func TestIsReadyPrompt(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"Welcome to Claude Code\n❯ ", true},
+		{"codex> ", true},
+		{"bash-5.2$ ", true},
+		{"Loading...", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsReadyPrompt(tt.content); got != tt.want {
+			t.Errorf("IsReadyPrompt(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestWatchAIPane_SelectsPaneWhenReady(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `[ "$1" = "capture-pane" ] && echo "❯ "; exit 0`)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	if !WatchAIPane(tmux, "dev-x-1:0.1", nil, time.Millisecond, stop) {
+		t.Fatal("expected watcher to focus the AI pane")
+	}
+
+	calls := tmuxCalls(t, logFile)
+	last := calls[len(calls)-1]
+	if strings.Join(last, " ") != "select-pane -t dev-x-1:0.1" {
+		t.Errorf("expected final call to select AI pane, got %q", last)
+	}
+}
+
+func TestWatchAIPane_KeepsPollingUntilReady(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "count")
+	// Report "Loading..." for the first two captures, then a prompt.
+	body := `if [ "$1" = "capture-pane" ]; then
+  n=$(cat "` + counter + `" 2>/dev/null || echo 0)
+  n=$((n + 1)); echo "$n" > "` + counter + `"
+  if [ "$n" -ge 3 ]; then echo "> "; else echo "Loading..."; fi
+fi
+exit 0`
+	tmux, logFile := fakeTmux(t, body)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	if !WatchAIPane(tmux, "dev-x-1:0.1", nil, time.Millisecond, stop) {
+		t.Fatal("expected watcher to focus the AI pane")
+	}
+
+	captures := 0
+	for _, c := range tmuxCalls(t, logFile) {
+		if c[0] == "capture-pane" {
+			captures++
+		}
+	}
+	if captures != 3 {
+		t.Errorf("expected 3 capture-pane calls, got %d", captures)
+	}
+}
+
+func TestWatchAIPane_CustomReadyPrompt(t *testing.T) {
+	// The default prompt characters appear while loading, but this tool is
+	// only ready once it prints "aider>".
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "count")
+	body := `if [ "$1" = "capture-pane" ]; then
+  n=$(cat "` + counter + `" 2>/dev/null || echo 0)
+  n=$((n + 1)); echo "$n" > "` + counter + `"
+  if [ "$n" -ge 2 ]; then echo "aider> "; else echo "$ loading >"; fi
+fi
+exit 0`
+	tmux, _ := fakeTmux(t, body)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	if !WatchAIPane(tmux, "dev-x-1:0.1", regexp.MustCompile(`(?m)^aider> `), time.Millisecond, stop) {
+		t.Fatal("expected watcher to focus the AI pane")
+	}
+	data, _ := os.ReadFile(counter)
+	if strings.TrimSpace(string(data)) != "2" {
+		t.Errorf("expected 2 captures before the custom prompt matched, got %s", data)
+	}
+}
+
+func TestWatchAIPane_IgnoresCaptureErrors(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `exit 1`)
+
+	stop := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(stop)
+	}()
+
+	if WatchAIPane(tmux, "dev-x-1:0.1", nil, time.Millisecond, stop) {
+		t.Fatal("expected watcher to stop without focusing")
+	}
+	for _, c := range tmuxCalls(t, logFile) {
+		if c[0] == "select-pane" {
+			t.Fatal("select-pane should not run while capture fails")
+		}
+	}
+}
+
+func TestLaunch_RunsNewSession(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `exit 0`)
+	cfg := testConfig()
+
+	if err := Launch(tmux, cfg); err != nil {
+		t.Fatalf("Launch: %v", err)
+	}
+
+	var found bool
+	for _, c := range tmuxCalls(t, logFile) {
+		if c[0] == "new-session" {
+			found = true
+			if strings.Join(c, "\x00") != strings.Join(NewSessionArgs(cfg), "\x00") {
+				t.Errorf("new-session args mismatch:\n got  %q\n want %q", c, NewSessionArgs(cfg))
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected a new-session invocation")
+	}
+}
+
+func TestLaunch_ReturnsTmuxError(t *testing.T) {
+	tmux, _ := fakeTmux(t, `exit 3`)
+	if err := Launch(tmux, testConfig()); err == nil {
+		t.Fatal("expected error when tmux fails")
+	}
+}
+
+func TestLaunch_PersistThenNonPersist(t *testing.T) {
+	tmux, logFile := fakeTmux(t, `exit 0`)
+	persist := testConfig()
+	persist.SessionName, persist.Persist = "dev-kept-1", true
+	other := testConfig()
+	other.SessionName = "dev-other-2"
+
+	for _, cfg := range []Config{persist, other} {
+		if err := Launch(tmux, cfg); err != nil {
+			t.Fatalf("Launch %s: %v", cfg.SessionName, err)
+		}
+	}
+
+	var launches [][][]string
+	for _, c := range tmuxCalls(t, logFile) {
+		if c[0] == "new-session" {
+			launches = append(launches, splitCommands(c))
+		}
+	}
+	if len(launches) != 2 {
+		t.Fatalf("expected 2 new-session invocations, got %d", len(launches))
+	}
+	for i, cmds := range launches {
+		for _, c := range cmds {
+			if strings.Contains(strings.Join(c, " "), "exit-unattached") {
+				t.Errorf("launch %d sets a server-wide option that ends other sessions: %q", i, c)
+			}
+		}
+	}
+
+	want := "set-option -t dev-other-2 destroy-unattached on"
+	for i, cmds := range launches {
+		var got []string
+		for _, c := range cmds {
+			if strings.Contains(strings.Join(c, " "), "destroy-unattached") {
+				got = append(got, strings.Join(c, " "))
+			}
+		}
+		if i == 0 && len(got) != 0 {
+			t.Errorf("persistent session should be kept when unattached, got %q", got)
+		}
+		if i == 1 && (len(got) != 1 || got[0] != want) {
+			t.Errorf("non-persistent session: got %q, want %q", got, want)
+		}
+	}
+}
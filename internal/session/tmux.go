@@ -0,0 +1,54 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Tmux runs tmux subcommands through a specific binary.
+type Tmux struct {
+	Path string
+}
+
+// NewTmux returns a Tmux for the tmux binary found on PATH.
+// Falls back to the bare "tmux" name if it cannot be resolved.
+func NewTmux() Tmux {
+	if path, err := exec.LookPath("tmux"); err == nil {
+		return Tmux{Path: path}
+	}
+	return Tmux{Path: "tmux"}
+}
+
+// Run executes tmux attached to the current terminal and waits for it to exit.
+func (t Tmux) Run(args ...string) error {
+	cmd := exec.Command(t.Path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running tmux: %w", err)
+	}
+	return nil
+}
+
+// Output executes tmux detached from the terminal and returns its stdout.
+func (t Tmux) Output(args ...string) (string, error) {
+	out, err := exec.Command(t.Path, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("running tmux %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// CapturePane returns the visible contents of the target pane.
+func (t Tmux) CapturePane(target string) (string, error) {
+	return t.Output("capture-pane", "-t", target, "-p")
+}
+
+// SelectPane focuses the target pane.
+func (t Tmux) SelectPane(target string) error {
+	_, err := t.Output("select-pane", "-t", target)
+	return err
+}
@@ -0,0 +1,183 @@
+package statusline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// Segments a status line can show, in their default order first.
+const (
+	SegmentRepo     = "repo"
+	SegmentBranch   = "branch"
+	SegmentDiff     = "diff"
+	SegmentContext  = "context"
+	SegmentMemory   = "memory"
+	SegmentModel    = "model"
+	SegmentCost     = "cost"
+	SegmentElapsed  = "elapsed"
+	SegmentWorktree = "worktree"
+)
+
+// AllSegments lists every segment name.
+var AllSegments = []string{
+	SegmentRepo, SegmentBranch, SegmentDiff, SegmentContext, SegmentMemory,
+	SegmentModel, SegmentCost, SegmentElapsed, SegmentWorktree,
+}
+
+// Color keys of the diff segment, which has a color per count.
+const (
+	colorAdded   = "added"
+	colorDeleted = "deleted"
+)
+
+// Config chooses the segments of the status line, their order and colors.
+type Config struct {
+	// Segments are shown in this order; empty ones are skipped.
+	Segments []string
+	// Separator goes between segments.
+	Separator string
+	// Colors are SGR parameters, like "01;36", by segment name. The diff
+	// segment uses "added" and "deleted" instead.
+	Colors map[string]string
+}
+
+// DefaultConfig returns the status line Ghost Tab has always shown.
+func DefaultConfig() Config {
+	return Config{
+		Segments:  []string{SegmentRepo, SegmentBranch, SegmentDiff, SegmentContext, SegmentMemory},
+		Separator: " | ",
+		Colors: map[string]string{
+			SegmentRepo:     "01;36",
+			SegmentBranch:   "01;32",
+			colorAdded:      "01;32",
+			colorDeleted:    "01;31",
+			SegmentContext:  "01;33",
+			SegmentMemory:   "01;35",
+			SegmentModel:    "01;34",
+			SegmentCost:     "01;33",
+			SegmentElapsed:  "00;37",
+			SegmentWorktree: "01;35",
+		},
+	}
+}
+
+// Shows reports whether the segment is part of the line.
+func (c Config) Shows(segment string) bool {
+	return slices.Contains(c.Segments, segment)
+}
+
+// DefaultConfigFile returns the path of the user's status line settings,
+// ${XDG_CONFIG_HOME:-~/.config}/ghost-tab/statusline.toml.
+func DefaultConfigFile() string {
+	return filepath.Join(util.ConfigDir(), "statusline.toml")
+}
+
+// LoadConfig returns the default config changed by the TOML file at path.
+// A missing file yields the default. The file may set
+//
+//	segments = ["repo", "branch", "model", "context"]
+//	separator = " · "
+//
+//	[colors]
+//	model = 141        # ANSI 256 color, drawn bold
+//	branch = "00;32"   # or raw SGR parameters
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := cfg.Decode(data); err != nil {
+		return DefaultConfig(), fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// sgrRegex matches raw SGR parameters such as "01;38;5;141".
+var sgrRegex = regexp.MustCompile(`^[0-9]+(;[0-9]+)*$`)
+
+// configFile is the TOML form of Config.
+type configFile struct {
+	Segments  []string         `toml:"segments"`
+	Separator string           `toml:"separator"`
+	Colors    map[string]color `toml:"colors"`
+}
+
+// Decode applies the settings of a TOML document to c.
+func (c *Config) Decode(data []byte) error {
+	var f configFile
+	md, err := toml.Decode(string(data), &f)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("unknown key %q", undecoded[0].String())
+	}
+	// toml leaves a map alone when the value isn't a table; implicit
+	// tables, like those of dotted keys, have no type
+	if t := md.Type("colors"); t != "" && t != "Hash" {
+		return fmt.Errorf("colors must be a table")
+	}
+	if md.IsDefined("segments") {
+		if err := checkSegments(f.Segments); err != nil {
+			return err
+		}
+		c.Segments = f.Segments
+	}
+	if md.IsDefined("separator") {
+		c.Separator = f.Separator
+	}
+	for name, v := range f.Colors {
+		if name == SegmentDiff || (!slices.Contains(AllSegments, name) && name != colorAdded && name != colorDeleted) {
+			return fmt.Errorf("unknown color %q, the diff segment uses %q and %q", name, colorAdded, colorDeleted)
+		}
+		c.Colors[name] = string(v)
+	}
+	return nil
+}
+
+func checkSegments(segments []string) error {
+	for i, name := range segments {
+		if !slices.Contains(AllSegments, name) {
+			return fmt.Errorf("unknown segment %s, expected one of %v", name, AllSegments)
+		}
+		if slices.Contains(segments[:i], name) {
+			return fmt.Errorf("segment %q is listed twice", name)
+		}
+	}
+	return nil
+}
+
+// color is a color of the TOML file: an ANSI 256 color number, drawn
+// bold, or a string of SGR parameters. It holds the SGR parameters.
+type color string
+
+// UnmarshalTOML implements toml.Unmarshaler; a number and a string of
+// digits mean different colors, so the text alone won't do.
+func (c *color) UnmarshalTOML(value any) error {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 || v > 255 {
+			return fmt.Errorf("color must be between 0 and 255, got %d", v)
+		}
+		*c = color(fmt.Sprintf("01;38;5;%d", v))
+		return nil
+	case string:
+		if !sgrRegex.MatchString(v) {
+			return fmt.Errorf("color must be a number or SGR parameters like \"01;36\", got %q", v)
+		}
+		*c = color(v)
+		return nil
+	}
+	return fmt.Errorf("color must be a number or a string")
+}
@@ -0,0 +1,79 @@
+package statusline
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statusline.toml")
+	os.WriteFile(path, []byte(`
+segments = ["model", "repo", "worktree", "cost"]
+separator = " · "
+
+[colors]
+model = 141
+repo = "00;36"
+added = 82
+`), 0644)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Segments, []string{"model", "repo", "worktree", "cost"}) || cfg.Separator != " · " {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	if cfg.Colors["model"] != "01;38;5;141" || cfg.Colors["repo"] != "00;36" || cfg.Colors["added"] != "01;38;5;82" {
+		t.Errorf("colors = %v", cfg.Colors)
+	}
+	if cfg.Colors["branch"] != "01;32" {
+		t.Errorf("unset colors keep their default, got %q", cfg.Colors["branch"])
+	}
+	if !cfg.Shows(SegmentWorktree) || cfg.Shows(SegmentMemory) {
+		t.Error("Shows should follow segments")
+	}
+}
+
+func TestLoadConfig_Missing(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil || !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Errorf("a missing file should give the default, got %+v, %v", cfg, err)
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		toml string
+		want string
+	}{
+		{"unknown segment", `segments = ["repo", "weather"]`, "unknown segment weather"},
+		{"duplicate segment", `segments = ["repo", "repo"]`, `segment "repo" is listed twice`},
+		{"segments not an array", `segments = "repo"`, `"segments"): incompatible types`},
+		{"separator not a string", `separator = 1`, `"separator"): incompatible types`},
+		{"unknown key", `theme = "dark"`, `unknown key "theme"`},
+		{"colors not a table", `colors = 1`, "colors must be a table"},
+		{"unknown color", "[colors]\nweather = 1\n", `unknown color "weather"`},
+		{"diff color", "[colors]\ndiff = 1\n", `uses "added" and "deleted"`},
+		{"color out of range", "[colors]\nrepo = 300\n", "between 0 and 255"},
+		{"bad SGR", "[colors]\nrepo = \"cyan\"\n", "SGR parameters"},
+		{"bad TOML", "segments = [", "line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "statusline.toml")
+			os.WriteFile(path, []byte(tt.toml), 0644)
+			cfg, err := LoadConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), path) {
+				t.Errorf("LoadConfig error = %v, want %q", err, tt.want)
+			}
+			if !reflect.DeepEqual(cfg, DefaultConfig()) {
+				t.Error("a broken file should fall back to the default")
+			}
+		})
+	}
+}
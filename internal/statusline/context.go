@@ -0,0 +1,96 @@
+package statusline
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/transcript"
+)
+
+// DefaultContextWindow is the context window size, in tokens, of models
+// missing from the window table.
+const DefaultContextWindow = 200000
+
+// longContextWindow is the window of models run with the 1M context beta,
+// which Claude Code marks with a "[1m]" suffix on the model ID.
+const longContextWindow = 1000000
+
+// contextWindows maps model ID prefixes to context window sizes in tokens.
+// The first matching prefix wins.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"claude-opus-4", 200000},
+	{"claude-sonnet-4", 200000},
+	{"claude-haiku-4", 200000},
+	{"claude-3-7-sonnet", 200000},
+	{"claude-3-5-sonnet", 200000},
+	{"claude-3-5-haiku", 200000},
+	{"claude-3-opus", 200000},
+	{"claude-3-haiku", 200000},
+}
+
+// ContextWindow returns the context window size of the model with the
+// given ID.
+func ContextWindow(model string) int {
+	if strings.HasSuffix(strings.ToLower(model), "[1m]") {
+		return longContextWindow
+	}
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			return w.tokens
+		}
+	}
+	return DefaultContextWindow
+}
+
+// Usage is how much of the context window a session uses.
+type Usage struct {
+	// Tokens in context after the last message: the prompt it was given,
+	// including cached parts.
+	Tokens int
+	// Model is the ID of the model that wrote the message.
+	Model string
+}
+
+// Percent returns Tokens as a percentage of the context window of model,
+// the one the status JSON names; "" uses the model that wrote the message.
+func (u Usage) Percent(model string) float64 {
+	if model == "" {
+		model = u.Model
+	}
+	return float64(u.Tokens) * 100 / float64(ContextWindow(model))
+}
+
+// transcriptEntry is the part of a transcript line that carries token usage.
+type transcriptEntry struct {
+	IsSidechain bool `json:"isSidechain"`
+	Message     struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ReadUsage returns the usage of the last main-chain message in the JSONL
+// transcript at path. ok is false when the transcript can't be read or has
+// no usage yet.
+func ReadUsage(path string) (u Usage, ok bool) {
+	ok = transcript.FindLast(path, func(line []byte) bool {
+		var e transcriptEntry
+		if json.Unmarshal(line, &e) != nil || e.IsSidechain || e.Message.Usage == nil {
+			return false
+		}
+		usage := e.Message.Usage
+		u = Usage{
+			Tokens: usage.InputTokens + usage.CacheReadInputTokens + usage.CacheCreationInputTokens,
+			Model:  e.Message.Model,
+		}
+		return true
+	})
+	return u, ok
+}
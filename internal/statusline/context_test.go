@@ -0,0 +1,85 @@
+package statusline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackuait/ghost-tab/internal/transcript"
+)
+
+func TestReadUsage(t *testing.T) {
+	lines := []string{
+		`{"type":"user","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","message":{"model":"claude-sonnet-4-5","usage":{"input_tokens":10,"cache_read_input_tokens":1000,"cache_creation_input_tokens":200,"output_tokens":50}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"model":"claude-opus-4-1","usage":{"input_tokens":5,"cache_read_input_tokens":45000,"cache_creation_input_tokens":0}}}`,
+		`{"type":"assistant","isSidechain":true,"message":{"usage":{"input_tokens":99999}}}`,
+		`{"type":"user","message":{"role":"user","content":"` + strings.Repeat("x", 100*1024) + `"}}`,
+	}
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+
+	u, ok := ReadUsage(path)
+	if !ok || u.Tokens != 45005 || u.Model != "claude-opus-4-1" {
+		t.Errorf("ReadUsage = %+v, %v; want 45005 tokens from the last main-chain message", u, ok)
+	}
+
+	if _, ok := ReadUsage(filepath.Join(t.TempDir(), "missing.jsonl")); ok {
+		t.Error("a missing transcript has no usage")
+	}
+	if _, ok := ReadUsage(""); ok {
+		t.Error("no transcript path has no usage")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.jsonl")
+	os.WriteFile(empty, []byte(lines[0]+"\n"), 0644)
+	if _, ok := ReadUsage(empty); ok {
+		t.Error("a transcript without assistant messages has no usage")
+	}
+}
+
+func TestReadUsage_LongTranscript(t *testing.T) {
+	// The last usage sits before more than a chunk of other lines, and
+	// a line crosses the first chunk's boundary
+	var b strings.Builder
+	b.WriteString(`{"message":{"usage":{"input_tokens":1}}}` + "\n")
+	b.WriteString(`{"message":{"usage":{"input_tokens":42000}}}` + "\n")
+	for b.Len() < 3*transcript.TailChunk {
+		b.WriteString(`{"type":"user","message":{"content":"` + strings.Repeat("y", 999) + `"}}` + "\n")
+	}
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	os.WriteFile(path, []byte(b.String()), 0644)
+
+	u, ok := ReadUsage(path)
+	if !ok || u.Tokens != 42000 {
+		t.Errorf("ReadUsage = %+v, %v; want 42000", u, ok)
+	}
+}
+
+func TestContextWindow(t *testing.T) {
+	tests := map[string]int{
+		"claude-opus-4-1-20250805":       200000,
+		"claude-sonnet-4-5-20250929":     200000,
+		"claude-sonnet-4-5-20250929[1m]": 1000000,
+		"claude-3-5-haiku-20241022":      200000,
+		"some-future-model":              DefaultContextWindow,
+		"":                               DefaultContextWindow,
+	}
+	for model, want := range tests {
+		if got := ContextWindow(model); got != want {
+			t.Errorf("ContextWindow(%q) = %d, want %d", model, got, want)
+		}
+	}
+}
+
+func TestUsage_Percent(t *testing.T) {
+	u := Usage{Tokens: 47000, Model: "claude-sonnet-4-5[1m]"}
+	if got := u.Percent("claude-sonnet-4-5"); got != 23.5 {
+		t.Errorf("the status JSON's model should win, got %v", got)
+	}
+	if got := u.Percent(""); got != 4.7 {
+		t.Errorf("the transcript's model is the fallback, got %v", got)
+	}
+}
@@ -2,7 +2,6 @@ package statusline
 
 import (
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 )
@@ -12,11 +11,19 @@ import (
 // Matches the behavior of format_memory() in lib/statusline.sh.
 func FormatMemory(kb string) string {
 	kbVal, err := strconv.ParseInt(strings.TrimSpace(kb), 10, 64)
-	if err != nil || kbVal <= 0 {
+	if err != nil {
+		return "0M"
+	}
+	return FormatMemoryKB(kbVal)
+}
+
+// FormatMemoryKB is FormatMemory for a number of kilobytes.
+func FormatMemoryKB(kb int64) string {
+	if kb <= 0 {
 		return "0M"
 	}
 
-	mb := kbVal / 1024
+	mb := kb / 1024
 	if mb >= 1024 {
 		// Use one decimal place, matching bash: echo "scale=1; $mb / 1024" | bc
 		// bc truncates (floors) rather than rounds.
@@ -28,24 +35,12 @@ func FormatMemory(kb string) string {
 	return fmt.Sprintf("%dM", mb)
 }
 
-// parseCWDRegex matches "current_dir":"<value>" using the same logic as the
-// bash sed: sed -n 's/.*"current_dir":"\([^"]*\)".*/\1/p'
-// The Go regex is greedy by default, so .* will match as far as possible,
-// giving us the LAST occurrence when multiple "current_dir" keys exist.
-var parseCWDRegex = regexp.MustCompile(`.*"current_dir":"([^"]*)"`)
-
-// ParseCWDFromJSON extracts the "current_dir" value from a JSON string.
-// Uses regex pattern matching (like the bash version with sed).
-// Returns empty string if not found or on error.
+// ParseCWDFromJSON returns the working directory from the status JSON in
+// jsonStr, or "" if it isn't valid JSON or has none.
 func ParseCWDFromJSON(jsonStr string) string {
-	// Replace \r\n with \n to handle Windows-style line endings,
-	// then collapse to single line for regex matching (matching sed behavior on piped input).
-	normalized := strings.ReplaceAll(jsonStr, "\r\n", "\n")
-	normalized = strings.ReplaceAll(normalized, "\n", "")
-
-	matches := parseCWDRegex.FindStringSubmatch(normalized)
-	if matches == nil {
+	in, err := ParseInput(strings.NewReader(jsonStr))
+	if err != nil {
 		return ""
 	}
-	return matches[1]
+	return in.Dir()
 }
@@ -101,7 +101,22 @@ func TestFormatMemory(t *testing.T) {
 	}
 }
 
-// --- ParseCWDFromJSON tests (ported from statusline.bats) ---
+func TestFormatMemoryKB(t *testing.T) {
+	tests := map[int64]string{
+		0:       "0M",
+		-5:      "0M",
+		1023:    "0M",
+		512000:  "500M",
+		1572864: "1.5G",
+	}
+	for kb, want := range tests {
+		if got := FormatMemoryKB(kb); got != want {
+			t.Errorf("FormatMemoryKB(%d) = %q, want %q", kb, got, want)
+		}
+	}
+}
+
+// --- ParseCWDFromJSON tests ---
 
 func TestParseCWDFromJSON(t *testing.T) {
 	tests := []struct {
@@ -132,12 +147,12 @@ func TestParseCWDFromJSON(t *testing.T) {
 		{
 			name:     "handles malformed JSON - missing braces",
 			input:    `"current_dir":"/tmp/test"`,
-			expected: "/tmp/test",
+			expected: "",
 		},
 		{
 			name:     "handles malformed JSON - trailing comma",
 			input:    `{"current_dir":"/tmp/test",}`,
-			expected: "/tmp/test",
+			expected: "",
 		},
 		{
 			name:     "handles empty JSON object",
@@ -160,14 +175,14 @@ func TestParseCWDFromJSON(t *testing.T) {
 			expected: "",
 		},
 		{
-			name:     "handles path with escaped characters",
+			name:     "decodes escaped backslashes",
 			input:    `{"current_dir":"/tmp/test\\ndir"}`,
-			expected: `/tmp/test\\ndir`,
+			expected: `/tmp/test\ndir`,
 		},
 		{
-			name:     "handles path with special JSON chars (stops at first unescaped quote)",
+			name:     "decodes escaped quotes",
 			input:    `{"current_dir":"/tmp/test\"quoted"}`,
-			expected: `/tmp/test\`,
+			expected: `/tmp/test"quoted`,
 		},
 		{
 			name:     "handles very long path",
@@ -184,6 +199,16 @@ func TestParseCWDFromJSON(t *testing.T) {
 			input:    `{"current_dir":"/first","other":"stuff","current_dir":"/second"}`,
 			expected: "/second",
 		},
+		{
+			name:     "prefers workspace current_dir",
+			input:    `{"cwd":"/tmp/cwd","workspace":{"current_dir":"/tmp/workspace","project_dir":"/tmp"}}`,
+			expected: "/tmp/workspace",
+		},
+		{
+			name:     "falls back to cwd",
+			input:    `{"cwd":"/tmp/cwd","model":{"id":"claude-opus-4"}}`,
+			expected: "/tmp/cwd",
+		},
 		{
 			name:     "handles Windows-style line endings",
 			input:    "{\r\n\"current_dir\":\"/tmp/test\"\r\n}\r\n",
@@ -0,0 +1,107 @@
+package statusline
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Git is the repository part of the status line.
+type Git struct {
+	Branch string
+	// Worktree is the name of the linked worktree dir is in, "" in the
+	// main working tree.
+	Worktree string
+	// HasDiff is set when a session baseline was found; Added and Deleted
+	// then count the lines changed since it.
+	HasDiff bool
+	Added   int
+	Deleted int
+}
+
+// ReadGit reads the branch and worktree of the repository at dir and, when
+// baselineFile names a commit, the lines changed since it. ok is false when
+// dir isn't in a git repository.
+func ReadGit(dir, baselineFile string) (g Git, ok bool) {
+	out, err := git(dir, "rev-parse", "--show-toplevel", "--git-dir", "--git-common-dir")
+	if err != nil {
+		return g, false
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		return g, false
+	}
+	// symbolic-ref names the branch even before its first commit, where
+	// rev-parse --abbrev-ref HEAD fails; it fails on a detached HEAD,
+	// which rev-parse shows as "HEAD".
+	g.Branch = "HEAD"
+	if out, err := git(dir, "symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		g.Branch = strings.TrimSpace(out)
+	}
+	if absDir(dir, lines[1]) != absDir(dir, lines[2]) {
+		g.Worktree = filepath.Base(lines[0])
+	}
+
+	sha := readBaseline(baselineFile)
+	if sha == "" {
+		return g, true
+	}
+	if out, err := git(dir, "diff", sha, "--numstat"); err == nil {
+		g.Added, g.Deleted = ParseNumstat(out)
+		g.HasDiff = true
+	}
+	return g, true
+}
+
+// absDir resolves a path git printed relative to dir.
+func absDir(dir, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// readBaseline returns the first line of the baseline file, "" if there
+// is none.
+func readBaseline(path string) string {
+	if path == "" {
+		return ""
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() {
+		return ""
+	}
+	return strings.TrimSpace(sc.Text())
+}
+
+// ParseNumstat sums the added and deleted line counts of git diff
+// --numstat output. Binary files, shown as "-", count as 0.
+func ParseNumstat(out string) (added, deleted int) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		a, _ := strconv.Atoi(fields[0])
+		d, _ := strconv.Atoi(fields[1])
+		added += a
+		deleted += d
+	}
+	return added, deleted
+}
+
+// git runs git in dir without taking optional locks, so a status refresh
+// never blocks the user's own git commands.
+func git(dir string, args ...string) (string, error) {
+	args = append([]string{"-C", dir, "--no-optional-locks"}, args...)
+	out, err := exec.Command("git", args...).Output()
+	return string(out), err
+}
@@ -0,0 +1,91 @@
+package statusline
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	out := "3\t1\tfile.txt\n-\t-\timage.png\n10\t0\tdir/new file.go\n\n"
+	added, deleted := ParseNumstat(out)
+	if added != 13 || deleted != 1 {
+		t.Errorf("ParseNumstat = +%d -%d, want +13 -1", added, deleted)
+	}
+	if a, d := ParseNumstat(""); a != 0 || d != 0 {
+		t.Errorf("empty output = +%d -%d", a, d)
+	}
+}
+
+// initRepo creates a git repository with one committed file and returns
+// its directory and HEAD.
+func initRepo(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "-q", "-b", "main")
+	run("config", "user.email", "test@test.com")
+	run("config", "user.name", "Test")
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\ntwo\n"), 0644)
+	run("add", "file.txt")
+	run("commit", "-q", "-m", "initial")
+	return dir, run("rev-parse", "HEAD")
+}
+
+func TestReadGit(t *testing.T) {
+	dir, head := initRepo(t)
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\nthree\nfour\n"), 0644)
+
+	g, ok := ReadGit(dir, "")
+	if !ok || g.Branch != "main" || g.HasDiff {
+		t.Errorf("without baseline: %+v, ok=%v", g, ok)
+	}
+
+	baseline := filepath.Join(t.TempDir(), "baseline")
+	os.WriteFile(baseline, []byte(head+"\n"), 0644)
+	g, ok = ReadGit(dir, baseline)
+	if !ok || !g.HasDiff || g.Added != 2 || g.Deleted != 1 {
+		t.Errorf("with baseline: %+v, ok=%v", g, ok)
+	}
+
+	g, _ = ReadGit(dir, filepath.Join(t.TempDir(), "missing"))
+	if g.HasDiff {
+		t.Error("a missing baseline file should not show a diff")
+	}
+
+	if _, ok := ReadGit(t.TempDir(), ""); ok {
+		t.Error("a plain directory is not a repository")
+	}
+}
+
+func TestReadGit_NoCommits(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q", "-b", "main").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	g, ok := ReadGit(dir, "")
+	if !ok || g.Branch != "main" {
+		t.Errorf("repository without commits: %+v, ok=%v", g, ok)
+	}
+}
+
+func TestReadGit_DetachedHead(t *testing.T) {
+	dir, head := initRepo(t)
+	if out, err := exec.Command("git", "-C", dir, "checkout", "-q", head).CombinedOutput(); err != nil {
+		t.Fatalf("git checkout: %v\n%s", err, out)
+	}
+
+	g, ok := ReadGit(dir, "")
+	if !ok || g.Branch != "HEAD" {
+		t.Errorf("detached HEAD: %+v, ok=%v", g, ok)
+	}
+}
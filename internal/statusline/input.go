@@ -0,0 +1,46 @@
+package statusline
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Input is the JSON Claude Code writes to a status line command's stdin.
+// Fields Ghost Tab doesn't use are ignored.
+type Input struct {
+	SessionID      string `json:"session_id"`
+	TranscriptPath string `json:"transcript_path"`
+	Cwd            string `json:"cwd"`
+	// CurrentDir is the top-level form older versions sent.
+	CurrentDir string `json:"current_dir"`
+	Model      struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"model"`
+	Workspace struct {
+		CurrentDir string `json:"current_dir"`
+		ProjectDir string `json:"project_dir"`
+	} `json:"workspace"`
+	Cost struct {
+		TotalCostUSD    float64 `json:"total_cost_usd"`
+		TotalDurationMS int64   `json:"total_duration_ms"`
+	} `json:"cost"`
+}
+
+// ParseInput decodes the status JSON from r.
+func ParseInput(r io.Reader) (Input, error) {
+	var in Input
+	err := json.NewDecoder(r).Decode(&in)
+	return in, err
+}
+
+// Dir returns the directory Claude is working in.
+func (in Input) Dir() string {
+	switch {
+	case in.Workspace.CurrentDir != "":
+		return in.Workspace.CurrentDir
+	case in.CurrentDir != "":
+		return in.CurrentDir
+	}
+	return in.Cwd
+}
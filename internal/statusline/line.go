@@ -0,0 +1,158 @@
+package statusline
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/process"
+)
+
+// Line holds the values shown in the status line. Empty values are left out.
+type Line struct {
+	Repo   string
+	InRepo bool
+	Git    Git
+	// Context is the share of the context window in use, like "23.5%".
+	Context string
+	// Memory is the resident memory of Claude's process tree, like "1.2G".
+	Memory string
+	// Model is the display name of the model in use.
+	Model string
+	// Cost is the session's cost so far, like "$0.42".
+	Cost string
+	// Elapsed is the session's duration, like "1h05m".
+	Elapsed string
+}
+
+// Options say where Collect finds what the status JSON doesn't carry.
+type Options struct {
+	// BaselineFile holds the commit the session started at.
+	BaselineFile string
+	// PID is a process under Claude, usually the status command's parent.
+	PID int
+	// Segments limits the work to what these segments show; nil
+	// gathers everything.
+	Segments []string
+}
+
+// gathers reports whether any of segments needs gathering.
+func (o Options) gathers(segments ...string) bool {
+	if o.Segments == nil {
+		return true
+	}
+	for _, s := range segments {
+		if slices.Contains(o.Segments, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Collect gathers everything shown in the status line for in.
+func Collect(in Input, opts Options) Line {
+	dir := in.Dir()
+	line := Line{
+		Repo:  filepath.Base(dir),
+		Model: in.Model.DisplayName,
+	}
+	if in.Cost.TotalCostUSD > 0 {
+		line.Cost = fmt.Sprintf("$%.2f", in.Cost.TotalCostUSD)
+	}
+	if in.Cost.TotalDurationMS > 0 {
+		line.Elapsed = FormatElapsed(time.Duration(in.Cost.TotalDurationMS) * time.Millisecond)
+	}
+	if opts.gathers(SegmentBranch, SegmentDiff, SegmentWorktree) {
+		baseline := opts.BaselineFile
+		if !opts.gathers(SegmentDiff) {
+			baseline = ""
+		}
+		line.Git, line.InRepo = ReadGit(dir, baseline)
+	}
+	if opts.gathers(SegmentContext) {
+		if usage, ok := ReadUsage(in.TranscriptPath); ok {
+			line.Context = fmt.Sprintf("%.1f%%", usage.Percent(in.Model.ID))
+		}
+	}
+	if opts.gathers(SegmentMemory) {
+		if procs, err := process.ReadSnapshot(); err == nil {
+			if claude := procs.Ancestor(opts.PID, "claude"); claude != 0 {
+				if kb := procs.TreeRSSKB(claude); kb > 0 {
+					line.Memory = FormatMemoryKB(kb)
+				}
+			}
+		}
+	}
+	return line
+}
+
+// FormatElapsed formats d as "45s", "12m" or "1h05m".
+func FormatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// Render formats the segments cfg chooses with their ANSI colors, joined
+// by its separator.
+func (l Line) Render(cfg Config) string {
+	var segments []string
+	for _, name := range cfg.Segments {
+		if s := l.segment(name, cfg); s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return strings.Join(segments, cfg.Separator)
+}
+
+// segment renders one segment, or "" when it has nothing to show.
+func (l Line) segment(name string, cfg Config) string {
+	var value string
+	switch name {
+	case SegmentRepo:
+		value = l.Repo
+	case SegmentBranch:
+		if l.InRepo {
+			value = l.Git.Branch
+		}
+	case SegmentDiff:
+		if !l.InRepo || !l.Git.HasDiff {
+			return ""
+		}
+		return colorize(cfg.Colors[colorAdded], "+"+strconv.Itoa(l.Git.Added)) + " / " +
+			colorize(cfg.Colors[colorDeleted], "-"+strconv.Itoa(l.Git.Deleted))
+	case SegmentContext:
+		value = l.Context
+	case SegmentMemory:
+		value = l.Memory
+	case SegmentModel:
+		value = l.Model
+	case SegmentCost:
+		value = l.Cost
+	case SegmentElapsed:
+		value = l.Elapsed
+	case SegmentWorktree:
+		if l.InRepo {
+			value = l.Git.Worktree
+		}
+	}
+	if value == "" {
+		return ""
+	}
+	return colorize(cfg.Colors[name], value)
+}
+
+// colorize wraps s in the SGR parameters sgr; no parameters leave it plain.
+func colorize(sgr, s string) string {
+	if sgr == "" {
+		return s
+	}
+	return "\033[" + sgr + "m" + s + "\033[00m"
+}
@@ -0,0 +1,127 @@
+package statusline
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLine_Render(t *testing.T) {
+	tests := []struct {
+		name string
+		line Line
+		want string
+	}{
+		{
+			name: "outside a repository",
+			line: Line{Repo: "tmp"},
+			want: "\033[01;36mtmp\033[00m",
+		},
+		{
+			name: "repository without baseline",
+			line: Line{Repo: "app", InRepo: true, Git: Git{Branch: "main"}},
+			want: "\033[01;36mapp\033[00m | \033[01;32mmain\033[00m",
+		},
+		{
+			name: "everything",
+			line: Line{Repo: "app", InRepo: true, Git: Git{Branch: "dev", HasDiff: true, Added: 3, Deleted: 1},
+				Context: "23.5%", Memory: "1.2G"},
+			want: "\033[01;36mapp\033[00m | \033[01;32mdev\033[00m | \033[01;32m+3\033[00m / \033[01;31m-1\033[00m" +
+				" | \033[01;33m23.5%\033[00m | \033[01;35m1.2G\033[00m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.line.Render(DefaultConfig()); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLine_RenderConfig(t *testing.T) {
+	line := Line{Repo: "app", InRepo: true, Git: Git{Branch: "dev", Worktree: "wt-login", HasDiff: true, Added: 3},
+		Model: "Opus", Cost: "$0.42", Elapsed: "12m"}
+	cfg := DefaultConfig()
+	cfg.Segments = []string{SegmentModel, SegmentWorktree, SegmentDiff, SegmentContext, SegmentCost, SegmentElapsed}
+	cfg.Separator = " · "
+	cfg.Colors[SegmentModel] = ""
+	cfg.Colors[colorAdded] = "01;38;5;82"
+
+	want := "Opus · \033[01;35mwt-login\033[00m · \033[01;38;5;82m+3\033[00m / \033[01;31m-0\033[00m" +
+		" · \033[01;33m$0.42\033[00m · \033[00;37m12m\033[00m"
+	if got := line.Render(cfg); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	line.InRepo = false
+	cfg.Segments = []string{SegmentBranch, SegmentDiff, SegmentWorktree}
+	if got := line.Render(cfg); got != "" {
+		t.Errorf("git segments outside a repository = %q", got)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second:                "45s",
+		12*time.Minute + 30*time.Second: "12m",
+		65 * time.Minute:                "1h05m",
+		26 * time.Hour:                  "26h00m",
+	}
+	for d, want := range tests {
+		if got := FormatElapsed(d); got != want {
+			t.Errorf("FormatElapsed(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestPreview(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Segments = AllSegments
+	cfg.Separator = "|"
+	got := stripANSI(Preview(cfg))
+	want := "ghost-tab|main|+12 / -3|23.5%|1.2G|Opus|$0.42|1h05m|feature-login"
+	if got != want {
+		t.Errorf("Preview() = %q, want %q", got, want)
+	}
+}
+
+// stripANSI removes SGR escape sequences from s.
+func stripANSI(s string) string {
+	return regexp.MustCompile("\033\\[[0-9;]*m").ReplaceAllString(s, "")
+}
+
+func TestCollect(t *testing.T) {
+	dir, head := initRepo(t)
+	os.WriteFile(filepath.Join(dir, "file.txt"), []byte("one\ntwo\nthree\n"), 0644)
+	baseline := filepath.Join(t.TempDir(), "baseline")
+	os.WriteFile(baseline, []byte(head), 0644)
+	transcript := filepath.Join(t.TempDir(), "t.jsonl")
+	os.WriteFile(transcript, []byte(`{"message":{"usage":{"input_tokens":20000}}}`+"\n"), 0644)
+
+	in, err := ParseInput(strings.NewReader(`{"transcript_path":"` + transcript + `","workspace":{"current_dir":"` + dir + `"}}`))
+	if err != nil {
+		t.Fatalf("ParseInput: %v", err)
+	}
+	line := Collect(in, Options{BaselineFile: baseline, PID: -1})
+	if line.Repo != filepath.Base(dir) || !line.InRepo || line.Git.Branch != "main" {
+		t.Errorf("repo = %+v", line)
+	}
+	if !line.Git.HasDiff || line.Git.Added != 1 || line.Git.Deleted != 0 {
+		t.Errorf("diff = %+v", line.Git)
+	}
+	if line.Context != "10.0%" {
+		t.Errorf("Context = %q", line.Context)
+	}
+	if line.Memory != "" {
+		t.Errorf("no claude process should mean no memory, got %q", line.Memory)
+	}
+
+	line = Collect(in, Options{BaselineFile: baseline, PID: -1, Segments: []string{SegmentRepo, SegmentContext}})
+	if line.InRepo || line.Git.Branch != "" || line.Context != "10.0%" {
+		t.Errorf("only the chosen segments should be gathered, got %+v", line)
+	}
+}
@@ -0,0 +1,26 @@
+package statusline
+
+import "strings"
+
+// SampleInput is the status JSON --preview renders.
+const SampleInput = `{
+  "session_id": "sample",
+  "transcript_path": "",
+  "cwd": "/Users/me/code/ghost-tab",
+  "model": {"id": "claude-opus-4-1", "display_name": "Opus"},
+  "workspace": {"current_dir": "/Users/me/code/ghost-tab", "project_dir": "/Users/me/code/ghost-tab"},
+  "cost": {"total_cost_usd": 0.42, "total_duration_ms": 3900000}
+}`
+
+// Preview renders cfg's status line for SampleInput. What the JSON doesn't
+// carry (git, context and memory) is filled in with sample values rather
+// than read from this machine.
+func Preview(cfg Config) string {
+	in, _ := ParseInput(strings.NewReader(SampleInput))
+	line := Collect(in, Options{Segments: []string{}})
+	line.InRepo = true
+	line.Git = Git{Branch: "main", Worktree: "feature-login", HasDiff: true, Added: 12, Deleted: 3}
+	line.Context = "23.5%"
+	line.Memory = "1.2G"
+	return line.Render(cfg)
+}
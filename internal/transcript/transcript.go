@@ -0,0 +1,54 @@
+// Package transcript reads Claude's JSONL session transcripts from the
+// end, where the lines the status line and notifications care about are.
+package transcript
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// TailChunk is how much of a transcript FindLast reads at first.
+const TailChunk = 256 * 1024
+
+// FindLast calls match on the lines of the JSONL transcript at path, last
+// first, and stops at the first one it accepts. Transcripts grow to many
+// megabytes, so it reads from the end, doubling the window until a line
+// matches; lines already tried may be passed to match again. Returns false
+// when the transcript can't be read or no line matches.
+func FindLast(path string, match func(line []byte) bool) bool {
+	if path == "" {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	size := info.Size()
+	for n := int64(TailChunk); ; n *= 2 {
+		offset := max(size-n, 0)
+		buf := make([]byte, size-offset)
+		if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+			return false
+		}
+		lines := bytes.Split(buf, []byte("\n"))
+		if offset > 0 {
+			// The first line may start before the window
+			lines = lines[1:]
+		}
+		for i := len(lines) - 1; i >= 0; i-- {
+			if match(lines[i]) {
+				return true
+			}
+		}
+		if offset == 0 {
+			return false
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTranscript(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindLast(t *testing.T) {
+	path := writeTranscript(t, "one\ntwo\nthree\n")
+
+	var tried []string
+	found := FindLast(path, func(line []byte) bool {
+		tried = append(tried, string(line))
+		return strings.HasPrefix(string(line), "t")
+	})
+	if !found {
+		t.Fatal("expected a match")
+	}
+	if want := []string{"", "three"}; strings.Join(tried, ",") != strings.Join(want, ",") {
+		t.Errorf("tried %q, want %q", tried, want)
+	}
+}
+
+func TestFindLast_WidensWindow(t *testing.T) {
+	// The match sits more than two windows from the end, and a line
+	// crosses the first window's boundary
+	var b strings.Builder
+	b.WriteString("match\n")
+	for b.Len() < 3*TailChunk {
+		b.WriteString(strings.Repeat("y", 999) + "\n")
+	}
+	path := writeTranscript(t, b.String())
+
+	var got string
+	if !FindLast(path, func(line []byte) bool {
+		if len(line) != 0 && len(line) != 999 {
+			got = string(line)
+		}
+		return string(line) == "match"
+	}) {
+		t.Fatal("expected the early line to be found")
+	}
+	if got != "match" {
+		t.Errorf("got partial line %q", got)
+	}
+}
+
+func TestFindLast_NoMatch(t *testing.T) {
+	never := func([]byte) bool { return false }
+	if FindLast("", never) {
+		t.Error("no path should not match")
+	}
+	if FindLast("/nonexistent/transcript.jsonl", never) {
+		t.Error("missing file should not match")
+	}
+	if FindLast(writeTranscript(t, "a\nb\n"), never) {
+		t.Error("expected no match")
+	}
+}
@@ -11,9 +11,16 @@ type aiToolItem struct {
 }
 
 func (i aiToolItem) Title() string       { return i.tool.String() }
-func (i aiToolItem) Description() string { return i.tool.Command }
 func (i aiToolItem) FilterValue() string { return i.tool.Name }
 
+// Description shows why a broken tool can't be selected, otherwise its command.
+func (i aiToolItem) Description() string {
+	if i.tool.Status == models.ToolBroken {
+		return i.tool.Error
+	}
+	return i.tool.Command
+}
+
 type AIToolSelectorModel struct {
 	list     list.Model
 	tools    []models.AITool
@@ -56,7 +63,7 @@ func (m AIToolSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			if item, ok := m.list.SelectedItem().(aiToolItem); ok {
-				if item.tool.Installed {
+				if item.tool.Usable() {
 					m.selected = &item.tool
 				}
 				m.quitting = true
@@ -48,6 +48,33 @@ func PathSuggestionProvider(maxResults int) SuggestionProvider {
 	}
 }
 
+// BranchSuggestionProvider returns a SuggestionProvider that suggests branch
+// names from branches. Names starting with the input come first, then
+// other names containing it; matching is case-insensitive. An exact match
+// is not suggested. Results are capped at maxResults.
+func BranchSuggestionProvider(branches []string, maxResults int) SuggestionProvider {
+	return func(input string) []string {
+		lower := strings.ToLower(input)
+		var prefix, contains []string
+		for _, b := range branches {
+			lb := strings.ToLower(b)
+			switch {
+			case lb == lower:
+				continue
+			case strings.HasPrefix(lb, lower):
+				prefix = append(prefix, b)
+			case strings.Contains(lb, lower):
+				contains = append(contains, b)
+			}
+		}
+		suggestions := append(prefix, contains...)
+		if len(suggestions) > maxResults {
+			suggestions = suggestions[:maxResults]
+		}
+		return suggestions
+	}
+}
+
 // searchPathSuggestions returns directory suggestions for the given input path.
 func searchPathSuggestions(input string) []string {
 	expanded := util.ExpandPath(input)
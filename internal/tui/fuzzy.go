@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fuzzy match scoring. Every matched character scores a point; runs of
+// consecutive characters and characters at the start of a word score
+// extra, and each skipped character between matches costs a little, so
+// "gt" ranks "ghost-tab" above "gadget".
+const (
+	fuzzyMatchScore       = 1
+	fuzzyConsecutiveBonus = 5
+	fuzzyWordStartBonus   = 8
+	fuzzyGapPenalty       = 1
+)
+
+// FuzzyMatch reports whether every character of pattern appears in text in
+// order (case-insensitive). It returns the match score and the rune
+// positions in text that matched. An empty pattern matches everything with
+// a score of 0.
+func FuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	p := foldRunes(pattern)
+	t := foldRunes(text)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	if len(p) > len(t) {
+		return 0, nil, false
+	}
+
+	// best[i][j] is the best score for matching p[:i+1] with p[i] at t[j];
+	// prev[i][j] is where p[i-1] matched in that alignment. Names and
+	// paths are short, so the quadratic inner loop is cheap.
+	const none = -1 << 31
+	best := make([][]int, len(p))
+	prev := make([][]int, len(p))
+	for i := range p {
+		best[i] = make([]int, len(t))
+		prev[i] = make([]int, len(t))
+		for j := range t {
+			best[i][j], prev[i][j] = none, -1
+			if t[j] != p[i] {
+				continue
+			}
+			charScore := fuzzyMatchScore
+			if j == 0 || !isWordRune(t[j-1]) {
+				charScore += fuzzyWordStartBonus
+			}
+			if i == 0 {
+				best[i][j] = charScore
+				continue
+			}
+			for k := i - 1; k < j; k++ {
+				if best[i-1][k] == none {
+					continue
+				}
+				s := best[i-1][k] + charScore
+				if k == j-1 {
+					s += fuzzyConsecutiveBonus
+				} else {
+					s -= (j - k - 1) * fuzzyGapPenalty
+				}
+				if s > best[i][j] {
+					best[i][j], prev[i][j] = s, k
+				}
+			}
+		}
+	}
+
+	last := len(p) - 1
+	end := -1
+	for j := range t {
+		if best[last][j] != none && (end < 0 || best[last][j] > best[last][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	positions = make([]int, len(p))
+	for i, j := last, end; i >= 0; i-- {
+		positions[i] = j
+		j = prev[i][j]
+	}
+	return best[last][end], positions, true
+}
+
+// foldRunes lowercases s one rune at a time, so the folded runes line up
+// with the runes of s and match positions index the original text even
+// for letters like İ whose full lowercase form is longer.
+func foldRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// highlightMatches renders text with the runes at positions in hl and the
+// rest in base.
+func highlightMatches(text string, positions []int, base, hl lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+
+	var b strings.Builder
+	var run []rune
+	runMarked := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMarked {
+			b.WriteString(hl.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if marked[i] != runMarked {
+			flush()
+			runMarked = marked[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
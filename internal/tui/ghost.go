@@ -2,6 +2,8 @@ package tui
 
 import (
 	"strings"
+
+	"github.com/jackuait/ghost-tab/internal/aitools"
 )
 
 // r is the ANSI reset escape sequence.
@@ -235,11 +237,12 @@ func ghostOpencodeSleeping(theme AIToolTheme) []string {
 	}
 }
 
-// GhostForTool returns the ghost ASCII art lines for the given tool.
-// Unknown tools fall back to the claude ghost.
+// GhostForTool returns the ghost ASCII art lines for the given tool, drawn
+// in its theme. A registered tool picks which built-in ghost it uses;
+// unknown tools fall back to the claude ghost.
 func GhostForTool(tool string, sleeping bool) []string {
 	theme := ThemeForTool(tool)
-	switch tool {
+	switch aitools.Current().Get(tool).Ghost {
 	case "codex":
 		if sleeping {
 			return ghostCodexSleeping(theme)
@@ -256,7 +259,7 @@ func GhostForTool(tool string, sleeping bool) []string {
 		}
 		return ghostOpencode(theme)
 	default:
-		// claude and unknown tools
+		// claude and tools without ghost art of their own
 		if sleeping {
 			return ghostClaudeSleeping(theme)
 		}
@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+// ImportSelectResult holds the output of the import checklist.
+type ImportSelectResult struct {
+	Projects  []models.Project `json:"projects"`
+	Confirmed bool             `json:"confirmed"`
+}
+
+// importSelectChrome is the number of lines around the list (title, blank
+// lines, hint).
+const importSelectChrome = 5
+
+// ImportSelectModel is a checkbox list of discovered repositories. Ones
+// already in the projects file are shown checked and cannot be toggled.
+type ImportSelectModel struct {
+	projects []models.Project
+	known    []bool
+	checked  []bool
+	cursor   int
+	offset   int // first visible row
+	height   int // visible rows, 0 = all
+	result   *ImportSelectResult
+	quitting bool
+}
+
+// NewImportSelect creates the checklist for discovered projects, marking
+// the ones already in existing (compared by expanded path).
+func NewImportSelect(discovered []models.Project, existing []models.Project) ImportSelectModel {
+	expanded := make([]models.Project, len(existing))
+	for i, p := range existing {
+		expanded[i] = models.Project{Name: p.Name, Path: util.ExpandPath(p.Path)}
+	}
+
+	known := make([]bool, len(discovered))
+	checked := make([]bool, len(discovered))
+	for i, p := range discovered {
+		known[i] = IsDuplicateProject(p.Path, expanded)
+		checked[i] = known[i]
+	}
+
+	return ImportSelectModel{
+		projects: discovered,
+		known:    known,
+		checked:  checked,
+	}
+}
+
+// Cursor returns the current cursor position.
+func (m ImportSelectModel) Cursor() int {
+	return m.cursor
+}
+
+// Checked returns the checked state of each item.
+func (m ImportSelectModel) Checked() []bool {
+	out := make([]bool, len(m.checked))
+	copy(out, m.checked)
+	return out
+}
+
+// Known returns which items are already in the projects file.
+func (m ImportSelectModel) Known() []bool {
+	out := make([]bool, len(m.known))
+	copy(out, m.known)
+	return out
+}
+
+// Result returns the selection result, or nil if not yet confirmed/cancelled.
+func (m ImportSelectModel) Result() *ImportSelectResult {
+	return m.result
+}
+
+// Init implements tea.Model.
+func (m ImportSelectModel) Init() tea.Cmd {
+	return nil
+}
+
+// toggle flips the item under the cursor unless it is already known.
+func (m *ImportSelectModel) toggle() {
+	if len(m.projects) == 0 || m.known[m.cursor] {
+		return
+	}
+	m.checked[m.cursor] = !m.checked[m.cursor]
+}
+
+// toggleAll checks every new item, or unchecks them all if they already
+// are.
+func (m *ImportSelectModel) toggleAll() {
+	allChecked := true
+	for i := range m.projects {
+		if !m.known[i] && !m.checked[i] {
+			allChecked = false
+			break
+		}
+	}
+	for i := range m.projects {
+		if !m.known[i] {
+			m.checked[i] = !allChecked
+		}
+	}
+}
+
+func (m *ImportSelectModel) move(delta int) {
+	n := len(m.projects)
+	if n == 0 {
+		return
+	}
+	m.cursor = (m.cursor + delta + n) % n
+	m.scrollToCursor()
+}
+
+// scrollToCursor keeps the cursor inside the visible window.
+func (m *ImportSelectModel) scrollToCursor() {
+	if m.height <= 0 {
+		m.offset = 0
+		return
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+// Update implements tea.Model.
+func (m ImportSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height - importSelectChrome
+		if m.height < 1 {
+			m.height = 1
+		}
+		m.scrollToCursor()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEscape:
+			m.result = &ImportSelectResult{Confirmed: false}
+			m.quitting = true
+			return m, tea.Quit
+
+		case tea.KeyUp:
+			m.move(-1)
+			return m, nil
+
+		case tea.KeyDown:
+			m.move(1)
+			return m, nil
+
+		case tea.KeySpace:
+			m.toggle()
+			return m, nil
+
+		case tea.KeyEnter:
+			// Collect newly selected projects in list order
+			selected := []models.Project{}
+			for i, p := range m.projects {
+				if m.checked[i] && !m.known[i] {
+					selected = append(selected, p)
+				}
+			}
+			m.result = &ImportSelectResult{
+				Projects:  selected,
+				Confirmed: true,
+			}
+			m.quitting = true
+			return m, tea.Quit
+
+		case tea.KeyRunes:
+			if len(msg.Runes) == 1 {
+				r := TranslateRune(msg.Runes[0])
+				switch r {
+				case ' ':
+					m.toggle()
+					return m, nil
+				case 'a':
+					m.toggleAll()
+					return m, nil
+				case 'k':
+					m.move(-1)
+					return m, nil
+				case 'j':
+					m.move(1)
+					return m, nil
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// View implements tea.Model.
+func (m ImportSelectModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+
+	newCount := 0
+	for _, k := range m.known {
+		if !k {
+			newCount++
+		}
+	}
+	b.WriteString(titleStyle.Render("Import Projects"))
+	b.WriteString(hintStyle.Render(fmt.Sprintf("  %d found, %d new", len(m.projects), newCount)))
+	b.WriteString("\n\n")
+
+	if len(m.projects) == 0 {
+		b.WriteString("  No git repositories found in the scan roots.\n")
+	}
+
+	knownStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	end := len(m.projects)
+	if m.height > 0 && m.offset+m.height < end {
+		end = m.offset + m.height
+	}
+	for i := m.offset; i < end; i++ {
+		p := m.projects[i]
+
+		// Cursor indicator
+		if i == m.cursor {
+			b.WriteString(selectedItemStyle.Render("  ❯ "))
+		} else {
+			b.WriteString("    ")
+		}
+
+		// Checkbox
+		if m.checked[i] {
+			b.WriteString("[x] ")
+		} else {
+			b.WriteString("[ ] ")
+		}
+
+		path := shortenHomePath(p.Path)
+		switch {
+		case m.known[i]:
+			b.WriteString(knownStyle.Render(path + "  (added)"))
+		case i == m.cursor:
+			b.WriteString(selectedItemStyle.Render(path))
+		default:
+			b.WriteString(path)
+		}
+
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(hintStyle.Render("  ↑↓ navigate  Space toggle  a all  Enter import  Esc cancel"))
+
+	return b.String()
+}
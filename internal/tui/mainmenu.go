@@ -7,13 +7,17 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/ghost-tab/internal/aitools"
+	"github.com/jackuait/ghost-tab/internal/history"
 	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/notify"
 	"github.com/jackuait/ghost-tab/internal/util"
 )
 
@@ -23,6 +27,39 @@ type bobTickMsg struct{}
 // sleepTickMsg is sent on each sleep timer tick.
 type sleepTickMsg struct{}
 
+// gitStatusMsg delivers the git status of a project or worktree directory.
+type gitStatusMsg struct {
+	path   string
+	status models.GitStatus
+}
+
+// worktreeAddedMsg reports the result of creating a worktree.
+type worktreeAddedMsg struct {
+	projectPath string
+	path        string
+	branch      string
+	err         error
+}
+
+// worktreeChangesMsg delivers the number of uncommitted changes in the
+// worktree at path, -1 if they couldn't be counted.
+type worktreeChangesMsg struct {
+	path    string
+	changes int
+}
+
+// worktreeRemovedMsg reports the result of removing a worktree.
+type worktreeRemovedMsg struct {
+	projectPath string
+	branch      string
+	err         error
+}
+
+// aiToolHealthMsg delivers the versions and health of the menu's AI tools.
+type aiToolHealthMsg struct {
+	tools []models.AITool
+}
+
 const (
 	// bobTickInterval is the animation tick rate (~60fps).
 	bobTickInterval = 16 * time.Millisecond
@@ -38,13 +75,31 @@ const (
 
 // MainMenuResult represents the JSON output when the main menu exits.
 type MainMenuResult struct {
-	Action       string `json:"action"`
-	Name         string `json:"name,omitempty"`
-	Path         string `json:"path,omitempty"`
-	AITool       string `json:"ai_tool"`
-	GhostDisplay string `json:"ghost_display,omitempty"`
-	TabTitle     string `json:"tab_title,omitempty"`
-	SoundName *string `json:"sound_name,omitempty"`
+	Action string `json:"action"`
+	Name   string `json:"name,omitempty"`
+	Path   string `json:"path,omitempty"`
+	AITool string `json:"ai_tool"`
+	// ProjectAITool is the selected project's default AI tool, which
+	// overrides AITool for this launch without changing the preference.
+	ProjectAITool string  `json:"project_ai_tool,omitempty"`
+	GhostDisplay  string  `json:"ghost_display,omitempty"`
+	TabTitle      string  `json:"tab_title,omitempty"`
+	SoundName     *string `json:"sound_name,omitempty"`
+	// Agents are the AI tools picked with M to run side by side, each with
+	// the directory it works in. Unset for a single-tool launch.
+	Agents []Agent `json:"agents,omitempty"`
+	// Profile is the launch profile chosen with Tab, "default" for none.
+	// Unset when nothing was chosen, so the project's default applies.
+	Profile string `json:"profile,omitempty"`
+	// Session is set when the user chose to reattach to a running tmux session.
+	Session        string `json:"session,omitempty"`
+	PersistSession string `json:"persist_session,omitempty"`
+}
+
+// Agent is an AI tool to launch and the directory it runs in.
+type Agent struct {
+	Tool string `json:"tool"`
+	Path string `json:"path"`
 }
 
 // MenuLayout describes how the ghost and menu are arranged at a given terminal size.
@@ -56,7 +111,7 @@ type MenuLayout struct {
 }
 
 // actionNames maps action item offsets to their action strings.
-var actionNames = []string{"add-project", "delete-project", "open-once", "plain-terminal"}
+var actionNames = []string{"add-project", "delete-project", "open-once", "plain-terminal", "import-projects"}
 
 // actionLabels maps action items to their display labels.
 var actionLabels = []struct {
@@ -67,17 +122,11 @@ var actionLabels = []struct {
 	{"D", "Delete a project"},
 	{"O", "Open once"},
 	{"P", "Plain terminal"},
+	{"I", "Import projects"},
 }
 
-// aiToolDisplayNames maps tool names to their display names.
-var aiToolDisplayNames = map[string]string{
-	"claude":   "Claude Code",
-	"codex":    "Codex CLI",
-	"copilot":  "Copilot CLI",
-	"opencode": "OpenCode",
-}
-
-// SystemSounds is the ordered list of macOS system sounds available for notification.
+// SystemSounds is the ordered list of macOS system sounds cycled through
+// when no sound backend has been set.
 var SystemSounds = []string{
 	"Basso", "Blow", "Bottle", "Frog", "Funk", "Glass", "Hero",
 	"Morse", "Ping", "Pop", "Purr", "Sosumi", "Submarine", "Tink",
@@ -86,44 +135,49 @@ var SystemSounds = []string{
 // AIToolDisplayName returns the display name for the given AI tool.
 // Unknown tools return the tool name as-is.
 func AIToolDisplayName(tool string) string {
-	if name, ok := aiToolDisplayNames[tool]; ok {
-		return name
-	}
-	return tool
+	return models.DisplayName(tool)
 }
 
 // MainMenuModel is the Bubbletea model for the unified main menu.
 type MainMenuModel struct {
-	projects            []models.Project
-	aiTools             []string
-	selectedAI          int
-	selectedItem        int
-	ghostDisplay        string
-	ghostSleeping       bool
-	bobPhase            float64
-	zzzCounter          int
-	sleepTimer          int
-	width               int
-	height              int
-	theme               AIToolTheme
-	quitting            bool
-	result              *MainMenuResult
-	updateVersion       string
-	settingsMode        bool
-	settingsSelected    int
-	initialGhostDisplay string
-	ghostDisplayChanged bool
-	tabTitle            string
-	initialTabTitle     string
-	tabTitleChanged     bool
-	soundName        string // "" means Off
-	initialSoundName string
-	soundNameChanged bool
-	zzz                 *ZzzAnimation
-	centerOffsetY       int
-
-	// Inline input mode (add-project or open-once)
-	inputMode    string // "", "add-project", "open-once"
+	projects              []models.Project
+	loadedProjects        []models.Project // projects in file order
+	projectSort           string           // "manual", "recent" or "frequent"
+	usage                 map[string]history.Usage
+	aiTools               []string
+	selectedAI            int
+	aiProfile             string // chosen with Tab, "" to use the project's default
+	selectedItem          int
+	ghostDisplay          string
+	ghostSleeping         bool
+	bobPhase              float64
+	zzzCounter            int
+	sleepTimer            int
+	width                 int
+	height                int
+	theme                 AIToolTheme
+	quitting              bool
+	result                *MainMenuResult
+	updateVersion         string
+	settingsMode          bool
+	settingsSelected      int
+	initialGhostDisplay   string
+	ghostDisplayChanged   bool
+	tabTitle              string
+	initialTabTitle       string
+	tabTitleChanged       bool
+	soundName             string // "" means Off
+	initialSoundName      string
+	soundNameChanged      bool
+	persistSession        string // "on" or "off"
+	initialPersistSession string
+	persistSessionChanged bool
+	zzz                   *ZzzAnimation
+	centerOffsetY         int
+
+	// Inline input mode (add-project, open-once or a new worktree's
+	// branch and then directory)
+	inputMode    string // "", "add-project", "open-once", "worktree-branch", "worktree-path"
 	pathInput    textinput.Model
 	autocomplete AutocompleteModel
 	inputErr     error
@@ -132,6 +186,18 @@ type MainMenuModel struct {
 	deleteMode     bool
 	deleteSelected int
 
+	// New worktree: the project it belongs to and the branch entered
+	worktreeProject int
+	worktreeBranch  string
+	worktreeAdding  bool // git worktree add is running
+
+	// Worktree removal confirmation
+	removeWorktreeMode     bool
+	removeWorktreeProject  int
+	removeWorktreeIdx      int
+	removeWorktreeChanges  int  // uncommitted changes, -1 if unknown
+	removeWorktreeCounting bool // still counting the changes
+
 	// Feedback message
 	feedbackMsg   string
 	feedbackStyle string // "success" or "error"
@@ -149,8 +215,41 @@ type MainMenuModel struct {
 	// File path for sound features persistence ({tool}-features.json)
 	soundFile string
 
+	// Backend that previews sounds; its sounds replace SystemSounds when set
+	soundBackend notify.Backend
+	sounds       []notify.Sound
+
 	// Worktree expand/collapse state (project index -> expanded)
 	expandedWorktrees map[int]bool
+
+	// Collapsed project groups (group name -> collapsed)
+	collapsedGroups map[string]bool
+
+	// Running tmux sessions per project name
+	liveSessions map[string][]string
+
+	// Filter bar ("/"): fuzzy query and the projects it matched
+	filterMode    bool
+	filterQuery   string
+	filterMatches map[int]projectMatch
+
+	// Session choice mode (attach to a running session or start a new one)
+	sessionMode     bool
+	sessionProject  int
+	sessionSelected int
+
+	// Agent selection mode (several AI tools side by side): the tools to
+	// pick from and the project they launch in
+	agentSelect  *MultiSelectModel
+	agentProject int
+
+	// Git statuses shared with menus in other tabs; nil reads git directly
+	gitStatusCache *models.GitStatusCache
+
+	// Version and health of each AI tool by name, filled in asynchronously
+	// when checkAITools is set
+	checkAITools bool
+	aiHealth     map[string]models.AITool
 }
 
 // NewMainMenu creates a new main menu model.
@@ -164,7 +263,9 @@ func NewMainMenu(projects []models.Project, aiTools []string, currentAI string,
 	}
 
 	return &MainMenuModel{
-		projects:            projects,
+		projects:            groupProjects(projects),
+		loadedProjects:      projects,
+		projectSort:         "manual",
 		aiTools:             aiTools,
 		selectedAI:          selectedAI,
 		selectedItem:        0,
@@ -173,6 +274,9 @@ func NewMainMenu(projects []models.Project, aiTools []string, currentAI string,
 		theme:               ThemeForTool(currentAI),
 		zzz:                 NewZzzAnimation(),
 		expandedWorktrees:   make(map[int]bool),
+		collapsedGroups:     make(map[string]bool),
+		liveSessions:        make(map[string][]string),
+		persistSession:      "off",
 	}
 }
 
@@ -186,9 +290,338 @@ func (m *MainMenuModel) SelectedItem() int {
 	return m.selectedItem
 }
 
-// TotalItems returns the total number of selectable items (projects + expanded worktrees + 4 actions).
+// menuRow is one selectable row of the main menu list.
+type menuRow struct {
+	kind     string // "group", "project", "worktree" or "action"
+	index    int    // group index, project index or action offset
+	worktree int    // worktree index for "worktree" rows, otherwise -1
+}
+
+// projectGroup is a run of consecutive projects sharing the same Group.
+type projectGroup struct {
+	name     string
+	projects []int
+}
+
+// groupProjects orders projects so each group is contiguous: ungrouped
+// projects first, then groups in order of first appearance. The order of
+// projects within a group is kept.
+func groupProjects(projects []models.Project) []models.Project {
+	rank := map[string]int{"": 0}
+	for _, p := range projects {
+		if _, ok := rank[p.Group]; !ok {
+			rank[p.Group] = len(rank)
+		}
+	}
+	grouped := append([]models.Project(nil), projects...)
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return rank[grouped[i].Group] < rank[grouped[j].Group]
+	})
+	return grouped
+}
+
+// projectSortModes lists the project orderings in cycle order.
+var projectSortModes = []string{"manual", "recent", "frequent"}
+
+// sortProjects orders projects by the sort setting: "recent" puts the most
+// recently launched first, "frequent" the most launched (ties broken by
+// recency). Projects never launched keep their file order after the rest.
+func (m *MainMenuModel) sortProjects(projects []models.Project) []models.Project {
+	sorted := append([]models.Project(nil), projects...)
+	if m.projectSort == "manual" {
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := m.usage[sorted[i].Name], m.usage[sorted[j].Name]
+		if m.projectSort == "frequent" && a.Launches != b.Launches {
+			return a.Launches > b.Launches
+		}
+		return a.LastUsed.After(b.LastUsed)
+	})
+	return sorted
+}
+
+// reorderProjects re-sorts the projects after the sort setting or usage
+// changed, keeping expanded worktrees and the selection on the same items.
+func (m *MainMenuModel) reorderProjects() {
+	current := m.currentRow()
+	var selectedPath string
+	if current.kind == "project" || current.kind == "worktree" {
+		selectedPath = m.projects[current.index].Path
+	}
+	expanded := make(map[string]bool)
+	for i := range m.expandedWorktrees {
+		expanded[m.projects[i].Path] = true
+	}
+
+	m.projects = groupProjects(m.sortProjects(m.loadedProjects))
+
+	m.expandedWorktrees = make(map[int]bool)
+	for i, p := range m.projects {
+		if expanded[p.Path] {
+			m.expandedWorktrees[i] = true
+		}
+		if selectedPath != "" && p.Path == selectedPath {
+			current.index = i
+		}
+	}
+	m.selectRow(current)
+}
+
+// SetProjectUsage sets the launch history summary used by the "recent" and
+// "frequent" sort orders, keyed by project name.
+func (m *MainMenuModel) SetProjectUsage(usage map[string]history.Usage) {
+	m.usage = usage
+	m.reorderProjects()
+}
+
+// SetProjectSort sets the project order ("manual", "recent" or "frequent").
+// Unknown values fall back to "manual".
+func (m *MainMenuModel) SetProjectSort(mode string) {
+	m.projectSort = "manual"
+	for _, s := range projectSortModes {
+		if s == mode {
+			m.projectSort = mode
+		}
+	}
+	m.reorderProjects()
+}
+
+// ProjectSort returns the project order.
+func (m *MainMenuModel) ProjectSort() string {
+	return m.projectSort
+}
+
+// CycleProjectSort advances to the next project order and persists it.
+func (m *MainMenuModel) CycleProjectSort() {
+	m.cycleProjectSort(1)
+}
+
+// CycleProjectSortReverse goes back to the previous project order and
+// persists it.
+func (m *MainMenuModel) CycleProjectSortReverse() {
+	m.cycleProjectSort(len(projectSortModes) - 1)
+}
+
+func (m *MainMenuModel) cycleProjectSort(step int) {
+	for i, s := range projectSortModes {
+		if s == m.projectSort {
+			m.projectSort = projectSortModes[(i+step)%len(projectSortModes)]
+			break
+		}
+	}
+	m.reorderProjects()
+	m.persistSetting("project_sort", m.projectSort)
+}
+
+// groups splits the projects into groups in display order. Ungrouped
+// projects form a leading group with an empty name, shown without a header.
+func (m *MainMenuModel) groups() []projectGroup {
+	var groups []projectGroup
+	for i, p := range m.projects {
+		if len(groups) == 0 || groups[len(groups)-1].name != p.Group {
+			groups = append(groups, projectGroup{name: p.Group})
+		}
+		groups[len(groups)-1].projects = append(groups[len(groups)-1].projects, i)
+	}
+	return groups
+}
+
+// rows returns the visible rows in display order: group headers, projects
+// of expanded groups with their expanded worktrees, then the actions.
+// While filtering, only matching projects are listed, best match first.
+func (m *MainMenuModel) rows() []menuRow {
+	if m.filterQuery != "" {
+		return m.filteredRows()
+	}
+	var rows []menuRow
+	for gi, g := range m.groups() {
+		if g.name != "" {
+			rows = append(rows, menuRow{kind: "group", index: gi, worktree: -1})
+			if m.collapsedGroups[g.name] {
+				continue
+			}
+		}
+		for _, i := range g.projects {
+			rows = append(rows, menuRow{kind: "project", index: i, worktree: -1})
+			if m.expandedWorktrees[i] {
+				for j := range m.projects[i].Worktrees {
+					rows = append(rows, menuRow{kind: "worktree", index: i, worktree: j})
+				}
+			}
+		}
+	}
+	for i := range actionNames {
+		rows = append(rows, menuRow{kind: "action", index: i, worktree: -1})
+	}
+	return rows
+}
+
+// projectMatch records where a filter query matched a project.
+type projectMatch struct {
+	score     int
+	name      []int         // matched rune positions in the name
+	path      []int         // matched rune positions in the displayed path
+	tag       string        // best matching tag, if a tag matched
+	worktrees map[int][]int // worktree index -> matched positions in the branch
+}
+
+// matchProject fuzzy-matches query against a project's name, path, tags and
+// worktree branches. The project's score is its best field score.
+func matchProject(p models.Project, query string) (projectMatch, bool) {
+	match := projectMatch{worktrees: map[int][]int{}}
+	found := false
+	consider := func(score int) {
+		if !found || score > match.score {
+			match.score = score
+		}
+		found = true
+	}
+
+	if score, pos, ok := FuzzyMatch(query, p.Name); ok {
+		match.name = pos
+		consider(score)
+	}
+	if score, pos, ok := FuzzyMatch(query, shortenHomePath(p.Path)); ok {
+		match.path = pos
+		consider(score)
+	}
+	tagScore := 0
+	for _, tag := range p.Tags {
+		if score, _, ok := FuzzyMatch(query, tag); ok && (match.tag == "" || score > tagScore) {
+			match.tag, tagScore = tag, score
+			consider(score)
+		}
+	}
+	for i, wt := range p.Worktrees {
+		if score, pos, ok := FuzzyMatch(query, wt.Branch); ok {
+			match.worktrees[i] = pos
+			consider(score)
+		}
+	}
+	return match, found
+}
+
+// filteredRows lists the projects matching the filter query, best score
+// first, each followed by its matching (or expanded) worktrees, then the
+// actions.
+func (m *MainMenuModel) filteredRows() []menuRow {
+	var matched []int
+	for i := range m.projects {
+		if _, ok := m.filterMatches[i]; ok {
+			matched = append(matched, i)
+		}
+	}
+	sort.SliceStable(matched, func(a, b int) bool {
+		return m.filterMatches[matched[a]].score > m.filterMatches[matched[b]].score
+	})
+
+	var rows []menuRow
+	for _, i := range matched {
+		rows = append(rows, menuRow{kind: "project", index: i, worktree: -1})
+		for j := range m.projects[i].Worktrees {
+			if _, ok := m.filterMatches[i].worktrees[j]; ok || m.expandedWorktrees[i] {
+				rows = append(rows, menuRow{kind: "worktree", index: i, worktree: j})
+			}
+		}
+	}
+	for i := range actionNames {
+		rows = append(rows, menuRow{kind: "action", index: i, worktree: -1})
+	}
+	return rows
+}
+
+// InFilterMode returns whether the filter bar is open.
+func (m *MainMenuModel) InFilterMode() bool { return m.filterMode }
+
+// FilterQuery returns the current filter query.
+func (m *MainMenuModel) FilterQuery() string { return m.filterQuery }
+
+// EnterFilterMode opens the filter bar with an empty query.
+func (m *MainMenuModel) EnterFilterMode() {
+	m.filterMode = true
+	m.SetFilter("")
+}
+
+// ExitFilterMode closes the filter bar and clears the query, keeping the
+// selection on the same item.
+func (m *MainMenuModel) ExitFilterMode() {
+	current := m.currentRow()
+	m.filterMode = false
+	m.filterQuery = ""
+	m.filterMatches = nil
+	m.selectRow(current)
+}
+
+// SetFilter sets the filter query and moves the selection to the top match.
+func (m *MainMenuModel) SetFilter(query string) {
+	m.filterQuery = query
+	m.filterMatches = make(map[int]projectMatch)
+	if query != "" {
+		for i, p := range m.projects {
+			if match, ok := matchProject(p, query); ok {
+				m.filterMatches[i] = match
+			}
+		}
+	}
+	m.selectedItem = 0
+}
+
+// FilterMatchCount returns how many projects match the filter query.
+func (m *MainMenuModel) FilterMatchCount() int {
+	if m.filterQuery == "" {
+		return len(m.projects)
+	}
+	return len(m.filterMatches)
+}
+
+// rowHeight returns how many terminal lines a row of the given kind takes.
+func rowHeight(kind string) int {
+	if kind == "project" {
+		return 2 // name + path
+	}
+	return 1
+}
+
+// TotalItems returns the total number of selectable items (group headers +
+// visible projects + expanded worktrees + 4 actions).
 func (m *MainMenuModel) TotalItems() int {
-	return len(m.projects) + m.expandedWorktreeCount() + len(actionNames)
+	return len(m.rows())
+}
+
+// currentRow returns the row under the selection.
+func (m *MainMenuModel) currentRow() menuRow {
+	itemType, idx, wt := m.ResolveItem(m.selectedItem)
+	return menuRow{kind: itemType, index: idx, worktree: wt}
+}
+
+// selectRow moves the selection to row. If row is no longer visible the
+// selection falls back to its project, then to its group header.
+func (m *MainMenuModel) selectRow(row menuRow) {
+	rows := m.rows()
+	find := func(want menuRow) bool {
+		for i, r := range rows {
+			if r == want {
+				m.selectedItem = i
+				return true
+			}
+		}
+		return false
+	}
+	if find(row) {
+		return
+	}
+	if row.kind == "worktree" && find(menuRow{kind: "project", index: row.index, worktree: -1}) {
+		return
+	}
+	if row.kind == "worktree" || row.kind == "project" {
+		for gi, g := range m.groups() {
+			if g.name == m.projects[row.index].Group {
+				find(menuRow{kind: "group", index: gi, worktree: -1})
+				return
+			}
+		}
+	}
 }
 
 // ToggleWorktrees toggles expand/collapse for the given project index.
@@ -201,68 +634,105 @@ func (m *MainMenuModel) ToggleWorktrees(projectIdx int) {
 		return
 	}
 
+	// Keep the selection on the same item; collapsing while on one of this
+	// project's worktrees snaps to the project.
+	current := m.currentRow()
 	if m.expandedWorktrees[projectIdx] {
-		// Collapsing: if selection is on one of this project's worktrees, snap to project
-		flatIdx := m.projectToFlatIndex(projectIdx)
-		wtCount := len(m.projects[projectIdx].Worktrees)
-		if m.selectedItem > flatIdx && m.selectedItem <= flatIdx+wtCount {
-			m.selectedItem = flatIdx
-		} else if m.selectedItem > flatIdx+wtCount {
-			// Adjust selection for removed items
-			m.selectedItem -= wtCount
-		}
 		delete(m.expandedWorktrees, projectIdx)
 	} else {
 		m.expandedWorktrees[projectIdx] = true
 	}
+	m.selectRow(current)
+}
+
+// ToggleGroup collapses or expands the group at groupIdx (as returned by
+// ResolveItem for "group" rows) and persists the collapsed groups.
+func (m *MainMenuModel) ToggleGroup(groupIdx int) {
+	groups := m.groups()
+	if groupIdx < 0 || groupIdx >= len(groups) || groups[groupIdx].name == "" {
+		return
+	}
+	name := groups[groupIdx].name
+
+	current := m.currentRow()
+	if m.collapsedGroups[name] {
+		delete(m.collapsedGroups, name)
+	} else {
+		m.collapsedGroups[name] = true
+	}
+	m.selectRow(current)
+	m.persistSetting("collapsed_groups", strings.Join(m.CollapsedGroups(), ","))
 }
 
-// expandedWorktreeCount returns the total number of visible worktree entries.
-func (m *MainMenuModel) expandedWorktreeCount() int {
-	count := 0
-	for idx := range m.expandedWorktrees {
-		if idx < len(m.projects) {
-			count += len(m.projects[idx].Worktrees)
+// SetCollapsedGroups sets which groups start collapsed.
+func (m *MainMenuModel) SetCollapsedGroups(names []string) {
+	m.collapsedGroups = make(map[string]bool)
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			m.collapsedGroups[name] = true
 		}
 	}
-	return count
 }
 
-// projectToFlatIndex converts a project index to its flat item index,
-// accounting for expanded worktrees above it.
+// CollapsedGroups returns the names of the collapsed groups, sorted.
+func (m *MainMenuModel) CollapsedGroups() []string {
+	var names []string
+	for name := range m.collapsedGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsGroupCollapsed returns whether the named group is collapsed.
+func (m *MainMenuModel) IsGroupCollapsed(name string) bool {
+	return m.collapsedGroups[name]
+}
+
+// GroupName returns the name of the group at groupIdx, or "".
+func (m *MainMenuModel) GroupName(groupIdx int) string {
+	groups := m.groups()
+	if groupIdx < 0 || groupIdx >= len(groups) {
+		return ""
+	}
+	return groups[groupIdx].name
+}
+
+// projectToFlatIndex converts a project index to its flat item index.
+// A project hidden in a collapsed group maps to its group header.
 func (m *MainMenuModel) projectToFlatIndex(projectIdx int) int {
-	flat := 0
-	for i := 0; i < projectIdx; i++ {
-		flat++ // the project itself
-		if m.expandedWorktrees[i] {
-			flat += len(m.projects[i].Worktrees)
+	rows := m.rows()
+	for i, r := range rows {
+		if r.kind == "project" && r.index == projectIdx {
+			return i
+		}
+	}
+	if projectIdx >= 0 && projectIdx < len(m.projects) {
+		for gi, g := range m.groups() {
+			if g.name == m.projects[projectIdx].Group {
+				for i, r := range rows {
+					if r.kind == "group" && r.index == gi {
+						return i
+					}
+				}
+			}
 		}
 	}
-	return flat
+	return len(rows) - len(actionNames)
 }
 
 // ResolveItem maps a flat selectedItem index to what it represents.
-// Returns: itemType ("project", "worktree", or "action"), projectIdx, worktreeIdx.
+// Returns: itemType ("group", "project", "worktree", or "action"), projectIdx, worktreeIdx.
+// For "group", projectIdx is the group index (see GroupName).
 // For "action", projectIdx is the action offset (0=add, 1=delete, etc).
 func (m *MainMenuModel) ResolveItem(flatIdx int) (itemType string, projectIdx int, worktreeIdx int) {
-	pos := 0
-	for i, proj := range m.projects {
-		if flatIdx == pos {
-			return "project", i, -1
-		}
-		pos++
-		if m.expandedWorktrees[i] {
-			for j := range proj.Worktrees {
-				if flatIdx == pos {
-					return "worktree", i, j
-				}
-				pos++
-			}
-		}
+	rows := m.rows()
+	if flatIdx >= 0 && flatIdx < len(rows) {
+		r := rows[flatIdx]
+		return r.kind, r.index, r.worktree
 	}
 	// Must be an action
-	actionIdx := flatIdx - pos
-	return "action", actionIdx, -1
+	return "action", flatIdx - (len(rows) - len(actionNames)), -1
 }
 
 // IsExpanded returns whether the given project index is expanded.
@@ -278,6 +748,18 @@ func (m *MainMenuModel) CurrentAITool() string {
 	return m.aiTools[m.selectedAI]
 }
 
+// projectAITool returns the project's default AI tool if it is one of the
+// available tools, otherwise "".
+func (m *MainMenuModel) projectAITool(projectIdx int) string {
+	tool := m.projects[projectIdx].AITool
+	for _, t := range m.aiTools {
+		if t == tool {
+			return tool
+		}
+	}
+	return ""
+}
+
 // CycleAITool cycles the AI tool selection forward ("next") or backward ("prev").
 // If aiToolFile is set, the new tool is persisted to disk immediately.
 func (m *MainMenuModel) CycleAITool(direction string) {
@@ -291,9 +773,63 @@ func (m *MainMenuModel) CycleAITool(direction string) {
 		m.selectedAI = (m.selectedAI - 1 + n) % n
 	}
 	m.theme = ThemeForTool(m.aiTools[m.selectedAI])
+	m.aiProfile = ""
 	m.persistAITool()
 }
 
+// CurrentProfile returns the launch profile shown next to the AI tool: the
+// one chosen with Tab, otherwise the selected project's default when the
+// current tool defines it, otherwise "default".
+func (m *MainMenuModel) CurrentProfile() string {
+	if m.aiProfile != "" {
+		return m.aiProfile
+	}
+	itemType, projectIdx, _ := m.ResolveItem(m.selectedItem)
+	if itemType != "project" && itemType != "worktree" {
+		return aitools.NoProfile
+	}
+	if tool := m.projectAITool(projectIdx); tool != "" && tool != m.CurrentAITool() {
+		return aitools.NoProfile
+	}
+	tool := aitools.Current().Get(m.CurrentAITool())
+	if p, ok := tool.Profile(m.projects[projectIdx].Profile); ok {
+		return p.Name
+	}
+	return aitools.NoProfile
+}
+
+// CycleProfile cycles the current AI tool's launch profile forward ("next")
+// or backward ("prev"), starting from the one shown.
+func (m *MainMenuModel) CycleProfile(direction string) {
+	names := aitools.Current().Get(m.CurrentAITool()).ProfileNames()
+	n := len(names)
+	if n <= 1 {
+		return
+	}
+	i := 0
+	for j, name := range names {
+		if name == m.CurrentProfile() {
+			i = j
+		}
+	}
+	if direction == "next" {
+		i = (i + 1) % n
+	} else {
+		i = (i - 1 + n) % n
+	}
+	m.aiProfile = names[i]
+}
+
+// profileForResult returns the profile chosen with Tab for a launch of
+// project projectIdx, or "" if none was chosen or the project launches with
+// a different tool.
+func (m *MainMenuModel) profileForResult(projectIdx int) string {
+	if tool := m.projectAITool(projectIdx); tool != "" && tool != m.CurrentAITool() {
+		return ""
+	}
+	return m.aiProfile
+}
+
 // persistAITool writes the current AI tool to the preference file if set.
 func (m *MainMenuModel) persistAITool() {
 	if m.aiToolFile == "" {
@@ -321,6 +857,11 @@ func (m *MainMenuModel) JumpTo(n int) {
 	if n < 1 || n > len(m.projects) {
 		return
 	}
+	// Jumping into a collapsed group opens it
+	if group := m.projects[n-1].Group; m.collapsedGroups[group] {
+		delete(m.collapsedGroups, group)
+		m.persistSetting("collapsed_groups", strings.Join(m.CollapsedGroups(), ","))
+	}
 	m.selectedItem = m.projectToFlatIndex(n - 1)
 }
 
@@ -369,57 +910,77 @@ func (m *MainMenuModel) SoundName() string {
 	return m.soundName
 }
 
-// CycleSoundName cycles forward through system sounds + Off.
+// SetSoundBackend sets the backend used to preview sounds. The sounds it
+// finds replace SystemSounds in the cycle.
+func (m *MainMenuModel) SetSoundBackend(b notify.Backend) {
+	m.soundBackend = b
+	m.sounds = b.Sounds()
+}
+
+// SoundNames returns the sounds CycleSoundName steps through, in order.
+func (m *MainMenuModel) SoundNames() []string {
+	if m.soundBackend == nil {
+		return SystemSounds
+	}
+	return notify.SoundNames(m.sounds)
+}
+
+// CycleSoundName cycles forward through the sounds + Off.
 func (m *MainMenuModel) CycleSoundName() {
-	if m.soundName == "" {
-		m.soundName = SystemSounds[0]
-	} else {
-		idx := -1
-		for i, s := range SystemSounds {
-			if s == m.soundName {
-				idx = i
-				break
-			}
-		}
-		if idx >= 0 && idx < len(SystemSounds)-1 {
-			m.soundName = SystemSounds[idx+1]
-		} else {
-			m.soundName = ""
-		}
+	names := m.SoundNames()
+	idx := indexOf(names, m.soundName)
+	switch {
+	case m.soundName == "" && len(names) > 0:
+		m.soundName = names[0]
+	case idx >= 0 && idx < len(names)-1:
+		m.soundName = names[idx+1]
+	default:
+		m.soundName = ""
 	}
 	m.soundNameChanged = m.soundName != m.initialSoundName
 	m.previewSound()
 	m.persistSound()
 }
 
-// CycleSoundNameReverse cycles backward through Off + system sounds.
+// CycleSoundNameReverse cycles backward through Off + the sounds.
 func (m *MainMenuModel) CycleSoundNameReverse() {
-	if m.soundName == "" {
-		m.soundName = SystemSounds[len(SystemSounds)-1]
-	} else {
-		idx := -1
-		for i, s := range SystemSounds {
-			if s == m.soundName {
-				idx = i
-				break
-			}
-		}
-		if idx > 0 {
-			m.soundName = SystemSounds[idx-1]
-		} else {
-			m.soundName = ""
-		}
+	names := m.SoundNames()
+	idx := indexOf(names, m.soundName)
+	switch {
+	case m.soundName == "" && len(names) > 0:
+		m.soundName = names[len(names)-1]
+	case idx > 0:
+		m.soundName = names[idx-1]
+	default:
+		m.soundName = ""
 	}
 	m.soundNameChanged = m.soundName != m.initialSoundName
 	m.previewSound()
 	m.persistSound()
 }
 
-// previewSound plays the current sound in the background using afplay.
+// indexOf returns the position of s in list, or -1.
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// previewSound plays the current sound in the background through the sound
+// backend, or with afplay from the macOS system sounds when none is set.
 func (m *MainMenuModel) previewSound() {
 	if m.soundName == "" {
 		return
 	}
+	if m.soundBackend != nil {
+		if s, ok := notify.FindSound(m.sounds, m.soundName); ok {
+			_ = m.soundBackend.Play(s)
+		}
+		return
+	}
 	path := "/System/Library/Sounds/" + m.soundName + ".aiff"
 	go func() {
 		cmd := exec.Command("afplay", path)
@@ -460,6 +1021,65 @@ func (m *MainMenuModel) soundNameForResult() *string {
 	return nil
 }
 
+// SetPersistSession sets the persist-session mode ("on" or "off") and records
+// the initial value.
+func (m *MainMenuModel) SetPersistSession(mode string) {
+	if mode != "on" {
+		mode = "off"
+	}
+	m.persistSession = mode
+	m.initialPersistSession = mode
+}
+
+// PersistSession returns the persist-session mode ("on" or "off").
+func (m *MainMenuModel) PersistSession() string {
+	return m.persistSession
+}
+
+// TogglePersistSession switches persist-session mode between "on" and "off".
+func (m *MainMenuModel) TogglePersistSession() {
+	if m.persistSession == "on" {
+		m.persistSession = "off"
+	} else {
+		m.persistSession = "on"
+	}
+	m.persistSessionChanged = m.persistSession != m.initialPersistSession
+	m.persistSetting("persist_session", m.persistSession)
+}
+
+// persistSessionForResult returns the persist-session value to include in
+// the result, or empty string if unchanged.
+func (m *MainMenuModel) persistSessionForResult() string {
+	if m.persistSessionChanged {
+		return m.persistSession
+	}
+	return ""
+}
+
+// SetLiveSessions sets the running tmux sessions for each project name.
+func (m *MainMenuModel) SetLiveSessions(sessions map[string][]string) {
+	m.liveSessions = make(map[string][]string, len(sessions))
+	for name, list := range sessions {
+		if len(list) > 0 {
+			m.liveSessions[name] = list
+		}
+	}
+}
+
+// LiveSessions returns the running tmux sessions for the given project name.
+func (m *MainMenuModel) LiveSessions(projectName string) []string {
+	return m.liveSessions[projectName]
+}
+
+// InSessionMode returns true if the menu is asking whether to attach to a
+// running session or start a new one.
+func (m *MainMenuModel) InSessionMode() bool { return m.sessionMode }
+
+// SessionSelected returns the highlighted option in session choice mode.
+// Indices below the number of live sessions are sessions; the last index is
+// "New session".
+func (m *MainMenuModel) SessionSelected() int { return m.sessionSelected }
+
 // InSettingsMode returns true if the menu is currently showing the settings panel.
 func (m *MainMenuModel) InSettingsMode() bool {
 	return m.settingsMode
@@ -633,16 +1253,19 @@ func (m *MainMenuModel) Result() *MainMenuResult {
 
 // CalculateLayout determines how the ghost and menu should be arranged.
 func (m *MainMenuModel) CalculateLayout(width, height int) MenuLayout {
-	numProjects := len(m.projects)
 	numSeparators := 0
-	if numProjects > 0 {
+	if len(m.projects) > 0 {
 		numSeparators = 1
 	}
-	// Projects = 2 rows each, worktrees = 1 row each, actions = 1 row each
-	projectRows := numProjects * 2
-	worktreeRows := m.expandedWorktreeCount()
-	actionRows := len(actionNames)
-	menuHeight := 7 + projectRows + worktreeRows + actionRows + numSeparators
+	// Projects = 2 rows each, group headers, worktrees and actions = 1 row each
+	itemRows := 0
+	for _, r := range m.rows() {
+		itemRows += rowHeight(r.kind)
+	}
+	menuHeight := 7 + itemRows + numSeparators
+	if m.filterMode {
+		menuHeight++ // filter bar
+	}
 	menuWidth := 48
 
 	ghostPosition := "hidden"
@@ -671,48 +1294,30 @@ func (m *MainMenuModel) MapRowToItem(clickY int) int {
 	// Row 1: title row
 	// Row 2: separator
 	// (optional) update notification row
-	// Row 3/4: empty row
+	// (optional) filter bar row
+	// Row 3/4/5: empty row
 	// Then items start
 	startRow := 4
 	if m.updateVersion != "" {
 		startRow++ // update notification takes a row
 	}
+	if m.filterMode {
+		startRow++ // filter bar takes a row
+	}
 
 	currentRow := startRow
-	flatIdx := 0
-
-	for i, proj := range m.projects {
-		// Project takes 2 rows (name + path)
-		if clickY == currentRow || clickY == currentRow+1 {
-			return flatIdx
-		}
-		currentRow += 2
-		flatIdx++
 
-		// Expanded worktrees (1 row each)
-		if m.expandedWorktrees[i] {
-			for range proj.Worktrees {
-				if clickY == currentRow {
-					return flatIdx
-				}
-				currentRow++
-				flatIdx++
-			}
+	for flatIdx, r := range m.rows() {
+		// Separator between projects and actions
+		if r.kind == "action" && r.index == 0 && len(m.projects) > 0 {
+			currentRow++
 		}
-	}
-
-	// Separator
-	if len(m.projects) > 0 {
-		currentRow++
-	}
-
-	// Action items (1 row each)
-	for range actionNames {
-		if clickY == currentRow {
+		// Projects take 2 rows (name + path), everything else 1
+		height := rowHeight(r.kind)
+		if clickY >= currentRow && clickY < currentRow+height {
 			return flatIdx
 		}
-		currentRow++
-		flatIdx++
+		currentRow += height
 	}
 
 	return -1
@@ -743,32 +1348,39 @@ func (m *MainMenuModel) selectCurrent() {
 	switch itemType {
 	case "project":
 		m.result = &MainMenuResult{
-			Action:       "select-project",
-			Name:         m.projects[projectIdx].Name,
-			Path:         m.projects[projectIdx].Path,
-			AITool:       m.CurrentAITool(),
-			GhostDisplay: m.ghostDisplayForResult(),
-			TabTitle:     m.tabTitleForResult(),
-			SoundName:    m.soundNameForResult(),
+			Action:         "select-project",
+			Name:           m.projects[projectIdx].Name,
+			Path:           m.projects[projectIdx].Path,
+			AITool:         m.CurrentAITool(),
+			ProjectAITool:  m.projectAITool(projectIdx),
+			Profile:        m.profileForResult(projectIdx),
+			GhostDisplay:   m.ghostDisplayForResult(),
+			TabTitle:       m.tabTitleForResult(),
+			SoundName:      m.soundNameForResult(),
+			PersistSession: m.persistSessionForResult(),
 		}
 	case "worktree":
 		m.result = &MainMenuResult{
-			Action:       "select-project",
-			Name:         m.projects[projectIdx].Name,
-			Path:         m.projects[projectIdx].Worktrees[worktreeIdx].Path,
-			AITool:       m.CurrentAITool(),
-			GhostDisplay: m.ghostDisplayForResult(),
-			TabTitle:     m.tabTitleForResult(),
-			SoundName:    m.soundNameForResult(),
+			Action:         "select-project",
+			Name:           m.projects[projectIdx].Name,
+			Path:           m.projects[projectIdx].Worktrees[worktreeIdx].Path,
+			AITool:         m.CurrentAITool(),
+			ProjectAITool:  m.projectAITool(projectIdx),
+			Profile:        m.profileForResult(projectIdx),
+			GhostDisplay:   m.ghostDisplayForResult(),
+			TabTitle:       m.tabTitleForResult(),
+			SoundName:      m.soundNameForResult(),
+			PersistSession: m.persistSessionForResult(),
 		}
 	case "action":
 		if projectIdx < len(actionNames) {
 			m.result = &MainMenuResult{
-				Action:       actionNames[projectIdx],
-				AITool:       m.CurrentAITool(),
-				GhostDisplay: m.ghostDisplayForResult(),
-				TabTitle:     m.tabTitleForResult(),
-				SoundName:    m.soundNameForResult(),
+				Action:         actionNames[projectIdx],
+				AITool:         m.CurrentAITool(),
+				GhostDisplay:   m.ghostDisplayForResult(),
+				TabTitle:       m.tabTitleForResult(),
+				SoundName:      m.soundNameForResult(),
+				PersistSession: m.persistSessionForResult(),
 			}
 		}
 	}
@@ -776,17 +1388,55 @@ func (m *MainMenuModel) selectCurrent() {
 	m.quitting = true
 }
 
-// setActionResult produces a result for the given action name.
-func (m *MainMenuModel) setActionResult(action string) {
-	m.result = &MainMenuResult{
-		Action:       action,
-		AITool:       m.CurrentAITool(),
-		GhostDisplay: m.ghostDisplayForResult(),
-		TabTitle:     m.tabTitleForResult(),
-		SoundName: m.soundNameForResult(),
+// activateCurrent launches the selected item. Selecting a project that
+// already has running sessions asks whether to attach or start a new one.
+func (m *MainMenuModel) activateCurrent() (tea.Model, tea.Cmd) {
+	itemType, projectIdx, _ := m.ResolveItem(m.selectedItem)
+	if itemType == "group" {
+		m.ToggleGroup(projectIdx)
+		return m, nil
 	}
-	m.quitting = true
-}
+	if itemType == "project" && len(m.LiveSessions(m.projects[projectIdx].Name)) > 0 {
+		m.sessionMode = true
+		m.sessionProject = projectIdx
+		m.sessionSelected = 0
+		return m, nil
+	}
+	m.selectCurrent()
+	return m, tea.Quit
+}
+
+// enterCurrent handles Enter on the selected item. Actions that need more
+// input open their inline mode; everything else is activated.
+func (m *MainMenuModel) enterCurrent() (tea.Model, tea.Cmd) {
+	itemType, projectIdx, _ := m.ResolveItem(m.selectedItem)
+	if itemType == "action" {
+		if projectIdx < len(actionNames) {
+			switch actionNames[projectIdx] {
+			case "add-project":
+				return m.enterInputMode("add-project")
+			case "delete-project":
+				return m.enterDeleteMode()
+			case "open-once":
+				return m.enterInputMode("open-once")
+			}
+		}
+	}
+	return m.activateCurrent()
+}
+
+// setActionResult produces a result for the given action name.
+func (m *MainMenuModel) setActionResult(action string) {
+	m.result = &MainMenuResult{
+		Action:         action,
+		AITool:         m.CurrentAITool(),
+		GhostDisplay:   m.ghostDisplayForResult(),
+		TabTitle:       m.tabTitleForResult(),
+		SoundName:      m.soundNameForResult(),
+		PersistSession: m.persistSessionForResult(),
+	}
+	m.quitting = true
+}
 
 // bobTickCmd returns a command that sends a bobTickMsg at ~60fps.
 func (m *MainMenuModel) bobTickCmd() tea.Cmd {
@@ -809,9 +1459,106 @@ func (m *MainMenuModel) Init() tea.Cmd {
 		cmds = append(cmds, m.bobTickCmd())
 		cmds = append(cmds, m.sleepTickCmd())
 	}
+	cmds = append(cmds, m.RefreshGitStatus())
+	if m.checkAITools {
+		cmds = append(cmds, m.checkAIToolsCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// SetCheckAITools makes Init run the AI tools' version commands so the
+// header can show the current tool's version.
+func (m *MainMenuModel) SetCheckAITools(check bool) { m.checkAITools = check }
+
+// NewAIToolHealthMsg creates an AI tool health result (for testing).
+func NewAIToolHealthMsg(tools []models.AITool) tea.Msg {
+	return aiToolHealthMsg{tools: tools}
+}
+
+// checkAIToolsCmd runs the version command of each of the menu's AI tools
+// in the background.
+func (m *MainMenuModel) checkAIToolsCmd() tea.Cmd {
+	var tools []aitools.Tool
+	for _, name := range m.aiTools {
+		tools = append(tools, aitools.Current().Get(name))
+	}
+	return func() tea.Msg {
+		return aiToolHealthMsg{tools: models.CheckTools(tools, models.ToolCheckTimeout)}
+	}
+}
+
+// SetGitStatusCache sets the cache git statuses are read through.
+func (m *MainMenuModel) SetGitStatusCache(c *models.GitStatusCache) { m.gitStatusCache = c }
+
+// NewGitStatusMsg creates a git status result for dir (for testing).
+func NewGitStatusMsg(dir string, status models.GitStatus) tea.Msg {
+	return gitStatusMsg{path: dir, status: status}
+}
+
+// RefreshGitStatus returns a command that reads the git status of every
+// project and worktree in the background, one message per directory, so
+// badges fill in as results arrive.
+func (m *MainMenuModel) RefreshGitStatus() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, p := range m.projects {
+		cmds = append(cmds, m.gitStatusCmd(p.Path))
+		for _, wt := range p.Worktrees {
+			cmds = append(cmds, m.gitStatusCmd(wt.Path))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// worktreesGitStatusCmd reads the git status of the worktrees of the
+// project at projectIdx after they were re-read.
+func (m *MainMenuModel) worktreesGitStatusCmd(projectIdx int) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, wt := range m.projects[projectIdx].Worktrees {
+		cmds = append(cmds, m.gitStatusCmd(wt.Path))
+	}
 	return tea.Batch(cmds...)
 }
 
+// gitStatusCmd reads the git status of dir, a project or worktree path as
+// listed, which may start with ~. Directories that aren't git repositories
+// produce no message.
+func (m *MainMenuModel) gitStatusCmd(dir string) tea.Cmd {
+	cache := m.gitStatusCache
+	return func() tea.Msg {
+		expanded := filepath.Clean(util.ExpandPath(dir))
+		var status models.GitStatus
+		var err error
+		if cache != nil {
+			status, err = cache.Status(expanded)
+		} else {
+			status, err = models.ReadGitStatus(expanded)
+		}
+		if err != nil {
+			return nil
+		}
+		return gitStatusMsg{path: dir, status: status}
+	}
+}
+
+// applyGitStatus attaches status to the project or worktree at dir,
+// including the file-order copy used for re-sorting.
+func (m *MainMenuModel) applyGitStatus(dir string, status models.GitStatus) {
+	for _, list := range [][]models.Project{m.projects, m.loadedProjects} {
+		for i := range list {
+			if list[i].Path == dir {
+				s := status
+				list[i].Status = &s
+			}
+			for j := range list[i].Worktrees {
+				if list[i].Worktrees[j].Path == dir {
+					s := status
+					list[i].Worktrees[j].Status = &s
+				}
+			}
+		}
+	}
+}
+
 // Update implements tea.Model. Handles key bindings, window resize, and animation ticks.
 func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -853,6 +1600,33 @@ func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.SetSize(msg.Width, msg.Height)
 		return m, nil
 
+	case gitStatusMsg:
+		m.applyGitStatus(msg.path, msg.status)
+		return m, nil
+
+	case worktreeAddedMsg:
+		return m.applyWorktreeAdded(msg)
+
+	case worktreeChangesMsg:
+		if m.removeWorktreeMode && m.removeWorktreeCounting {
+			wt := m.projects[m.removeWorktreeProject].Worktrees[m.removeWorktreeIdx]
+			if wt.Path == msg.path {
+				m.removeWorktreeChanges = msg.changes
+				m.removeWorktreeCounting = false
+			}
+		}
+		return m, nil
+
+	case worktreeRemovedMsg:
+		return m.applyWorktreeRemoved(msg)
+
+	case aiToolHealthMsg:
+		m.aiHealth = make(map[string]models.AITool, len(msg.tools))
+		for _, t := range msg.tools {
+			m.aiHealth[t.Name] = t
+		}
+		return m, nil
+
 	case tea.MouseMsg:
 		// Reset sleep state on any mouse activity
 		m.Wake()
@@ -862,8 +1636,7 @@ func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if item >= 0 {
 				if m.selectedItem == item {
 					// Already selected, activate (double-click-like behavior)
-					m.selectCurrent()
-					return m, tea.Quit
+					return m.activateCurrent()
 				}
 				m.selectedItem = item
 			}
@@ -889,6 +1662,26 @@ func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateDeleteMode(msg)
 		}
 
+		// Worktree removal confirmation intercepts all key handling
+		if m.removeWorktreeMode {
+			return m.updateRemoveWorktreeMode(msg)
+		}
+
+		// Session choice mode intercepts all key handling
+		if m.sessionMode {
+			return m.updateSessionMode(msg)
+		}
+
+		// Agent selection intercepts all key handling
+		if m.agentSelect != nil {
+			return m.updateAgentSelect(msg)
+		}
+
+		// Filter mode sends typed characters to the filter bar
+		if m.filterMode {
+			return m.updateFilterMode(msg)
+		}
+
 		switch msg.Type {
 		case tea.KeyUp:
 			m.MoveUp()
@@ -902,22 +1695,14 @@ func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyRight:
 			m.CycleAITool("next")
 			return m, nil
+		case tea.KeyTab:
+			m.CycleProfile("next")
+			return m, nil
+		case tea.KeyShiftTab:
+			m.CycleProfile("prev")
+			return m, nil
 		case tea.KeyEnter:
-			itemType, projectIdx, _ := m.ResolveItem(m.selectedItem)
-			if itemType == "action" {
-				if projectIdx < len(actionNames) {
-					switch actionNames[projectIdx] {
-					case "add-project":
-						return m.enterInputMode("add-project")
-					case "delete-project":
-						return m.enterDeleteMode()
-					case "open-once":
-						return m.enterInputMode("open-once")
-					}
-				}
-			}
-			m.selectCurrent()
-			return m, tea.Quit
+			return m.enterCurrent()
 		case tea.KeyEsc:
 			m.setActionResult("quit")
 			return m, tea.Quit
@@ -953,6 +1738,16 @@ func (m *MainMenuModel) handleRune(r rune) (tea.Model, tea.Cmd) {
 	case 'p', 'P':
 		m.setActionResult("plain-terminal")
 		return m, tea.Quit
+	case 'i', 'I':
+		m.setActionResult("import-projects")
+		return m, tea.Quit
+	case 'n', 'N':
+		return m.enterNewWorktree()
+	case 'x', 'X':
+		return m.enterRemoveWorktree()
+	case 'm', 'M':
+		m.enterAgentSelect()
+		return m, nil
 	case 'w', 'W':
 		itemType, projectIdx, _ := m.ResolveItem(m.selectedItem)
 		if itemType == "project" {
@@ -960,24 +1755,31 @@ func (m *MainMenuModel) handleRune(r rune) (tea.Model, tea.Cmd) {
 		} else if itemType == "worktree" {
 			// If on a worktree, toggle its parent project
 			m.ToggleWorktrees(projectIdx)
+		} else if itemType == "group" {
+			m.ToggleGroup(projectIdx)
 		}
 		return m, nil
 	case 's', 'S':
 		m.settingsMode = true
 		m.settingsSelected = 0
 		return m, nil
+	case '/':
+		m.EnterFilterMode()
+		return m, nil
 	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		n := int(r - '0')
 		if n > len(m.projects) {
 			return m, nil
 		}
 		m.JumpTo(n)
-		m.selectCurrent()
-		return m, tea.Quit
+		return m.activateCurrent()
 	}
 	return m, nil
 }
 
+// settingsItemCount is the number of rows in the settings panel.
+const settingsItemCount = 5
+
 // updateSettings handles key events while in settings mode.
 func (m *MainMenuModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
@@ -997,6 +1799,10 @@ func (m *MainMenuModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.CycleTabTitle()
 		case 2:
 			m.CycleSoundName()
+		case 3:
+			m.TogglePersistSession()
+		case 4:
+			m.CycleProjectSort()
 		}
 		return m, nil
 	case tea.KeyUp:
@@ -1005,7 +1811,7 @@ func (m *MainMenuModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case tea.KeyDown:
-		if m.settingsSelected < 2 {
+		if m.settingsSelected < settingsItemCount-1 {
 			m.settingsSelected++
 		}
 		return m, nil
@@ -1017,6 +1823,10 @@ func (m *MainMenuModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.CycleTabTitle()
 		case 2:
 			m.CycleSoundName()
+		case 3:
+			m.TogglePersistSession()
+		case 4:
+			m.CycleProjectSort()
 		}
 		return m, nil
 	case tea.KeyLeft:
@@ -1027,6 +1837,10 @@ func (m *MainMenuModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.CycleTabTitle()
 		case 2:
 			m.CycleSoundNameReverse()
+		case 3:
+			m.TogglePersistSession()
+		case 4:
+			m.CycleProjectSortReverse()
 		}
 		return m, nil
 	case tea.KeyRunes:
@@ -1034,7 +1848,7 @@ func (m *MainMenuModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			r := TranslateRune(msg.Runes[0])
 			switch r {
 			case 'j':
-				if m.settingsSelected < 2 {
+				if m.settingsSelected < settingsItemCount-1 {
 					m.settingsSelected++
 				}
 				return m, nil
@@ -1049,6 +1863,55 @@ func (m *MainMenuModel) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateFilterMode handles key events while the filter bar is open.
+// Printable keys edit the query; Enter launches the selected (by default
+// the best) match.
+func (m *MainMenuModel) updateFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.ExitFilterMode()
+		return m, nil
+	case tea.KeyCtrlC:
+		m.setActionResult("quit")
+		return m, tea.Quit
+	case tea.KeyUp:
+		m.MoveUp()
+		return m, nil
+	case tea.KeyDown:
+		m.MoveDown()
+		return m, nil
+	case tea.KeyLeft:
+		m.CycleAITool("prev")
+		return m, nil
+	case tea.KeyRight:
+		m.CycleAITool("next")
+		return m, nil
+	case tea.KeyEnter:
+		if m.FilterMatchCount() == 0 {
+			return m, nil
+		}
+		if itemType, _, _ := m.ResolveItem(m.selectedItem); itemType == "action" {
+			m.ExitFilterMode()
+		}
+		return m.enterCurrent()
+	case tea.KeyBackspace:
+		if m.filterQuery == "" {
+			m.ExitFilterMode()
+			return m, nil
+		}
+		query := []rune(m.filterQuery)
+		m.SetFilter(string(query[:len(query)-1]))
+		return m, nil
+	case tea.KeySpace:
+		m.SetFilter(m.filterQuery + " ")
+		return m, nil
+	case tea.KeyRunes:
+		m.SetFilter(m.filterQuery + string(msg.Runes))
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m *MainMenuModel) enterInputMode(mode string) (tea.Model, tea.Cmd) {
 	m.inputMode = mode
 	m.inputErr = nil
@@ -1132,6 +1995,13 @@ func (m *MainMenuModel) updateInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *MainMenuModel) submitInputMode() (tea.Model, tea.Cmd) {
+	switch m.inputMode {
+	case "worktree-branch":
+		return m.submitWorktreeBranch()
+	case "worktree-path":
+		return m.submitWorktreePath()
+	}
+
 	path := strings.TrimSpace(m.pathInput.Value())
 
 	if path == "" {
@@ -1154,31 +2024,33 @@ func (m *MainMenuModel) submitInputMode() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if err := AppendProject(name, expanded, m.projectsFile); err != nil {
+		if err := AppendProject(models.Project{Name: name, Path: expanded}, m.projectsFile); err != nil {
 			m.inputErr = fmt.Errorf("Failed to save: %v", err)
 			return m, nil
 		}
 
 		projects, _ := models.LoadProjects(m.projectsFile)
 		models.PopulateWorktrees(projects)
-		m.projects = projects
+		m.loadedProjects = projects
+		m.projects = groupProjects(m.sortProjects(projects))
 		m.expandedWorktrees = make(map[int]bool)
 
 		m.exitInputMode()
 		m.setFeedback("Added "+name, "success")
-		return m, nil
+		return m, m.RefreshGitStatus()
 	}
 
 	// open-once: return result with path
 	m.exitInputMode()
 	m.result = &MainMenuResult{
-		Action:       "open-once",
-		Name:         name,
-		Path:         expanded,
-		AITool:       m.CurrentAITool(),
-		GhostDisplay: m.ghostDisplayForResult(),
-		TabTitle:     m.tabTitleForResult(),
-		SoundName: m.soundNameForResult(),
+		Action:         "open-once",
+		Name:           name,
+		Path:           expanded,
+		AITool:         m.CurrentAITool(),
+		GhostDisplay:   m.ghostDisplayForResult(),
+		TabTitle:       m.tabTitleForResult(),
+		SoundName:      m.soundNameForResult(),
+		PersistSession: m.persistSessionForResult(),
 	}
 	m.quitting = true
 	return m, tea.Quit
@@ -1267,9 +2139,8 @@ func (m *MainMenuModel) confirmDelete() (tea.Model, tea.Cmd) {
 	}
 
 	proj := m.projects[m.deleteSelected]
-	line := proj.Name + ":" + proj.Path
 
-	if err := RemoveProject(line, m.projectsFile); err != nil {
+	if err := RemoveProject(proj, m.projectsFile); err != nil {
 		m.setFeedback("Failed to delete", "error")
 		m.exitDeleteMode()
 		return m, nil
@@ -1277,7 +2148,8 @@ func (m *MainMenuModel) confirmDelete() (tea.Model, tea.Cmd) {
 
 	projects, _ := models.LoadProjects(m.projectsFile)
 	models.PopulateWorktrees(projects)
-	m.projects = projects
+	m.loadedProjects = projects
+	m.projects = groupProjects(m.sortProjects(projects))
 	m.expandedWorktrees = make(map[int]bool)
 
 	if m.selectedItem >= m.TotalItems() {
@@ -1289,9 +2161,492 @@ func (m *MainMenuModel) confirmDelete() (tea.Model, tea.Cmd) {
 
 	m.exitDeleteMode()
 	m.setFeedback("Deleted "+proj.Name, "success")
+	return m, m.RefreshGitStatus()
+}
+
+// enterNewWorktree starts creating a worktree for the selected project (or
+// the project of the selected worktree), asking for the branch first.
+func (m *MainMenuModel) enterNewWorktree() (tea.Model, tea.Cmd) {
+	itemType, projectIdx, _ := m.ResolveItem(m.selectedItem)
+	if itemType != "project" && itemType != "worktree" {
+		m.setFeedback("Select a project to add a worktree", "error")
+		return m, nil
+	}
+	m.worktreeProject = projectIdx
+	m.worktreeBranch = ""
+
+	_, cmd := m.enterInputMode("worktree-branch")
+	m.pathInput.Placeholder = "Branch name (new or existing)"
+	// "  Branch: " is 2 wider than "  Path: "
+	m.pathInput.Width = menuInnerWidth - 13
+	branches := models.ListBranches(m.projects[projectIdx].Path)
+	m.autocomplete = NewAutocomplete(BranchSuggestionProvider(branches, 8), 8)
+	return m, cmd
+}
+
+// submitWorktreeBranch records the branch and asks for the worktree
+// directory, prefilled with a sibling of the project.
+func (m *MainMenuModel) submitWorktreeBranch() (tea.Model, tea.Cmd) {
+	branch := strings.TrimSpace(m.pathInput.Value())
+	if branch == "" {
+		m.exitInputMode()
+		return m, nil
+	}
+	if strings.ContainsAny(branch, " \t") {
+		m.inputErr = fmt.Errorf("Branch names cannot contain spaces")
+		return m, nil
+	}
+	m.worktreeBranch = branch
+
+	proj := m.projects[m.worktreeProject]
+	m.inputMode = "worktree-path"
+	m.inputErr = nil
+	m.pathInput.Placeholder = "Worktree directory"
+	m.pathInput.Width = menuInnerWidth - 11
+	m.pathInput.SetValue(shortenHomePath(models.DefaultWorktreePath(proj.Path, branch)))
+	m.pathInput.CursorEnd()
+	m.autocomplete = NewAutocomplete(PathSuggestionProvider(8), 8)
+	return m, nil
+}
+
+// submitWorktreePath creates the worktree in the background; the result
+// arrives as a worktreeAddedMsg.
+func (m *MainMenuModel) submitWorktreePath() (tea.Model, tea.Cmd) {
+	if m.worktreeAdding {
+		return m, nil
+	}
+	path := strings.TrimSpace(m.pathInput.Value())
+	if path == "" {
+		m.inputErr = fmt.Errorf("Enter a directory for the worktree")
+		return m, nil
+	}
+	expanded := filepath.Clean(util.ExpandPath(path))
+
+	projectPath := m.projects[m.worktreeProject].Path
+	branch := m.worktreeBranch
+	m.worktreeAdding = true
+	m.inputErr = nil
+	return m, func() tea.Msg {
+		err := models.AddWorktree(projectPath, expanded, branch)
+		return worktreeAddedMsg{projectPath: projectPath, path: expanded, branch: branch, err: err}
+	}
+}
+
+// applyWorktreeAdded shows a created worktree under its project, or the
+// git error in the still-open input.
+func (m *MainMenuModel) applyWorktreeAdded(msg worktreeAddedMsg) (tea.Model, tea.Cmd) {
+	m.worktreeAdding = false
+	if msg.err != nil {
+		errMsg := TruncateMiddle(msg.err.Error(), menuInnerWidth-4)
+		if m.inputMode == "worktree-path" {
+			m.inputErr = fmt.Errorf("%s", errMsg)
+		} else {
+			m.setFeedback(errMsg, "error")
+		}
+		return m, nil
+	}
+
+	projectIdx := m.projectIndex(msg.projectPath)
+	if projectIdx < 0 {
+		return m, nil
+	}
+	m.refreshWorktrees(projectIdx)
+	m.expandedWorktrees[projectIdx] = true
+	for i, wt := range m.projects[projectIdx].Worktrees {
+		if filepath.Clean(wt.Path) == msg.path {
+			m.selectRow(menuRow{kind: "worktree", index: projectIdx, worktree: i})
+		}
+	}
+
+	if m.inputMode == "worktree-path" {
+		m.exitInputMode()
+	}
+	m.setFeedback("Created worktree "+msg.branch, "success")
+	return m, m.worktreesGitStatusCmd(projectIdx)
+}
+
+// projectIndex returns the index of the project at path, or -1.
+func (m *MainMenuModel) projectIndex(path string) int {
+	for i, p := range m.projects {
+		if p.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// refreshWorktrees re-reads the worktrees of the project at projectIdx,
+// keeping the file-order copy in sync so re-sorting doesn't lose them.
+func (m *MainMenuModel) refreshWorktrees(projectIdx int) {
+	proj := &m.projects[projectIdx]
+	proj.Worktrees = models.DetectWorktrees(proj.Path)
+	for i := range m.loadedProjects {
+		if m.loadedProjects[i].Path == proj.Path {
+			m.loadedProjects[i].Worktrees = proj.Worktrees
+		}
+	}
+	if len(proj.Worktrees) == 0 {
+		delete(m.expandedWorktrees, projectIdx)
+	}
+}
+
+// InRemoveWorktreeMode returns true when confirming a worktree removal.
+func (m *MainMenuModel) InRemoveWorktreeMode() bool { return m.removeWorktreeMode }
+
+// enterRemoveWorktree asks to confirm removing the selected worktree,
+// counting its uncommitted changes for the warning in the background.
+func (m *MainMenuModel) enterRemoveWorktree() (tea.Model, tea.Cmd) {
+	itemType, projectIdx, worktreeIdx := m.ResolveItem(m.selectedItem)
+	if itemType != "worktree" {
+		m.setFeedback("Select a worktree to remove", "error")
+		return m, nil
+	}
+	path := m.projects[projectIdx].Worktrees[worktreeIdx].Path
+	m.removeWorktreeMode = true
+	m.removeWorktreeProject = projectIdx
+	m.removeWorktreeIdx = worktreeIdx
+	m.removeWorktreeChanges = -1
+	m.removeWorktreeCounting = true
+	return m, func() tea.Msg {
+		changes, err := models.WorktreeChanges(path)
+		if err != nil {
+			changes = -1
+		}
+		return worktreeChangesMsg{path: path, changes: changes}
+	}
+}
+
+// updateRemoveWorktreeMode handles key events while confirming a worktree
+// removal.
+func (m *MainMenuModel) updateRemoveWorktreeMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.removeWorktreeMode = false
+		return m, nil
+	case tea.KeyCtrlC:
+		m.removeWorktreeMode = false
+		m.setActionResult("quit")
+		return m, tea.Quit
+	case tea.KeyRunes:
+		if len(msg.Runes) == 1 {
+			switch TranslateRune(msg.Runes[0]) {
+			case 'y', 'Y':
+				return m.confirmRemoveWorktree()
+			case 'n', 'N', 'q', 'Q':
+				m.removeWorktreeMode = false
+				return m, nil
+			}
+		}
+	}
 	return m, nil
 }
 
+// confirmRemoveWorktree removes the worktree in the background. Only
+// changes the user was warned about are discarded: when they couldn't be
+// counted, git is left to refuse removing a dirty worktree.
+func (m *MainMenuModel) confirmRemoveWorktree() (tea.Model, tea.Cmd) {
+	m.removeWorktreeMode = false
+	proj := m.projects[m.removeWorktreeProject]
+	wt := proj.Worktrees[m.removeWorktreeIdx]
+
+	force := m.removeWorktreeChanges > 0
+	return m, func() tea.Msg {
+		err := models.RemoveWorktree(proj.Path, wt.Path, force)
+		return worktreeRemovedMsg{projectPath: proj.Path, branch: wt.Branch, err: err}
+	}
+}
+
+// applyWorktreeRemoved re-reads the project's worktrees after a removal,
+// or shows why git refused it.
+func (m *MainMenuModel) applyWorktreeRemoved(msg worktreeRemovedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.setFeedback("Not removed: "+TruncateMiddle(msg.err.Error(), menuInnerWidth-17), "error")
+		return m, nil
+	}
+
+	projectIdx := m.projectIndex(msg.projectPath)
+	if projectIdx < 0 {
+		return m, nil
+	}
+	m.refreshWorktrees(projectIdx)
+	m.selectRow(menuRow{kind: "project", index: projectIdx, worktree: -1})
+	m.setFeedback("Removed worktree "+msg.branch, "success")
+	return m, m.worktreesGitStatusCmd(projectIdx)
+}
+
+// exitSessionMode leaves session choice mode without launching.
+func (m *MainMenuModel) exitSessionMode() {
+	m.sessionMode = false
+	m.sessionSelected = 0
+}
+
+// updateSessionMode handles key events while choosing between attaching to a
+// running session and starting a new one.
+func (m *MainMenuModel) updateSessionMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Live sessions plus the trailing "New session" option
+	count := len(m.LiveSessions(m.projects[m.sessionProject].Name)) + 1
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.exitSessionMode()
+		return m, nil
+	case tea.KeyCtrlC:
+		m.exitSessionMode()
+		m.setActionResult("quit")
+		return m, tea.Quit
+	case tea.KeyUp:
+		m.sessionSelected = (m.sessionSelected - 1 + count) % count
+		return m, nil
+	case tea.KeyDown:
+		m.sessionSelected = (m.sessionSelected + 1) % count
+		return m, nil
+	case tea.KeyEnter:
+		return m.confirmSession()
+	case tea.KeyRunes:
+		if len(msg.Runes) == 1 {
+			r := TranslateRune(msg.Runes[0])
+			switch r {
+			case 'q', 'Q':
+				m.exitSessionMode()
+				return m, nil
+			case 'j':
+				m.sessionSelected = (m.sessionSelected + 1) % count
+				return m, nil
+			case 'k':
+				m.sessionSelected = (m.sessionSelected - 1 + count) % count
+				return m, nil
+			case 'a', 'A':
+				m.sessionSelected = 0
+				return m.confirmSession()
+			case 'n', 'N':
+				m.sessionSelected = count - 1
+				return m.confirmSession()
+			}
+		}
+	}
+	return m, nil
+}
+
+// confirmSession produces a select-project result for the session choice,
+// carrying the session name when attaching.
+func (m *MainMenuModel) confirmSession() (tea.Model, tea.Cmd) {
+	sessions := m.LiveSessions(m.projects[m.sessionProject].Name)
+	m.selectCurrent()
+	if m.result != nil && m.sessionSelected < len(sessions) {
+		m.result.Session = sessions[m.sessionSelected]
+	}
+	m.sessionMode = false
+	return m, tea.Quit
+}
+
+// renderSessionBox builds the attach-or-new box string.
+func (m *MainMenuModel) renderSessionBox() string {
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	primaryBoldStyle := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(m.theme.Text)
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("247"))
+
+	hLine := strings.Repeat("\u2500", menuInnerWidth)
+	topBorder := dimStyle.Render("\u250c" + hLine + "\u2510")
+	separator := dimStyle.Render("\u251c" + hLine + "\u2524")
+	bottomBorder := dimStyle.Render("\u2514" + hLine + "\u2518")
+	leftBorder := dimStyle.Render("\u2502")
+	rightBorder := dimStyle.Render("\u2502")
+	emptyRow := leftBorder + strings.Repeat(" ", menuInnerWidth) + rightBorder
+
+	var lines []string
+
+	lines = append(lines, topBorder)
+
+	proj := m.projects[m.sessionProject]
+	title := primaryBoldStyle.Render("\u2b21  Ghost Tab")
+	titleContent := title + " " + dimStyle.Render("\u00b7 "+TruncateMiddle(proj.Name, menuInnerWidth-16))
+	titlePadding := menuInnerWidth - lipgloss.Width(titleContent) - 1
+	if titlePadding < 0 {
+		titlePadding = 0
+	}
+	lines = append(lines, leftBorder+" "+titleContent+strings.Repeat(" ", titlePadding)+rightBorder)
+	lines = append(lines, separator)
+	lines = append(lines, emptyRow)
+
+	options := make([]string, 0)
+	for _, name := range m.LiveSessions(proj.Name) {
+		options = append(options, "Attach  "+name)
+	}
+	options = append(options, "New session")
+
+	for i, label := range options {
+		label = TruncateMiddle(label, menuInnerWidth-6)
+		var content string
+		if i == m.sessionSelected {
+			marker := primaryBoldStyle.Render("\u258e")
+			content = "  " + marker + " " + primaryBoldStyle.Render(label)
+		} else {
+			content = "    " + textStyle.Render(label)
+		}
+		padding := menuInnerWidth - lipgloss.Width(content)
+		if padding < 0 {
+			padding = 0
+		}
+		lines = append(lines, leftBorder+content+strings.Repeat(" ", padding)+rightBorder)
+	}
+
+	lines = append(lines, emptyRow)
+	lines = append(lines, separator)
+
+	helpText := "\u2191\u2193 navigate  A attach  N new  Esc back"
+	helpContent := helpStyle.Render(helpText)
+	helpPadding := menuInnerWidth - lipgloss.Width(helpContent) - 1
+	if helpPadding < 0 {
+		helpPadding = 0
+	}
+	lines = append(lines, leftBorder+" "+helpContent+strings.Repeat(" ", helpPadding)+rightBorder)
+	lines = append(lines, bottomBorder)
+
+	return strings.Join(lines, "\n")
+}
+
+// InAgentSelect reports whether the AI tools to run side by side are being
+// picked.
+func (m *MainMenuModel) InAgentSelect() bool { return m.agentSelect != nil }
+
+// enterAgentSelect opens the selection of AI tools to run side by side on
+// the selected project or worktree. Each tool can run in the project or one
+// of its worktrees; the current tool starts checked.
+func (m *MainMenuModel) enterAgentSelect() {
+	itemType, projectIdx, worktreeIdx := m.ResolveItem(m.selectedItem)
+	if (itemType != "project" && itemType != "worktree") || len(m.aiTools) <= 1 {
+		return
+	}
+	proj := m.projects[projectIdx]
+	dirs := []string{proj.Path}
+	for _, wt := range proj.Worktrees {
+		dirs = append(dirs, wt.Path)
+	}
+	dir := 0
+	if itemType == "worktree" {
+		dir = worktreeIdx + 1
+	}
+	tools := make([]models.AITool, len(m.aiTools))
+	for i, name := range m.aiTools {
+		tools[i] = models.AITool{Name: name, Installed: true}
+	}
+	sel := NewAgentSelect(tools, m.CurrentAITool(), dirs, dir)
+	m.agentSelect = &sel
+	m.agentProject = projectIdx
+}
+
+// updateAgentSelect passes keys to the agent selection. Confirming it
+// produces a select-project result carrying the agents; cancelling returns
+// to the menu.
+func (m *MainMenuModel) updateAgentSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		m.agentSelect = nil
+		m.setActionResult("quit")
+		return m, tea.Quit
+	}
+	if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
+		if r := TranslateRune(msg.Runes[0]); r == 'q' || r == 'Q' {
+			m.agentSelect = nil
+			return m, nil
+		}
+	}
+
+	updated, _ := m.agentSelect.Update(msg)
+	sel := updated.(MultiSelectModel)
+	m.agentSelect = &sel
+	result := sel.Result()
+	if result == nil {
+		return m, nil
+	}
+	m.agentSelect = nil
+	if !result.Confirmed {
+		return m, nil
+	}
+
+	m.selectCurrent()
+	agents := make([]Agent, len(result.Tools))
+	for i, tool := range result.Tools {
+		agents[i] = Agent{Tool: tool, Path: result.Dirs[i]}
+	}
+	m.result.Agents = agents
+	m.result.ProjectAITool = ""
+	m.result.Profile = ""
+	if agents[0].Tool == m.CurrentAITool() {
+		m.result.Profile = m.aiProfile
+	}
+	return m, tea.Quit
+}
+
+// renderAgentBox builds the box for picking AI tools to run side by side.
+func (m *MainMenuModel) renderAgentBox() string {
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	primaryBoldStyle := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(m.theme.Text)
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("247"))
+
+	hLine := strings.Repeat("\u2500", menuInnerWidth)
+	topBorder := dimStyle.Render("\u250c" + hLine + "\u2510")
+	separator := dimStyle.Render("\u251c" + hLine + "\u2524")
+	bottomBorder := dimStyle.Render("\u2514" + hLine + "\u2518")
+	leftBorder := dimStyle.Render("\u2502")
+	rightBorder := dimStyle.Render("\u2502")
+	emptyRow := leftBorder + strings.Repeat(" ", menuInnerWidth) + rightBorder
+	row := func(content string) string {
+		padding := menuInnerWidth - lipgloss.Width(content)
+		if padding < 0 {
+			padding = 0
+		}
+		return leftBorder + content + strings.Repeat(" ", padding) + rightBorder
+	}
+
+	var lines []string
+	lines = append(lines, topBorder)
+
+	proj := m.projects[m.agentProject]
+	title := primaryBoldStyle.Render("\u2b21  Ghost Tab")
+	lines = append(lines, row(" "+title+" "+dimStyle.Render("\u00b7 "+TruncateMiddle(proj.Name, menuInnerWidth-16))))
+	lines = append(lines, separator)
+	lines = append(lines, emptyRow)
+
+	sel := m.agentSelect
+	checked := sel.Checked()
+	dirs := sel.Dirs()
+	for i, tool := range sel.Tools() {
+		box := "[ ]"
+		if checked[i] {
+			box = "[x]"
+		}
+		name := AIToolDisplayName(tool.Name)
+		var content string
+		if i == sel.Cursor() {
+			marker := primaryBoldStyle.Render("\u258e")
+			content = "  " + marker + " " + primaryBoldStyle.Render(box+" "+name)
+		} else {
+			content = "    " + textStyle.Render(box+" "+name)
+		}
+		dir := TruncateMiddle(filepath.Base(dirs[i]), menuInnerWidth-lipgloss.Width(content)-3)
+		gap := menuInnerWidth - lipgloss.Width(content) - lipgloss.Width(dir) - 2
+		if gap < 1 {
+			gap = 1
+		}
+		lines = append(lines, row(content+strings.Repeat(" ", gap)+dimStyle.Render(dir)))
+	}
+
+	if msg := sel.ErrorMsg(); msg != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		lines = append(lines, emptyRow)
+		lines = append(lines, row("  "+errStyle.Render(msg)))
+	}
+
+	lines = append(lines, emptyRow)
+	lines = append(lines, separator)
+	lines = append(lines, row(" "+helpStyle.Render("\u2423 toggle  \u2190\u2192 folder  \u23ce launch  Esc back")))
+	lines = append(lines, bottomBorder)
+
+	return strings.Join(lines, "\n")
+}
+
 // ghostDisplayLabel returns a capitalized display label for the ghost display mode.
 func ghostDisplayLabel(mode string) string {
 	switch mode {
@@ -1339,6 +2694,18 @@ func tabTitleLabel(mode string) string {
 	}
 }
 
+// projectSortLabel returns a display label for the project sort order.
+func projectSortLabel(mode string) string {
+	switch mode {
+	case "recent":
+		return "Recent"
+	case "frequent":
+		return "Frequent"
+	default:
+		return "Manual"
+	}
+}
+
 // renderSettingsBox builds the settings panel box string.
 func (m *MainMenuModel) renderSettingsBox() string {
 	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
@@ -1417,6 +2784,25 @@ func (m *MainMenuModel) renderSettingsBox() string {
 	}
 	lines = append(lines, m.renderSettingsItem(2, soundLabel, soundState, soundStyle, primaryBoldStyle, leftBorder, rightBorder))
 
+	// Persist Sessions item
+	persistColor := lipgloss.Color("241") // gray
+	persistState := "[Off]"
+	if m.persistSession == "on" {
+		persistColor = lipgloss.Color("114") // green
+		persistState = "[On]"
+	}
+	persistStyle := lipgloss.NewStyle().Foreground(persistColor)
+	lines = append(lines, m.renderSettingsItem(3, "Persist Sessions", persistState, persistStyle, primaryBoldStyle, leftBorder, rightBorder))
+
+	// Sort Projects item
+	sortColor := lipgloss.Color("241") // gray
+	if m.projectSort != "manual" {
+		sortColor = lipgloss.Color("114") // green
+	}
+	sortStyle := lipgloss.NewStyle().Foreground(sortColor)
+	sortState := "[" + projectSortLabel(m.projectSort) + "]"
+	lines = append(lines, m.renderSettingsItem(4, "Sort Projects", sortState, sortStyle, primaryBoldStyle, leftBorder, rightBorder))
+
 	// Empty row
 	lines = append(lines, emptyRow)
 
@@ -1471,6 +2857,7 @@ func (m *MainMenuModel) renderMenuBox() string {
 	textStyle := lipgloss.NewStyle().Foreground(m.theme.Text)
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("247"))
 	updateStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	liveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
 
 	hLine := strings.Repeat("\u2500", menuInnerWidth)
 	topBorder := dimStyle.Render("\u250c" + hLine + "\u2510")
@@ -1487,11 +2874,22 @@ func (m *MainMenuModel) renderMenuBox() string {
 	// Title row
 	title := primaryBoldStyle.Render("\u2b21  Ghost Tab")
 	aiDisplay := AIToolDisplayName(m.CurrentAITool())
-	var aiPart string
-	if len(m.aiTools) > 1 {
-		aiPart = dimStyle.Render(" \u25c2 ") + primaryStyle.Render(aiDisplay) + dimStyle.Render(" \u25b8")
-	} else {
-		aiPart = " " + primaryStyle.Render(aiDisplay)
+	if profile := m.CurrentProfile(); profile != aitools.NoProfile {
+		aiDisplay += " \u00b7 " + profile
+	}
+	aiChooser := func(display string) string {
+		if len(m.aiTools) > 1 {
+			return dimStyle.Render(" \u25c2 ") + primaryStyle.Render(display) + dimStyle.Render(" \u25b8")
+		}
+		return " " + primaryStyle.Render(display)
+	}
+	aiPart := aiChooser(aiDisplay)
+	if version := m.aiToolVersion(m.CurrentAITool()); version != "" {
+		// Only show the version when it fits next to the title
+		withVersion := aiChooser(aiDisplay + " " + version)
+		if lipgloss.Width(title)+lipgloss.Width(withVersion)+2 <= menuInnerWidth {
+			aiPart = withVersion
+		}
 	}
 	// Right-align AI tool chooser: "⬡ Ghost Tab" left, "◂ Claude Code ▸" right
 	aiPadding := menuInnerWidth - lipgloss.Width(title) - lipgloss.Width(aiPart) - 1 // -1 for leading space
@@ -1516,155 +2914,234 @@ func (m *MainMenuModel) renderMenuBox() string {
 		lines = append(lines, updateRow)
 	}
 
+	// Filter bar (if open): "/ query▏" left, match count right
+	if m.filterMode {
+		var countText string
+		switch n := m.FilterMatchCount(); {
+		case n == 0:
+			countText = "no matches"
+		case n == 1:
+			countText = "1 match"
+		default:
+			countText = fmt.Sprintf("%d matches", n)
+		}
+		countStyled := dimStyle.Render(countText)
+		query := []rune(m.filterQuery)
+		if maxQuery := menuInnerWidth - 8 - len(countText); len(query) > maxQuery {
+			// Keep the end of a long query visible, where the cursor is
+			query = append([]rune("\u2026"), query[len(query)-maxQuery+1:]...)
+		}
+		filterContent := "  " + primaryBoldStyle.Render("/") + " " + textStyle.Render(string(query)) + primaryStyle.Render("\u258f")
+		gap := menuInnerWidth - lipgloss.Width(filterContent) - lipgloss.Width(countStyled) - 1
+		if gap < 1 {
+			gap = 1
+		}
+		lines = append(lines, leftBorder+filterContent+strings.Repeat(" ", gap)+countStyled+" "+rightBorder)
+	}
+
 	// Empty line before items
 	emptyRow := leftBorder + strings.Repeat(" ", menuInnerWidth) + rightBorder
 	lines = append(lines, emptyRow)
 
-	// Project items
+	// Project items: group headers, projects and their expanded worktrees
 	numProjects := len(m.projects)
-	for i, proj := range m.projects {
-		selected := m.selectedItem == m.projectToFlatIndex(i)
-		num := fmt.Sprintf("%d", i+1)
-
-		var nameLine string
-		var pathLine string
-
-		shortPath := TruncateMiddle(shortenHomePath(proj.Path), menuInnerWidth-7)
+	groups := m.groups()
+	for flatIdx, row := range m.rows() {
+		selected := m.selectedItem == flatIdx
+
+		switch row.kind {
+		case "group":
+			group := groups[row.index]
+			arrow := "\u25be"
+			if m.collapsedGroups[group.name] {
+				arrow = "\u25b8"
+			}
+			countText := fmt.Sprintf("%d projects", len(group.projects))
+			if len(group.projects) == 1 {
+				countText = "1 project"
+			}
+			countStyled := dimStyle.Render(countText)
 
-		// Worktree count indicator
-		var wtIndicator string
-		if len(proj.Worktrees) > 0 {
-			wtCount := len(proj.Worktrees)
-			wtWord := "worktrees"
-			if wtCount == 1 {
-				wtWord = "worktree"
+			var content string
+			if selected {
+				marker := primaryBoldStyle.Render("\u258e")
+				truncName := TruncateMiddle(group.name, menuInnerWidth-8-len(countText))
+				content = "  " + marker + " " + primaryBoldStyle.Render(arrow+" "+truncName)
+			} else {
+				truncName := TruncateMiddle(group.name, menuInnerWidth-8-len(countText))
+				content = "    " + dimStyle.Render(arrow) + " " + textStyle.Bold(true).Render(truncName)
 			}
-			wtIndicator = fmt.Sprintf("%d %s", wtCount, wtWord)
-		}
+			gap := menuInnerWidth - lipgloss.Width(content) - lipgloss.Width(countStyled) - 1
+			if gap < 1 {
+				gap = 1
+			}
+			lines = append(lines, leftBorder+content+strings.Repeat(" ", gap)+countStyled+" "+rightBorder)
 
-		if selected {
-			marker := primaryBoldStyle.Render("\u258e")
-			truncName := TruncateMiddle(proj.Name, menuInnerWidth-7-len(num))
-			nameText := primaryBoldStyle.Render(num + "  " + truncName)
-			// "  ▎ 1  name" -> 2 spaces + marker + space + num + 2 spaces + name
-			nameContent := "  " + marker + " " + nameText
+		case "project":
+			i := row.index
+			proj := m.projects[i]
+			num := fmt.Sprintf("%d", i+1)
 
-			if wtIndicator != "" {
-				wtStyled := dimStyle.Render(wtIndicator)
-				gap := menuInnerWidth - lipgloss.Width(nameContent) - lipgloss.Width(wtStyled)
-				if gap < 1 {
-					gap = 1
-				}
-				nameLine = leftBorder + nameContent + strings.Repeat(" ", gap) + wtStyled + rightBorder
-			} else {
-				namePadding := menuInnerWidth - lipgloss.Width(nameContent)
-				if namePadding < 0 {
-					namePadding = 0
+			var nameLine string
+			var pathLine string
+
+			// Git status badge, right-aligned on the path line
+			badge := gitStatusBadge(proj.Status, true, dimStyle)
+			pathWidth := menuInnerWidth - 7
+			if badge != "" {
+				pathWidth -= lipgloss.Width(badge) + 1
+			}
+			shortPath := TruncateMiddle(shortenHomePath(proj.Path), pathWidth)
+
+			// Filter matches; positions only apply to text that was not truncated
+			var match projectMatch
+			if m.filterQuery != "" {
+				match = m.filterMatches[i]
+				if shortPath != shortenHomePath(proj.Path) {
+					match.path = nil
 				}
-				nameLine = leftBorder + nameContent + strings.Repeat(" ", namePadding) + rightBorder
 			}
 
-			pathContent := "       " + primaryStyle.Render(shortPath)
-			pathPadding := menuInnerWidth - lipgloss.Width(pathContent)
-			if pathPadding < 0 {
-				pathPadding = 0
+			// Worktree count indicator
+			var wtIndicator string
+			if len(proj.Worktrees) > 0 {
+				wtCount := len(proj.Worktrees)
+				wtWord := "worktrees"
+				if wtCount == 1 {
+					wtWord = "worktree"
+				}
+				wtIndicator = fmt.Sprintf("%d %s", wtCount, wtWord)
 			}
-			pathLine = leftBorder + pathContent + strings.Repeat(" ", pathPadding) + rightBorder
-		} else {
-			numText := dimStyle.Render(num)
-			truncName := TruncateMiddle(proj.Name, menuInnerWidth-6-len(num))
-			nameText := textStyle.Render(truncName)
-			nameContent := "    " + numText + "  " + nameText
 
-			if wtIndicator != "" {
-				wtStyled := dimStyle.Render(wtIndicator)
-				gap := menuInnerWidth - lipgloss.Width(nameContent) - lipgloss.Width(wtStyled)
-				if gap < 1 {
-					gap = 1
+			// Live session indicator, shown before the worktree count
+			liveIndicator := ""
+			if n := len(m.LiveSessions(proj.Name)); n > 0 {
+				liveIndicator = liveStyle.Render("\u25cf live")
+				if n > 1 {
+					liveIndicator = liveStyle.Render(fmt.Sprintf("\u25cf %d live", n))
 				}
-				nameLine = leftBorder + nameContent + strings.Repeat(" ", gap) + wtStyled + rightBorder
-			} else {
-				namePadding := menuInnerWidth - lipgloss.Width(nameContent)
-				if namePadding < 0 {
-					namePadding = 0
+			}
+			indicator := liveIndicator
+			if match.tag != "" {
+				if indicator != "" {
+					indicator += "  "
 				}
-				nameLine = leftBorder + nameContent + strings.Repeat(" ", namePadding) + rightBorder
+				indicator += primaryStyle.Render("#" + match.tag)
 			}
-
-			pathContent := "       " + dimStyle.Render(shortPath)
-			pathPadding := menuInnerWidth - lipgloss.Width(pathContent)
-			if pathPadding < 0 {
-				pathPadding = 0
+			if wtIndicator != "" {
+				if indicator != "" {
+					indicator += "  "
+				}
+				indicator += dimStyle.Render(wtIndicator)
 			}
-			pathLine = leftBorder + pathContent + strings.Repeat(" ", pathPadding) + rightBorder
-		}
-
-		lines = append(lines, nameLine)
-		lines = append(lines, pathLine)
-
-		// Expanded worktree entries
-		if m.expandedWorktrees[i] {
-			for j, wt := range proj.Worktrees {
-				wtFlatIdx := m.projectToFlatIndex(i) + 1 + j
-				wtSelected := m.selectedItem == wtFlatIdx
-				var wtLine string
-				branchDisplay := TruncateMiddle(wt.Branch, menuInnerWidth-10)
-
-				if wtSelected {
-					marker := primaryBoldStyle.Render("\u258e")
-					branchText := primaryBoldStyle.Render(branchDisplay)
-					content := "    " + marker + "   " + branchText
-					padding := menuInnerWidth - lipgloss.Width(content)
-					if padding < 0 {
-						padding = 0
+
+			if selected {
+				marker := primaryBoldStyle.Render("\u258e")
+				truncName := TruncateMiddle(proj.Name, menuInnerWidth-7-len(num))
+				nameText := primaryBoldStyle.Render(num+"  ") + m.highlight(truncName, proj.Name, match.name, primaryBoldStyle)
+				// "  ▎ 1  name" -> 2 spaces + marker + space + num + 2 spaces + name
+				nameContent := "  " + marker + " " + nameText
+
+				if indicator != "" {
+					wtStyled := indicator
+					gap := menuInnerWidth - lipgloss.Width(nameContent) - lipgloss.Width(wtStyled)
+					if gap < 1 {
+						gap = 1
 					}
-					wtLine = leftBorder + content + strings.Repeat(" ", padding) + rightBorder
+					nameLine = leftBorder + nameContent + strings.Repeat(" ", gap) + wtStyled + rightBorder
 				} else {
-					branchText := dimStyle.Render(branchDisplay)
-					content := "         " + branchText
-					padding := menuInnerWidth - lipgloss.Width(content)
-					if padding < 0 {
-						padding = 0
+					namePadding := menuInnerWidth - lipgloss.Width(nameContent)
+					if namePadding < 0 {
+						namePadding = 0
 					}
-					wtLine = leftBorder + content + strings.Repeat(" ", padding) + rightBorder
+					nameLine = leftBorder + nameContent + strings.Repeat(" ", namePadding) + rightBorder
 				}
-				lines = append(lines, wtLine)
+
+				pathContent := "       " + highlightMatches(shortPath, match.path, primaryStyle, primaryBoldStyle.Underline(true))
+				pathLine = leftBorder + padBetween(pathContent, badge, menuInnerWidth) + rightBorder
+			} else {
+				numText := dimStyle.Render(num)
+				truncName := TruncateMiddle(proj.Name, menuInnerWidth-6-len(num))
+				nameText := m.highlight(truncName, proj.Name, match.name, textStyle)
+				nameContent := "    " + numText + "  " + nameText
+
+				if indicator != "" {
+					wtStyled := indicator
+					gap := menuInnerWidth - lipgloss.Width(nameContent) - lipgloss.Width(wtStyled)
+					if gap < 1 {
+						gap = 1
+					}
+					nameLine = leftBorder + nameContent + strings.Repeat(" ", gap) + wtStyled + rightBorder
+				} else {
+					namePadding := menuInnerWidth - lipgloss.Width(nameContent)
+					if namePadding < 0 {
+						namePadding = 0
+					}
+					nameLine = leftBorder + nameContent + strings.Repeat(" ", namePadding) + rightBorder
+				}
+
+				pathContent := "       " + highlightMatches(shortPath, match.path, dimStyle, primaryStyle)
+				pathLine = leftBorder + padBetween(pathContent, badge, menuInnerWidth) + rightBorder
 			}
-		}
-	}
 
-	// Separator between projects and actions (only if there are projects)
-	if numProjects > 0 {
-		lines = append(lines, separator)
-	}
+			lines = append(lines, nameLine)
+			lines = append(lines, pathLine)
+
+		case "worktree":
+			wt := m.projects[row.index].Worktrees[row.worktree]
+			var wtLine string
+			// The branch is already shown, so the badge only has the state
+			badge := gitStatusBadge(wt.Status, false, dimStyle)
+			branchWidth := menuInnerWidth - 10
+			if badge != "" {
+				branchWidth -= lipgloss.Width(badge) + 1
+			}
+			branchDisplay := TruncateMiddle(wt.Branch, branchWidth)
+			var branchMatch []int
+			if m.filterQuery != "" {
+				branchMatch = m.filterMatches[row.index].worktrees[row.worktree]
+			}
 
-	// Action items
-	for i, action := range actionLabels {
-		actionIdx := numProjects + m.expandedWorktreeCount() + i
-		selected := m.selectedItem == actionIdx
+			if selected {
+				marker := primaryBoldStyle.Render("\u258e")
+				branchText := m.highlight(branchDisplay, wt.Branch, branchMatch, primaryBoldStyle)
+				content := "    " + marker + "   " + branchText
+				wtLine = leftBorder + padBetween(content, badge, menuInnerWidth) + rightBorder
+			} else {
+				branchText := m.highlight(branchDisplay, wt.Branch, branchMatch, dimStyle)
+				content := "         " + branchText
+				wtLine = leftBorder + padBetween(content, badge, menuInnerWidth) + rightBorder
+			}
+			lines = append(lines, wtLine)
 
-		var actionLine string
-		if selected {
-			marker := primaryBoldStyle.Render("\u258e")
-			shortcutText := primaryBoldStyle.Render(action.shortcut + "  " + action.label)
-			content := "  " + marker + " " + shortcutText
-			padding := menuInnerWidth - lipgloss.Width(content)
-			if padding < 0 {
-				padding = 0
+		case "action":
+			// Separator between projects and actions (only if there are projects)
+			if row.index == 0 && numProjects > 0 {
+				lines = append(lines, separator)
 			}
-			actionLine = leftBorder + content + strings.Repeat(" ", padding) + rightBorder
-		} else {
-			shortcutText := dimStyle.Render(action.shortcut)
-			labelText := textStyle.Render(action.label)
-			content := "    " + shortcutText + "  " + labelText
-			padding := menuInnerWidth - lipgloss.Width(content)
-			if padding < 0 {
-				padding = 0
+			action := actionLabels[row.index]
+			var actionLine string
+			if selected {
+				marker := primaryBoldStyle.Render("\u258e")
+				shortcutText := primaryBoldStyle.Render(action.shortcut + "  " + action.label)
+				content := "  " + marker + " " + shortcutText
+				padding := menuInnerWidth - lipgloss.Width(content)
+				if padding < 0 {
+					padding = 0
+				}
+				actionLine = leftBorder + content + strings.Repeat(" ", padding) + rightBorder
+			} else {
+				shortcutText := dimStyle.Render(action.shortcut)
+				labelText := textStyle.Render(action.label)
+				content := "    " + shortcutText + "  " + labelText
+				padding := menuInnerWidth - lipgloss.Width(content)
+				if padding < 0 {
+					padding = 0
+				}
+				actionLine = leftBorder + content + strings.Repeat(" ", padding) + rightBorder
 			}
-			actionLine = leftBorder + content + strings.Repeat(" ", padding) + rightBorder
+			lines = append(lines, actionLine)
 		}
-
-		lines = append(lines, actionLine)
 	}
 
 	// Feedback message (if any)
@@ -1697,15 +3174,27 @@ func (m *MainMenuModel) renderMenuBox() string {
 	}
 
 	var helpText string
-	if len(m.aiTools) > 1 {
-		helpText = "\u2191\u2193 navigate \u2190\u2192 AI tool S settings"
+	if m.filterMode {
+		helpText = "\u2191\u2193 navigate \u23ce launch Esc clear filter"
 	} else {
-		helpText = "\u2191\u2193 navigate S settings"
-	}
-	if hasWorktrees {
-		helpText += " w worktrees"
+		if len(m.aiTools) > 1 {
+			helpText = "\u2191\u2193 navigate \u2190\u2192 AI tool S settings"
+		} else {
+			helpText = "\u2191\u2193 navigate S settings"
+		}
+		if hasWorktrees {
+			helpText += " w worktrees"
+		}
+		if len(aitools.Current().Get(m.CurrentAITool()).Profiles) > 0 &&
+			lipgloss.Width(helpText+" \u21e5 profile \u23ce select") < menuInnerWidth {
+			helpText += " \u21e5 profile"
+		}
+		// Only advertise the filter when the hint still fits on the row
+		if len(m.projects) > 0 && lipgloss.Width(helpText+" / filter \u23ce select") < menuInnerWidth {
+			helpText += " / filter"
+		}
+		helpText += " \u23ce select"
 	}
-	helpText += " \u23ce select"
 	helpContent := helpStyle.Render(helpText)
 	helpPadding := menuInnerWidth - lipgloss.Width(helpContent) - 1 // -1 for leading space
 	if helpPadding < 0 {
@@ -1720,6 +3209,82 @@ func (m *MainMenuModel) renderMenuBox() string {
 	return strings.Join(lines, "\n")
 }
 
+// highlight renders the display form of text with the filter matches in
+// positions emphasised. Matches are dropped when display was truncated,
+// since the positions no longer line up.
+func (m *MainMenuModel) highlight(display, text string, positions []int, base lipgloss.Style) string {
+	if display != text {
+		positions = nil
+	}
+	hl := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true).Underline(true)
+	return highlightMatches(display, positions, base, hl)
+}
+
+// Longest branch name shown in a git status badge.
+const badgeBranchWidth = 14
+
+// aiToolVersion returns the version shown next to tool in the header:
+// the version with a warning sign when the tool is outdated or broken,
+// or "" before its version command has finished.
+func (m *MainMenuModel) aiToolVersion(tool string) string {
+	health, ok := m.aiHealth[tool]
+	if !ok {
+		return ""
+	}
+	switch health.Status {
+	case models.ToolOutdated:
+		return health.Version + " \u26a0"
+	case models.ToolBroken:
+		return "\u26a0"
+	}
+	return health.Version
+}
+
+// gitStatusBadge renders a git status as "main ✓" or "main ● ↑2 ↓1 ≡1":
+// the branch (if withBranch), a clean or dirty mark, commits ahead of and
+// behind the upstream, and stash entries. Stashes are shared by all
+// worktrees of a repository, so they are only shown with the branch.
+// Returns "" while the status is unknown.
+func gitStatusBadge(s *models.GitStatus, withBranch bool, dim lipgloss.Style) string {
+	if s == nil {
+		return ""
+	}
+	cleanStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+	dirtyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	var parts []string
+	if withBranch && s.Branch != "" {
+		parts = append(parts, dim.Render(TruncateMiddle(s.Branch, badgeBranchWidth)))
+	}
+	if s.Dirty {
+		parts = append(parts, dirtyStyle.Render("\u25cf"))
+	} else {
+		parts = append(parts, cleanStyle.Render("\u2713"))
+	}
+	if s.Ahead > 0 {
+		parts = append(parts, dim.Render(fmt.Sprintf("\u2191%d", s.Ahead)))
+	}
+	if s.Behind > 0 {
+		parts = append(parts, dim.Render(fmt.Sprintf("\u2193%d", s.Behind)))
+	}
+	if withBranch && s.Stashes > 0 {
+		parts = append(parts, dim.Render(fmt.Sprintf("\u2261%d", s.Stashes)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// padBetween fills width with left, then right flush against the end.
+func padBetween(left, right string, width int) string {
+	gap := width - lipgloss.Width(left) - lipgloss.Width(right)
+	if gap < 0 {
+		gap = 0
+	}
+	if right != "" && gap < 1 {
+		gap = 1
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
 // renderInputBox builds the input mode box string (add-project or open-once).
 func (m *MainMenuModel) renderInputBox() string {
 	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
@@ -1741,9 +3306,12 @@ func (m *MainMenuModel) renderInputBox() string {
 
 	title := primaryBoldStyle.Render("\u2b21  Ghost Tab")
 	var label string
-	if m.inputMode == "add-project" {
+	switch m.inputMode {
+	case "add-project":
 		label = "Add Project"
-	} else {
+	case "worktree-branch", "worktree-path":
+		label = "New Worktree"
+	default:
 		label = "Open Once"
 	}
 	titleContent := title + " " + dimStyle.Render("\u00b7 "+label)
@@ -1756,6 +3324,22 @@ func (m *MainMenuModel) renderInputBox() string {
 	lines = append(lines, emptyRow)
 
 	pathLabel := "  Path: "
+	if m.inputMode == "worktree-branch" || m.inputMode == "worktree-path" {
+		context := m.projects[m.worktreeProject].Name
+		if m.inputMode == "worktree-path" {
+			context += " \u00b7 " + m.worktreeBranch
+		}
+		contextContent := "  " + dimStyle.Render(TruncateMiddle(context, menuInnerWidth-4))
+		contextPadding := menuInnerWidth - lipgloss.Width(contextContent)
+		if contextPadding < 0 {
+			contextPadding = 0
+		}
+		lines = append(lines, leftBorder+contextContent+strings.Repeat(" ", contextPadding)+rightBorder)
+		lines = append(lines, emptyRow)
+	}
+	if m.inputMode == "worktree-branch" {
+		pathLabel = "  Branch: "
+	}
 	inputView := m.pathInput.View()
 	inputContent := pathLabel + inputView
 	inputPadding := menuInnerWidth - lipgloss.Width(inputContent)
@@ -1902,6 +3486,66 @@ func (m *MainMenuModel) renderDeleteBox() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderRemoveWorktreeBox builds the worktree removal confirmation box,
+// warning when the worktree has uncommitted changes.
+func (m *MainMenuModel) renderRemoveWorktreeBox() string {
+	dimStyle := lipgloss.NewStyle().Foreground(m.theme.Dim)
+	primaryBoldStyle := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true)
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("247"))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+
+	hLine := strings.Repeat("\u2500", menuInnerWidth)
+	topBorder := dimStyle.Render("\u250c" + hLine + "\u2510")
+	separator := dimStyle.Render("\u251c" + hLine + "\u2524")
+	bottomBorder := dimStyle.Render("\u2514" + hLine + "\u2518")
+	leftBorder := dimStyle.Render("\u2502")
+	rightBorder := dimStyle.Render("\u2502")
+	emptyRow := leftBorder + strings.Repeat(" ", menuInnerWidth) + rightBorder
+	row := func(content string) string {
+		padding := menuInnerWidth - lipgloss.Width(content)
+		if padding < 0 {
+			padding = 0
+		}
+		return leftBorder + content + strings.Repeat(" ", padding) + rightBorder
+	}
+
+	proj := m.projects[m.removeWorktreeProject]
+	wt := proj.Worktrees[m.removeWorktreeIdx]
+
+	var lines []string
+	lines = append(lines, topBorder)
+	title := primaryBoldStyle.Render("\u2b21  Ghost Tab")
+	lines = append(lines, row(" "+title+" "+dimStyle.Render("\u00b7 Remove Worktree")))
+	lines = append(lines, separator)
+	lines = append(lines, emptyRow)
+
+	lines = append(lines, row("  Remove "+TruncateMiddle(wt.Branch, menuInnerWidth-12)+"?"))
+	lines = append(lines, row("  "+dimStyle.Render(TruncateMiddle(shortenHomePath(wt.Path), menuInnerWidth-4))))
+	lines = append(lines, emptyRow)
+
+	switch {
+	case m.removeWorktreeCounting:
+		lines = append(lines, row("  "+dimStyle.Render("Checking for uncommitted changes\u2026")))
+	case m.removeWorktreeChanges > 0:
+		noun := "changes"
+		if m.removeWorktreeChanges == 1 {
+			noun = "change"
+		}
+		lines = append(lines, row("  "+warnStyle.Render(fmt.Sprintf("\u26a0 %d uncommitted %s will be lost", m.removeWorktreeChanges, noun))))
+	case m.removeWorktreeChanges < 0:
+		lines = append(lines, row("  "+warnStyle.Render("\u26a0 Could not check for uncommitted changes")))
+	default:
+		lines = append(lines, row("  "+dimStyle.Render("No uncommitted changes")))
+	}
+
+	lines = append(lines, emptyRow)
+	lines = append(lines, separator)
+	lines = append(lines, row(" "+helpStyle.Render("Y remove  N cancel")))
+	lines = append(lines, bottomBorder)
+
+	return strings.Join(lines, "\n")
+}
+
 // View implements tea.Model. Renders the full box-drawing menu with optional ghost.
 func (m *MainMenuModel) View() string {
 	if m.quitting {
@@ -1913,6 +3557,12 @@ func (m *MainMenuModel) View() string {
 		menuBox = m.renderSettingsBox()
 	} else if m.deleteMode {
 		menuBox = m.renderDeleteBox()
+	} else if m.removeWorktreeMode {
+		menuBox = m.renderRemoveWorktreeBox()
+	} else if m.sessionMode {
+		menuBox = m.renderSessionBox()
+	} else if m.agentSelect != nil {
+		menuBox = m.renderAgentBox()
 	} else if m.inputMode != "" {
 		menuBox = m.renderInputBox()
 	} else {
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,7 +12,9 @@ import (
 
 // MultiSelectResult holds the output of a multi-select interaction.
 type MultiSelectResult struct {
-	Tools     []string `json:"tools"`
+	Tools []string `json:"tools"`
+	// Dirs holds the directory chosen for each tool, in agent selection.
+	Dirs      []string `json:"dirs,omitempty"`
 	Confirmed bool     `json:"confirmed"`
 }
 
@@ -23,6 +26,11 @@ type MultiSelectModel struct {
 	result   *MultiSelectResult
 	quitting bool
 	errorMsg string
+
+	// Agent selection: the directories a tool can run in and the one
+	// chosen for each tool (see NewAgentSelect)
+	dirs   []string
+	dirIdx []int
 }
 
 // NewMultiSelect creates a new multi-select model.
@@ -44,6 +52,52 @@ func NewMultiSelect(tools []models.AITool) MultiSelectModel {
 	}
 }
 
+// NewAgentSelect creates a multi-select of AI tools to run side by side.
+// Only current is checked. Every tool starts in dirs[dir], and ←→ moves the
+// highlighted tool through dirs, e.g. a project and its worktrees.
+func NewAgentSelect(tools []models.AITool, current string, dirs []string, dir int) MultiSelectModel {
+	checked := make([]bool, len(tools))
+	dirIdx := make([]int, len(tools))
+	for i, t := range tools {
+		checked[i] = t.Name == current
+		dirIdx[i] = dir
+	}
+	return MultiSelectModel{
+		tools:   tools,
+		checked: checked,
+		dirs:    dirs,
+		dirIdx:  dirIdx,
+	}
+}
+
+// Tools returns the tools being chosen from.
+func (m MultiSelectModel) Tools() []models.AITool {
+	return m.tools
+}
+
+// Dirs returns the directory chosen for each tool, or nil outside agent
+// selection.
+func (m MultiSelectModel) Dirs() []string {
+	if m.dirs == nil {
+		return nil
+	}
+	out := make([]string, len(m.tools))
+	for i, d := range m.dirIdx {
+		out[i] = m.dirs[d]
+	}
+	return out
+}
+
+// cycleDir moves the highlighted tool to the next (+1) or previous (-1)
+// directory.
+func (m *MultiSelectModel) cycleDir(direction int) {
+	n := len(m.dirs)
+	if n <= 1 {
+		return
+	}
+	m.dirIdx[m.cursor] = (m.dirIdx[m.cursor] + direction + n) % n
+}
+
 // Cursor returns the current cursor position.
 func (m MultiSelectModel) Cursor() int {
 	return m.cursor
@@ -104,12 +158,23 @@ func (m MultiSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.checked[m.cursor] = !m.checked[m.cursor]
 			return m, nil
 
+		case tea.KeyLeft:
+			m.cycleDir(-1)
+			return m, nil
+
+		case tea.KeyRight:
+			m.cycleDir(1)
+			return m, nil
+
 		case tea.KeyEnter:
 			// Collect selected tools in list order
-			var selected []string
+			var selected, dirs []string
 			for i, t := range m.tools {
 				if m.checked[i] {
 					selected = append(selected, t.Name)
+					if m.dirs != nil {
+						dirs = append(dirs, m.dirs[m.dirIdx[i]])
+					}
 				}
 			}
 
@@ -120,6 +185,7 @@ func (m MultiSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			m.result = &MultiSelectResult{
 				Tools:     selected,
+				Dirs:      dirs,
 				Confirmed: true,
 			}
 			m.quitting = true
@@ -144,6 +210,12 @@ func (m MultiSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.cursor = 0
 					}
 					return m, nil
+				case 'h':
+					m.cycleDir(-1)
+					return m, nil
+				case 'l':
+					m.cycleDir(1)
+					return m, nil
 				}
 			}
 		}
@@ -188,8 +260,11 @@ func (m MultiSelectModel) View() string {
 			b.WriteString(displayName)
 		}
 
-		// Installed tag
-		if tool.Installed {
+		// Directory in agent selection, otherwise the installed tag
+		if m.dirs != nil {
+			b.WriteString("  ")
+			b.WriteString(hintStyle.Render(filepath.Base(m.dirs[m.dirIdx[i]])))
+		} else if tool.Installed {
 			b.WriteString("  ")
 			b.WriteString(installedStyle.Render("(installed)"))
 		}
@@ -1,48 +1,43 @@
 package tui
 
 import (
-	"bufio"
+	"errors"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/jackuait/ghost-tab/internal/models"
 )
 
-// AppendProject appends a name:path entry to the projects file.
-func AppendProject(name, path, filePath string) error {
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return err
-	}
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// AppendProject adds a project to the projects file, creating the file if
+// needed. A legacy name:path file is migrated to the structured format.
+func AppendProject(project models.Project, filePath string) error {
+	pf, err := models.ReadProjectsFile(filePath)
 	if err != nil {
-		return err
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		pf = &models.ProjectsFile{}
 	}
-	defer f.Close()
-	_, err = f.WriteString(name + ":" + path + "\n")
-	return err
+	pf.Projects = append(pf.Projects, project)
+	return models.WriteProjectsFile(filePath, pf)
 }
 
-// RemoveProject removes an exact line from the projects file.
-func RemoveProject(line, filePath string) error {
-	data, err := os.ReadFile(filePath)
+// RemoveProject removes the project with the same name and path from the
+// projects file. Other projects and their metadata are kept as they are.
+func RemoveProject(project models.Project, filePath string) error {
+	pf, err := models.ReadProjectsFile(filePath)
 	if err != nil {
 		return err
 	}
 
-	var kept []string
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
-	for scanner.Scan() {
-		if scanner.Text() != line {
-			kept = append(kept, scanner.Text())
+	kept := pf.Projects[:0]
+	for _, p := range pf.Projects {
+		if p.Name != project.Name || p.Path != project.Path {
+			kept = append(kept, p)
 		}
 	}
-
-	var result string
-	if len(kept) > 0 {
-		result = strings.Join(kept, "\n") + "\n"
-	}
-	return os.WriteFile(filePath, []byte(result), 0644)
+	pf.Projects = kept
+	return models.WriteProjectsFile(filePath, pf)
 }
 
 // IsDuplicateProject checks if an expanded path already exists in the project list.
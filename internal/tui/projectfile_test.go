@@ -10,35 +10,115 @@ import (
 	"github.com/jackuait/ghost-tab/internal/tui"
 )
 
+// readProjects loads the projects file and fails the test on error.
+func readProjects(t *testing.T, file string) []models.Project {
+	t.Helper()
+	pf, err := models.ReadProjectsFile(file)
+	if err != nil {
+		t.Fatalf("ReadProjectsFile: %v", err)
+	}
+	if pf.IsLegacy() {
+		t.Fatal("expected projects file in structured format")
+	}
+	return pf.Projects
+}
+
+// writeProjects writes projects in the structured format.
+func writeProjects(t *testing.T, file string, projects ...models.Project) {
+	t.Helper()
+	if err := models.WriteProjectsFile(file, &models.ProjectsFile{Projects: projects}); err != nil {
+		t.Fatalf("WriteProjectsFile: %v", err)
+	}
+}
+
+// projectNames returns "name:path" for each project, for compact comparisons.
+func projectNames(projects []models.Project) string {
+	var parts []string
+	for _, p := range projects {
+		parts = append(parts, p.Name+":"+p.Path)
+	}
+	return strings.Join(parts, ",")
+}
+
 func TestAppendProject(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "projects")
 
-	err := tui.AppendProject("my-app", "/home/user/my-app", file)
+	err := tui.AppendProject(models.Project{Name: "my-app", Path: "/home/user/my-app"}, file)
 	if err != nil {
 		t.Fatalf("AppendProject: %v", err)
 	}
 
 	data, _ := os.ReadFile(file)
-	if string(data) != "my-app:/home/user/my-app\n" {
-		t.Errorf("File content: expected 'my-app:/home/user/my-app\\n', got %q", string(data))
+	if !strings.Contains(string(data), `"version": 1`) {
+		t.Errorf("expected versioned file, got %q", string(data))
+	}
+	if got := projectNames(readProjects(t, file)); got != "my-app:/home/user/my-app" {
+		t.Errorf("Projects: expected my-app:/home/user/my-app, got %q", got)
 	}
 }
 
 func TestAppendProject_AppendsToExisting(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "projects")
+	writeProjects(t, file, models.Project{Name: "first", Path: "/tmp/first", Tags: []string{"work"}})
+
+	err := tui.AppendProject(models.Project{Name: "second", Path: "/tmp/second"}, file)
+	if err != nil {
+		t.Fatalf("AppendProject: %v", err)
+	}
+
+	projects := readProjects(t, file)
+	if got := projectNames(projects); got != "first:/tmp/first,second:/tmp/second" {
+		t.Errorf("Projects: got %q", got)
+	}
+	if len(projects[0].Tags) != 1 || projects[0].Tags[0] != "work" {
+		t.Errorf("existing metadata should be kept, got tags %v", projects[0].Tags)
+	}
+}
+
+func TestAppendProject_MigratesLegacyFile(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "projects")
 	os.WriteFile(file, []byte("first:/tmp/first\n"), 0644)
 
-	err := tui.AppendProject("second", "/tmp/second", file)
+	err := tui.AppendProject(models.Project{Name: "second", Path: "/tmp/second"}, file)
 	if err != nil {
 		t.Fatalf("AppendProject: %v", err)
 	}
 
-	data, _ := os.ReadFile(file)
-	expected := "first:/tmp/first\nsecond:/tmp/second\n"
-	if string(data) != expected {
-		t.Errorf("File content: expected %q, got %q", expected, string(data))
+	if got := projectNames(readProjects(t, file)); got != "first:/tmp/first,second:/tmp/second" {
+		t.Errorf("Projects: got %q", got)
+	}
+}
+
+func TestAppendProject_KeepsMetadata(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "projects")
+
+	project := models.Project{
+		Name:   "api",
+		Path:   "/tmp/api",
+		AITool: "codex",
+		AIArgs: []string{"--model", "o3"},
+		Layout: "tests",
+		Env:    map[string]string{"PORT": "8080"},
+		Tags:   []string{"work", "go"},
+		Notes:  "staging creds in 1password",
+	}
+	if err := tui.AppendProject(project, file); err != nil {
+		t.Fatalf("AppendProject: %v", err)
+	}
+
+	got := readProjects(t, file)[0]
+	if got.AITool != "codex" || got.Layout != "tests" || got.Notes != project.Notes {
+		t.Errorf("scalar fields not round-tripped: %+v", got)
+	}
+	if strings.Join(got.AIArgs, " ") != "--model o3" || strings.Join(got.Tags, ",") != "work,go" {
+		t.Errorf("list fields not round-tripped: %+v", got)
+	}
+	if got.Env["PORT"] != "8080" {
+		t.Errorf("env not round-tripped: %v", got.Env)
 	}
 }
 
@@ -46,81 +126,102 @@ func TestAppendProject_CreatesParentDirs(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "sub", "dir", "projects")
 
-	err := tui.AppendProject("test", "/tmp/test", file)
+	err := tui.AppendProject(models.Project{Name: "test", Path: "/tmp/test"}, file)
 	if err != nil {
 		t.Fatalf("AppendProject should create parent dirs: %v", err)
 	}
 
-	data, _ := os.ReadFile(file)
-	if string(data) != "test:/tmp/test\n" {
-		t.Errorf("File content: expected 'test:/tmp/test\\n', got %q", string(data))
+	if got := projectNames(readProjects(t, file)); got != "test:/tmp/test" {
+		t.Errorf("Projects: expected test:/tmp/test, got %q", got)
 	}
 }
 
 func TestRemoveProject(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "projects")
-	os.WriteFile(file, []byte("first:/tmp/first\nsecond:/tmp/second\nthird:/tmp/third\n"), 0644)
+	writeProjects(t, file,
+		models.Project{Name: "first", Path: "/tmp/first"},
+		models.Project{Name: "second", Path: "/tmp/second"},
+		models.Project{Name: "third", Path: "/tmp/third", Notes: "keep me"},
+	)
 
-	err := tui.RemoveProject("second:/tmp/second", file)
+	err := tui.RemoveProject(models.Project{Name: "second", Path: "/tmp/second"}, file)
 	if err != nil {
 		t.Fatalf("RemoveProject: %v", err)
 	}
 
-	data, _ := os.ReadFile(file)
-	expected := "first:/tmp/first\nthird:/tmp/third\n"
-	if string(data) != expected {
-		t.Errorf("File content: expected %q, got %q", expected, string(data))
+	projects := readProjects(t, file)
+	if got := projectNames(projects); got != "first:/tmp/first,third:/tmp/third" {
+		t.Errorf("Projects: got %q", got)
+	}
+	if projects[1].Notes != "keep me" {
+		t.Errorf("remaining project metadata should be kept, got %+v", projects[1])
 	}
 }
 
 func TestRemoveProject_SingleEntry(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "projects")
-	os.WriteFile(file, []byte("only:/tmp/only\n"), 0644)
+	writeProjects(t, file, models.Project{Name: "only", Path: "/tmp/only"})
 
-	err := tui.RemoveProject("only:/tmp/only", file)
+	err := tui.RemoveProject(models.Project{Name: "only", Path: "/tmp/only"}, file)
 	if err != nil {
 		t.Fatalf("RemoveProject: %v", err)
 	}
 
-	data, _ := os.ReadFile(file)
-	if string(data) != "" {
-		t.Errorf("File content should be empty, got %q", string(data))
+	if projects := readProjects(t, file); len(projects) != 0 {
+		t.Errorf("expected no projects, got %v", projects)
 	}
 }
 
 func TestRemoveProject_NoMatch(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "projects")
-	original := "first:/tmp/first\nsecond:/tmp/second\n"
-	os.WriteFile(file, []byte(original), 0644)
+	writeProjects(t, file,
+		models.Project{Name: "first", Path: "/tmp/first"},
+		models.Project{Name: "second", Path: "/tmp/second"},
+	)
 
-	err := tui.RemoveProject("nonexistent:/tmp/nope", file)
+	err := tui.RemoveProject(models.Project{Name: "nonexistent", Path: "/tmp/nope"}, file)
 	if err != nil {
 		t.Fatalf("RemoveProject with no match: %v", err)
 	}
 
-	data, _ := os.ReadFile(file)
-	if string(data) != original {
-		t.Errorf("File should be unchanged, got %q", string(data))
+	if got := projectNames(readProjects(t, file)); got != "first:/tmp/first,second:/tmp/second" {
+		t.Errorf("projects should be unchanged, got %q", got)
 	}
 }
 
 func TestRemoveProject_PartialMatchNotDeleted(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "projects")
-	original := "app:/tmp/app\napp-long:/tmp/app-long\n"
-	os.WriteFile(file, []byte(original), 0644)
+	writeProjects(t, file,
+		models.Project{Name: "app", Path: "/tmp/app"},
+		models.Project{Name: "app-long", Path: "/tmp/app-long"},
+	)
 
-	err := tui.RemoveProject("app:/tmp/app", file)
+	err := tui.RemoveProject(models.Project{Name: "app", Path: "/tmp/app"}, file)
 	if err != nil {
 		t.Fatalf("RemoveProject: %v", err)
 	}
 
-	data, _ := os.ReadFile(file)
-	if string(data) != "app-long:/tmp/app-long\n" {
-		t.Errorf("Partial match should survive, got %q", string(data))
+	if got := projectNames(readProjects(t, file)); got != "app-long:/tmp/app-long" {
+		t.Errorf("Partial match should survive, got %q", got)
+	}
+}
+
+func TestRemoveProject_LegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "projects")
+	os.WriteFile(file, []byte("first:/tmp/first\nsecond:/tmp/second\n"), 0644)
+
+	err := tui.RemoveProject(models.Project{Name: "first", Path: "/tmp/first"}, file)
+	if err != nil {
+		t.Fatalf("RemoveProject: %v", err)
+	}
+
+	if got := projectNames(readProjects(t, file)); got != "second:/tmp/second" {
+		t.Errorf("Projects: got %q", got)
 	}
 }
 
@@ -150,14 +251,14 @@ func TestIsDuplicateProject_TrailingSlash(t *testing.T) {
 
 func TestAppendProject_ErrorOnUnwritablePath(t *testing.T) {
 	// /dev/null is not a directory, so MkdirAll should fail
-	err := tui.AppendProject("test", "/tmp/test", "/dev/null/sub/projects")
+	err := tui.AppendProject(models.Project{Name: "test", Path: "/tmp/test"}, "/dev/null/sub/projects")
 	if err == nil {
 		t.Error("Should return error when parent cannot be created")
 	}
 }
 
 func TestRemoveProject_ErrorOnMissingFile(t *testing.T) {
-	err := tui.RemoveProject("foo:/tmp/foo", "/nonexistent/projects")
+	err := tui.RemoveProject(models.Project{Name: "foo", Path: "/tmp/foo"}, "/nonexistent/projects")
 	if err == nil {
 		t.Error("Should return error when file doesn't exist")
 	}
@@ -170,50 +271,15 @@ func TestAppendProject_EdgeCases(t *testing.T) {
 		name     string
 		projName string
 		projPath string
-		expected string
 	}{
-		{
-			name:     "handles paths with spaces",
-			projName: "myapp",
-			projPath: "/tmp/path with spaces",
-			expected: "myapp:/tmp/path with spaces\n",
-		},
-		{
-			name:     "handles name with spaces",
-			projName: "my app",
-			projPath: "/path/to/app",
-			expected: "my app:/path/to/app\n",
-		},
-		{
-			name:     "handles path with quotes",
-			projName: "app",
-			projPath: `/path/with"quotes`,
-			expected: "app:/path/with\"quotes\n",
-		},
-		{
-			name:     "handles path with unicode",
-			projName: "app",
-			projPath: "/path/\u00e9moji/\U0001F47B",
-			expected: "app:/path/\u00e9moji/\U0001F47B\n",
-		},
-		{
-			name:     "handles very long paths",
-			projName: "app",
-			projPath: strings.Repeat("/very/long/path", 50),
-			expected: "app:" + strings.Repeat("/very/long/path", 50) + "\n",
-		},
-		{
-			name:     "handles name with colons",
-			projName: "app:v2.0",
-			projPath: "/path/to/app",
-			expected: "app:v2.0:/path/to/app\n",
-		},
-		{
-			name:     "handles special characters in name",
-			projName: "app-v1.0_test",
-			projPath: "/path/to/app",
-			expected: "app-v1.0_test:/path/to/app\n",
-		},
+		{"handles paths with spaces", "myapp", "/tmp/path with spaces"},
+		{"handles name with spaces", "my app", "/path/to/app"},
+		{"handles path with quotes", "app", `/path/with"quotes`},
+		{"handles path with unicode", "app", "/path/\u00e9moji/\U0001F47B"},
+		{"handles very long paths", "app", strings.Repeat("/very/long/path", 50)},
+		{"handles name with colons", "app:v2.0", "/path/to/app"},
+		{"handles path with colons", "app", "/path/to:app"},
+		{"handles special characters in name", "app-v1.0_test", "/path/to/app"},
 	}
 
 	for _, tt := range tests {
@@ -221,17 +287,17 @@ func TestAppendProject_EdgeCases(t *testing.T) {
 			dir := t.TempDir()
 			file := filepath.Join(dir, "projects")
 
-			err := tui.AppendProject(tt.projName, tt.projPath, file)
+			err := tui.AppendProject(models.Project{Name: tt.projName, Path: tt.projPath}, file)
 			if err != nil {
 				t.Fatalf("AppendProject: %v", err)
 			}
 
-			data, err := os.ReadFile(file)
-			if err != nil {
-				t.Fatalf("ReadFile: %v", err)
+			projects := readProjects(t, file)
+			if len(projects) != 1 {
+				t.Fatalf("expected 1 project, got %d", len(projects))
 			}
-			if string(data) != tt.expected {
-				t.Errorf("File content: expected %q, got %q", tt.expected, string(data))
+			if projects[0].Name != tt.projName || projects[0].Path != tt.projPath {
+				t.Errorf("round trip: expected %q:%q, got %q:%q", tt.projName, tt.projPath, projects[0].Name, projects[0].Path)
 			}
 		})
 	}
@@ -241,60 +307,33 @@ func TestAppendProject_EdgeCases(t *testing.T) {
 
 func TestRemoveProject_EdgeCases(t *testing.T) {
 	tests := []struct {
-		name     string
-		initial  string
-		remove   string
-		expected string
+		name   string
+		target models.Project
 	}{
-		{
-			name:     "handles paths with spaces",
-			initial:  "app1:/path/app1\napp2:/tmp/path with spaces\napp3:/path/app3\n",
-			remove:   "app2:/tmp/path with spaces",
-			expected: "app1:/path/app1\napp3:/path/app3\n",
-		},
-		{
-			name:     "handles entry with quotes",
-			initial:  "app1:/path/app1\napp2:/path/with\"quotes\napp3:/path/app3\n",
-			remove:   "app2:/path/with\"quotes",
-			expected: "app1:/path/app1\napp3:/path/app3\n",
-		},
-		{
-			name:     "handles entry with unicode",
-			initial:  "app1:/path/app1\napp2:/path/\u00e9moji/\U0001F47B\napp3:/path/app3\n",
-			remove:   "app2:/path/\u00e9moji/\U0001F47B",
-			expected: "app1:/path/app1\napp3:/path/app3\n",
-		},
-		{
-			name:     "does not delete partial matches",
-			initial:  "app:/path/app\napp-long:/path/app-longer-name\n",
-			remove:   "app:/path/app",
-			expected: "app-long:/path/app-longer-name\n",
-		},
-		{
-			name:     "handles very long entries",
-			initial:  "app1:/path/app1\napp2:" + strings.Repeat("/very/long/path", 50) + "\napp3:/path/app3\n",
-			remove:   "app2:" + strings.Repeat("/very/long/path", 50),
-			expected: "app1:/path/app1\napp3:/path/app3\n",
-		},
+		{"handles paths with spaces", models.Project{Name: "app2", Path: "/tmp/path with spaces"}},
+		{"handles entry with quotes", models.Project{Name: "app2", Path: "/path/with\"quotes"}},
+		{"handles entry with unicode", models.Project{Name: "app2", Path: "/path/\u00e9moji/\U0001F47B"}},
+		{"handles very long entries", models.Project{Name: "app2", Path: strings.Repeat("/very/long/path", 50)}},
+		{"handles name with colons", models.Project{Name: "app:v2", Path: "/path/app2"}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dir := t.TempDir()
 			file := filepath.Join(dir, "projects")
-			os.WriteFile(file, []byte(tt.initial), 0644)
+			writeProjects(t, file,
+				models.Project{Name: "app1", Path: "/path/app1"},
+				tt.target,
+				models.Project{Name: "app3", Path: "/path/app3"},
+			)
 
-			err := tui.RemoveProject(tt.remove, file)
+			err := tui.RemoveProject(tt.target, file)
 			if err != nil {
 				t.Fatalf("RemoveProject: %v", err)
 			}
 
-			data, err := os.ReadFile(file)
-			if err != nil {
-				t.Fatalf("ReadFile: %v", err)
-			}
-			if string(data) != tt.expected {
-				t.Errorf("File content: expected %q, got %q", tt.expected, string(data))
+			if got := projectNames(readProjects(t, file)); got != "app1:/path/app1,app3:/path/app3" {
+				t.Errorf("Projects: expected app1 and app3 to remain, got %q", got)
 			}
 		})
 	}
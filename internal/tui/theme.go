@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/ghost-tab/internal/aitools"
 )
 
 // AIToolTheme defines the color palette for an AI tool's TUI appearance.
@@ -25,84 +26,31 @@ type AIToolTheme struct {
 	Text          lipgloss.Color
 }
 
-var themes = map[string]AIToolTheme{
-	"claude": {
-		Name:          "claude",
-		Primary:       lipgloss.Color("209"),
-		Dim:           lipgloss.Color("166"),
-		Bright:        lipgloss.Color("208"),
-		Accent:        lipgloss.Color("220"),
-		Cap:           lipgloss.Color("223"),
-		DarkFeet:      lipgloss.Color("166"),
-		EyeWhite:      lipgloss.Color("255"),
-		EyePupil:      lipgloss.Color("232"),
-		SleepPrimary:  lipgloss.Color("166"),
-		SleepAccent:   lipgloss.Color("178"),
-		SleepDim:      lipgloss.Color("166"),
-		SleepDarkFeet: lipgloss.Color("94"),
-		SleepCap:      lipgloss.Color("180"),
-		Text:          lipgloss.Color("223"),
-	},
-	"codex": {
-		Name:          "codex",
-		Primary:       lipgloss.Color("114"),
-		Dim:           lipgloss.Color("71"),
-		Bright:        lipgloss.Color("113"),
-		Accent:        lipgloss.Color("78"),
-		Cap:           lipgloss.Color("157"),
-		DarkFeet:      lipgloss.Color("71"),
-		EyeWhite:      lipgloss.Color("255"),
-		EyePupil:      lipgloss.Color("232"),
-		SleepPrimary:  lipgloss.Color("71"),
-		SleepAccent:   lipgloss.Color("65"),
-		SleepDim:      lipgloss.Color("71"),
-		SleepDarkFeet: lipgloss.Color("58"),
-		SleepCap:      lipgloss.Color("114"),
-		Text:          lipgloss.Color("157"),
-	},
-	"copilot": {
-		Name:          "copilot",
-		Primary:       lipgloss.Color("141"),
-		Dim:           lipgloss.Color("98"),
-		Bright:        lipgloss.Color("140"),
-		Accent:        lipgloss.Color("134"),
-		Cap:           lipgloss.Color("183"),
-		DarkFeet:      lipgloss.Color("98"),
-		EyeWhite:      lipgloss.Color("255"),
-		EyePupil:      lipgloss.Color("232"),
-		SleepPrimary:  lipgloss.Color("98"),
-		SleepAccent:   lipgloss.Color("96"),
-		SleepDim:      lipgloss.Color("98"),
-		SleepDarkFeet: lipgloss.Color("60"),
-		SleepCap:      lipgloss.Color("140"),
-		Text:          lipgloss.Color("183"),
-	},
-	"opencode": {
-		Name:          "opencode",
-		Primary:       lipgloss.Color("250"),
-		Dim:           lipgloss.Color("244"),
-		Bright:        lipgloss.Color("255"),
-		Accent:        lipgloss.Color("240"),
-		Cap:           lipgloss.Color("252"),
-		DarkFeet:      lipgloss.Color("240"),
-		EyeWhite:      lipgloss.Color("255"),
-		EyePupil:      lipgloss.Color("238"),
-		SleepPrimary:  lipgloss.Color("244"),
-		SleepAccent:   lipgloss.Color("234"),
-		SleepDim:      lipgloss.Color("236"),
-		SleepDarkFeet: lipgloss.Color("232"),
-		SleepCap:      lipgloss.Color("242"),
-		Text:          lipgloss.Color("252"),
-	},
-}
-
-// ThemeForTool returns the color theme for the given AI tool.
-// Unknown tools fall back to the claude theme.
+// ThemeForTool returns the color theme for the given AI tool from the
+// aitools registry. Unknown tools fall back to the claude theme.
 func ThemeForTool(tool string) AIToolTheme {
-	if theme, ok := themes[tool]; ok {
-		return theme
+	t, ok := aitools.Current().Lookup(tool)
+	if !ok {
+		t = aitools.Current().Get(aitools.DefaultTool)
+	}
+	c := t.Theme
+	return AIToolTheme{
+		Name:          t.Name,
+		Primary:       lipgloss.Color(c.Primary),
+		Dim:           lipgloss.Color(c.Dim),
+		Bright:        lipgloss.Color(c.Bright),
+		Accent:        lipgloss.Color(c.Accent),
+		Cap:           lipgloss.Color(c.Cap),
+		DarkFeet:      lipgloss.Color(c.DarkFeet),
+		EyeWhite:      lipgloss.Color(c.EyeWhite),
+		EyePupil:      lipgloss.Color(c.EyePupil),
+		SleepPrimary:  lipgloss.Color(c.SleepPrimary),
+		SleepAccent:   lipgloss.Color(c.SleepAccent),
+		SleepDim:      lipgloss.Color(c.SleepDim),
+		SleepDarkFeet: lipgloss.Color(c.SleepDarkFeet),
+		SleepCap:      lipgloss.Color(c.SleepCap),
+		Text:          lipgloss.Color(c.Text),
 	}
-	return themes["claude"]
 }
 
 // AnsiFromThemeColor converts a lipgloss.Color (ANSI 256 string) to an
@@ -1,33 +1,41 @@
 package util
 
-import "strings"
+import "github.com/jackuait/ghost-tab/internal/aitools"
 
-// BuildAILaunchCmd constructs the shell command string to launch an AI tool.
-// tool: the AI tool identifier (claude, codex, copilot, opencode)
+// BuildAILaunchArgv constructs the argument vector that launches an AI tool.
+// tool: the AI tool identifier, looked up in the aitools registry
 // command: the command/binary path to execute
-// projectDir: the project directory path (used by codex via --cd, opencode as positional arg)
-// args: additional arguments (used by claude/unknown tools)
-// Returns the complete command string ready for shell execution.
+// projectDir: the project directory path (passed as the tool's registry entry says)
+// profile: launch profile whose arguments follow the command, for every tool
+// args: additional arguments (used by tools with PassArgs, and unknown tools)
+// Every element is passed to the tool verbatim; nothing is quoted here.
 //
-// Behavior per tool matches the bash build_ai_launch_cmd():
-//   - codex:   command --cd "projectDir"
+// For the built-in tools without a profile the arguments follow the bash
+// build_ai_launch_cmd():
+//   - codex:   command --cd projectDir
 //   - copilot: command (no args, no projectDir)
-//   - opencode: command "projectDir"
-//   - claude/unknown: command args... (space-joined, omitted if empty)
-func BuildAILaunchCmd(tool, command, projectDir string, args []string) string {
-	switch tool {
-	case "codex":
-		return command + ` --cd "` + projectDir + `"`
-	case "copilot":
-		return command
-	case "opencode":
-		return command + ` "` + projectDir + `"`
-	default:
-		// claude and unknown tools: append args if present
-		extra := strings.Join(args, " ")
-		if extra != "" {
-			return command + " " + extra
-		}
-		return command
+//   - opencode: command projectDir
+//   - claude/unknown: command args...
+func BuildAILaunchArgv(tool, command, projectDir string, profile aitools.Profile, args []string) []string {
+	t := aitools.Current().Get(tool)
+
+	argv := []string{command}
+	argv = append(argv, profile.Args...)
+	switch t.ProjectDir {
+	case aitools.ProjectDirFlag:
+		argv = append(argv, t.ProjectDirFlag, projectDir)
+	case aitools.ProjectDirPositional:
+		argv = append(argv, projectDir)
+	}
+	if t.PassArgs {
+		argv = append(argv, args...)
 	}
+	return argv
+}
+
+// BuildAILaunchCmd constructs the shell command string to launch an AI tool:
+// BuildAILaunchArgv with every argument shell-quoted, so any project path
+// or argument reaches the tool unchanged.
+func BuildAILaunchCmd(tool, command, projectDir string, profile aitools.Profile, args []string) string {
+	return ShellJoin(BuildAILaunchArgv(tool, command, projectDir, profile, args))
 }
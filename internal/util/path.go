@@ -7,6 +7,17 @@ import (
 	"strings"
 )
 
+// ConfigDir returns Ghost Tab's config directory,
+// ${XDG_CONFIG_HOME:-~/.config}/ghost-tab.
+func ConfigDir() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "ghost-tab")
+}
+
 // ExpandPath expands ~ to $HOME in path
 func ExpandPath(path string) string {
 	if strings.HasPrefix(path, "~/") {
@@ -0,0 +1,37 @@
+package util
+
+import "strings"
+
+// ShellQuote quotes s as a single word for POSIX shells. Words made only
+// of characters no shell treats specially are returned unchanged, so
+// common flags stay readable. A leading = is quoted too: zsh expands
+// =word to the path of the command word.
+func ShellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !strings.HasPrefix(s, "=") && strings.IndexFunc(s, needsQuoting) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func needsQuoting(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case strings.ContainsRune("_@%+=:,./-", r):
+		return false
+	}
+	return true
+}
+
+// ShellJoin renders argv as a POSIX shell command line that a shell splits
+// back into exactly argv.
+func ShellJoin(argv []string) string {
+	words := make([]string, len(argv))
+	for i, a := range argv {
+		words[i] = ShellQuote(a)
+	}
+	return strings.Join(words, " ")
+}
@@ -17,3 +17,13 @@ validate_ai_tool() {
     fi
   fi
 }
+
+# Fills AI_TOOLS_AVAILABLE with the installed AI tools in registry order:
+# the built-in tools, then any defined in ai-tools.toml.
+detect_ai_tools() {
+  local _t
+  AI_TOOLS_AVAILABLE=()
+  while IFS= read -r _t; do
+    [ -n "$_t" ] && AI_TOOLS_AVAILABLE+=("$_t")
+  done < <(ghost-tab-tui tools --installed 2>/dev/null)
+}
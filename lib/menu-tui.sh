@@ -4,7 +4,10 @@
 
 # Interactive project selection using ghost-tab-tui main-menu
 # Returns 0 if an actionable item was selected, 1 if quit/cancelled
-# Sets: _selected_project_name, _selected_project_path, _selected_project_action, _selected_ai_tool
+# Sets: _selected_project_name, _selected_project_path, _selected_project_action, _selected_ai_tool,
+#       _selected_ai_profile (launch profile chosen in the menu, empty for the project's default),
+#       _selected_agents (tool=path per AI tool to run side by side, empty for a single tool),
+#       _selected_project_session (running tmux session to attach to, empty for a new session)
 select_project_interactive() {
   local projects_file="$1"
 
@@ -16,6 +19,9 @@ select_project_interactive() {
   # Read preferences from settings file
   local ghost_display="animated"
   local tab_title="full"
+  local persist_session="off"
+  local collapsed_groups=""
+  local project_sort="manual"
   local settings_file="${XDG_CONFIG_HOME:-$HOME/.config}/ghost-tab/settings"
   if [ -f "$settings_file" ]; then
     local saved_display
@@ -28,6 +34,17 @@ select_project_interactive() {
     if [ -n "$saved_tab_title" ]; then
       tab_title="$saved_tab_title"
     fi
+    local saved_persist
+    saved_persist=$(grep '^persist_session=' "$settings_file" 2>/dev/null | cut -d= -f2)
+    if [ -n "$saved_persist" ]; then
+      persist_session="$saved_persist"
+    fi
+    collapsed_groups=$(grep '^collapsed_groups=' "$settings_file" 2>/dev/null | cut -d= -f2-)
+    local saved_sort
+    saved_sort=$(grep '^project_sort=' "$settings_file" 2>/dev/null | cut -d= -f2)
+    if [ -n "$saved_sort" ]; then
+      project_sort="$saved_sort"
+    fi
   fi
 
   # Read sound notification state
@@ -49,6 +66,13 @@ select_project_interactive() {
   cmd_args+=("--ai-tool-file" "$ai_tool_file")
   cmd_args+=("--ghost-display" "$ghost_display")
   cmd_args+=("--tab-title" "$tab_title")
+  cmd_args+=("--persist-session" "$persist_session")
+  cmd_args+=("--project-sort" "$project_sort")
+  cmd_args+=("--history-file" "$gt_config_dir/history.jsonl")
+  cmd_args+=("--git-status-cache" "$gt_config_dir/git-status.json")
+  if [[ -n "$collapsed_groups" ]]; then
+    cmd_args+=("--collapsed-groups" "$collapsed_groups")
+  fi
   cmd_args+=("--settings-file" "$settings_file")
   local sound_file="$gt_config_dir/${SELECTED_AI_TOOL:-claude}-features.json"
   cmd_args+=("--sound-file" "$sound_file")
@@ -106,6 +130,27 @@ select_project_interactive() {
 
       _selected_project_name="$name"
       _selected_project_path="$path"
+      # A project's default AI tool applies to this launch only
+      local project_ai_tool
+      project_ai_tool=$(echo "$result" | jq -r '.project_ai_tool // ""' 2>/dev/null)
+      if [[ -n "$project_ai_tool" ]]; then
+        _selected_ai_tool="$project_ai_tool"
+      fi
+      _selected_ai_profile=$(echo "$result" | jq -r '.profile // ""' 2>/dev/null)
+      # Several AI tools side by side: the first is the one launched
+      _selected_agents=()
+      local agent
+      while IFS= read -r agent; do
+        [[ -n "$agent" ]] && _selected_agents+=("$agent")
+      done < <(echo "$result" | jq -r '.agents // [] | .[] | .tool + "=" + .path' 2>/dev/null)
+      if [[ ${#_selected_agents[@]} -gt 0 ]]; then
+        _selected_ai_tool="${_selected_agents[0]%%=*}"
+      fi
+      _selected_project_session=$(echo "$result" | jq -r '.session // ""' 2>/dev/null)
+      return 0
+      ;;
+    import-projects)
+      import_projects_interactive "$projects_file" "$settings_file"
       return 0
       ;;
     quit)
@@ -117,3 +162,27 @@ select_project_interactive() {
       ;;
   esac
 }
+
+# Scan for git repositories and import the chosen ones into the projects file
+# Scan roots (comma-separated) and depth come from the scan_roots= and
+# scan_depth= settings; ghost-tab-tui's defaults are used when unset.
+import_projects_interactive() {
+  local projects_file="$1"
+  local settings_file="$2"
+
+  local cmd_args=("discover" "--projects-file" "$projects_file")
+  cmd_args+=("--ai-tool" "${SELECTED_AI_TOOL:-claude}")
+  if [ -f "$settings_file" ]; then
+    local scan_roots scan_depth
+    scan_roots=$(grep '^scan_roots=' "$settings_file" 2>/dev/null | cut -d= -f2-)
+    if [ -n "$scan_roots" ]; then
+      cmd_args+=("--root" "$scan_roots")
+    fi
+    scan_depth=$(grep '^scan_depth=' "$settings_file" 2>/dev/null | cut -d= -f2)
+    if [ -n "$scan_depth" ]; then
+      cmd_args+=("--max-depth" "$scan_depth")
+    fi
+  fi
+
+  ghost-tab-tui "${cmd_args[@]}" >/dev/null 2>&1 || true
+}
@@ -105,6 +105,61 @@ with open(path, 'w') as f:
 " "$features_file" "$enabled"
 }
 
+# Register ghost-tab-tui notify for Claude's hooks. It plays the chosen
+# sound itself, along with desktop notifications, the tmux bell and the
+# tab title mark. Returns 1 when ghost-tab-tui is not installed.
+# Usage: setup_notify_hooks <settings_path>
+setup_notify_hooks() {
+  local settings_path="$1" result
+  command -v ghost-tab-tui &>/dev/null || return 1
+  result="$(ghost-tab-tui notify --install --settings "$settings_path" 2>/dev/null)" || return 1
+  if [ "$result" = "added" ]; then
+    success "Notifications configured"
+  else
+    success "Notifications already configured"
+  fi
+}
+
+# Unregister the ghost-tab-tui notify hooks, so turning notifications off
+# stops the desktop notifications, bell, title mark and sinks along with
+# the sound. Does nothing when ghost-tab-tui is not installed.
+# Usage: remove_notify_hooks <settings_path>
+remove_notify_hooks() {
+  local settings_path="$1"
+  [ -f "$settings_path" ] || return 0
+  command -v ghost-tab-tui &>/dev/null || return 0
+  ghost-tab-tui notify --uninstall --settings "$settings_path" >/dev/null 2>&1 || true
+}
+
+# Print the hook command that plays the named sound on this platform.
+# Uses the player and sound directories ghost-tab-tui detects, falling
+# back to the macOS system sound when it is not installed.
+# Usage: sound_hook_command <sound_name>
+sound_hook_command() {
+  local sound_name="$1" cmd=""
+  if command -v ghost-tab-tui &>/dev/null; then
+    cmd="$(ghost-tab-tui sounds --hook-command "$sound_name" 2>/dev/null)"
+  fi
+  if [[ -z "$cmd" ]]; then
+    cmd="afplay /System/Library/Sounds/${sound_name:-Bottle}.aiff &"
+  fi
+  echo "$cmd"
+}
+
+# Remove every Ghost Tab sound hook, whichever player it uses. Which Stop
+# hooks are Ghost Tab's is decided by ghost-tab-tui, so the user's own
+# hooks that happen to run a player are kept.
+# Usage: remove_sound_hooks <settings_path>
+remove_sound_hooks() {
+  local settings_path="$1"
+  [ -f "$settings_path" ] || return 0
+  if ! command -v ghost-tab-tui &>/dev/null; then
+    error "ghost-tab-tui binary not found. Please reinstall." >&2
+    return 1
+  fi
+  ghost-tab-tui hooks remove --settings "$settings_path" --event Stop --ghost-tab-sound >/dev/null
+}
+
 # Remove sound notification hook from Claude settings.
 # Usage: remove_sound_notification <settings_path> <sound_command>
 remove_sound_notification() {
@@ -121,14 +176,14 @@ toggle_sound_notification() {
   local tool="$1" config_dir="$2" settings_path="$3"
   local current
   current="$(is_sound_enabled "$tool" "$config_dir")"
-  local sound_command="afplay /System/Library/Sounds/Bottle.aiff &"
 
   if [[ "$current" == "true" ]]; then
     # Disable
     set_sound_feature_flag "$tool" "$config_dir" false
     case "$tool" in
       claude)
-        remove_sound_notification "$settings_path" "$sound_command"
+        remove_sound_hooks "$settings_path"
+        remove_notify_hooks "$settings_path"
         ;;
     esac
     success "Sound notifications disabled"
@@ -137,7 +192,10 @@ toggle_sound_notification() {
     set_sound_feature_flag "$tool" "$config_dir" true
     case "$tool" in
       claude)
-        setup_sound_notification "$settings_path" "$sound_command"
+        remove_sound_hooks "$settings_path"
+        setup_notify_hooks "$settings_path" ||
+          setup_sound_notification "$settings_path" \
+            "$(sound_hook_command "$(get_sound_name "$tool" "$config_dir")")"
         ;;
     esac
     success "Sound notifications enabled"
@@ -153,10 +211,11 @@ apply_sound_notification() {
   if [[ -z "$sound_name" ]]; then
     # Disable sound
     set_sound_feature_flag "$tool" "$config_dir" false
-    # Remove any existing afplay hook
+    # Remove any existing sound hook and the notify hooks
     case "$tool" in
       claude)
-        remove_sound_notification "$settings_path" "afplay /System/Library/Sounds/"
+        remove_sound_hooks "$settings_path"
+        remove_notify_hooks "$settings_path"
         ;;
     esac
     success "Sound notifications disabled"
@@ -164,12 +223,14 @@ apply_sound_notification() {
     # Enable sound with specific name
     set_sound_feature_flag "$tool" "$config_dir" true
     set_sound_name "$tool" "$config_dir" "$sound_name"
-    local sound_command="afplay /System/Library/Sounds/${sound_name}.aiff &"
     case "$tool" in
       claude)
-        # Remove old hook first (any afplay sound), then add new one
-        remove_sound_notification "$settings_path" "afplay /System/Library/Sounds/"
-        setup_sound_notification "$settings_path" "$sound_command"
+        # Replace any raw player hook with the notify hook, which reads the
+        # sound name from the features file; without ghost-tab-tui fall
+        # back to a hook that plays the sound directly
+        remove_sound_hooks "$settings_path"
+        setup_notify_hooks "$settings_path" ||
+          setup_sound_notification "$settings_path" "$(sound_hook_command "$sound_name")"
         ;;
     esac
     success "Sound notifications enabled"
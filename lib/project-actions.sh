@@ -1,9 +1,9 @@
 #!/bin/bash
 # Project file operations — add, delete, validate.
 
-# Append a project entry to the projects file (creates parent dirs).
+# Add a project entry to the structured projects file (creates parent dirs
+# and migrates a legacy name:path file).
 add_project_to_file() {
   local name="$1" path="$2" projects_file="$3"
-  mkdir -p "$(dirname "$projects_file")"
-  echo "${name}:${path}" >> "$projects_file"
+  ghost-tab-tui project add --projects-file "$projects_file" --name "$name" --path "$path" >/dev/null
 }
@@ -1,5 +1,6 @@
 #!/bin/bash
-# Claude settings.json manipulation helpers.
+# Claude settings.json manipulation helpers. Hooks are edited through
+# `ghost-tab-tui hooks`.
 
 # Merge statusLine into Claude settings.json (create if missing).
 merge_claude_settings() {
@@ -27,68 +28,19 @@ CSEOF
 
 # Add a sound notification hook (Stop event) to settings.json.
 # Migrates old Notification.idle_prompt hooks to Stop.
+# Outputs "added" or "exists".
 add_sound_notification_hook() {
   local path="$1" command="$2"
-  mkdir -p "$(dirname "$path")"
-  _GT_HOOK_CMD="$command" python3 - "$path" << 'PYEOF'
-import json, sys, os
-
-settings_path = sys.argv[1]
-hook_cmd = os.environ["_GT_HOOK_CMD"]
-
-# Load existing settings or start fresh
-if os.path.exists(settings_path):
-    try:
-        with open(settings_path, "r") as f:
-            settings = json.load(f)
-    except (json.JSONDecodeError, ValueError):
-        settings = {}
-else:
-    settings = {}
-
-hooks = settings.setdefault("hooks", {})
-
-# Migrate: remove old Notification.idle_prompt entries
-if "Notification" in hooks:
-    hooks["Notification"] = [
-        entry for entry in hooks["Notification"]
-        if entry.get("matcher") != "idle_prompt"
-    ]
-    if not hooks["Notification"]:
-        del hooks["Notification"]
-
-# Build the Stop hook entry
-new_hook_entry = {
-    "hooks": [
-        {
-            "type": "command",
-            "command": hook_cmd
-        }
-    ]
-}
-
-# Ensure hooks.Stop exists
-stop_list = hooks.setdefault("Stop", [])
-
-# Check if a sound hook already exists in Stop
-already_exists = any(
-    hook_cmd in h.get("command", "")
-    for entry in stop_list
-    for h in entry.get("hooks", [])
-)
-
-if not already_exists:
-    stop_list.append(new_hook_entry)
-    with open(settings_path, "w") as f:
-        json.dump(settings, f, indent=2)
-        f.write("\n")
-    print("added")
-else:
-    print("exists")
-PYEOF
+  if ! command -v ghost-tab-tui &>/dev/null; then
+    error "ghost-tab-tui binary not found. Please reinstall." >&2
+    return 1
+  fi
+  ghost-tab-tui hooks add --settings "$path" --event Stop --command "$command" || return 1
+  ghost-tab-tui hooks migrate --settings "$path" >/dev/null
 }
 
-# Remove a sound notification hook (Stop event) from settings.json.
+# Remove sound notification hooks (Stop event) from settings.json: every
+# Stop hook whose command contains the given command.
 # Outputs "removed" or "not_found".
 remove_sound_notification_hook() {
   local path="$1" command="$2"
@@ -96,48 +48,9 @@ remove_sound_notification_hook() {
     echo "not_found"
     return 0
   fi
-  _GT_HOOK_CMD="$command" python3 - "$path" << 'PYEOF'
-import json, sys, os
-
-settings_path = sys.argv[1]
-hook_cmd = os.environ["_GT_HOOK_CMD"]
-
-try:
-    with open(settings_path, "r") as f:
-        settings = json.load(f)
-except (json.JSONDecodeError, ValueError, FileNotFoundError):
-    print("not_found")
-    sys.exit(0)
-
-hooks = settings.get("hooks", {})
-stop_list = hooks.get("Stop", [])
-
-if not stop_list:
-    print("not_found")
-    sys.exit(0)
-
-# Filter out entries containing the matching command
-new_stop = [
-    entry for entry in stop_list
-    if not any(hook_cmd in h.get("command", "") for h in entry.get("hooks", []))
-]
-
-if len(new_stop) == len(stop_list):
-    print("not_found")
-    sys.exit(0)
-
-if new_stop:
-    hooks["Stop"] = new_stop
-else:
-    del hooks["Stop"]
-
-if not hooks:
-    del settings["hooks"]
-
-with open(settings_path, "w") as f:
-    json.dump(settings, f, indent=2)
-    f.write("\n")
-
-print("removed")
-PYEOF
+  if ! command -v ghost-tab-tui &>/dev/null; then
+    error "ghost-tab-tui binary not found. Please reinstall." >&2
+    return 1
+  fi
+  ghost-tab-tui hooks remove --settings "$path" --event Stop --contains "$command"
 }
@@ -1,6 +1,5 @@
 #!/bin/bash
-# Tmux session helpers — build launch command, cleanup.
-# Depends on: process.sh (kill_tree)
+# Tmux session helpers — build launch command.
 
 # Build the AI tool launch command string.
 # Usage: build_ai_launch_cmd <tool> <claude_cmd> <codex_cmd> <copilot_cmd> <opencode_cmd> [extra_args_or_project_dir]
@@ -29,21 +28,3 @@ build_ai_launch_cmd() {
   esac
 }
 
-# Clean up a tmux session: kill watcher, TERM pane trees, KILL survivors, destroy session.
-cleanup_tmux_session() {
-  local session_name="$1" watcher_pid="$2" tmux_cmd="$3"
-
-  kill "$watcher_pid" 2>/dev/null || true
-
-  local pane_pid
-  for pane_pid in $("$tmux_cmd" list-panes -s -t "$session_name" -F '#{pane_pid}' 2>/dev/null); do
-    kill_tree "$pane_pid" TERM
-  done
-
-  sleep 0.3
-  for pane_pid in $("$tmux_cmd" list-panes -s -t "$session_name" -F '#{pane_pid}' 2>/dev/null); do
-    kill_tree "$pane_pid" KILL
-  done
-
-  "$tmux_cmd" kill-session -t "$session_name" 2>/dev/null || true
-}
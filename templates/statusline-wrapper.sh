@@ -1,4 +1,10 @@
 #!/bin/bash
+# Prefer the native status line: one process instead of bash, git, ps and
+# bc on every refresh.
+if command -v ghost-tab-tui &>/dev/null; then
+  exec ghost-tab-tui statusline
+fi
+
 # shellcheck source=../lib/statusline.sh
 source "$(dirname "$0")/../lib/statusline.sh" 2>/dev/null \
   || source ~/.claude/statusline-helpers.sh 2>/dev/null \
@@ -6,7 +12,10 @@ source "$(dirname "$0")/../lib/statusline.sh" 2>/dev/null \
 
 input=$(cat)
 git_info=$(echo "$input" | bash ~/.claude/statusline-command.sh)
-context_pct=$(echo "$input" | npx ccstatusline 2>/dev/null)
+context_pct=""
+if command -v ccstatusline &>/dev/null; then
+  context_pct=$(echo "$input" | ccstatusline 2>/dev/null)
+fi
 
 # Find parent Claude Code process and get total tree memory usage
 pid=$PPID
@@ -33,8 +42,11 @@ while [ -n "$pid" ] && [ "$pid" != "1" ]; do
   pid=$(ps -o ppid= -p "$pid" 2>/dev/null | tr -d ' ')
 done
 
+line="$git_info"
+if [ -n "$context_pct" ]; then
+  line="$line | $context_pct"
+fi
 if [ -n "$mem_label" ]; then
-  printf '%s | %s | \033[01;35m%s\033[00m' "$git_info" "$context_pct" "$mem_label"
-else
-  printf '%s | %s' "$git_info" "$context_pct"
+  line="$line | $(printf '\033[01;35m%s\033[00m' "$mem_label")"
 fi
+printf '%s' "$line"
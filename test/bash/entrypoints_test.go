@@ -407,7 +407,7 @@ func createWrapperTestEnv(t *testing.T) (tmpDir, wrapperDir, binDir, shareDir st
 
 	// Copy real lib files for sourcing
 	libFiles := []string{
-		"tui.sh", "ai-tools.sh", "projects.sh", "process.sh", "input.sh",
+		"tui.sh", "ai-tools.sh", "projects.sh", "input.sh",
 		"update.sh", "menu-tui.sh", "project-actions.sh", "project-actions-tui.sh",
 		"tmux-session.sh", "settings-menu-tui.sh",
 	}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -22,7 +23,7 @@ func projectRoot(t *testing.T) string {
 }
 
 // runBashFunc sources a lib module and calls a function, returning stdout and exit code.
-// The module path is relative to the project root (e.g., "lib/process.sh").
+// The module path is relative to the project root (e.g., "lib/projects.sh").
 // Environment variables can be passed via env parameter (nil for default).
 func runBashFunc(t *testing.T, module string, funcName string, args []string, env []string) (string, int) {
 	t.Helper()
@@ -202,6 +203,51 @@ func buildEnv(t *testing.T, mockDirs []string, extra ...string) []string {
 	return env
 }
 
+var (
+	ghostTabTUIOnce sync.Once
+	ghostTabTUIDir  string
+	ghostTabTUIErr  error
+)
+
+// ghostTabTUIBin builds the real ghost-tab-tui binary once per test run and
+// returns the directory holding it (caller should prepend to PATH), for
+// scripts that edit files through it.
+func ghostTabTUIBin(t *testing.T) string {
+	t.Helper()
+	ghostTabTUIOnce.Do(func() {
+		ghostTabTUIDir, ghostTabTUIErr = os.MkdirTemp("", "ghost-tab-tui-bin")
+		if ghostTabTUIErr != nil {
+			return
+		}
+		cmd := exec.Command("go", "build", "-o", filepath.Join(ghostTabTUIDir, "ghost-tab-tui"), "./cmd/ghost-tab-tui")
+		cmd.Dir = projectRoot(t)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			ghostTabTUIErr = fmt.Errorf("%v\n%s", err, out)
+		}
+	})
+	if ghostTabTUIErr != nil {
+		t.Fatalf("building ghost-tab-tui: %v", ghostTabTUIErr)
+	}
+	return ghostTabTUIDir
+}
+
+// mockGhostTabTUI is mockCommand for ghost-tab-tui, except that the hooks
+// subcommand runs the real binary so settings.json is really edited.
+func mockGhostTabTUI(t *testing.T, dir string, body string) string {
+	t.Helper()
+	real := filepath.Join(ghostTabTUIBin(t), "ghost-tab-tui")
+	return mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf("[ \"$1\" = hooks ] && exec %q \"$@\"\n%s", real, body))
+}
+
+// TestMain removes the ghost-tab-tui binary built for the tests.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if ghostTabTUIDir != "" {
+		os.RemoveAll(ghostTabTUIDir)
+	}
+	os.Exit(code)
+}
+
 // assertContains checks that output contains the expected substring.
 func assertContains(t *testing.T, output, expected string) {
 	t.Helper()
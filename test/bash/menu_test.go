@@ -644,6 +644,101 @@ select_project_interactive %q
 	}
 }
 
+func TestMenu_project_ai_tool_overrides_without_persisting(t *testing.T) {
+	dir := t.TempDir()
+	binDir := mockCommand(t, dir, "ghost-tab-tui", `echo '{"action":"select-project","name":"proj1","path":"/tmp/p1","ai_tool":"claude","project_ai_tool":"codex"}'`)
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	configDir := filepath.Join(dir, "config", "ghost-tab")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude" "codex")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q
+echo "ai_tool=$_selected_ai_tool"
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	out, code := runBashSnippet(t, script, env)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "ai_tool=codex")
+
+	aiToolFile := filepath.Join(dir, "config", "ghost-tab", "ai-tool")
+	if _, err := os.Stat(aiToolFile); err == nil {
+		t.Error("a project's default AI tool should not change the saved preference")
+	}
+}
+
+func TestMenu_sets_selected_ai_profile(t *testing.T) {
+	dir := t.TempDir()
+	binDir := mockCommand(t, dir, "ghost-tab-tui", `echo '{"action":"select-project","name":"proj1","path":"/tmp/p1","ai_tool":"claude","profile":"yolo"}'`)
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude" "codex")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q
+echo "profile=$_selected_ai_profile"
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	out, code := runBashSnippet(t, script, env)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "profile=yolo")
+}
+
+func TestMenu_sets_selected_agents(t *testing.T) {
+	dir := t.TempDir()
+	binDir := mockCommand(t, dir, "ghost-tab-tui", `echo '{"action":"select-project","name":"proj1","path":"/tmp/p1","ai_tool":"claude","agents":[{"tool":"codex","path":"/tmp/p1"},{"tool":"claude","path":"/tmp/p1 wt"}]}'`)
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude" "codex")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q
+echo "ai_tool=$_selected_ai_tool"
+printf 'agent=%%s\n' "${_selected_agents[@]}"
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	out, code := runBashSnippet(t, script, env)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "ai_tool=codex")
+	assertContains(t, out, "agent=codex=/tmp/p1\n")
+	assertContains(t, out, "agent=claude=/tmp/p1 wt\n")
+}
+
 func TestMenu_sets_selected_ai_tool_for_settings_action(t *testing.T) {
 	dir := t.TempDir()
 	binDir := mockCommand(t, dir, "ghost-tab-tui", `echo '{"action":"settings","ai_tool":"codex"}'`)
@@ -1071,6 +1166,50 @@ echo "tool=$SELECTED_AI_TOOL"
 	}
 }
 
+func TestAITools_detect_reads_installed_tools_from_registry(t *testing.T) {
+	dir := t.TempDir()
+	binDir := mockCommand(t, dir, "ghost-tab-tui", `
+if [[ "$1 $2" == "tools --installed" ]]; then
+  printf 'claude\naider tool\n'
+fi
+`)
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir})
+
+	script := fmt.Sprintf(`
+source %q
+AI_TOOLS_AVAILABLE=("stale")
+detect_ai_tools
+echo "count=${#AI_TOOLS_AVAILABLE[@]}"
+printf 'tool=%%s\n' "${AI_TOOLS_AVAILABLE[@]}"
+`, filepath.Join(root, "lib/ai-tools.sh"))
+
+	out, code := runBashSnippet(t, script, env)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "count=2")
+	assertContains(t, out, "tool=claude")
+	assertContains(t, out, "tool=aider tool")
+}
+
+func TestAITools_detect_empty_when_tui_fails(t *testing.T) {
+	dir := t.TempDir()
+	binDir := mockCommand(t, dir, "ghost-tab-tui", `exit 1`)
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir})
+
+	script := fmt.Sprintf(`
+source %q
+detect_ai_tools
+echo "count=${#AI_TOOLS_AVAILABLE[@]}"
+`, filepath.Join(root, "lib/ai-tools.sh"))
+
+	out, code := runBashSnippet(t, script, env)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "count=0")
+}
+
 // ---------- settings-menu-tui.sh tests (TestSettingsMenu_*) ----------
 
 func TestSettingsMenu_calls_ghost_tab_tui_and_parses_JSON(t *testing.T) {
@@ -1219,3 +1358,270 @@ settings_menu_interactive
 		t.Errorf("expected empty output for empty action, got %q", strings.TrimSpace(out))
 	}
 }
+
+func TestMenu_passes_collapsed_groups_from_settings(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "captured_args")
+	binDir := mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf(`
+echo "$*" > %q
+echo '{"action":"quit"}'
+`, argsFile))
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	writeTempFile(t, dir, "config/ghost-tab/settings", "collapsed_groups=client work,oss\n")
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q || true
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	_, _ = runBashSnippet(t, script, env)
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("args file not found: %v", err)
+	}
+	assertContains(t, string(data), "--collapsed-groups client work,oss")
+}
+
+func TestMenu_omits_collapsed_groups_when_unset(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "captured_args")
+	binDir := mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf(`
+echo "$*" > %q
+echo '{"action":"quit"}'
+`, argsFile))
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q || true
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	_, _ = runBashSnippet(t, script, env)
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("args file not found: %v", err)
+	}
+	if strings.Contains(string(data), "--collapsed-groups") {
+		t.Errorf("expected no --collapsed-groups flag, got %q", string(data))
+	}
+}
+
+func TestMenu_passes_project_sort_and_history_file(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "captured_args")
+	binDir := mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf(`
+echo "$*" > %q
+echo '{"action":"quit"}'
+`, argsFile))
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	writeTempFile(t, dir, "config/ghost-tab/settings", "project_sort=frequent\n")
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q || true
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	_, _ = runBashSnippet(t, script, env)
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("args file not found: %v", err)
+	}
+	assertContains(t, string(data), "--project-sort frequent")
+	assertContains(t, string(data), "--history-file "+filepath.Join(dir, "config/ghost-tab/history.jsonl"))
+}
+
+func TestMenu_project_sort_defaults_to_manual(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "captured_args")
+	binDir := mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf(`
+echo "$*" > %q
+echo '{"action":"quit"}'
+`, argsFile))
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q || true
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	_, _ = runBashSnippet(t, script, env)
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("args file not found: %v", err)
+	}
+	assertContains(t, string(data), "--project-sort manual")
+}
+
+func TestMenu_import_projects_passes_scan_settings(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "captured_args")
+	binDir := mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf(`
+if [ "$1" = "discover" ]; then
+  echo "$*" > %q
+  echo '{"confirmed":false}'
+  exit 0
+fi
+echo '{"action":"import-projects"}'
+`, argsFile))
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	writeTempFile(t, dir, "config/ghost-tab/settings", "scan_roots=~/work,~/oss\nscan_depth=2\n")
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q
+echo "rc=$? action=$_selected_project_action"
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	out, _ := runBashSnippet(t, script, env)
+	assertContains(t, out, "rc=0 action=import-projects")
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("discover was not run: %v", err)
+	}
+	assertContains(t, string(data), "discover --projects-file "+projectsFile)
+	assertContains(t, string(data), "--root ~/work,~/oss")
+	assertContains(t, string(data), "--max-depth 2")
+}
+
+func TestMenu_import_projects_uses_default_scan_roots(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "captured_args")
+	binDir := mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf(`
+if [ "$1" = "discover" ]; then
+  echo "$*" > %q
+  exit 0
+fi
+echo '{"action":"import-projects"}'
+`, argsFile))
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q || true
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	_, _ = runBashSnippet(t, script, env)
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("discover was not run: %v", err)
+	}
+	if strings.Contains(string(data), "--root") || strings.Contains(string(data), "--max-depth") {
+		t.Errorf("expected no scan flags without settings, got %q", string(data))
+	}
+}
+
+func TestMenu_passes_git_status_cache(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "captured_args")
+	binDir := mockCommand(t, dir, "ghost-tab-tui", fmt.Sprintf(`
+echo "$*" > %q
+echo '{"action":"quit"}'
+`, argsFile))
+	projectsFile := writeTempFile(t, dir, "projects", "proj1:/tmp/p1\n")
+
+	root := projectRoot(t)
+	env := buildEnv(t, []string{binDir},
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+	)
+
+	script := fmt.Sprintf(`
+source %q 2>/dev/null || true
+source %q
+error() { echo "ERROR: $*" >&2; }
+AI_TOOLS_AVAILABLE=("claude")
+SELECTED_AI_TOOL="claude"
+_update_version=""
+select_project_interactive %q || true
+`, filepath.Join(root, "lib/tui.sh"),
+		filepath.Join(root, "lib/menu-tui.sh"),
+		projectsFile)
+
+	_, _ = runBashSnippet(t, script, env)
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("args file not found: %v", err)
+	}
+	assertContains(t, string(data), "--git-status-cache "+filepath.Join(dir, "config/ghost-tab/git-status.json"))
+}
@@ -40,7 +40,7 @@ func TestNotification_setup_sound_notification_adds_Stop_hook_to_empty_settings(
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`setup_sound_notification %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "configured")
 
@@ -69,7 +69,7 @@ func TestNotification_setup_sound_notification_reports_already_exists(t *testing
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`setup_sound_notification %q "afplay sound &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "already configured")
 }
@@ -81,7 +81,7 @@ func TestNotification_setup_sound_notification_creates_file_when_missing(t *test
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`setup_sound_notification %q "afplay sound &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "configured")
 
@@ -166,7 +166,7 @@ func TestNotification_remove_sound_notification_removes_Stop_hook(t *testing.T)
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`remove_sound_notification %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "removed")
 
@@ -184,7 +184,7 @@ func TestNotification_remove_sound_notification_noop_when_hook_not_present(t *te
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`remove_sound_notification %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "not_found")
 }
@@ -208,7 +208,7 @@ func TestNotification_remove_sound_notification_removes_matching_keeps_others(t
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`remove_sound_notification %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "removed")
 
@@ -418,7 +418,9 @@ func TestNotification_toggle_sound_notification_enables_for_claude(t *testing.T)
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`toggle_sound_notification "claude" %q %q`, configDir, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	// Only the hooks subcommand works, so the raw sound hook is written
+	binDir := mockGhostTabTUI(t, tmpDir, `exit 1`)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{binDir}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "enabled")
 
@@ -459,7 +461,9 @@ func TestNotification_toggle_sound_notification_disables_for_claude(t *testing.T
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`toggle_sound_notification "claude" %q %q`, configDir, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	// Only the hooks subcommand works, so the raw sound hook is written
+	binDir := mockGhostTabTUI(t, tmpDir, `exit 1`)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{binDir}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "disabled")
 
@@ -493,7 +497,9 @@ func TestNotification_apply_sound_notification_enables_with_custom_sound(t *test
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`apply_sound_notification "claude" %q %q "Glass"`, configDir, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	// Only the hooks subcommand works, so the raw sound hook is written
+	binDir := mockGhostTabTUI(t, tmpDir, `exit 1`)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{binDir}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "enabled")
 
@@ -534,7 +540,9 @@ func TestNotification_apply_sound_notification_disables_sound(t *testing.T) {
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`apply_sound_notification "claude" %q %q ""`, configDir, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	// Only the hooks subcommand works, so the raw sound hook is written
+	binDir := mockGhostTabTUI(t, tmpDir, `exit 1`)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{binDir}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "disabled")
 
@@ -556,6 +564,44 @@ func TestNotification_apply_sound_notification_disables_sound(t *testing.T) {
 	assertNotContains(t, string(data), "afplay")
 }
 
+func TestNotification_disabling_sound_removes_notify_hooks(t *testing.T) {
+	const settings = `{
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "ghost-tab-tui notify"}]},
+      {"hooks": [{"type": "command", "command": "echo done"}]}
+    ],
+    "Notification": [{"hooks": [{"type": "command", "command": "ghost-tab-tui notify"}]}],
+    "UserPromptSubmit": [{"hooks": [{"type": "command", "command": "ghost-tab-tui notify"}]}]
+  }
+}
+`
+	for name, disable := range map[string]string{
+		"apply":  `apply_sound_notification "claude" %q %q ""`,
+		"toggle": `toggle_sound_notification "claude" %q %q`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configDir := filepath.Join(tmpDir, "config")
+			os.MkdirAll(configDir, 0755)
+			writeTempFile(t, configDir, "claude-features.json", `{"sound": true, "sound_name": "Glass"}`)
+			settingsFile := writeTempFile(t, tmpDir, "settings.json", settings)
+
+			snippet := notificationSnippet(t, fmt.Sprintf(disable, configDir, settingsFile))
+			out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
+			assertExitCode(t, code, 0)
+			assertContains(t, out, "disabled")
+
+			data, err := os.ReadFile(settingsFile)
+			if err != nil {
+				t.Fatalf("failed to read settings.json: %v", err)
+			}
+			assertNotContains(t, string(data), "ghost-tab-tui notify")
+			assertContains(t, string(data), "echo done")
+		})
+	}
+}
+
 func TestNotification_apply_sound_notification_changes_sound(t *testing.T) {
 	tmpDir := t.TempDir()
 	configDir := filepath.Join(tmpDir, "config")
@@ -575,7 +621,9 @@ func TestNotification_apply_sound_notification_changes_sound(t *testing.T) {
 	snippet := notificationSnippet(t,
 		fmt.Sprintf(`apply_sound_notification "claude" %q %q "Ping"`, configDir, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	// Only the hooks subcommand works, so the raw sound hook is written
+	binDir := mockGhostTabTUI(t, tmpDir, `exit 1`)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{binDir}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "enabled")
 
@@ -588,6 +636,155 @@ func TestNotification_apply_sound_notification_changes_sound(t *testing.T) {
 	assertNotContains(t, string(data), "Bottle.aiff")
 }
 
+func TestNotification_apply_sound_notification_uses_platform_hook_command(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	os.MkdirAll(configDir, 0755)
+	binDir := mockGhostTabTUI(t, tmpDir, `
+[ "$1 $2" = "sounds --hook-command" ] || exit 1
+echo "paplay /usr/share/sounds/freedesktop/stereo/$3.oga &"
+`)
+	settingsFile := writeTempFile(t, tmpDir, "settings.json", `{
+  "hooks": {
+    "Stop": [
+      {
+        "hooks": [{"type": "command", "command": "paplay /usr/share/sounds/freedesktop/stereo/bell.oga &"}]
+      }
+    ]
+  }
+}
+`)
+
+	snippet := notificationSnippet(t,
+		fmt.Sprintf(`apply_sound_notification "claude" %q %q "complete"`, configDir, settingsFile))
+
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{binDir}))
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "enabled")
+
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		t.Fatalf("failed to read settings.json: %v", err)
+	}
+	assertContains(t, string(data), "paplay /usr/share/sounds/freedesktop/stereo/complete.oga &")
+	assertNotContains(t, string(data), "bell.oga")
+	assertNotContains(t, string(data), "afplay")
+}
+
+func TestNotification_apply_sound_notification_installs_notify_hooks(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	os.MkdirAll(configDir, 0755)
+	argsFile := filepath.Join(tmpDir, "args")
+	binDir := mockGhostTabTUI(t, tmpDir, fmt.Sprintf(`
+echo "$*" > %q
+echo added
+`, argsFile))
+	settingsFile := writeTempFile(t, tmpDir, "settings.json", `{
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "afplay /System/Library/Sounds/Bottle.aiff &"}]}
+    ]
+  }
+}
+`)
+
+	snippet := notificationSnippet(t,
+		fmt.Sprintf(`apply_sound_notification "claude" %q %q "Glass"`, configDir, settingsFile))
+
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{binDir}))
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "Notifications configured")
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("ghost-tab-tui was not called: %v", err)
+	}
+	assertContains(t, string(args), "notify --install --settings "+settingsFile)
+
+	// The raw player hook is replaced; notify reads the sound name itself
+	data, _ := os.ReadFile(settingsFile)
+	assertNotContains(t, string(data), "afplay")
+	features, _ := os.ReadFile(filepath.Join(configDir, "claude-features.json"))
+	assertContains(t, string(features), "Glass")
+}
+
+// --- sound_hook_command ---
+
+func TestNotification_sound_hook_command_falls_back_to_afplay(t *testing.T) {
+	tmpDir := t.TempDir()
+	binDir := mockCommand(t, tmpDir, "ghost-tab-tui", `exit 1`)
+
+	out, code := runBashSnippet(t, notificationSnippet(t, `sound_hook_command "Glass"`),
+		buildEnv(t, []string{binDir}))
+	assertExitCode(t, code, 0)
+	if strings.TrimSpace(out) != "afplay /System/Library/Sounds/Glass.aiff &" {
+		t.Errorf("expected afplay fallback, got %q", strings.TrimSpace(out))
+	}
+}
+
+// --- remove_sound_hooks ---
+
+func TestNotification_remove_sound_hooks_removes_every_player_keeps_others(t *testing.T) {
+	tmpDir := t.TempDir()
+	settingsFile := writeTempFile(t, tmpDir, "settings.json", `{
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "afplay /System/Library/Sounds/Bottle.aiff &"}]},
+      {"hooks": [{"type": "command", "command": "pw-play /usr/share/sounds/freedesktop/stereo/bell.oga &"}]},
+      {"hooks": [{"type": "command", "command": "aplay -q /home/me/.local/share/sounds/ding.wav &"}]},
+      {"hooks": [{"type": "command", "command": "echo done"}]},
+      {"hooks": [{"type": "command", "command": "cd ~/proj && paplay ~/done.wav"}]}
+    ]
+  }
+}
+`)
+
+	_, code := runBashSnippet(t, notificationSnippet(t,
+		fmt.Sprintf(`remove_sound_hooks %q`, settingsFile)), buildEnv(t, []string{ghostTabTUIBin(t)}))
+	assertExitCode(t, code, 0)
+
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		t.Fatalf("failed to read settings.json: %v", err)
+	}
+	content := string(data)
+	assertNotContains(t, content, "afplay")
+	assertNotContains(t, content, "pw-play")
+	assertNotContains(t, content, "aplay -q")
+	assertContains(t, content, "echo done")
+	assertContains(t, content, "cd ~/proj && paplay ~/done.wav")
+}
+
+func TestNotification_remove_sound_hooks_removes_user_library_sounds(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	settingsFile := writeTempFile(t, tmpDir, "settings.json", fmt.Sprintf(`{
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "afplay %[1]s/Library/Sounds/Chime.aiff &"}]},
+      {"hooks": [{"type": "command", "command": "afplay '/Library/Sounds/Soft Bell.aiff' &"}]},
+      {"hooks": [{"type": "command", "command": "afplay %[1]s/Music/song.mp3"}]}
+    ]
+  }
+}
+`, home))
+
+	_, code := runBashSnippet(t, notificationSnippet(t,
+		fmt.Sprintf(`remove_sound_hooks %q`, settingsFile)),
+		buildEnv(t, []string{ghostTabTUIBin(t)}, "HOME="+home))
+	assertExitCode(t, code, 0)
+
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		t.Fatalf("failed to read settings.json: %v", err)
+	}
+	content := string(data)
+	assertNotContains(t, content, "Chime.aiff")
+	assertNotContains(t, content, "Soft Bell.aiff")
+	assertContains(t, content, "song.mp3")
+}
+
 // ==================== update.sh tests ====================
 
 // --- check_for_update: no brew ---
@@ -35,7 +35,7 @@ func TestSettingsJson_add_sound_notification_hook_migrates_old_notification_idle
 	snippet := settingsJsonSnippet(t,
 		fmt.Sprintf(`add_sound_notification_hook %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "added")
 
@@ -70,7 +70,7 @@ func TestSettingsJson_add_sound_notification_hook_migration_preserves_other_noti
 	snippet := settingsJsonSnippet(t,
 		fmt.Sprintf(`add_sound_notification_hook %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "added")
 
@@ -94,7 +94,7 @@ func TestSettingsJson_add_sound_notification_hook_creates_file_when_missing(t *t
 	snippet := settingsJsonSnippet(t,
 		fmt.Sprintf(`add_sound_notification_hook %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, out, "added")
 
@@ -123,7 +123,7 @@ func TestSettingsJson_add_sound_notification_hook_reports_exists_when_duplicate(
 	snippet := settingsJsonSnippet(t,
 		fmt.Sprintf(`add_sound_notification_hook %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, strings.TrimSpace(out), "exists")
 }
@@ -144,7 +144,7 @@ func TestSettingsJson_remove_sound_notification_hook_removes_existing_hook(t *te
 	snippet := settingsJsonSnippet(t,
 		fmt.Sprintf(`remove_sound_notification_hook %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, strings.TrimSpace(out), "removed")
 
@@ -163,7 +163,7 @@ func TestSettingsJson_remove_sound_notification_hook_returns_not_found_for_missi
 	snippet := settingsJsonSnippet(t,
 		fmt.Sprintf(`remove_sound_notification_hook %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, strings.TrimSpace(out), "not_found")
 }
@@ -184,7 +184,7 @@ func TestSettingsJson_remove_sound_notification_hook_returns_not_found_when_no_m
 	snippet := settingsJsonSnippet(t,
 		fmt.Sprintf(`remove_sound_notification_hook %q "afplay /System/Library/Sounds/Bottle.aiff &"`, settingsFile))
 
-	out, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, buildEnv(t, []string{ghostTabTUIBin(t)}))
 	assertExitCode(t, code, 0)
 	assertContains(t, strings.TrimSpace(out), "not_found")
 }
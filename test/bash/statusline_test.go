@@ -330,6 +330,24 @@ func TestStatusline_statusline_command_non_git_directory_shows_just_dirname(t *t
 	assertNotContains(t, out, "/ -")
 }
 
+// --- statusline-wrapper.sh ---
+
+func TestStatusline_wrapper_uses_native_statusline_when_available(t *testing.T) {
+	dir := t.TempDir()
+	mockCommand(t, dir, "ghost-tab-tui", `printf 'native %s: %s' "$1" "$(cat)"`)
+	mockCommand(t, dir, "npx", `echo "npx should not run"; exit 1`)
+
+	root := projectRoot(t)
+	wrapperPath := filepath.Join(root, "templates", "statusline-wrapper.sh")
+	script := fmt.Sprintf(`echo '{"cwd":"/tmp"}' | bash '%s'`, wrapperPath)
+
+	env := buildEnv(t, []string{filepath.Join(dir, "bin")})
+	out, code := runBashSnippet(t, script, env)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, `native statusline: {"cwd":"/tmp"}`)
+	assertNotContains(t, out, "npx")
+}
+
 // ============================================================
 // statusline-setup.sh tests (TestStatuslineSetup_*)
 // ============================================================
@@ -353,15 +371,11 @@ func setupStatuslineTestDirs(t *testing.T) (string, string) {
 	tmpDir := t.TempDir()
 
 	shareDir := filepath.Join(tmpDir, "share")
-	writeTempFile(t, shareDir, "templates/ccstatusline-settings.json", "mock-settings")
 	writeTempFile(t, shareDir, "templates/statusline-command.sh", "mock-command")
 	writeTempFile(t, shareDir, "templates/statusline-wrapper.sh", "mock-wrapper")
 	writeTempFile(t, shareDir, "lib/statusline.sh", "mock-helpers")
 
 	fakeHome := filepath.Join(tmpDir, "home")
-	if err := os.MkdirAll(filepath.Join(fakeHome, ".config", "ccstatusline"), 0755); err != nil {
-		t.Fatalf("mkdir: %v", err)
-	}
 	if err := os.MkdirAll(filepath.Join(fakeHome, ".claude"), 0755); err != nil {
 		t.Fatalf("mkdir: %v", err)
 	}
@@ -369,27 +383,25 @@ func setupStatuslineTestDirs(t *testing.T) (string, string) {
 	return shareDir, fakeHome
 }
 
-func TestStatuslineSetup_copies_config_and_scripts_when_npm_available(t *testing.T) {
+func TestStatuslineSetup_copies_scripts(t *testing.T) {
 	shareDir, fakeHome := setupStatuslineTestDirs(t)
 
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() { return 0; }
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
+	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`setup_statusline %q %q %q`,
+		shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
 
-	_, code := runBashSnippet(t, snippet, nil)
+	out, code := runBashSnippet(t, snippet, nil)
 	assertExitCode(t, code, 0)
+	assertContains(t, out, "Created statusline scripts")
 
 	// Verify files were copied
-	for _, path := range []string{
-		filepath.Join(fakeHome, ".config", "ccstatusline", "settings.json"),
-		filepath.Join(fakeHome, ".claude", "statusline-command.sh"),
-		filepath.Join(fakeHome, ".claude", "statusline-wrapper.sh"),
-		filepath.Join(fakeHome, ".claude", "statusline-helpers.sh"),
+	for name, want := range map[string]string{
+		"statusline-command.sh": "mock-command",
+		"statusline-wrapper.sh": "mock-wrapper",
+		"statusline-helpers.sh": "mock-helpers",
 	} {
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			t.Errorf("expected file to exist: %s", path)
+		data, err := os.ReadFile(filepath.Join(fakeHome, ".claude", name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, want)
 		}
 	}
 
@@ -406,97 +418,24 @@ setup_statusline %q %q %q
 	}
 }
 
-func TestStatuslineSetup_skips_when_npm_not_available_and_brew_fails(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 1; }
-brew() { return 1; }
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	_, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-
-	if _, err := os.Stat(filepath.Join(fakeHome, ".claude", "statusline-command.sh")); !os.IsNotExist(err) {
-		t.Error("statusline-command.sh should not exist when npm not available and brew fails")
-	}
-}
-
-func TestStatuslineSetup_reports_already_installed(t *testing.T) {
+func TestStatuslineSetup_does_not_install_node_or_ccstatusline(t *testing.T) {
 	shareDir, fakeHome := setupStatuslineTestDirs(t)
+	dir := t.TempDir()
+	mockCommand(t, dir, "brew", `echo "brew $*"`)
+	mockCommand(t, dir, "npm", `echo "npm $*"`)
+	mockCommand(t, dir, "npx", `echo "npx $*"`)
 
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$1" == "list" ]]; then return 0; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "already installed")
-}
-
-func TestStatuslineSetup_warns_and_skips_when_npm_install_fails(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
+	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`setup_statusline %q %q %q`,
+		shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
 
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$1" == "list" ]]; then return 1; fi
-  if [[ "$1" == "install" ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
+	env := buildEnv(t, []string{filepath.Join(dir, "bin")})
+	out, code := runBashSnippet(t, snippet, env)
 	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-
-	if _, err := os.Stat(filepath.Join(fakeHome, ".claude", "statusline-command.sh")); !os.IsNotExist(err) {
-		t.Error("statusline-command.sh should not exist when npm install fails")
+	for _, cmd := range []string{"brew", "npm", "npx", "Node.js", "ccstatusline"} {
+		assertNotContains(t, out, cmd)
 	}
-	if _, err := os.Stat(filepath.Join(fakeHome, ".claude", "statusline-wrapper.sh")); !os.IsNotExist(err) {
-		t.Error("statusline-wrapper.sh should not exist when npm install fails")
-	}
-}
-
-func TestStatuslineSetup_installs_ccstatusline_and_copies_files_on_fresh_install(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	// First npm list call returns 1 (not installed), subsequent calls return 0
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-_npm_list_call_count=0
-npm() {
-  if [[ "$1" == "list" ]]; then
-    _npm_list_call_count=$((_npm_list_call_count + 1))
-    if [[ "$_npm_list_call_count" -eq 1 ]]; then return 1; fi
-    return 0
-  fi
-  if [[ "$1" == "install" ]]; then return 0; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "ccstatusline installed")
-	assertNotContains(t, out, "already installed")
-
-	for _, path := range []string{
-		filepath.Join(fakeHome, ".config", "ccstatusline", "settings.json"),
-		filepath.Join(fakeHome, ".claude", "statusline-command.sh"),
-		filepath.Join(fakeHome, ".claude", "statusline-wrapper.sh"),
-	} {
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			t.Errorf("expected file to exist: %s", path)
-		}
+	if _, err := os.Stat(filepath.Join(fakeHome, ".config", "ccstatusline")); !os.IsNotExist(err) {
+		t.Error("no ccstatusline config should be created")
 	}
 }
 
@@ -504,11 +443,8 @@ func TestStatuslineSetup_calls_merge_claude_settings_after_file_copy(t *testing.
 	shareDir, fakeHome := setupStatuslineTestDirs(t)
 	claudeSettings := filepath.Join(t.TempDir(), "claude-settings", "settings.json")
 
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() { return 0; }
-setup_statusline %q %q %q
-`, shareDir, claudeSettings, fakeHome))
+	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`setup_statusline %q %q %q`,
+		shareDir, claudeSettings, fakeHome))
 
 	_, code := runBashSnippet(t, snippet, nil)
 	assertExitCode(t, code, 0)
@@ -524,387 +460,52 @@ setup_statusline %q %q %q
 	assertContains(t, string(data), `"statusLine"`)
 }
 
-// --- npm install failure scenarios ---
-
-func TestStatuslineSetup_handles_npm_install_network_timeout(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! network timeout" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-	if _, err := os.Stat(filepath.Join(fakeHome, ".claude", "statusline-command.sh")); !os.IsNotExist(err) {
-		t.Error("statusline-command.sh should not exist on network timeout")
-	}
-}
-
-func TestStatuslineSetup_handles_npm_install_ECONNREFUSED(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! network request to https://registry.npmjs.org/ccstatusline failed, reason: connect ECONNREFUSED" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-func TestStatuslineSetup_handles_npm_install_ETIMEDOUT(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! network request timed out, reason: ETIMEDOUT" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-func TestStatuslineSetup_handles_npm_registry_returning_404(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! 404 Not Found - GET https://registry.npmjs.org/ccstatusline" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-func TestStatuslineSetup_handles_npm_registry_returning_500(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! 500 Internal Server Error - GET https://registry.npmjs.org/ccstatusline" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-func TestStatuslineSetup_handles_npm_registry_returning_503_unavailable(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! 503 Service Unavailable - GET https://registry.npmjs.org/ccstatusline" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-func TestStatuslineSetup_handles_npm_install_hanging(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    sleep 5 &
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-func TestStatuslineSetup_handles_npm_install_disk_full_error(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! ENOSPC: no space left on device" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-func TestStatuslineSetup_handles_npm_install_permission_denied(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"install"* ]]; then
-    echo "npm ERR! EACCES: permission denied" >&2
-    return 1
-  fi
-  if [[ "$*" == *"list"* ]]; then return 1; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Failed to install")
-}
-
-// --- npm list failure scenarios ---
-
-func TestStatuslineSetup_handles_npm_list_returning_malformed_output(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"list"* ]]; then
-    echo "CORRUPT@#$%%DATA"
-    return 0
-  fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "already installed")
-}
-
-func TestStatuslineSetup_handles_npm_list_command_hanging(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"list"* ]]; then
-    sleep 5 &
-    return 0
-  fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	_, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-}
-
-func TestStatuslineSetup_handles_npm_returning_non_JSON_output(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"list"* ]]; then
-    echo "This is not JSON"
-    return 0
-  fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	_, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-}
-
-func TestStatuslineSetup_handles_npm_list_returning_empty_output(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() {
-  if [[ "$*" == *"list"* ]]; then
-    echo ""
-    return 0
-  fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	_, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-}
-
-// --- npm not found scenarios ---
-
-func TestStatuslineSetup_handles_npm_not_in_PATH_after_install(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 1; }
-brew() {
-  if [[ "$*" == *"install node"* ]]; then return 0; fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertNotContains(t, out, "ccstatusline installed")
-}
-
-func TestStatuslineSetup_handles_brew_node_install_failure(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 1; }
-brew() {
-  if [[ "$*" == *"install node"* ]]; then
-    echo "Error: Failed to install node" >&2
-    return 1
-  fi
-  return 0
-}
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertContains(t, out, "Node.js installation failed")
-}
-
-func TestStatuslineSetup_handles_brew_not_available_for_node_install(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 1; }
-brew() { return 127; }
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	out, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-	assertNotContains(t, out, "ccstatusline")
-}
-
 // --- File operation failure scenarios ---
 
-func TestStatuslineSetup_handles_missing_template_files(t *testing.T) {
+func TestStatuslineSetup_skips_when_template_files_are_missing(t *testing.T) {
 	shareDir, fakeHome := setupStatuslineTestDirs(t)
+	claudeSettings := filepath.Join(fakeHome, ".claude", "settings.json")
 
 	// Remove template files
 	if err := os.RemoveAll(filepath.Join(shareDir, "templates")); err != nil {
 		t.Fatalf("remove templates: %v", err)
 	}
 
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() { return 0; }
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
+	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`setup_statusline %q %q %q`,
+		shareDir, claudeSettings, fakeHome))
 
-	_, code := runBashSnippet(t, snippet, nil)
-	// cp will fail but script should handle gracefully
-	// Either non-zero exit OR the config file won't be created
-	configFile := filepath.Join(fakeHome, ".config", "ccstatusline", "settings.json")
-	if code == 0 {
-		if _, err := os.Stat(configFile); err == nil {
-			t.Error("config file should not exist when templates are missing, but it does")
-		}
+	out, code := runBashSnippet(t, snippet, nil)
+	assertExitCode(t, code, 0)
+	assertContains(t, out, "Failed to copy status line scripts")
+	if _, err := os.Stat(claudeSettings); !os.IsNotExist(err) {
+		t.Error("settings should not point at a status line that wasn't installed")
 	}
-	// Either failure or missing file is acceptable
 }
 
-func TestStatuslineSetup_handles_read_only_config_directory(t *testing.T) {
+func TestStatuslineSetup_handles_read_only_claude_directory(t *testing.T) {
 	shareDir, fakeHome := setupStatuslineTestDirs(t)
 
-	// Make config dir read-only
-	configDir := filepath.Join(fakeHome, ".config")
-	if err := os.Chmod(configDir, 0444); err != nil {
+	claudeDir := filepath.Join(fakeHome, ".claude")
+	if err := os.Chmod(claudeDir, 0555); err != nil {
 		t.Fatalf("chmod: %v", err)
 	}
-	defer os.Chmod(configDir, 0755)
+	defer os.Chmod(claudeDir, 0755)
+	if os.WriteFile(filepath.Join(claudeDir, "probe"), nil, 0644) == nil {
+		t.Skip("running as a user that ignores directory permissions")
+	}
 
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() { return 0; }
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
+	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`setup_statusline %q %q %q`,
+		shareDir, filepath.Join(t.TempDir(), "settings.json"), fakeHome))
 
-	_, code := runBashSnippet(t, snippet, nil)
-	// Function doesn't check mkdir errors, so it completes successfully
+	out, code := runBashSnippet(t, snippet, nil)
 	assertExitCode(t, code, 0)
+	assertContains(t, out, "Failed to copy status line scripts")
 }
 
 func TestStatuslineSetup_handles_chmod_failure_on_scripts(t *testing.T) {
 	shareDir, fakeHome := setupStatuslineTestDirs(t)
 
 	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() { return 0; }
 chmod() { return 1; }
 setup_statusline %q %q %q
 `, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
@@ -913,49 +514,3 @@ setup_statusline %q %q %q
 	// Function doesn't check chmod errors, completes successfully
 	assertExitCode(t, code, 0)
 }
-
-func TestStatuslineSetup_handles_config_file_copy_permission_denied(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	// Make ccstatusline dir read-only
-	ccDir := filepath.Join(fakeHome, ".config", "ccstatusline")
-	if err := os.Chmod(ccDir, 0444); err != nil {
-		t.Fatalf("chmod: %v", err)
-	}
-	defer os.Chmod(ccDir, 0755)
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() { return 0; }
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	_, code := runBashSnippet(t, snippet, nil)
-	// Function doesn't check cp errors, may succeed or fail - either is acceptable
-	_ = code
-}
-
-func TestStatuslineSetup_handles_corrupted_template_file(t *testing.T) {
-	shareDir, fakeHome := setupStatuslineTestDirs(t)
-
-	// Create corrupted template (non-UTF8)
-	corruptPath := filepath.Join(shareDir, "templates", "ccstatusline-settings.json")
-	if err := os.WriteFile(corruptPath, []byte{0xff, 0xfe, 0xfd}, 0644); err != nil {
-		t.Fatalf("write corrupt file: %v", err)
-	}
-
-	snippet := statuslineSetupSnippet(t, fmt.Sprintf(`
-_has_npm() { return 0; }
-npm() { return 0; }
-setup_statusline %q %q %q
-`, shareDir, filepath.Join(fakeHome, ".claude", "settings.json"), fakeHome))
-
-	_, code := runBashSnippet(t, snippet, nil)
-	assertExitCode(t, code, 0)
-
-	// Should copy file even if corrupted
-	configFile := filepath.Join(fakeHome, ".config", "ccstatusline", "settings.json")
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		t.Error("config file should exist even with corrupted template")
-	}
-}
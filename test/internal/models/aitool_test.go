@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jackuait/ghost-tab/internal/aitools"
 	"github.com/jackuait/ghost-tab/internal/models"
 )
 
@@ -145,6 +148,124 @@ func TestDisplayName(t *testing.T) {
 	}
 }
 
+func TestDetectAITools_RegisteredTools(t *testing.T) {
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "aider"), []byte("#!/bin/bash\necho test"), 0755)
+	t.Setenv("PATH", binDir)
+
+	r := aitools.Defaults()
+	if err := r.Decode([]byte("[[tool]]\nname = \"aider\"\ndisplay_name = \"Aider\"\n")); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	prev := aitools.Current()
+	aitools.Use(r)
+	defer aitools.Use(prev)
+
+	tools := models.DetectAITools()
+	if len(tools) != 5 {
+		t.Fatalf("Expected 5 tools, got %d", len(tools))
+	}
+	aider := tools[4]
+	if aider.Name != "aider" || !aider.Installed || aider.String() != "Aider ✓" {
+		t.Errorf("aider = %+v (%s)", aider, aider)
+	}
+	if got := models.DisplayName("aider"); got != "Aider" {
+		t.Errorf("DisplayName(aider) = %q", got)
+	}
+}
+
+func TestCheckTool(t *testing.T) {
+	binDir := t.TempDir()
+	scripts := map[string]string{
+		"current": "#!/bin/sh\necho '1.0.42 (Claude Code)'",
+		"old":     "#!/bin/sh\necho 'old v0.9.1'",
+		"silent":  "#!/bin/sh\necho test",
+		"crashes": "#!/bin/sh\necho 'dyld: Library not loaded' >&2\nexit 134",
+		"hangs":   "#!/bin/sh\nsleep 10",
+		"custom":  "#!/bin/sh\n[ \"$1\" = version ] && echo 2.3.4",
+	}
+	for name, script := range scripts {
+		os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755)
+	}
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	tests := []struct {
+		name    string
+		tool    aitools.Tool
+		status  string
+		version string
+		err     string
+	}{
+		{"current", aitools.Tool{Name: "current", Command: "current", MinVersion: "1.0.0"}, models.ToolOK, "1.0.42", ""},
+		{"outdated", aitools.Tool{Name: "old", Command: "old", MinVersion: "1.0.0"}, models.ToolOutdated, "0.9.1", ""},
+		{"no minimum", aitools.Tool{Name: "old", Command: "old"}, models.ToolOK, "0.9.1", ""},
+		{"no version printed", aitools.Tool{Name: "silent", Command: "silent", MinVersion: "1.0.0"}, models.ToolOK, "", ""},
+		{"exits non-zero", aitools.Tool{Name: "crashes", Command: "crashes"}, models.ToolBroken, "", "exit status 134: dyld: Library not loaded"},
+		{"hangs", aitools.Tool{Name: "hangs", Command: "hangs"}, models.ToolBroken, "", "no response after 200ms"},
+		{"custom version args", aitools.Tool{Name: "custom", Command: "custom", VersionArgs: []string{"version"}}, models.ToolOK, "2.3.4", ""},
+		{"missing", aitools.Tool{Name: "gone", Command: "ghost-tab-no-such-tool"}, models.ToolMissing, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			got := models.CheckTool(tt.tool, 200*time.Millisecond)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("CheckTool took %s", elapsed)
+			}
+			if got.Status != tt.status || got.Version != tt.version || got.Error != tt.err {
+				t.Errorf("CheckTool = status %q version %q error %q, want %q %q %q",
+					got.Status, got.Version, got.Error, tt.status, tt.version, tt.err)
+			}
+			if got.Installed != (tt.status != models.ToolMissing) {
+				t.Errorf("Installed = %v for status %q", got.Installed, got.Status)
+			}
+			if got.Usable() != (got.Installed && tt.status != models.ToolBroken) {
+				t.Errorf("Usable = %v for status %q", got.Usable(), got.Status)
+			}
+		})
+	}
+}
+
+func TestCheckTools_Parallel(t *testing.T) {
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "slow"), []byte("#!/bin/sh\nsleep 0.3\necho 1.0.0"), 0755)
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	var tools []aitools.Tool
+	for _, name := range []string{"a", "b", "c", "d"} {
+		tools = append(tools, aitools.Tool{Name: name, Command: "slow"})
+	}
+	start := time.Now()
+	got := models.CheckTools(tools, 5*time.Second)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("CheckTools took %s; tools should be checked in parallel", elapsed)
+	}
+	for i, tool := range got {
+		if tool.Name != tools[i].Name || tool.Version != "1.0.0" {
+			t.Errorf("result %d = %+v", i, tool)
+		}
+	}
+}
+
+func TestAIToolString_Health(t *testing.T) {
+	tests := []struct {
+		tool models.AITool
+		want string
+	}{
+		{models.AITool{Name: "claude", Installed: true, Status: models.ToolOK, Version: "1.0.42"}, "Claude Code 1.0.42 ✓"},
+		{models.AITool{Name: "claude", Installed: true, Status: models.ToolOutdated, Version: "0.2.9", MinVersion: "1.0.0"}, "Claude Code 0.2.9 ⚠ (needs 1.0.0+)"},
+		{models.AITool{Name: "codex", Installed: true, Status: models.ToolBroken}, "Codex CLI (broken)"},
+	}
+	for _, tt := range tests {
+		if got := tt.tool.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+		if strings.Contains(tt.want, "broken") == tt.tool.Usable() {
+			t.Errorf("%q: Usable() = %v", tt.want, tt.tool.Usable())
+		}
+	}
+}
+
 func TestCycleTool(t *testing.T) {
 	tests := []struct {
 		name      string
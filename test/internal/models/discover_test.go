@@ -0,0 +1,120 @@
+package models_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackuait/ghost-tab/internal/models"
+)
+
+// makeRepo creates dir with a .git directory inside it.
+func makeRepo(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func discoveredPaths(projects []models.Project) []string {
+	var paths []string
+	for _, p := range projects {
+		paths = append(paths, p.Path)
+	}
+	return paths
+}
+
+func TestDiscoverProjects(t *testing.T) {
+	root := t.TempDir()
+	makeRepo(t, filepath.Join(root, "api"))
+	makeRepo(t, filepath.Join(root, "clients", "acme", "web"))
+	makeRepo(t, filepath.Join(root, "a", "b", "c", "too-deep"))
+	makeRepo(t, filepath.Join(root, "api", "nested")) // inside a repo: not searched
+	makeRepo(t, filepath.Join(root, ".hidden", "secret"))
+	makeRepo(t, filepath.Join(root, "node_modules", "dep"))
+	os.MkdirAll(filepath.Join(root, "plain", "dir"), 0755)
+
+	// A linked worktree has a .git file, not a directory
+	os.MkdirAll(filepath.Join(root, "api-feature"), 0755)
+	os.WriteFile(filepath.Join(root, "api-feature", ".git"), []byte("gitdir: /elsewhere\n"), 0644)
+
+	projects := models.DiscoverProjects([]string{root}, 3)
+
+	want := []string{
+		filepath.Join(root, "api"),
+		filepath.Join(root, "clients", "acme", "web"),
+	}
+	got := discoveredPaths(projects)
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("project %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+	if projects[1].Name != "web" {
+		t.Errorf("expected project named after its directory, got %q", projects[1].Name)
+	}
+}
+
+func TestDiscoverProjects_Depth(t *testing.T) {
+	root := t.TempDir()
+	makeRepo(t, filepath.Join(root, "one"))
+	makeRepo(t, filepath.Join(root, "x", "two"))
+
+	if got := discoveredPaths(models.DiscoverProjects([]string{root}, 1)); len(got) != 1 {
+		t.Errorf("depth 1 should only find direct children, got %v", got)
+	}
+	if got := discoveredPaths(models.DiscoverProjects([]string{root}, 2)); len(got) != 2 {
+		t.Errorf("depth 2 should find both, got %v", got)
+	}
+}
+
+func TestDiscoverProjects_RootIsRepo(t *testing.T) {
+	root := t.TempDir()
+	makeRepo(t, root)
+	got := discoveredPaths(models.DiscoverProjects([]string{root}, 3))
+	if len(got) != 1 || got[0] != root {
+		t.Errorf("expected the root itself, got %v", got)
+	}
+}
+
+func TestDiscoverProjects_OverlappingAndMissingRoots(t *testing.T) {
+	root := t.TempDir()
+	makeRepo(t, filepath.Join(root, "sub", "repo"))
+
+	got := discoveredPaths(models.DiscoverProjects([]string{
+		root,
+		filepath.Join(root, "sub"),
+		filepath.Join(root, "missing"),
+	}, 3))
+	if len(got) != 1 {
+		t.Errorf("overlapping roots should not duplicate results, got %v", got)
+	}
+}
+
+func TestDiscoverProjects_SkipsSymlinks(t *testing.T) {
+	root := t.TempDir()
+	target := t.TempDir()
+	makeRepo(t, filepath.Join(target, "linked"))
+	if err := os.Symlink(target, filepath.Join(root, "link")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+
+	if got := discoveredPaths(models.DiscoverProjects([]string{root}, 3)); len(got) != 0 {
+		t.Errorf("symlinked directories should not be followed, got %v", got)
+	}
+}
+
+func TestExistingScanRoots(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	os.MkdirAll(filepath.Join(home, "code"), 0755)
+	os.WriteFile(filepath.Join(home, "file"), nil, 0644)
+
+	got := models.ExistingScanRoots([]string{"~/code", "~/missing", "~/file", " ~/code "})
+	if len(got) != 1 || got[0] != filepath.Join(home, "code") {
+		t.Errorf("expected only ~/code, got %v", got)
+	}
+}
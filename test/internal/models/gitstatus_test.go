@@ -0,0 +1,175 @@
+package models_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackuait/ghost-tab/internal/models"
+)
+
+func TestParseStatusPorcelainV2(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   models.GitStatus
+	}{
+		{
+			name:   "clean with upstream",
+			output: "# branch.oid abc\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -0\n",
+			want:   models.GitStatus{Branch: "main"},
+		},
+		{
+			name:   "ahead and behind",
+			output: "# branch.head feature/x\n# branch.ab +3 -12\n",
+			want:   models.GitStatus{Branch: "feature/x", Ahead: 3, Behind: 12},
+		},
+		{
+			name:   "modified file",
+			output: "# branch.head main\n1 .M N... 100644 100644 100644 a b file.go\n",
+			want:   models.GitStatus{Branch: "main", Dirty: true},
+		},
+		{
+			name:   "untracked file",
+			output: "# branch.head main\n? notes.txt\n",
+			want:   models.GitStatus{Branch: "main", Dirty: true},
+		},
+		{
+			name:   "detached without upstream",
+			output: "# branch.oid abc\n# branch.head (detached)\n",
+			want:   models.GitStatus{Branch: "(detached)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := models.ParseStatusPorcelainV2(tt.output); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadGitStatus(t *testing.T) {
+	repo := initRepo(t)
+
+	s, err := models.ReadGitStatus(repo)
+	if err != nil {
+		t.Fatalf("ReadGitStatus: %v", err)
+	}
+	if s != (models.GitStatus{Branch: "main"}) {
+		t.Errorf("clean repo status = %+v", s)
+	}
+
+	os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a"), 0644)
+	stash := exec.Command("git", "-C", repo, "-c", "user.name=t", "-c", "user.email=t@t", "stash", "-q", "-u")
+	if out, err := stash.CombinedOutput(); err != nil {
+		t.Fatalf("git stash: %v\n%s", err, out)
+	}
+	os.WriteFile(filepath.Join(repo, "b.txt"), []byte("b"), 0644)
+
+	s, err = models.ReadGitStatus(repo)
+	if err != nil {
+		t.Fatalf("ReadGitStatus: %v", err)
+	}
+	if !s.Dirty || s.Stashes != 1 {
+		t.Errorf("expected dirty with 1 stash, got %+v", s)
+	}
+
+	if _, err := models.ReadGitStatus(t.TempDir()); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
+
+func TestReadGitStatus_TildePath(t *testing.T) {
+	repo := initRepo(t)
+	t.Setenv("HOME", filepath.Dir(repo))
+
+	s, err := models.ReadGitStatus("~/" + filepath.Base(repo) + "/")
+	if err != nil {
+		t.Fatalf("ReadGitStatus: %v", err)
+	}
+	if s.Branch != "main" {
+		t.Errorf("status = %+v, want branch main", s)
+	}
+}
+
+func TestReadGitStatus_LeavesIndexAlone(t *testing.T) {
+	repo := initRepo(t)
+	file := filepath.Join(repo, "a.txt")
+	os.WriteFile(file, []byte("a"), 0644)
+	for _, args := range [][]string{
+		{"add", "a.txt"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "a"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	// A new mtime makes the index entry stale; a plain git status would
+	// refresh it and rewrite the index.
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(file, later, later)
+	index := filepath.Join(repo, ".git", "index")
+	before, _ := os.ReadFile(index)
+
+	if _, err := models.ReadGitStatus(repo); err != nil {
+		t.Fatalf("ReadGitStatus: %v", err)
+	}
+	if after, _ := os.ReadFile(index); string(after) != string(before) {
+		t.Error("ReadGitStatus rewrote the index")
+	}
+}
+
+func TestGitStatusCache_SharedThroughFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "git-status.json")
+	want := models.GitStatus{Branch: "main", Dirty: true, Ahead: 1}
+
+	a := models.NewGitStatusCache(file, time.Minute)
+	if _, ok := a.Get("/src/app"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+	if err := a.Put("/src/app", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Another menu reads what the first one cached
+	b := models.NewGitStatusCache(file, time.Minute)
+	got, ok := b.Get("/src/app")
+	if !ok || got != want {
+		t.Errorf("Get = %+v, %v; want %+v", got, ok, want)
+	}
+}
+
+func TestGitStatusCache_Expires(t *testing.T) {
+	c := models.NewGitStatusCache(filepath.Join(t.TempDir(), "git-status.json"), 20*time.Millisecond)
+	c.Put("/src/app", models.GitStatus{Branch: "main"})
+	if _, ok := c.Get("/src/app"); !ok {
+		t.Fatal("expected fresh entry")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := c.Get("/src/app"); ok {
+		t.Error("expected entry to expire after the TTL")
+	}
+}
+
+func TestGitStatusCache_Status(t *testing.T) {
+	repo := initRepo(t)
+	c := models.NewGitStatusCache("", time.Minute)
+
+	s, err := c.Status(repo)
+	if err != nil || s.Branch != "main" {
+		t.Fatalf("Status = %+v, %v", s, err)
+	}
+
+	// Within the TTL a change in the repo isn't seen
+	os.WriteFile(filepath.Join(repo, "a.txt"), []byte("a"), 0644)
+	if s, _ := c.Status(repo); s.Dirty {
+		t.Error("expected cached clean status within the TTL")
+	}
+
+	if _, err := c.Status(t.TempDir()); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
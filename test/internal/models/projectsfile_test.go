@@ -0,0 +1,189 @@
+package models_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackuait/ghost-tab/internal/models"
+)
+
+func TestReadProjectsFile_Structured(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	content := `{
+  "version": 1,
+  "projects": [
+    {
+      "name": "api",
+      "path": "/srv/api:v2",
+      "ai_tool": "codex",
+      "ai_args": ["--model", "o3"],
+      "layout": "tests",
+      "env": {"PORT": "8080"},
+      "tags": ["work"],
+      "notes": "needs VPN"
+    },
+    {"name": "web", "path": "/srv/web"}
+  ]
+}`
+	os.WriteFile(file, []byte(content), 0644)
+
+	pf, err := models.ReadProjectsFile(file)
+	if err != nil {
+		t.Fatalf("ReadProjectsFile: %v", err)
+	}
+	if pf.IsLegacy() {
+		t.Error("structured file should not be reported as legacy")
+	}
+	if len(pf.Projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(pf.Projects))
+	}
+
+	api := pf.Projects[0]
+	if api.Path != "/srv/api:v2" {
+		t.Errorf("path with colon should be kept intact, got %q", api.Path)
+	}
+	if api.AITool != "codex" || api.Layout != "tests" || api.Notes != "needs VPN" {
+		t.Errorf("unexpected scalar fields: %+v", api)
+	}
+	if strings.Join(api.AIArgs, " ") != "--model o3" {
+		t.Errorf("AIArgs: got %v", api.AIArgs)
+	}
+	if api.Env["PORT"] != "8080" {
+		t.Errorf("Env: got %v", api.Env)
+	}
+	if len(api.Tags) != 1 || api.Tags[0] != "work" {
+		t.Errorf("Tags: got %v", api.Tags)
+	}
+}
+
+func TestReadProjectsFile_Legacy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	os.WriteFile(file, []byte("# comment\napp:/tmp/app\n\nweb:/tmp/web\n"), 0644)
+
+	pf, err := models.ReadProjectsFile(file)
+	if err != nil {
+		t.Fatalf("ReadProjectsFile: %v", err)
+	}
+	if !pf.IsLegacy() {
+		t.Error("name:path file should be reported as legacy")
+	}
+	if len(pf.Projects) != 2 || pf.Projects[1].Name != "web" {
+		t.Errorf("unexpected projects: %+v", pf.Projects)
+	}
+}
+
+func TestReadProjectsFile_NewerVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	os.WriteFile(file, []byte(`{"version": 99, "projects": []}`), 0644)
+
+	_, err := models.ReadProjectsFile(file)
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("expected version error, got %v", err)
+	}
+}
+
+func TestReadProjectsFile_InvalidJSON(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	os.WriteFile(file, []byte(`{"version": 1, "projects": [`), 0644)
+
+	if _, err := models.ReadProjectsFile(file); err == nil {
+		t.Error("expected error for truncated JSON")
+	}
+}
+
+func TestReadProjectsFile_Missing(t *testing.T) {
+	if _, err := models.ReadProjectsFile(filepath.Join(t.TempDir(), "nope")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestWriteProjectsFile_RoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sub", "projects")
+	in := &models.ProjectsFile{Projects: []models.Project{
+		{Name: "a", Path: "/tmp/a", Tags: []string{"x"}},
+		{Name: "b", Path: "/tmp/b", Worktrees: []models.Worktree{{Path: "/tmp/b-wt", Branch: "feat"}}},
+	}}
+	if err := models.WriteProjectsFile(file, in); err != nil {
+		t.Fatalf("WriteProjectsFile: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if strings.Contains(string(data), "b-wt") {
+		t.Error("worktrees are discovered at runtime and should not be written")
+	}
+	if strings.Contains(string(data), "ai_tool") {
+		t.Error("empty optional fields should be omitted")
+	}
+
+	out, err := models.ReadProjectsFile(file)
+	if err != nil {
+		t.Fatalf("ReadProjectsFile: %v", err)
+	}
+	if out.Version != models.ProjectsFileVersion {
+		t.Errorf("Version: got %d, want %d", out.Version, models.ProjectsFileVersion)
+	}
+	if len(out.Projects) != 2 || out.Projects[0].Tags[0] != "x" {
+		t.Errorf("unexpected projects after round trip: %+v", out.Projects)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(file))
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files left behind, got %d entries", len(entries))
+	}
+}
+
+func TestWriteProjectsFile_Empty(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	if err := models.WriteProjectsFile(file, &models.ProjectsFile{}); err != nil {
+		t.Fatalf("WriteProjectsFile: %v", err)
+	}
+	data, _ := os.ReadFile(file)
+	if !strings.Contains(string(data), `"projects": []`) {
+		t.Errorf("expected empty projects array, got %q", string(data))
+	}
+}
+
+func TestMigrateProjectsFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "projects")
+	legacy := "app:/tmp/app\nweb:/tmp/web\n"
+	os.WriteFile(file, []byte(legacy), 0644)
+
+	migrated, err := models.MigrateProjectsFile(file)
+	if err != nil {
+		t.Fatalf("MigrateProjectsFile: %v", err)
+	}
+	if !migrated {
+		t.Fatal("expected legacy file to be migrated")
+	}
+
+	pf, err := models.ReadProjectsFile(file)
+	if err != nil {
+		t.Fatalf("ReadProjectsFile: %v", err)
+	}
+	if pf.IsLegacy() || len(pf.Projects) != 2 {
+		t.Errorf("expected 2 structured projects, got legacy=%v %+v", pf.IsLegacy(), pf.Projects)
+	}
+
+	backup, err := os.ReadFile(file + models.LegacyBackupSuffix)
+	if err != nil {
+		t.Fatalf("expected legacy backup: %v", err)
+	}
+	if string(backup) != legacy {
+		t.Errorf("backup should hold the original content, got %q", string(backup))
+	}
+
+	// Second run is a no-op.
+	migrated, err = models.MigrateProjectsFile(file)
+	if err != nil || migrated {
+		t.Errorf("second migration should be a no-op, got migrated=%v err=%v", migrated, err)
+	}
+}
+
+func TestMigrateProjectsFile_Missing(t *testing.T) {
+	migrated, err := models.MigrateProjectsFile(filepath.Join(t.TempDir(), "projects"))
+	if err != nil || migrated {
+		t.Errorf("missing file should not migrate, got migrated=%v err=%v", migrated, err)
+	}
+}
@@ -1,6 +1,10 @@
 package models_test
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jackuait/ghost-tab/internal/models"
@@ -93,3 +97,137 @@ func TestLoadProjectsWithWorktrees_NonGitDir(t *testing.T) {
 		t.Errorf("expected 0 worktrees for non-git dir, got %d", len(projects[0].Worktrees))
 	}
 }
+
+// initRepo creates a git repository with one commit in a temp directory.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := filepath.Join(t.TempDir(), "app")
+	os.MkdirAll(dir, 0755)
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "feature/login"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestListBranches(t *testing.T) {
+	repo := initRepo(t)
+
+	got := models.ListBranches(repo)
+	want := []string{"feature/login", "main"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ListBranches = %v, want %v", got, want)
+	}
+	if branches := models.ListBranches(t.TempDir()); branches != nil {
+		t.Errorf("expected nil for non-git dir, got %v", branches)
+	}
+}
+
+func TestDefaultWorktreePath(t *testing.T) {
+	tests := []struct {
+		project, branch, want string
+	}{
+		{"/src/app", "fix", "/src/app-fix"},
+		{"/src/app/", "feature/login", "/src/app-feature-login"},
+	}
+	for _, tt := range tests {
+		if got := models.DefaultWorktreePath(tt.project, tt.branch); got != tt.want {
+			t.Errorf("DefaultWorktreePath(%q, %q) = %q, want %q", tt.project, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestAddWorktree(t *testing.T) {
+	repo := initRepo(t)
+
+	t.Run("existing branch", func(t *testing.T) {
+		path := models.DefaultWorktreePath(repo, "feature/login")
+		if err := models.AddWorktree(repo, path, "feature/login"); err != nil {
+			t.Fatalf("AddWorktree: %v", err)
+		}
+	})
+
+	t.Run("new branch", func(t *testing.T) {
+		path := models.DefaultWorktreePath(repo, "spike")
+		if err := models.AddWorktree(repo, path, "spike"); err != nil {
+			t.Fatalf("AddWorktree: %v", err)
+		}
+	})
+
+	wts := models.DetectWorktrees(repo)
+	if len(wts) != 2 || wts[0].Branch != "feature/login" || wts[1].Branch != "spike" {
+		t.Errorf("DetectWorktrees = %+v", wts)
+	}
+
+	t.Run("branch already checked out", func(t *testing.T) {
+		err := models.AddWorktree(repo, filepath.Join(filepath.Dir(repo), "other"), "spike")
+		if err == nil {
+			t.Fatal("expected error adding a checked out branch")
+		}
+		if strings.HasPrefix(err.Error(), "fatal:") {
+			t.Errorf("expected git's message without prefix, got %q", err)
+		}
+	})
+}
+
+func TestRemoveWorktree_Changes(t *testing.T) {
+	repo := initRepo(t)
+	path := models.DefaultWorktreePath(repo, "feature/login")
+	if err := models.AddWorktree(repo, path, "feature/login"); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+
+	if n, err := models.WorktreeChanges(path); err != nil || n != 0 {
+		t.Fatalf("WorktreeChanges clean = %d, %v", n, err)
+	}
+	os.WriteFile(filepath.Join(path, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(path, "b.txt"), []byte("b"), 0644)
+	if n, _ := models.WorktreeChanges(path); n != 2 {
+		t.Errorf("WorktreeChanges dirty = %d, want 2", n)
+	}
+
+	if err := models.RemoveWorktree(repo, path, false); err == nil {
+		t.Fatal("expected dirty worktree removal to fail without force")
+	}
+	if err := models.RemoveWorktree(repo, path, true); err != nil {
+		t.Fatalf("RemoveWorktree force: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected worktree directory removed, got %v", err)
+	}
+	if wts := models.DetectWorktrees(repo); len(wts) != 0 {
+		t.Errorf("expected no worktrees, got %+v", wts)
+	}
+}
+
+func TestWorktrees_TildeProjectPath(t *testing.T) {
+	repo := initRepo(t)
+	t.Setenv("HOME", filepath.Dir(repo))
+	project := "~/" + filepath.Base(repo)
+
+	if branches := models.ListBranches(project); len(branches) != 2 {
+		t.Errorf("ListBranches = %v, want 2 branches", branches)
+	}
+	path := models.DefaultWorktreePath(project, "spike")
+	if want := repo + "-spike"; path != want {
+		t.Errorf("DefaultWorktreePath = %q, want %q", path, want)
+	}
+	if err := models.AddWorktree(project, path, "spike"); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	if wts := models.DetectWorktrees(project); len(wts) != 1 || wts[0].Branch != "spike" {
+		t.Errorf("DetectWorktrees = %+v", wts)
+	}
+	if err := models.RemoveWorktree(project, path, false); err != nil {
+		t.Fatalf("RemoveWorktree: %v", err)
+	}
+}
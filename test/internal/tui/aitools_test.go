@@ -1,6 +1,7 @@
 package tui_test
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -102,6 +103,33 @@ func TestAIToolSelector_EnterSkipsUninstalled(t *testing.T) {
 	}
 }
 
+func TestAIToolSelector_EnterSkipsBroken(t *testing.T) {
+	tools := []models.AITool{
+		{Name: "codex", Command: "codex", Installed: true, Status: models.ToolBroken, Error: "exit status 1"},
+	}
+	m := tui.NewAIToolSelector(tools)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	result := updated.(tui.AIToolSelectorModel)
+	if result.Selected() != nil {
+		t.Error("Enter on a broken tool should not select it")
+	}
+}
+
+func TestAIToolSelector_ShowsVersion(t *testing.T) {
+	tools := []models.AITool{
+		{Name: "claude", Command: "claude", Installed: true, Status: models.ToolOK, Version: "1.0.42"},
+		{Name: "codex", Command: "codex", Installed: true, Status: models.ToolBroken, Error: "no response after 3s"},
+	}
+	m := tui.NewAIToolSelector(tools)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	view := updated.(tui.AIToolSelectorModel).View()
+	for _, want := range []string{"Claude Code 1.0.42", "Codex CLI (broken)", "no response after 3s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+}
+
 func TestAIToolSelector_WindowSizeMsg(t *testing.T) {
 	tools := []models.AITool{{Name: "claude", Command: "claude", Installed: true}}
 	m := tui.NewAIToolSelector(tools)
@@ -355,3 +355,29 @@ func TestAutocompleteModel_NewWithZeroMax(t *testing.T) {
 		t.Error("Should show suggestions even with maxResults=0 (should default to 8)")
 	}
 }
+
+func TestBranchSuggestionProvider(t *testing.T) {
+	branches := []string{"develop", "feature/login", "fix/login-redirect", "main"}
+	provider := tui.BranchSuggestionProvider(branches, 8)
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"fe", []string{"feature/login"}},
+		{"LOGIN", []string{"feature/login", "fix/login-redirect"}},
+		{"f", []string{"feature/login", "fix/login-redirect"}},
+		{"main", nil},
+		{"nope", nil},
+	}
+	for _, tt := range tests {
+		got := provider(tt.input)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("provider(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if got := tui.BranchSuggestionProvider(branches, 2)(""); len(got) != 2 {
+		t.Errorf("expected results capped at 2, got %v", got)
+	}
+}
@@ -0,0 +1,55 @@
+package tui_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jackuait/ghost-tab/internal/tui"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"empty pattern", "", "ghost-tab", true, nil},
+		{"prefix", "gho", "ghost-tab", true, []int{0, 1, 2}},
+		{"subsequence", "gtb", "ghost-tab", true, []int{0, 6, 8}},
+		{"case insensitive", "GT", "ghost-tab", true, []int{0, 6}},
+		{"prefers word start", "t", "ghost-tab", true, []int{6}},
+		{"out of order", "bg", "ghost-tab", false, nil},
+		{"missing character", "gx", "ghost-tab", false, nil},
+		{"pattern longer than text", "ghost-tab-x", "ghost-tab", false, nil},
+		{"unicode", "çé", "façade-été", true, []int{2, 7}},
+		{"dotted capital I", "ist", "İstanbul", true, []int{0, 1, 2}},
+		{"positions after İ", "tab", "İİ-tab", true, []int{3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := tui.FuzzyMatch(tt.pattern, tt.text)
+			if ok != tt.ok {
+				t.Fatalf("FuzzyMatch(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.ok)
+			}
+			if !reflect.DeepEqual(positions, tt.positions) {
+				t.Errorf("FuzzyMatch(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatch_Ranking(t *testing.T) {
+	better, _, _ := tui.FuzzyMatch("gt", "ghost-tab")
+	worse, _, _ := tui.FuzzyMatch("gt", "gadget")
+	if better <= worse {
+		t.Errorf("word-start match should outrank scattered match: %d <= %d", better, worse)
+	}
+
+	consecutive, _, _ := tui.FuzzyMatch("web", "website")
+	scattered, _, _ := tui.FuzzyMatch("web", "wide-label")
+	if consecutive <= scattered {
+		t.Errorf("consecutive match should outrank scattered match: %d <= %d", consecutive, scattered)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/jackuait/ghost-tab/internal/aitools"
 	"github.com/jackuait/ghost-tab/internal/tui"
 )
 
@@ -61,6 +62,33 @@ func TestGhostForTool_Unknown(t *testing.T) {
 	}
 }
 
+func TestGhostForTool_RegisteredTool(t *testing.T) {
+	r := aitools.Defaults()
+	if err := r.Decode([]byte("[[tool]]\nname = \"aider\"\nghost = \"codex\"\ntheme = { primary = 39 }\n")); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	prev := aitools.Current()
+	aitools.Use(r)
+	defer aitools.Use(prev)
+
+	theme := tui.ThemeForTool("aider")
+	if theme.Name != "aider" || theme.Primary != "39" {
+		t.Errorf("ThemeForTool(aider) = %+v", theme)
+	}
+	// Unset colors come from the claude palette
+	if theme.Dim != tui.ThemeForTool("claude").Dim {
+		t.Errorf("expected claude's Dim, got %q", theme.Dim)
+	}
+
+	aider := tui.RenderGhost(tui.GhostForTool("aider", false))
+	if !strings.Contains(aider, tui.AnsiFromThemeColor("39")) {
+		t.Error("expected the aider ghost drawn in its primary color")
+	}
+	if aider == tui.RenderGhost(tui.GhostForTool("claude", false)) {
+		t.Error("expected the codex ghost art, got claude's")
+	}
+}
+
 func TestRenderZzz(t *testing.T) {
 	result := tui.RenderZzz()
 
@@ -0,0 +1,184 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/tui"
+)
+
+func discoveredProjects() []models.Project {
+	return []models.Project{
+		{Name: "api", Path: "/src/api"},
+		{Name: "blog", Path: "/src/blog"},
+		{Name: "web", Path: "/src/web"},
+	}
+}
+
+func TestNewImportSelect_KnownPreChecked(t *testing.T) {
+	existing := []models.Project{{Name: "blog", Path: "/src/blog"}}
+	m := tui.NewImportSelect(discoveredProjects(), existing)
+
+	wantKnown := []bool{false, true, false}
+	for i, k := range m.Known() {
+		if k != wantKnown[i] {
+			t.Errorf("Known()[%d] = %v, want %v", i, k, wantKnown[i])
+		}
+		if m.Checked()[i] != wantKnown[i] {
+			t.Errorf("Checked()[%d] = %v, want %v", i, m.Checked()[i], wantKnown[i])
+		}
+	}
+}
+
+func TestNewImportSelect_KnownWithTrailingSlash(t *testing.T) {
+	existing := []models.Project{{Name: "web", Path: "/src/web/"}}
+	m := tui.NewImportSelect(discoveredProjects(), existing)
+
+	if !m.Known()[2] {
+		t.Error("Expected /src/web/ to match discovered /src/web")
+	}
+}
+
+func TestImportSelect_SpaceToggles(t *testing.T) {
+	m := tui.NewImportSelect(discoveredProjects(), nil)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	is := updated.(tui.ImportSelectModel)
+	if !is.Checked()[0] {
+		t.Error("Expected first project checked after space")
+	}
+
+	updated, _ = is.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	is = updated.(tui.ImportSelectModel)
+	if is.Checked()[0] {
+		t.Error("Expected first project unchecked after second space")
+	}
+}
+
+func TestImportSelect_KnownCannotBeToggled(t *testing.T) {
+	existing := []models.Project{{Name: "api", Path: "/src/api"}}
+	m := tui.NewImportSelect(discoveredProjects(), existing)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	is := updated.(tui.ImportSelectModel)
+	if !is.Checked()[0] {
+		t.Error("Known project should stay checked")
+	}
+}
+
+func TestImportSelect_ToggleAll(t *testing.T) {
+	existing := []models.Project{{Name: "blog", Path: "/src/blog"}}
+	m := tui.NewImportSelect(discoveredProjects(), existing)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	is := updated.(tui.ImportSelectModel)
+	for i, c := range is.Checked() {
+		if !c {
+			t.Errorf("Checked()[%d] = false after 'a', want true", i)
+		}
+	}
+
+	updated, _ = is.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	is = updated.(tui.ImportSelectModel)
+	want := []bool{false, true, false}
+	for i, c := range is.Checked() {
+		if c != want[i] {
+			t.Errorf("Checked()[%d] = %v after second 'a', want %v", i, c, want[i])
+		}
+	}
+}
+
+func TestImportSelect_CursorWraps(t *testing.T) {
+	m := tui.NewImportSelect(discoveredProjects(), nil)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	is := updated.(tui.ImportSelectModel)
+	if is.Cursor() != 2 {
+		t.Errorf("Cursor after up from top = %d, want 2", is.Cursor())
+	}
+
+	updated, _ = is.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	is = updated.(tui.ImportSelectModel)
+	if is.Cursor() != 0 {
+		t.Errorf("Cursor after j from bottom = %d, want 0", is.Cursor())
+	}
+}
+
+func TestImportSelect_EnterReturnsOnlyNewChecked(t *testing.T) {
+	existing := []models.Project{{Name: "api", Path: "/src/api"}}
+	m := tui.NewImportSelect(discoveredProjects(), existing)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeySpace})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	is := updated.(tui.ImportSelectModel)
+
+	if cmd == nil {
+		t.Error("Expected a quit command on enter")
+	}
+	result := is.Result()
+	if result == nil || !result.Confirmed {
+		t.Fatalf("Expected confirmed result, got %+v", result)
+	}
+	if len(result.Projects) != 1 || result.Projects[0].Path != "/src/web" {
+		t.Errorf("Projects = %+v, want only /src/web", result.Projects)
+	}
+}
+
+func TestImportSelect_EscCancels(t *testing.T) {
+	m := tui.NewImportSelect(discoveredProjects(), nil)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	is := updated.(tui.ImportSelectModel)
+
+	if cmd == nil {
+		t.Error("Expected a quit command on esc")
+	}
+	if is.Result() == nil || is.Result().Confirmed {
+		t.Errorf("Expected unconfirmed result, got %+v", is.Result())
+	}
+}
+
+func TestImportSelect_View(t *testing.T) {
+	existing := []models.Project{{Name: "blog", Path: "/src/blog"}}
+	m := tui.NewImportSelect(discoveredProjects(), existing)
+	view := m.View()
+
+	for _, want := range []string{"Import Projects", "3 found, 2 new", "/src/api", "[x] ", "(added)", "Space toggle"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestImportSelect_ViewEmpty(t *testing.T) {
+	m := tui.NewImportSelect(nil, nil)
+	if !strings.Contains(m.View(), "No git repositories found") {
+		t.Errorf("Expected empty message, got:\n%s", m.View())
+	}
+}
+
+func TestImportSelect_ScrollsToCursor(t *testing.T) {
+	var projects []models.Project
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		projects = append(projects, models.Project{Name: name, Path: "/src/" + name})
+	}
+	m := tui.NewImportSelect(projects, nil)
+
+	// Leaves room for 3 rows
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 8})
+	for i := 0; i < 5; i++ {
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view := updated.(tui.ImportSelectModel).View()
+
+	if !strings.Contains(view, "/src/f") {
+		t.Errorf("Expected cursor row /src/f visible:\n%s", view)
+	}
+	if strings.Contains(view, "/src/a") {
+		t.Errorf("Expected /src/a scrolled out of view:\n%s", view)
+	}
+}
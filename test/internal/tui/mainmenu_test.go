@@ -2,13 +2,17 @@ package tui_test
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jackuait/ghost-tab/internal/history"
 	"github.com/jackuait/ghost-tab/internal/models"
+	"github.com/jackuait/ghost-tab/internal/notify"
 	"github.com/jackuait/ghost-tab/internal/tui"
 	"github.com/muesli/termenv"
 )
@@ -73,23 +77,23 @@ func TestMainMenu_Navigation(t *testing.T) {
 }
 
 func TestMainMenu_TotalItems(t *testing.T) {
-	// 3 projects + 4 actions = 7
+	// 3 projects + 5 actions = 8
 	projects := testProjects()
 	m := tui.NewMainMenu(projects, testAITools(), "claude", "animated")
-	if m.TotalItems() != 7 {
-		t.Errorf("TotalItems with 3 projects: expected 7, got %d", m.TotalItems())
+	if m.TotalItems() != 8 {
+		t.Errorf("TotalItems with 3 projects: expected 8, got %d", m.TotalItems())
 	}
 
-	// 0 projects + 4 actions = 4
+	// 0 projects + 5 actions = 5
 	m2 := tui.NewMainMenu(nil, testAITools(), "claude", "animated")
-	if m2.TotalItems() != 4 {
-		t.Errorf("TotalItems with 0 projects: expected 4, got %d", m2.TotalItems())
+	if m2.TotalItems() != 5 {
+		t.Errorf("TotalItems with 0 projects: expected 5, got %d", m2.TotalItems())
 	}
 
-	// 1 project + 4 actions = 5
+	// 1 project + 5 actions = 6
 	m3 := tui.NewMainMenu([]models.Project{{Name: "solo", Path: "/solo"}}, testAITools(), "claude", "animated")
-	if m3.TotalItems() != 5 {
-		t.Errorf("TotalItems with 1 project: expected 5, got %d", m3.TotalItems())
+	if m3.TotalItems() != 6 {
+		t.Errorf("TotalItems with 1 project: expected 6, got %d", m3.TotalItems())
 	}
 }
 
@@ -259,22 +263,22 @@ func TestMainMenu_LayoutCalculation(t *testing.T) {
 }
 
 func TestMainMenu_LayoutCalculation_MenuHeight(t *testing.T) {
-	// 3 projects (2 rows each) + 4 actions (1 row each) + 1 separator
+	// 3 projects (2 rows each) + 5 actions (1 row each) + 1 separator
 	projects := testProjects()
 	m := tui.NewMainMenu(projects, testAITools(), "claude", "animated")
 	layout := m.CalculateLayout(100, 40)
 
-	// MenuHeight = 7 (chrome) + 3*2 (projects) + 4 (actions) + 1 (separator) = 18
-	expectedHeight := 7 + (3 * 2) + 4 + 1
+	// MenuHeight = 7 (chrome) + 3*2 (projects) + 5 (actions) + 1 (separator) = 19
+	expectedHeight := 7 + (3 * 2) + 5 + 1
 	if layout.MenuHeight != expectedHeight {
 		t.Errorf("MenuHeight with 3 projects: expected %d, got %d", expectedHeight, layout.MenuHeight)
 	}
 
-	// 0 projects + 4 actions (1 row each), 0 separators (no projects means no separator)
+	// 0 projects + 5 actions (1 row each), 0 separators (no projects means no separator)
 	m2 := tui.NewMainMenu(nil, testAITools(), "claude", "animated")
 	layout2 := m2.CalculateLayout(100, 40)
-	// MenuHeight = 7 (chrome) + 0 (projects) + 4 (actions) + 0 (separator) = 11
-	expectedHeight2 := 7 + 0 + 4 + 0
+	// MenuHeight = 7 (chrome) + 0 (projects) + 5 (actions) + 0 (separator) = 12
+	expectedHeight2 := 7 + 0 + 5 + 0
 	if layout2.MenuHeight != expectedHeight2 {
 		t.Errorf("MenuHeight with 0 projects: expected %d, got %d", expectedHeight2, layout2.MenuHeight)
 	}
@@ -439,6 +443,23 @@ func TestMainMenu_SelectAction(t *testing.T) {
 			t.Errorf("Expected action 'plain-terminal', got %q", result.Action)
 		}
 	})
+
+	t.Run("import-projects", func(t *testing.T) {
+		m := tui.NewMainMenu(projects, testAITools(), "claude", "animated")
+		for i := 0; i < 7; i++ {
+			m.MoveDown()
+		}
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		mm := newModel.(*tui.MainMenuModel)
+		result := mm.Result()
+
+		if result == nil {
+			t.Fatal("Expected result, got nil")
+		}
+		if result.Action != "import-projects" {
+			t.Errorf("Expected action 'import-projects', got %q", result.Action)
+		}
+	})
 }
 
 func TestMainMenu_ActionShortcuts(t *testing.T) {
@@ -519,6 +540,20 @@ func TestMainMenu_ActionShortcuts(t *testing.T) {
 		}
 	})
 
+	t.Run("i_shortcut", func(t *testing.T) {
+		m := tui.NewMainMenu(projects, testAITools(), "claude", "animated")
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+		mm := newModel.(*tui.MainMenuModel)
+		result := mm.Result()
+
+		if result == nil {
+			t.Fatal("Expected result for 'i' shortcut, got nil")
+		}
+		if result.Action != "import-projects" {
+			t.Errorf("Expected 'import-projects', got %q", result.Action)
+		}
+	})
+
 	t.Run("s_shortcut_enters_settings", func(t *testing.T) {
 		m := tui.NewMainMenu(projects, testAITools(), "claude", "animated")
 		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
@@ -695,12 +730,18 @@ func TestMainMenu_SetSize(t *testing.T) {
 }
 
 func TestMainMenu_Init(t *testing.T) {
-	// Static mode: Init returns nil (no ticks)
-	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "static")
+	// Static mode: Init returns nil (no ticks, no projects to read status of)
+	m := tui.NewMainMenu(nil, testAITools(), "claude", "static")
 	cmd := m.Init()
 	if cmd != nil {
 		t.Error("Init() should return nil for static mode")
 	}
+
+	// Projects get their git status read in the background
+	m = tui.NewMainMenu(testProjects(), testAITools(), "claude", "static")
+	if m.Init() == nil {
+		t.Error("Init() should read git status of projects")
+	}
 }
 
 func TestMainMenu_View(t *testing.T) {
@@ -2441,9 +2482,12 @@ func TestMainMenu_AddProject_SubmitValid(t *testing.T) {
 		t.Error("Expected feedback message after adding project")
 	}
 
-	data, _ := os.ReadFile(projFile)
-	if !strings.Contains(string(data), "new-project:"+targetDir) {
-		t.Errorf("Projects file should contain new entry, got: %q", string(data))
+	projects, err := models.LoadProjects(projFile)
+	if err != nil {
+		t.Fatalf("LoadProjects: %v", err)
+	}
+	if len(projects) != 2 || projects[1].Name != "new-project" || projects[1].Path != targetDir {
+		t.Errorf("Projects file should contain new entry, got: %+v", projects)
 	}
 }
 
@@ -3021,6 +3065,67 @@ func TestMainMenu_CycleSoundNameReverse_wraps_through_off(t *testing.T) {
 	}
 }
 
+func linuxSoundBackend() *notify.Fake {
+	return &notify.Fake{SoundList: []notify.Sound{
+		{Name: "bell", Path: "/usr/share/sounds/freedesktop/stereo/bell.oga"},
+		{Name: "complete", Path: "/usr/share/sounds/freedesktop/stereo/complete.oga"},
+	}}
+}
+
+func TestMainMenu_SetSoundBackend_replaces_system_sounds(t *testing.T) {
+	m := tui.NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	if got := m.SoundNames(); len(got) != len(tui.SystemSounds) {
+		t.Fatalf("without a backend SoundNames() = %v, want the macOS sounds", got)
+	}
+	m.SetSoundBackend(linuxSoundBackend())
+	got := m.SoundNames()
+	if len(got) != 2 || got[0] != "bell" || got[1] != "complete" {
+		t.Errorf("SoundNames() = %v, want [bell complete]", got)
+	}
+}
+
+func TestMainMenu_CycleSoundName_uses_backend_sounds_and_previews(t *testing.T) {
+	backend := linuxSoundBackend()
+	m := tui.NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.SetSoundBackend(backend)
+	m.SetSoundName("")
+
+	m.CycleSoundName()
+	if m.SoundName() != "bell" {
+		t.Fatalf("expected 'bell' after Off, got %q", m.SoundName())
+	}
+	m.CycleSoundName()
+	m.CycleSoundName()
+	if m.SoundName() != "" {
+		t.Errorf("expected Off after the last sound, got %q", m.SoundName())
+	}
+	m.CycleSoundNameReverse()
+	if m.SoundName() != "complete" {
+		t.Errorf("expected 'complete' after Off reversed, got %q", m.SoundName())
+	}
+
+	if len(backend.Played) != 3 {
+		t.Fatalf("expected 3 previews (not for Off), got %v", backend.Played)
+	}
+	if p := backend.Played[0].Path; p != "/usr/share/sounds/freedesktop/stereo/bell.oga" {
+		t.Errorf("previewed %q, want the bell sound file", p)
+	}
+}
+
+func TestMainMenu_CycleSoundName_without_sounds_stays_off(t *testing.T) {
+	m := tui.NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.SetSoundBackend(&notify.Fake{})
+	m.SetSoundName("")
+	m.CycleSoundName()
+	if m.SoundName() != "" {
+		t.Errorf("expected Off with no sounds installed, got %q", m.SoundName())
+	}
+	m.CycleSoundNameReverse()
+	if m.SoundName() != "" {
+		t.Errorf("expected Off with no sounds installed, got %q", m.SoundName())
+	}
+}
+
 func TestMainMenu_SoundNameInResult(t *testing.T) {
 	projects := testProjects()
 	m := tui.NewMainMenu(projects, []string{"claude"}, "claude", "animated")
@@ -3398,31 +3503,82 @@ func testProjectsWithWorktrees() []models.Project {
 	}
 }
 
+func TestMainMenu_AgentSelect(t *testing.T) {
+	m := tui.NewMainMenu(testProjectsWithWorktrees(), testAITools(), "claude", "animated")
+	m.ToggleWorktrees(0)
+	m.MoveDown() // feature-auth worktree
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if !m.InAgentSelect() {
+		t.Fatal("M should open the agent selection")
+	}
+	if view := m.View(); !strings.Contains(view, "[x] Claude Code") || !strings.Contains(view, "feature-auth") {
+		t.Errorf("agent box should list the checked tool and its folder:\n%s", view)
+	}
+
+	// Codex on the project itself: down, check, ← back to the project
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("confirming should quit the menu")
+	}
+
+	result := m.Result()
+	if result.Action != "select-project" || result.Path != "/Users/jack/wt/feature-auth" {
+		t.Errorf("result = %+v", result)
+	}
+	want := []tui.Agent{
+		{Tool: "claude", Path: "/Users/jack/wt/feature-auth"},
+		{Tool: "codex", Path: "/Users/jack/ghost-tab"},
+	}
+	if len(result.Agents) != 2 || result.Agents[0] != want[0] || result.Agents[1] != want[1] {
+		t.Errorf("Agents = %+v, want %+v", result.Agents, want)
+	}
+}
+
+func TestMainMenu_AgentSelectCancel(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.InAgentSelect() || m.Result() != nil {
+		t.Errorf("Esc should return to the menu, result %+v", m.Result())
+	}
+
+	// Nothing to pick from with a single tool, or on an action row
+	m = tui.NewMainMenu(testProjects(), []string{"claude"}, "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if m.InAgentSelect() {
+		t.Error("agent selection needs more than one tool")
+	}
+}
+
 func TestMainMenu_TotalItemsWithExpanded(t *testing.T) {
 	projects := testProjectsWithWorktrees()
 	m := tui.NewMainMenu(projects, testAITools(), "claude", "animated")
 
-	// No expansions: 3 projects + 4 actions = 7
-	if m.TotalItems() != 7 {
-		t.Errorf("unexpanded: expected 7, got %d", m.TotalItems())
+	// No expansions: 3 projects + 5 actions = 8
+	if m.TotalItems() != 8 {
+		t.Errorf("unexpanded: expected 8, got %d", m.TotalItems())
 	}
 
-	// Expand first project (2 worktrees): 3 + 2 + 4 = 9
+	// Expand first project (2 worktrees): 3 + 2 + 5 = 10
 	m.ToggleWorktrees(0)
-	if m.TotalItems() != 9 {
-		t.Errorf("expanded first: expected 9, got %d", m.TotalItems())
+	if m.TotalItems() != 10 {
+		t.Errorf("expanded first: expected 10, got %d", m.TotalItems())
 	}
 
-	// Expand third project too (1 worktree): 3 + 2 + 1 + 4 = 10
+	// Expand third project too (1 worktree): 3 + 2 + 1 + 5 = 11
 	m.ToggleWorktrees(2)
-	if m.TotalItems() != 10 {
-		t.Errorf("expanded first+third: expected 10, got %d", m.TotalItems())
+	if m.TotalItems() != 11 {
+		t.Errorf("expanded first+third: expected 11, got %d", m.TotalItems())
 	}
 
-	// Collapse first project: 3 + 1 + 4 = 8
+	// Collapse first project: 3 + 1 + 5 = 9
 	m.ToggleWorktrees(0)
-	if m.TotalItems() != 8 {
-		t.Errorf("collapsed first, third expanded: expected 8, got %d", m.TotalItems())
+	if m.TotalItems() != 9 {
+		t.Errorf("collapsed first, third expanded: expected 9, got %d", m.TotalItems())
 	}
 }
 
@@ -3595,10 +3751,10 @@ func TestMainMenu_CalculateLayoutWithWorktrees(t *testing.T) {
 
 	layout1 := m.CalculateLayout(100, 40)
 
-	// Collapsed: 3 projects * 2 rows + 4 actions * 1 row + 7 (chrome) + 1 (separator) = 18
+	// Collapsed: 3 projects * 2 rows + 5 actions * 1 row + 7 (chrome) + 1 (separator) = 19
 	// 7 = top border + title + separator + empty + separator-before-help + help + bottom border
-	// Total = 7 + 6 + 4 + 1 = 18
-	expectedCollapsed := 7 + (3 * 2) + 4 + 1
+	// Total = 7 + 6 + 5 + 1 = 19
+	expectedCollapsed := 7 + (3 * 2) + 5 + 1
 	if layout1.MenuHeight != expectedCollapsed {
 		t.Errorf("collapsed height: got %d, want %d", layout1.MenuHeight, expectedCollapsed)
 	}
@@ -3607,8 +3763,8 @@ func TestMainMenu_CalculateLayoutWithWorktrees(t *testing.T) {
 	m.ToggleWorktrees(0)
 	layout2 := m.CalculateLayout(100, 40)
 
-	// Expanded: 3 projects * 2 rows + 2 worktrees * 1 row + 4 actions * 1 row + 7 + 1 = 20
-	expectedExpanded := 7 + (3 * 2) + 2 + 4 + 1
+	// Expanded: 3 projects * 2 rows + 2 worktrees * 1 row + 5 actions * 1 row + 7 + 1 = 21
+	expectedExpanded := 7 + (3 * 2) + 2 + 5 + 1
 	if layout2.MenuHeight != expectedExpanded {
 		t.Errorf("expanded height: got %d, want %d", layout2.MenuHeight, expectedExpanded)
 	}
@@ -3635,16 +3791,16 @@ func TestMainMenu_WorktreeFullFlow(t *testing.T) {
 	m := tui.NewMainMenu(projects, []string{"claude"}, "claude", "animated")
 	m.SetSize(100, 40)
 
-	// Verify initial state: 2 projects + 4 actions = 6
-	if m.TotalItems() != 6 {
-		t.Fatalf("initial total: expected 6, got %d", m.TotalItems())
+	// Verify initial state: 2 projects + 5 actions = 7
+	if m.TotalItems() != 7 {
+		t.Fatalf("initial total: expected 7, got %d", m.TotalItems())
 	}
 
 	// Press 'w' to expand project 0 (which has 1 worktree)
 	wKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}}
 	m.Update(wKey)
-	if m.TotalItems() != 7 { // 2 projects + 1 worktree + 4 actions
-		t.Fatalf("after expand: expected 7, got %d", m.TotalItems())
+	if m.TotalItems() != 8 { // 2 projects + 1 worktree + 5 actions
+		t.Fatalf("after expand: expected 8, got %d", m.TotalItems())
 	}
 
 	// Move down to worktree
@@ -3673,3 +3829,1298 @@ func TestMainMenu_WorktreeFullFlow(t *testing.T) {
 		t.Errorf("action: got %q, want %q", result.Action, "select-project")
 	}
 }
+
+func TestMainMenu_LiveSessionIndicator(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "static")
+	m.SetSize(100, 40)
+	m.SetLiveSessions(map[string][]string{
+		"ghost-tab": {"dev-ghost-tab-100"},
+		"my-app":    {"dev-my-app-200", "dev-my-app-300"},
+	})
+
+	view := m.View()
+	if !strings.Contains(view, "● live") {
+		t.Error("expected single live session indicator in view")
+	}
+	if !strings.Contains(view, "● 2 live") {
+		t.Error("expected live session count indicator in view")
+	}
+}
+
+func TestMainMenu_SelectProjectWithoutSessions_Quits(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetLiveSessions(map[string][]string{"my-app": {"dev-my-app-200"}})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected quit command for project without live sessions")
+	}
+	if m.InSessionMode() {
+		t.Error("should not enter session mode without live sessions")
+	}
+	if m.Result().Session != "" {
+		t.Errorf("expected empty session, got %q", m.Result().Session)
+	}
+}
+
+func TestMainMenu_SelectProjectWithSessions_EntersSessionMode(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetLiveSessions(map[string][]string{"ghost-tab": {"dev-ghost-tab-100"}})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("entering session mode should not quit")
+	}
+	if !m.InSessionMode() {
+		t.Fatal("expected session mode for project with live sessions")
+	}
+	if m.Result() != nil {
+		t.Error("expected no result while choosing a session")
+	}
+}
+
+func TestMainMenu_SessionMode_Attach(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetLiveSessions(map[string][]string{"ghost-tab": {"dev-ghost-tab-100", "dev-ghost-tab-200"}})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected quit command after choosing a session")
+	}
+
+	result := m.Result()
+	if result.Action != "select-project" {
+		t.Errorf("Action: expected select-project, got %q", result.Action)
+	}
+	if result.Session != "dev-ghost-tab-200" {
+		t.Errorf("Session: expected dev-ghost-tab-200, got %q", result.Session)
+	}
+	if result.Path != "/Users/jack/ghost-tab" {
+		t.Errorf("Path: expected project path, got %q", result.Path)
+	}
+}
+
+func TestMainMenu_SessionMode_New(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetLiveSessions(map[string][]string{"ghost-tab": {"dev-ghost-tab-100"}})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+
+	result := m.Result()
+	if result == nil {
+		t.Fatal("expected result after choosing new session")
+	}
+	if result.Session != "" {
+		t.Errorf("expected empty session for new session, got %q", result.Session)
+	}
+	if result.Name != "ghost-tab" {
+		t.Errorf("Name: expected ghost-tab, got %q", result.Name)
+	}
+}
+
+func TestMainMenu_SessionMode_EscReturnsToMenu(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetLiveSessions(map[string][]string{"ghost-tab": {"dev-ghost-tab-100"}})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.InSessionMode() {
+		t.Error("Esc should leave session mode")
+	}
+	if m.Result() != nil {
+		t.Error("Esc in session mode should not produce a result")
+	}
+}
+
+func TestMainMenu_SessionMode_NavigationWraps(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetLiveSessions(map[string][]string{"ghost-tab": {"dev-ghost-tab-100"}})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if m.SessionSelected() != 1 {
+		t.Errorf("Up from first option should wrap to New session (1), got %d", m.SessionSelected())
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if m.SessionSelected() != 0 {
+		t.Errorf("j from last option should wrap to 0, got %d", m.SessionSelected())
+	}
+}
+
+func TestMainMenu_SessionMode_ViewListsSessions(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "static")
+	m.SetSize(100, 40)
+	m.SetLiveSessions(map[string][]string{"ghost-tab": {"dev-ghost-tab-100"}})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	view := m.View()
+	if !strings.Contains(view, "Attach  dev-ghost-tab-100") {
+		t.Error("expected session option in view")
+	}
+	if !strings.Contains(view, "New session") {
+		t.Error("expected New session option in view")
+	}
+}
+
+func TestMainMenu_TogglePersistSession(t *testing.T) {
+	dir := t.TempDir()
+	settingsFile := filepath.Join(dir, "settings")
+
+	m := tui.NewMainMenu(nil, []string{"claude"}, "claude", "animated")
+	m.SetSettingsFile(settingsFile)
+	m.SetPersistSession("off")
+	m.TogglePersistSession()
+
+	if m.PersistSession() != "on" {
+		t.Errorf("expected on after toggle, got %q", m.PersistSession())
+	}
+	data, _ := os.ReadFile(settingsFile)
+	if !strings.Contains(string(data), "persist_session=on") {
+		t.Errorf("expected persist_session=on in settings file, got %q", string(data))
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.Result().PersistSession != "on" {
+		t.Errorf("expected persist_session in result, got %q", m.Result().PersistSession)
+	}
+}
+
+func TestMainMenu_SettingsPersistSessionRow(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	m := tui.NewMainMenu(nil, []string{"claude"}, "claude", "static")
+	m.SetSize(100, 40)
+	m.EnterSettings()
+
+	if !strings.Contains(m.View(), "Persist Sessions") {
+		t.Fatal("expected Persist Sessions row in settings")
+	}
+
+	for i := 0; i < 3; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.PersistSession() != "on" {
+		t.Errorf("Enter on Persist Sessions row should toggle it, got %q", m.PersistSession())
+	}
+}
+
+func TestMainMenu_ProjectDefaultAITool(t *testing.T) {
+	projects := []models.Project{
+		{Name: "api", Path: "/srv/api", AITool: "codex"},
+		{Name: "web", Path: "/srv/web", AITool: "not-installed"},
+	}
+	m := tui.NewMainMenu(projects, []string{"claude", "codex"}, "claude", "animated")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	result := m.Result()
+	if result.AITool != "claude" {
+		t.Errorf("AITool should stay the menu selection, got %q", result.AITool)
+	}
+	if result.ProjectAITool != "codex" {
+		t.Errorf("ProjectAITool: expected codex, got %q", result.ProjectAITool)
+	}
+
+	m = tui.NewMainMenu(projects, []string{"claude", "codex"}, "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.Result().ProjectAITool; got != "" {
+		t.Errorf("unavailable project tool should be ignored, got %q", got)
+	}
+}
+
+func TestMainMenu_CycleProfile(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	if got := m.CurrentProfile(); got != "default" {
+		t.Fatalf("initial profile: expected default, got %q", got)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := m.CurrentProfile(); got != "yolo" {
+		t.Errorf("Tab: expected yolo, got %q", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if got := m.CurrentProfile(); got != "resume" {
+		t.Errorf("Shift+Tab should wrap to resume, got %q", got)
+	}
+	if !strings.Contains(m.View(), "Claude Code \u00b7 resume") {
+		t.Error("header should show the chosen profile")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.Result().Profile; got != "resume" {
+		t.Errorf("result profile: expected resume, got %q", got)
+	}
+
+	// Switching tools drops a profile only the old tool has
+	m = tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.CycleProfile("next")
+	m.CycleAITool("next")
+	if got := m.CurrentProfile(); got != "default" {
+		t.Errorf("after switching to codex: expected default, got %q", got)
+	}
+
+	// Tools without profiles have nothing to cycle
+	m = tui.NewMainMenu(testProjects(), []string{"copilot"}, "copilot", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.Result().Profile; got != "" {
+		t.Errorf("copilot has no profiles, got %q", got)
+	}
+}
+
+func TestMainMenu_ProjectDefaultProfile(t *testing.T) {
+	projects := []models.Project{
+		{Name: "api", Path: "/srv/api", Profile: "yolo"},
+		{Name: "web", Path: "/srv/web", AITool: "codex", Profile: "full-auto"},
+	}
+	m := tui.NewMainMenu(projects, []string{"claude", "codex"}, "claude", "animated")
+	if got := m.CurrentProfile(); got != "yolo" {
+		t.Errorf("expected the project's default profile, got %q", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.Result().Profile; got != "" {
+		t.Errorf("project default should be left to launch, got %q", got)
+	}
+
+	// Tab starts from the project's default
+	m = tui.NewMainMenu(projects, []string{"claude", "codex"}, "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.Result().Profile; got != "default" {
+		t.Errorf("Shift+Tab from yolo: expected default, got %q", got)
+	}
+
+	// A project launched with another tool ignores the chosen profile
+	m = tui.NewMainMenu(projects, []string{"claude", "codex"}, "claude", "animated")
+	m.CycleProfile("next")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.Result().Profile; got != "" {
+		t.Errorf("profile for another tool should be dropped, got %q", got)
+	}
+}
+
+func groupedProjects() []models.Project {
+	return []models.Project{
+		{Name: "api", Path: "/w/api", Group: "work"},
+		{Name: "dotfiles", Path: "/p/dotfiles"},
+		{Name: "web", Path: "/w/web", Group: "work"},
+		{Name: "blog", Path: "/p/blog", Group: "personal"},
+	}
+}
+
+func TestMainMenu_Groups_Order(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+
+	// dotfiles (ungrouped), work header, api, web, personal header, blog, 5 actions
+	expected := []struct {
+		kind string
+		name string
+	}{
+		{"project", "dotfiles"},
+		{"group", "work"},
+		{"project", "api"},
+		{"project", "web"},
+		{"group", "personal"},
+		{"project", "blog"},
+	}
+	if m.TotalItems() != len(expected)+5 {
+		t.Fatalf("TotalItems: expected %d, got %d", len(expected)+5, m.TotalItems())
+	}
+	for i, exp := range expected {
+		kind, idx, _ := m.ResolveItem(i)
+		if kind != exp.kind {
+			t.Errorf("item %d: expected %s, got %s", i, exp.kind, kind)
+			continue
+		}
+		if kind == "group" {
+			if got := m.GroupName(idx); got != exp.name {
+				t.Errorf("item %d: expected group %q, got %q", i, exp.name, got)
+			}
+		}
+	}
+	if kind, idx, _ := m.ResolveItem(6); kind != "action" || idx != 0 {
+		t.Errorf("item 6: expected first action, got %s %d", kind, idx)
+	}
+}
+
+func TestMainMenu_Groups_NoGroupsUnchanged(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	for i := 0; i < 3; i++ {
+		if kind, idx, _ := m.ResolveItem(i); kind != "project" || idx != i {
+			t.Errorf("item %d: expected project %d, got %s %d", i, i, kind, idx)
+		}
+	}
+}
+
+func TestMainMenu_Groups_EnterTogglesCollapse(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown}) // work header
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil {
+		t.Error("toggling a group should not quit")
+	}
+	if !m.IsGroupCollapsed("work") {
+		t.Fatal("expected work group collapsed after Enter")
+	}
+	if m.TotalItems() != 9 {
+		t.Errorf("TotalItems with work collapsed: expected 9, got %d", m.TotalItems())
+	}
+	if kind, _, _ := m.ResolveItem(m.SelectedItem()); kind != "group" {
+		t.Errorf("selection should stay on the group header, got %s", kind)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsGroupCollapsed("work") {
+		t.Error("second Enter should expand the group again")
+	}
+	if m.Result() != nil {
+		t.Error("toggling a group should not produce a result")
+	}
+}
+
+func TestMainMenu_Groups_CollapseSnapsSelectionToHeader(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown}) // web
+
+	m.ToggleGroup(1)
+	kind, idx, _ := m.ResolveItem(m.SelectedItem())
+	if kind != "group" || m.GroupName(idx) != "work" {
+		t.Errorf("expected selection on work header, got %s %d", kind, idx)
+	}
+}
+
+func TestMainMenu_Groups_CollapseKeepsSelectionBelow(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	for i := 0; i < 5; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyDown}) // blog
+	}
+
+	m.ToggleGroup(1)
+	kind, idx, _ := m.ResolveItem(m.SelectedItem())
+	if kind != "project" || idx != 3 {
+		t.Errorf("expected selection to stay on blog (project 3), got %s %d", kind, idx)
+	}
+}
+
+func TestMainMenu_Groups_WKeyTogglesGroup(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if !m.IsGroupCollapsed("work") {
+		t.Error("w on a group header should collapse it")
+	}
+}
+
+func TestMainMenu_Groups_JumpIntoCollapsedGroup(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.SetCollapsedGroups([]string{"work"})
+
+	// Projects are numbered in display order: 1 dotfiles, 2 api, 3 web, 4 blog
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+	if m.IsGroupCollapsed("work") {
+		t.Error("jumping to a project should expand its group")
+	}
+	result := m.Result()
+	if result == nil || result.Name != "web" {
+		t.Errorf("expected web to be selected, got %+v", result)
+	}
+}
+
+func TestMainMenu_Groups_MapRowToItem(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.SetSize(80, 40)
+
+	// Rows: 4-5 dotfiles, 6 work header, 7-8 api, 9-10 web, 11 personal, 12-13 blog,
+	// 14 separator, 15 first action
+	tests := []struct {
+		row  int
+		want int
+	}{
+		{4, 0}, {5, 0}, {6, 1}, {7, 2}, {10, 3}, {11, 4}, {13, 5}, {14, -1}, {15, 6},
+	}
+	for _, tt := range tests {
+		if got := m.MapRowToItem(tt.row); got != tt.want {
+			t.Errorf("MapRowToItem(%d): expected %d, got %d", tt.row, tt.want, got)
+		}
+	}
+
+	m.SetCollapsedGroups([]string{"work"})
+	// 6 work header, 7 personal header, 8-9 blog
+	if got := m.MapRowToItem(7); got != 2 {
+		t.Errorf("with work collapsed, row 7 should be the personal header (2), got %d", got)
+	}
+}
+
+func TestMainMenu_Groups_MouseDoubleClickToggles(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.SetSize(80, 40)
+	m.View()
+	offset := m.CenterOffsetY()
+
+	click := tea.MouseMsg{X: 10, Y: offset + 6, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+	m.Update(click)
+	m.Update(click)
+	if !m.IsGroupCollapsed("work") {
+		t.Error("clicking a selected group header should collapse it")
+	}
+}
+
+func TestMainMenu_Groups_PersistCollapsed(t *testing.T) {
+	settingsFile := filepath.Join(t.TempDir(), "settings")
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.SetSettingsFile(settingsFile)
+
+	m.ToggleGroup(2) // personal
+	m.ToggleGroup(1) // work
+
+	data, _ := os.ReadFile(settingsFile)
+	if !strings.Contains(string(data), "collapsed_groups=personal,work") {
+		t.Errorf("expected collapsed groups persisted, got %q", string(data))
+	}
+
+	m.ToggleGroup(1)
+	data, _ = os.ReadFile(settingsFile)
+	if !strings.Contains(string(data), "collapsed_groups=personal\n") {
+		t.Errorf("expected work removed from collapsed groups, got %q", string(data))
+	}
+}
+
+func TestMainMenu_Groups_View(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "static")
+	m.SetSize(100, 40)
+	m.SetCollapsedGroups([]string{"personal"})
+
+	view := m.View()
+	if !strings.Contains(view, "▾ work") {
+		t.Error("expected expanded work header")
+	}
+	if !strings.Contains(view, "2 projects") {
+		t.Error("expected project count on work header")
+	}
+	if !strings.Contains(view, "▸ personal") {
+		t.Error("expected collapsed personal header")
+	}
+	if strings.Contains(view, "blog") {
+		t.Error("projects of a collapsed group should be hidden")
+	}
+}
+
+func TestMainMenu_Groups_CalculateLayoutHeight(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	full := m.CalculateLayout(100, 40).MenuHeight
+
+	m.SetCollapsedGroups([]string{"work"})
+	collapsed := m.CalculateLayout(100, 40).MenuHeight
+	if full-collapsed != 4 {
+		t.Errorf("collapsing two projects should save 4 rows, got %d -> %d", full, collapsed)
+	}
+}
+
+func filterProjects() []models.Project {
+	return []models.Project{
+		{Name: "ghost-tab", Path: "/Users/jack/ghost-tab", Tags: []string{"oss"}},
+		{Name: "my-app", Path: "/Users/jack/code/my-app", Worktrees: []models.Worktree{
+			{Path: "/Users/jack/code/my-app-feature", Branch: "feature/payments"},
+			{Path: "/Users/jack/code/my-app-fix", Branch: "fix/login"},
+		}},
+		{Name: "website", Path: "/Users/jack/website", Tags: []string{"client"}},
+	}
+}
+
+func typeFilter(m *tui.MainMenuModel, query string) {
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	for _, r := range query {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestMainMenu_Filter_SlashOpensFilterBar(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !m.InFilterMode() {
+		t.Fatal("expected filter mode after /")
+	}
+	if m.FilterQuery() != "" {
+		t.Errorf("expected empty query, got %q", m.FilterQuery())
+	}
+	if m.TotalItems() != 3+5 {
+		t.Errorf("empty query should list everything, got %d items", m.TotalItems())
+	}
+}
+
+func TestMainMenu_Filter_TypingSendsToQuery(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	// Letters that are shortcuts in the menu (a, d, o, p, s, w, j, k) and digits
+	// must go to the filter instead
+	typeFilter(m, "adopswjk1")
+	if m.FilterQuery() != "adopswjk1" {
+		t.Errorf("expected query %q, got %q", "adopswjk1", m.FilterQuery())
+	}
+	if m.InInputMode() || m.InDeleteMode() || m.InSettingsMode() || m.Result() != nil {
+		t.Error("shortcut keys should not trigger actions while filtering")
+	}
+}
+
+func TestMainMenu_Filter_MatchesNamePathTagAndBranch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"ghost", []string{"ghost-tab"}},
+		{"code", []string{"my-app"}},     // path only
+		{"client", []string{"website"}},  // tag only
+		{"payments", []string{"my-app"}}, // worktree branch
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+			typeFilter(m, tt.query)
+			var got []string
+			for i := 0; i < m.TotalItems(); i++ {
+				if kind, idx, _ := m.ResolveItem(i); kind == "project" {
+					got = append(got, filterProjects()[idx].Name)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("query %q: expected %v, got %v", tt.query, tt.want, got)
+			}
+			if m.FilterMatchCount() != len(tt.want) {
+				t.Errorf("FilterMatchCount: expected %d, got %d", len(tt.want), m.FilterMatchCount())
+			}
+		})
+	}
+}
+
+func TestMainMenu_Filter_BestMatchFirst(t *testing.T) {
+	projects := []models.Project{
+		{Name: "gadget", Path: "/p/gadget"},
+		{Name: "ghost-tab", Path: "/p/ghost-tab"},
+	}
+	m := tui.NewMainMenu(projects, testAITools(), "claude", "animated")
+	typeFilter(m, "gt")
+
+	if kind, idx, _ := m.ResolveItem(0); kind != "project" || idx != 1 {
+		t.Errorf("expected ghost-tab ranked first, got %s %d", kind, idx)
+	}
+	if kind, idx, _ := m.ResolveItem(1); kind != "project" || idx != 0 {
+		t.Errorf("expected gadget ranked second, got %s %d", kind, idx)
+	}
+}
+
+func TestMainMenu_Filter_MatchingWorktreesListed(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	typeFilter(m, "login")
+
+	if kind, idx, _ := m.ResolveItem(0); kind != "project" || idx != 1 {
+		t.Fatalf("expected my-app first, got %s %d", kind, idx)
+	}
+	kind, idx, wt := m.ResolveItem(1)
+	if kind != "worktree" || idx != 1 || wt != 1 {
+		t.Errorf("expected the fix/login worktree, got %s %d %d", kind, idx, wt)
+	}
+	if kind, _, _ := m.ResolveItem(2); kind != "action" {
+		t.Errorf("non-matching worktrees should be hidden, got %s", kind)
+	}
+}
+
+func TestMainMenu_Filter_EnterLaunchesTopMatch(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown}) // selection elsewhere before filtering
+	typeFilter(m, "web")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+	result := m.Result()
+	if result == nil || result.Action != "select-project" || result.Name != "website" {
+		t.Errorf("expected website launched, got %+v", result)
+	}
+}
+
+func TestMainMenu_Filter_EnterOnWorktreeLaunchesWorktree(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	typeFilter(m, "login")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	result := m.Result()
+	if result == nil || result.Path != "/Users/jack/code/my-app-fix" {
+		t.Errorf("expected worktree path, got %+v", result)
+	}
+}
+
+func TestMainMenu_Filter_EnterWithNoMatchesDoesNothing(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	typeFilter(m, "zzz")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || m.Result() != nil || m.InInputMode() {
+		t.Error("Enter with no matches should do nothing")
+	}
+}
+
+func TestMainMenu_Filter_EnterOnActionLeavesFilter(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	typeFilter(m, "web")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown}) // Add new project
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.InFilterMode() {
+		t.Error("activating an action should close the filter")
+	}
+	if m.InputMode() != "add-project" {
+		t.Errorf("expected add-project input mode, got %q", m.InputMode())
+	}
+}
+
+func TestMainMenu_Filter_Backspace(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	typeFilter(m, "zzz")
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.FilterQuery() != "zz" {
+		t.Errorf("expected %q, got %q", "zz", m.FilterQuery())
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if !m.InFilterMode() || m.FilterMatchCount() != 3 {
+		t.Error("clearing the query should keep the filter open and list everything")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.InFilterMode() {
+		t.Error("backspace on an empty query should close the filter")
+	}
+}
+
+func TestMainMenu_Filter_EscClearsAndKeepsSelection(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	typeFilter(m, "web")
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	if m.InFilterMode() || m.FilterQuery() != "" {
+		t.Error("Esc should close and clear the filter")
+	}
+	if m.Result() != nil {
+		t.Error("Esc in filter mode should not quit the menu")
+	}
+	if kind, idx, _ := m.ResolveItem(m.SelectedItem()); kind != "project" || idx != 2 {
+		t.Errorf("selection should stay on website, got %s %d", kind, idx)
+	}
+	if m.TotalItems() != 3+5 {
+		t.Errorf("all projects should be back, got %d items", m.TotalItems())
+	}
+}
+
+func TestMainMenu_Filter_IgnoresGroups(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.SetCollapsedGroups([]string{"work"})
+	typeFilter(m, "api")
+
+	if kind, _, _ := m.ResolveItem(0); kind != "project" {
+		t.Fatalf("filtered rows should not include group headers, got %s", kind)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if result := m.Result(); result == nil || result.Name != "api" {
+		t.Errorf("projects in collapsed groups should still be found, got %+v", result)
+	}
+}
+
+func TestMainMenu_Filter_View(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "static")
+	m.SetSize(100, 40)
+	typeFilter(m, "client")
+
+	view := m.View()
+	if !strings.Contains(view, "/ client") {
+		t.Error("expected the filter bar with the query")
+	}
+	if !strings.Contains(view, "1 match") {
+		t.Error("expected the match count")
+	}
+	if !strings.Contains(view, "#client") {
+		t.Error("expected the matching tag to be shown")
+	}
+	if strings.Contains(view, "ghost-tab") {
+		t.Error("non-matching projects should be hidden")
+	}
+	if !strings.Contains(view, "Esc clear filter") {
+		t.Error("expected filter help text")
+	}
+
+	typeFilter(m, "zzz")
+	if !strings.Contains(m.View(), "no matches") {
+		t.Error("expected no matches message")
+	}
+}
+
+func TestMainMenu_Filter_HighlightsMatches(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(termenv.Ascii)
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "static")
+	m.SetSize(100, 40)
+
+	if !strings.Contains(m.View(), "ghost-tab") {
+		t.Fatal("unfiltered name should be rendered in one piece")
+	}
+	typeFilter(m, "gt")
+	// Matched characters are styled separately, so the name is split by
+	// escape sequences
+	if strings.Contains(m.View(), "ghost-tab") {
+		t.Error("expected matched characters of the name to be highlighted")
+	}
+}
+
+func TestMainMenu_Filter_MapRowToItem(t *testing.T) {
+	m := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	m.SetSize(80, 40)
+	typeFilter(m, "web")
+
+	// Filter bar pushes items down by one: border 0, title 1, separator 2,
+	// filter 3, empty 4, first match 5-6, separator 7, first action 8
+	if got := m.MapRowToItem(5); got != 0 {
+		t.Errorf("row 5: expected 0, got %d", got)
+	}
+	if got := m.MapRowToItem(7); got != -1 {
+		t.Errorf("row 7: expected -1, got %d", got)
+	}
+	if got := m.MapRowToItem(8); got != 1 {
+		t.Errorf("row 8: expected 1, got %d", got)
+	}
+
+	base := tui.NewMainMenu(filterProjects(), testAITools(), "claude", "animated")
+	if m.CalculateLayout(100, 40).MenuHeight != base.CalculateLayout(100, 40).MenuHeight-4+1 {
+		t.Error("layout height should drop the hidden projects and add the filter bar")
+	}
+}
+
+func sortUsage() map[string]history.Usage {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	return map[string]history.Usage{
+		"ghost-tab": {Launches: 1, LastUsed: base.Add(-48 * time.Hour)},
+		"my-app":    {Launches: 7, LastUsed: base.Add(-24 * time.Hour)},
+		"website":   {Launches: 2, LastUsed: base},
+	}
+}
+
+// projectOrder returns the names of the first n projects in display order,
+// by launching each one through its number shortcut position.
+func projectOrder(m *tui.MainMenuModel, n int) string {
+	var names []string
+	for i := 1; i <= n; i++ {
+		m.JumpTo(i)
+		m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		names = append(names, m.Result().Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestMainMenu_ProjectSort(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"manual", "ghost-tab,my-app,website"},
+		{"recent", "website,my-app,ghost-tab"},
+		{"frequent", "my-app,website,ghost-tab"},
+		{"bogus", "ghost-tab,my-app,website"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+			m.SetProjectUsage(sortUsage())
+			m.SetProjectSort(tt.mode)
+			if got := projectOrder(m, 3); got != tt.want {
+				t.Errorf("sort %q: expected %s, got %s", tt.mode, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMainMenu_ProjectSort_NeverLaunchedKeepFileOrder(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetProjectUsage(map[string]history.Usage{
+		"website": {Launches: 1, LastUsed: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	m.SetProjectSort("recent")
+
+	if got := projectOrder(m, 3); got != "website,ghost-tab,my-app" {
+		t.Errorf("never-launched projects should follow in file order, got %s", got)
+	}
+}
+
+func TestMainMenu_ProjectSort_WithinGroups(t *testing.T) {
+	m := tui.NewMainMenu(groupedProjects(), []string{"claude"}, "claude", "animated")
+	m.SetProjectUsage(map[string]history.Usage{
+		"web": {Launches: 3, LastUsed: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)},
+	})
+	m.SetProjectSort("frequent")
+
+	// dotfiles (ungrouped), work header, web, api, ...
+	if kind, _, _ := m.ResolveItem(1); kind != "group" {
+		t.Fatalf("groups should stay contiguous, item 1 is %s", kind)
+	}
+	m.MoveDown()
+	m.MoveDown()
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Result() == nil || m.Result().Name != "web" {
+		t.Errorf("expected web first in the work group, got %+v", m.Result())
+	}
+}
+
+func TestMainMenu_CycleProjectSort_PersistsAndKeepsSelection(t *testing.T) {
+	settingsFile := filepath.Join(t.TempDir(), "settings")
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.SetSettingsFile(settingsFile)
+	m.SetProjectUsage(sortUsage())
+	m.MoveDown() // my-app
+
+	m.CycleProjectSort()
+	if m.ProjectSort() != "recent" {
+		t.Fatalf("expected recent, got %q", m.ProjectSort())
+	}
+	data, _ := os.ReadFile(settingsFile)
+	if !strings.Contains(string(data), "project_sort=recent") {
+		t.Errorf("expected project_sort persisted, got %q", string(data))
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Result() == nil || m.Result().Name != "my-app" {
+		t.Errorf("selection should follow my-app after re-sorting, got %+v", m.Result())
+	}
+
+	m2 := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m2.CycleProjectSortReverse()
+	if m2.ProjectSort() != "frequent" {
+		t.Errorf("reverse from manual should wrap to frequent, got %q", m2.ProjectSort())
+	}
+}
+
+func TestMainMenu_SettingsSortProjectsRow(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	m := tui.NewMainMenu(testProjects(), []string{"claude"}, "claude", "static")
+	m.SetSize(100, 40)
+	m.EnterSettings()
+
+	view := m.View()
+	if !strings.Contains(view, "Sort Projects") || !strings.Contains(view, "[Manual]") {
+		t.Fatal("expected Sort Projects row in settings")
+	}
+
+	for i := 0; i < 4; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.ProjectSort() != "recent" {
+		t.Errorf("Enter on Sort Projects should cycle it, got %q", m.ProjectSort())
+	}
+	if !strings.Contains(m.View(), "[Recent]") {
+		t.Error("expected [Recent] after cycling")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.ProjectSort() != "manual" {
+		t.Errorf("Left should cycle back, got %q", m.ProjectSort())
+	}
+}
+
+// worktreeRepo creates a git repository with a commit and a feature/login
+// branch, returning a menu over it with the projects file alongside.
+func worktreeRepo(t *testing.T) (*tui.MainMenuModel, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "app")
+	os.MkdirAll(repo, 0755)
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "feature/login"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	m := tui.NewMainMenu([]models.Project{{Name: "app", Path: repo}}, testAITools(), "claude", "animated")
+	m.SetProjectsFile(filepath.Join(dir, "projects"))
+	return m, repo
+}
+
+func typeText(m *tui.MainMenuModel, text string) {
+	for _, r := range text {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// updateAndDrain sends msg to the menu and runs the command it returns,
+// for keys that hand their work to a background command.
+func updateAndDrain(m *tui.MainMenuModel, msg tea.Msg) {
+	_, cmd := m.Update(msg)
+	drainCmd(m, cmd)
+}
+
+func TestMainMenu_NewWorktree(t *testing.T) {
+	m, repo := worktreeRepo(t)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.InputMode() != "worktree-branch" {
+		t.Fatalf("expected worktree-branch input, got %q", m.InputMode())
+	}
+	if view := m.View(); !strings.Contains(view, "New Worktree") || !strings.Contains(view, "Branch:") {
+		t.Errorf("expected branch input box:\n%s", view)
+	}
+
+	typeText(m, "spike")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.InputMode() != "worktree-path" {
+		t.Fatalf("expected worktree-path input, got %q", m.InputMode())
+	}
+	if !strings.Contains(m.View(), "app-spike") {
+		t.Errorf("expected sibling directory default:\n%s", m.View())
+	}
+
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.InInputMode() {
+		t.Fatalf("expected input closed, error view:\n%s", m.View())
+	}
+	if m.FeedbackMsg() != "Created worktree spike" {
+		t.Errorf("FeedbackMsg = %q", m.FeedbackMsg())
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(repo), "app-spike")); err != nil {
+		t.Errorf("worktree directory not created: %v", err)
+	}
+	if !m.IsExpanded(0) {
+		t.Error("expected project expanded to show the new worktree")
+	}
+	itemType, _, wt := m.ResolveItem(m.SelectedItem())
+	if itemType != "worktree" || wt != 0 {
+		t.Errorf("expected new worktree selected, got %s %d", itemType, wt)
+	}
+}
+
+func TestMainMenu_NewWorktree_BranchAutocomplete(t *testing.T) {
+	m, _ := worktreeRepo(t)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	typeText(m, "feat")
+	if !strings.Contains(m.View(), "feature/login") {
+		t.Fatalf("expected branch suggestion:\n%s", m.View())
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.InputMode() != "worktree-path" {
+		t.Fatalf("expected worktree-path input, got %q", m.InputMode())
+	}
+	if !strings.Contains(m.View(), "app-feature-login") {
+		t.Errorf("expected directory from completed branch:\n%s", m.View())
+	}
+}
+
+func TestMainMenu_NewWorktree_GitErrorShown(t *testing.T) {
+	m, _ := worktreeRepo(t)
+
+	// main is checked out in the project itself
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	typeText(m, "main")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.InputMode() != "worktree-path" {
+		t.Fatalf("expected to stay in worktree-path input, got %q", m.InputMode())
+	}
+	if !strings.Contains(m.View(), "main") || !strings.Contains(strings.ToLower(m.View()), "already") {
+		t.Errorf("expected git error in view:\n%s", m.View())
+	}
+}
+
+func TestMainMenu_NewWorktree_NeedsProject(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "animated")
+	m.MoveUp() // wraps to the last action
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.InInputMode() {
+		t.Error("should not open worktree input on an action row")
+	}
+	if m.FeedbackMsg() == "" {
+		t.Error("expected feedback explaining a project is needed")
+	}
+}
+
+func TestMainMenu_RemoveWorktree(t *testing.T) {
+	m, repo := worktreeRepo(t)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	typeText(m, "spike")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyEnter})
+	wtPath := filepath.Join(filepath.Dir(repo), "app-spike")
+	os.WriteFile(filepath.Join(wtPath, "notes.txt"), []byte("wip"), 0644)
+
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !m.InRemoveWorktreeMode() {
+		t.Fatal("expected remove confirmation")
+	}
+	if view := m.View(); !strings.Contains(view, "Remove spike?") || !strings.Contains(view, "1 uncommitted change will be lost") {
+		t.Errorf("expected warning about uncommitted changes:\n%s", view)
+	}
+
+	// N cancels and keeps the worktree
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.InRemoveWorktreeMode() {
+		t.Fatal("expected n to cancel")
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Fatalf("worktree removed on cancel: %v", err)
+	}
+
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Errorf("expected worktree directory removed, got %v", err)
+	}
+	if m.FeedbackMsg() != "Removed worktree spike" {
+		t.Errorf("FeedbackMsg = %q", m.FeedbackMsg())
+	}
+	if itemType, idx, _ := m.ResolveItem(m.SelectedItem()); itemType != "project" || idx != 0 {
+		t.Errorf("expected selection back on the project, got %s %d", itemType, idx)
+	}
+	if m.IsExpanded(0) {
+		t.Error("project without worktrees should not stay expanded")
+	}
+}
+
+func TestMainMenu_RemoveWorktree_CleanWorktree(t *testing.T) {
+	m, _ := worktreeRepo(t)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	typeText(m, "spike")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !strings.Contains(m.View(), "No uncommitted changes") {
+		t.Errorf("expected clean worktree note:\n%s", m.View())
+	}
+}
+
+func TestMainMenu_RemoveWorktree_UnknownChangesNotForced(t *testing.T) {
+	m, repo := worktreeRepo(t)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	typeText(m, "spike")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyEnter})
+	wtPath := filepath.Join(filepath.Dir(repo), "app-spike")
+	os.WriteFile(filepath.Join(wtPath, "notes.txt"), []byte("wip"), 0644)
+	// A corrupt index makes git status fail, so the changes can't be counted
+	os.WriteFile(filepath.Join(repo, ".git", "worktrees", "app-spike", "index"), []byte("garbage"), 0644)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !strings.Contains(m.View(), "Checking for uncommitted changes") {
+		t.Errorf("expected the count to be pending:\n%s", m.View())
+	}
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyEsc})
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !strings.Contains(m.View(), "Could not check for uncommitted changes") {
+		t.Errorf("expected unknown changes warning:\n%s", m.View())
+	}
+
+	updateAndDrain(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if _, err := os.Stat(filepath.Join(wtPath, "notes.txt")); err != nil {
+		t.Fatalf("worktree with unknown changes was force-removed: %v", err)
+	}
+	if fb := m.FeedbackMsg(); !strings.HasPrefix(fb, "Not removed: ") || !strings.Contains(fb, "than expected") {
+		t.Errorf("expected git's refusal in feedback, got %q", fb)
+	}
+}
+
+func TestMainMenu_RemoveWorktree_NeedsWorktree(t *testing.T) {
+	m := tui.NewMainMenu(testProjectsWithWorktrees(), testAITools(), "claude", "animated")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if m.InRemoveWorktreeMode() {
+		t.Error("should not confirm removal on a project row")
+	}
+	if m.FeedbackMsg() == "" {
+		t.Error("expected feedback explaining a worktree is needed")
+	}
+}
+
+// drainCmd runs cmd and any batched commands, feeding their messages back
+// into the menu.
+func drainCmd(m *tui.MainMenuModel, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			drainCmd(m, c)
+		}
+	default:
+		_, next := m.Update(msg)
+		drainCmd(m, next)
+	}
+}
+
+func TestMainMenu_GitStatusBadge(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "static")
+
+	if strings.Contains(m.View(), "\u2713") {
+		t.Fatal("no badge expected before the status arrives")
+	}
+
+	m.Update(tui.NewGitStatusMsg("/Users/jack/ghost-tab", models.GitStatus{Branch: "main"}))
+	m.Update(tui.NewGitStatusMsg("/Users/jack/my-app", models.GitStatus{Branch: "dev", Dirty: true, Ahead: 2, Behind: 1, Stashes: 3}))
+	view := m.View()
+
+	for _, want := range []string{"main \u2713", "dev \u25cf \u21912 \u21931 \u22613"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing badge %q:\n%s", want, view)
+		}
+	}
+	// Badges sit on the path line, so the path is still there
+	if !strings.Contains(view, "/Users/jack/my-app") {
+		t.Errorf("expected path beside the badge:\n%s", view)
+	}
+}
+
+func TestMainMenu_AIToolVersion(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "static")
+	if m.Init() == nil {
+		t.Fatal("Init() should read git status")
+	}
+	m.SetCheckAITools(true)
+	if m.Init() == nil {
+		t.Fatal("Init() should check AI tool versions when enabled")
+	}
+
+	m.Update(tui.NewAIToolHealthMsg([]models.AITool{
+		{Name: "claude", Installed: true, Status: models.ToolOK, Version: "1.0.42"},
+		{Name: "codex", Installed: true, Status: models.ToolOutdated, Version: "0.1.0", MinVersion: "0.2.0"},
+		{Name: "copilot", Installed: true, Status: models.ToolBroken},
+	}))
+	if !strings.Contains(m.View(), "Claude Code 1.0.42") {
+		t.Errorf("header should show the version:\n%s", m.View())
+	}
+	m.CycleAITool("next")
+	if !strings.Contains(m.View(), "Codex CLI 0.1.0 \u26a0") {
+		t.Errorf("header should warn about an outdated tool:\n%s", m.View())
+	}
+	m.CycleAITool("next")
+	if !strings.Contains(m.View(), "Copilot CLI \u26a0") {
+		t.Errorf("header should warn about a broken tool:\n%s", m.View())
+	}
+}
+
+func TestMainMenu_AIToolVersion_DroppedWhenTooWide(t *testing.T) {
+	m := tui.NewMainMenu(testProjects(), testAITools(), "claude", "static")
+	m.CycleProfile("next")
+	m.Update(tui.NewAIToolHealthMsg([]models.AITool{
+		{Name: "claude", Installed: true, Status: models.ToolOK, Version: "1.0.42-beta.20251017"},
+	}))
+	view := m.View()
+	if strings.Contains(view, "beta.20251017") {
+		t.Errorf("a version that doesn't fit should be left out:\n%s", view)
+	}
+	if !strings.Contains(view, "Claude Code \u00b7 ") {
+		t.Errorf("tool and profile should still show:\n%s", view)
+	}
+}
+
+func TestMainMenu_GitStatusBadge_Worktree(t *testing.T) {
+	m := tui.NewMainMenu(testProjectsWithWorktrees(), testAITools(), "claude", "static")
+	m.ToggleWorktrees(0)
+
+	m.Update(tui.NewGitStatusMsg("/Users/jack/wt/feature-auth", models.GitStatus{Branch: "feature/auth", Dirty: true, Stashes: 2}))
+	view := m.View()
+
+	var row string
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "feature/auth") {
+			row = line
+		}
+	}
+	if !strings.Contains(row, "\u25cf") {
+		t.Errorf("expected dirty mark on worktree row %q", row)
+	}
+	if strings.Count(row, "feature/auth") != 1 || strings.Contains(row, "\u2261") {
+		t.Errorf("worktree badge should not repeat the branch or stashes: %q", row)
+	}
+}
+
+func TestMainMenu_GitStatusBadge_LongPathTruncated(t *testing.T) {
+	long := "/Users/jack/code/some/deeply/nested/directory/project"
+	m := tui.NewMainMenu([]models.Project{{Name: "p", Path: long}}, testAITools(), "claude", "static")
+	m.Update(tui.NewGitStatusMsg(long, models.GitStatus{Branch: "main"}))
+
+	found := false
+	for _, line := range strings.Split(m.View(), "\n") {
+		if strings.Contains(line, "main \u2713") {
+			found = true
+			if !strings.Contains(line, "\u2026") {
+				t.Errorf("expected path truncated to make room for the badge: %q", line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("badge not shown:\n%s", m.View())
+	}
+}
+
+func TestMainMenu_RefreshGitStatus(t *testing.T) {
+	m, repo := worktreeRepo(t)
+	m.SetGitStatusCache(models.NewGitStatusCache(filepath.Join(t.TempDir(), "cache.json"), time.Minute))
+	os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x"), 0644)
+
+	drainCmd(m, m.RefreshGitStatus())
+
+	if !strings.Contains(m.View(), "main \u25cf") {
+		t.Errorf("expected dirty badge after refresh:\n%s", m.View())
+	}
+}
+
+func TestMainMenu_RefreshGitStatus_TildePath(t *testing.T) {
+	_, repo := worktreeRepo(t)
+	t.Setenv("HOME", filepath.Dir(repo))
+	m := tui.NewMainMenu([]models.Project{{Name: "app", Path: "~/app"}}, testAITools(), "claude", "static")
+
+	drainCmd(m, m.RefreshGitStatus())
+
+	if !strings.Contains(m.View(), "main \u2713") {
+		t.Errorf("expected badge for a ~ project path:\n%s", m.View())
+	}
+}
+
+func TestMainMenu_NewWorktree_ReadsStatus(t *testing.T) {
+	m, _ := worktreeRepo(t)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	typeText(m, "spike")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	drainCmd(m, cmd)
+
+	found := false
+	for _, line := range strings.Split(m.View(), "\n") {
+		if strings.Contains(line, "spike") && !strings.Contains(line, "Created") {
+			found = true
+			if !strings.Contains(line, "\u2713") {
+				t.Errorf("expected clean badge on the new worktree: %q", line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("new worktree row not shown:\n%s", m.View())
+	}
+}
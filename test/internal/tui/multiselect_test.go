@@ -454,3 +454,43 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestAgentSelect_DirsAndResult(t *testing.T) {
+	dirs := []string{"/srv/api", "/srv/api-feature"}
+	m := tui.NewAgentSelect(testTools(), "codex", dirs, 1)
+
+	checked := m.Checked()
+	if checked[0] || !checked[1] || checked[2] {
+		t.Fatalf("only the current tool should be checked, got %v", checked)
+	}
+	if got := m.Dirs(); got[0] != "/srv/api-feature" || got[3] != "/srv/api-feature" {
+		t.Errorf("every tool should start in the given dir, got %v", got)
+	}
+
+	// Check claude and move it to the project itself
+	var model tea.Model = m
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	result := model.(tui.MultiSelectModel).Result()
+	if result == nil || !result.Confirmed {
+		t.Fatalf("expected a confirmed result, got %+v", result)
+	}
+	if len(result.Tools) != 2 || result.Tools[0] != "claude" || result.Tools[1] != "codex" {
+		t.Errorf("Tools = %v", result.Tools)
+	}
+	if len(result.Dirs) != 2 || result.Dirs[0] != "/srv/api" || result.Dirs[1] != "/srv/api-feature" {
+		t.Errorf("Dirs = %v", result.Dirs)
+	}
+}
+
+func TestMultiSelect_NoDirsOutsideAgentSelect(t *testing.T) {
+	var model tea.Model = tui.NewMultiSelect(testTools())
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	result := model.(tui.MultiSelectModel).Result()
+	if result.Dirs != nil {
+		t.Errorf("installer selection should carry no dirs, got %v", result.Dirs)
+	}
+}
@@ -1,276 +1,18 @@
 package util_test
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/jackuait/ghost-tab/internal/aitools"
 	"github.com/jackuait/ghost-tab/internal/util"
 )
 
-func TestBuildAILaunchCmd(t *testing.T) {
-	// --- Basic tool behavior ---
-
-	t.Run("claude passes args through", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("claude", "/usr/bin/claude", "", []string{"--resume"})
-		expected := "/usr/bin/claude --resume"
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("codex uses --cd flag", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/my/project", nil)
-		expected := `/usr/bin/codex --cd "/my/project"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("copilot has no extra args", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("copilot", "/usr/bin/copilot", "", nil)
-		expected := "/usr/bin/copilot"
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("opencode passes project dir", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("opencode", "/usr/bin/opencode", "/my/project", nil)
-		expected := `/usr/bin/opencode "/my/project"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	// --- codex edge cases ---
-
-	t.Run("handles project path with spaces (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/path/with spaces", nil)
-		expected := `/usr/bin/codex --cd "/path/with spaces"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with spaces (opencode)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("opencode", "/usr/bin/opencode", "/path/with spaces", nil)
-		expected := `/usr/bin/opencode "/path/with spaces"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with single quotes (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/path/with'quotes", nil)
-		expected := `/usr/bin/codex --cd "/path/with'quotes"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with double quotes (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", `/path/with"quotes`, nil)
-		expected := `/usr/bin/codex --cd "/path/with"quotes"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with double quotes (opencode)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("opencode", "/usr/bin/opencode", `/path/with"quotes`, nil)
-		expected := `/usr/bin/opencode "/path/with"quotes"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with unicode (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/path/\u00e9moji/\U0001F47B", nil)
-		expected := "/usr/bin/codex --cd \"/path/\u00e9moji/\U0001F47B\""
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with unicode (opencode)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("opencode", "/usr/bin/opencode", "/path/\u00e9moji/\U0001F47B", nil)
-		expected := "/usr/bin/opencode \"/path/\u00e9moji/\U0001F47B\""
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles very long project path (codex)", func(t *testing.T) {
-		longPath := strings.Repeat("/very/long/path", 50)
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", longPath, nil)
-		expected := `/usr/bin/codex --cd "` + longPath + `"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles very long project path (opencode)", func(t *testing.T) {
-		longPath := strings.Repeat("/very/long/path", 50)
-		result := util.BuildAILaunchCmd("opencode", "/usr/bin/opencode", longPath, nil)
-		expected := `/usr/bin/opencode "` + longPath + `"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with backslash (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", `/path/with\backslash`, nil)
-		expected := `/usr/bin/codex --cd "/path/with\backslash"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with dollar sign (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/path/with$dollar", nil)
-		expected := `/usr/bin/codex --cd "/path/with$dollar"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles project path with newline (codex)", func(t *testing.T) {
-		path := "/path/with\nnewline"
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", path, nil)
-		if !strings.Contains(result, "newline") {
-			t.Errorf("expected result to contain 'newline', got %q", result)
-		}
-		if !strings.Contains(result, "/usr/bin/codex --cd \"") {
-			t.Errorf("expected result to start with codex --cd prefix, got %q", result)
-		}
-	})
-
-	t.Run("handles project path with tab (codex)", func(t *testing.T) {
-		path := "/path/with\ttab"
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", path, nil)
-		expected := "/usr/bin/codex --cd \"/path/with\ttab\""
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles empty project path (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "", nil)
-		expected := `/usr/bin/codex --cd ""`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles empty project path (opencode)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("opencode", "/usr/bin/opencode", "", nil)
-		expected := `/usr/bin/opencode ""`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles tilde in project path (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "~/projects/app", nil)
-		expected := `/usr/bin/codex --cd "~/projects/app"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles relative path (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "./relative/path", nil)
-		expected := `/usr/bin/codex --cd "./relative/path"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles path with colons (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/path:with:colons", nil)
-		expected := `/usr/bin/codex --cd "/path:with:colons"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	// --- claude edge cases ---
-
-	t.Run("handles claude with args containing spaces", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("claude", "/usr/bin/claude", "", []string{"--message 'test message'"})
-		expected := "/usr/bin/claude --message 'test message'"
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles claude with multiple args", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("claude", "/usr/bin/claude", "", []string{"--resume", "--fast"})
-		expected := "/usr/bin/claude --resume --fast"
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles claude with no args", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("claude", "/usr/bin/claude", "", nil)
-		expected := "/usr/bin/claude"
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles copilot (no args ever)", func(t *testing.T) {
-		// Copilot ignores projectDir and args
-		result := util.BuildAILaunchCmd("copilot", "/usr/bin/copilot", "/any/path", []string{"--some-flag"})
-		expected := "/usr/bin/copilot"
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles unknown tool falls back to claude behavior", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("unknown-tool", "/usr/bin/unknown", "", []string{"--some-flag"})
-		expected := "/usr/bin/unknown --some-flag"
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles command path with spaces (codex)", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/path/with spaces/codex", "/project", nil)
-		expected := `/path/with spaces/codex --cd "/project"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("preserves exact quoting structure for shell safety", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/path/with spaces", nil)
-		if !strings.Contains(result, `"/path/with spaces"`) {
-			t.Errorf("expected result to contain quoted path, got %q", result)
-		}
-	})
-
-	t.Run("handles multiple tildes in path", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "~/foo/~/bar", nil)
-		expected := `/usr/bin/codex --cd "~/foo/~/bar"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-
-	t.Run("handles .. components in path", func(t *testing.T) {
-		result := util.BuildAILaunchCmd("codex", "/usr/bin/codex", "/foo/../bar", nil)
-		expected := `/usr/bin/codex --cd "/foo/../bar"`
-		if result != expected {
-			t.Errorf("got %q, want %q", result, expected)
-		}
-	})
-}
-
-// Table-driven tests for comprehensive coverage
-func TestBuildAILaunchCmdTable(t *testing.T) {
+func TestBuildAILaunchArgv(t *testing.T) {
 	longPath := strings.Repeat("/very/long/path", 50)
 
 	tests := []struct {
@@ -279,173 +21,80 @@ func TestBuildAILaunchCmdTable(t *testing.T) {
 		command    string
 		projectDir string
 		args       []string
-		expected   string
+		expected   []string
 	}{
 		{
-			name:     "claude basic",
+			name:     "claude passes args through",
 			tool:     "claude",
 			command:  "/usr/bin/claude",
 			args:     []string{"--resume"},
-			expected: "/usr/bin/claude --resume",
+			expected: []string{"/usr/bin/claude", "--resume"},
 		},
 		{
-			name:       "codex basic",
+			name:       "codex uses --cd flag",
 			tool:       "codex",
 			command:    "/usr/bin/codex",
 			projectDir: "/my/project",
-			expected:   `/usr/bin/codex --cd "/my/project"`,
+			expected:   []string{"/usr/bin/codex", "--cd", "/my/project"},
 		},
 		{
-			name:    "copilot basic",
-			tool:    "copilot",
-			command: "/usr/bin/copilot",
-			expected: "/usr/bin/copilot",
+			name:     "copilot has no extra args",
+			tool:     "copilot",
+			command:  "/usr/bin/copilot",
+			expected: []string{"/usr/bin/copilot"},
 		},
 		{
-			name:       "opencode basic",
+			name:       "opencode passes project dir",
 			tool:       "opencode",
 			command:    "/usr/bin/opencode",
 			projectDir: "/my/project",
-			expected:   `/usr/bin/opencode "/my/project"`,
+			expected:   []string{"/usr/bin/opencode", "/my/project"},
 		},
 		{
 			name:       "codex spaces in path",
 			tool:       "codex",
 			command:    "/usr/bin/codex",
 			projectDir: "/path/with spaces",
-			expected:   `/usr/bin/codex --cd "/path/with spaces"`,
-		},
-		{
-			name:       "opencode spaces in path",
-			tool:       "opencode",
-			command:    "/usr/bin/opencode",
-			projectDir: "/path/with spaces",
-			expected:   `/usr/bin/opencode "/path/with spaces"`,
-		},
-		{
-			name:       "codex single quotes in path",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "/path/with'quotes",
-			expected:   `/usr/bin/codex --cd "/path/with'quotes"`,
-		},
-		{
-			name:       "codex double quotes in path",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: `/path/with"quotes`,
-			expected:   `/usr/bin/codex --cd "/path/with"quotes"`,
+			expected:   []string{"/usr/bin/codex", "--cd", "/path/with spaces"},
 		},
 		{
 			name:       "opencode double quotes in path",
 			tool:       "opencode",
 			command:    "/usr/bin/opencode",
 			projectDir: `/path/with"quotes`,
-			expected:   `/usr/bin/opencode "/path/with"quotes"`,
-		},
-		{
-			name:       "codex unicode",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "/path/\u00e9moji/\U0001F47B",
-			expected:   "/usr/bin/codex --cd \"/path/\u00e9moji/\U0001F47B\"",
-		},
-		{
-			name:       "opencode unicode",
-			tool:       "opencode",
-			command:    "/usr/bin/opencode",
-			projectDir: "/path/\u00e9moji/\U0001F47B",
-			expected:   "/usr/bin/opencode \"/path/\u00e9moji/\U0001F47B\"",
+			expected:   []string{"/usr/bin/opencode", `/path/with"quotes`},
 		},
 		{
 			name:       "codex long path",
 			tool:       "codex",
 			command:    "/usr/bin/codex",
 			projectDir: longPath,
-			expected:   `/usr/bin/codex --cd "` + longPath + `"`,
-		},
-		{
-			name:       "opencode long path",
-			tool:       "opencode",
-			command:    "/usr/bin/opencode",
-			projectDir: longPath,
-			expected:   `/usr/bin/opencode "` + longPath + `"`,
+			expected:   []string{"/usr/bin/codex", "--cd", longPath},
 		},
 		{
-			name:       "codex backslash",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: `/path/with\backslash`,
-			expected:   `/usr/bin/codex --cd "/path/with\backslash"`,
-		},
-		{
-			name:       "codex dollar sign",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "/path/with$dollar",
-			expected:   `/usr/bin/codex --cd "/path/with$dollar"`,
-		},
-		{
-			name:       "codex tab",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "/path/with\ttab",
-			expected:   "/usr/bin/codex --cd \"/path/with\ttab\"",
-		},
-		{
-			name:       "codex empty path",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "",
-			expected:   `/usr/bin/codex --cd ""`,
-		},
-		{
-			name:       "opencode empty path",
-			tool:       "opencode",
-			command:    "/usr/bin/opencode",
-			projectDir: "",
-			expected:   `/usr/bin/opencode ""`,
-		},
-		{
-			name:       "codex tilde path",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "~/projects/app",
-			expected:   `/usr/bin/codex --cd "~/projects/app"`,
-		},
-		{
-			name:       "codex relative path",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "./relative/path",
-			expected:   `/usr/bin/codex --cd "./relative/path"`,
+			name:     "codex empty path",
+			tool:     "codex",
+			command:  "/usr/bin/codex",
+			expected: []string{"/usr/bin/codex", "--cd", ""},
 		},
 		{
-			name:       "codex colons in path",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "/path:with:colons",
-			expected:   `/usr/bin/codex --cd "/path:with:colons"`,
+			name:     "opencode empty path",
+			tool:     "opencode",
+			command:  "/usr/bin/opencode",
+			expected: []string{"/usr/bin/opencode", ""},
 		},
 		{
 			name:     "claude args with spaces",
 			tool:     "claude",
 			command:  "/usr/bin/claude",
-			args:     []string{"--message 'test message'"},
-			expected: "/usr/bin/claude --message 'test message'",
-		},
-		{
-			name:     "claude multiple args",
-			tool:     "claude",
-			command:  "/usr/bin/claude",
-			args:     []string{"--resume", "--fast"},
-			expected: "/usr/bin/claude --resume --fast",
+			args:     []string{"--message", "test message"},
+			expected: []string{"/usr/bin/claude", "--message", "test message"},
 		},
 		{
 			name:     "claude no args",
 			tool:     "claude",
 			command:  "/usr/bin/claude",
-			expected: "/usr/bin/claude",
+			expected: []string{"/usr/bin/claude"},
 		},
 		{
 			name:       "copilot ignores everything",
@@ -453,45 +102,203 @@ func TestBuildAILaunchCmdTable(t *testing.T) {
 			command:    "/usr/bin/copilot",
 			projectDir: "/any/path",
 			args:       []string{"--some-flag"},
-			expected:   "/usr/bin/copilot",
+			expected:   []string{"/usr/bin/copilot"},
 		},
 		{
 			name:     "unknown tool falls back to claude",
 			tool:     "unknown-tool",
 			command:  "/usr/bin/unknown",
 			args:     []string{"--some-flag"},
-			expected: "/usr/bin/unknown --some-flag",
+			expected: []string{"/usr/bin/unknown", "--some-flag"},
 		},
 		{
 			name:       "codex command path with spaces",
 			tool:       "codex",
 			command:    "/path/with spaces/codex",
 			projectDir: "/project",
-			expected:   `/path/with spaces/codex --cd "/project"`,
-		},
-		{
-			name:       "codex multiple tildes",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "~/foo/~/bar",
-			expected:   `/usr/bin/codex --cd "~/foo/~/bar"`,
-		},
-		{
-			name:       "codex dotdot components",
-			tool:       "codex",
-			command:    "/usr/bin/codex",
-			projectDir: "/foo/../bar",
-			expected:   `/usr/bin/codex --cd "/foo/../bar"`,
+			expected:   []string{"/path/with spaces/codex", "--cd", "/project"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := util.BuildAILaunchCmd(tt.tool, tt.command, tt.projectDir, tt.args)
-			if result != tt.expected {
-				t.Errorf("BuildAILaunchCmd(%q, %q, %q, %v) = %q, want %q",
+			result := util.BuildAILaunchArgv(tt.tool, tt.command, tt.projectDir, aitools.Profile{}, tt.args)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("BuildAILaunchArgv(%q, %q, %q, %v) = %q, want %q",
 					tt.tool, tt.command, tt.projectDir, tt.args, result, tt.expected)
 			}
 		})
 	}
 }
+
+func TestBuildAILaunchCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		tool       string
+		command    string
+		projectDir string
+		args       []string
+		expected   string
+	}{
+		{"plain words stay readable", "codex", "/usr/bin/codex", "/my/project", nil,
+			"/usr/bin/codex --cd /my/project"},
+		{"spaces", "opencode", "/usr/bin/opencode", "/path/with spaces", nil,
+			"/usr/bin/opencode '/path/with spaces'"},
+		{"double quotes", "codex", "/usr/bin/codex", `/path/with"quotes`, nil,
+			`/usr/bin/codex --cd '/path/with"quotes'`},
+		{"single quotes", "codex", "/usr/bin/codex", "/path/with'quotes", nil,
+			`/usr/bin/codex --cd '/path/with'\''quotes'`},
+		{"dollar sign", "codex", "/usr/bin/codex", "/path/with$dollar", nil,
+			"/usr/bin/codex --cd '/path/with$dollar'"},
+		{"tilde is not expanded", "codex", "/usr/bin/codex", "~/projects/app", nil,
+			"/usr/bin/codex --cd '~/projects/app'"},
+		{"empty path", "codex", "/usr/bin/codex", "", nil,
+			"/usr/bin/codex --cd ''"},
+		{"command path with spaces", "codex", "/path/with spaces/codex", "/project", nil,
+			"'/path/with spaces/codex' --cd /project"},
+		{"claude args with spaces", "claude", "/usr/bin/claude", "", []string{"--message", "test message"},
+			"/usr/bin/claude --message 'test message'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := util.BuildAILaunchCmd(tt.tool, tt.command, tt.projectDir, aitools.Profile{}, tt.args)
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestBuildAILaunchCmd_RoundTrip creates directories with hostile names,
+// checks ValidatePath accepts them and that a shell splits the launch
+// command back into exactly the argv it was built from.
+func TestBuildAILaunchCmd_RoundTrip(t *testing.T) {
+	names := []string{
+		"with spaces",
+		`with"double"quotes`,
+		"with'single'quotes",
+		"with$dollar and ${HOME}",
+		"with`backtick`",
+		"$(touch pwned)",
+		"semi;colon && pipe | amp &",
+		"back\\slash",
+		"new\nline",
+		"tab\there",
+		"glob * ? [a]",
+		"~tilde",
+		"-leading-dash",
+		"émoji 👻",
+		"#hash !bang",
+	}
+	root := t.TempDir()
+	for _, name := range names {
+		dir := filepath.Join(root, name)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("mkdir %q: %v", name, err)
+		}
+		if err := util.ValidatePath(dir); err != nil {
+			t.Fatalf("ValidatePath(%q): %v", dir, err)
+		}
+		for _, tool := range []string{"claude", "codex", "opencode"} {
+			argv := util.BuildAILaunchArgv(tool, "/bin/"+tool, dir, aitools.Profile{Args: []string{name}}, []string{"--message", name})
+
+			// Replace the tool with a shell function that prints its argv.
+			cmd := util.BuildAILaunchCmd(tool, "show", dir, aitools.Profile{Args: []string{name}}, []string{"--message", name})
+			out, err := exec.Command("sh", "-c", `show() { for a; do printf '%s\0' "$a"; done; }; `+cmd).Output()
+			if err != nil {
+				t.Fatalf("sh -c %q: %v", cmd, err)
+			}
+			got := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+			if !reflect.DeepEqual(got, argv[1:]) {
+				t.Errorf("%s %q:\n shell read %q\n want      %q", tool, name, got, argv[1:])
+			}
+		}
+	}
+	if _, err := os.Stat("pwned"); err == nil {
+		os.Remove("pwned")
+		t.Error("a project path ran a command substitution")
+	}
+}
+
+func TestBuildAILaunchCmd_RegisteredTools(t *testing.T) {
+	r := aitools.Defaults()
+	if err := r.Decode([]byte(`
+[[tool]]
+name = "aider"
+
+[[tool]]
+name = "goose"
+project_dir = "flag"
+project_dir_flag = "--path"
+pass_args = false
+
+[[tool]]
+name = "gemini"
+project_dir = "positional"
+`)); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	prev := aitools.Current()
+	aitools.Use(r)
+	defer aitools.Use(prev)
+
+	tests := []struct {
+		tool     string
+		expected string
+	}{
+		{"aider", `/bin/aider --yes`},
+		{"goose", `/bin/goose --path '/my project'`},
+		{"gemini", `/bin/gemini '/my project' --yes`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			result := util.BuildAILaunchCmd(tt.tool, "/bin/"+tt.tool, "/my project", aitools.Profile{}, []string{"--yes"})
+			if result != tt.expected {
+				t.Errorf("BuildAILaunchCmd(%q) = %q, want %q", tt.tool, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildAILaunchCmd_Profiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string
+		profile  string
+		args     []string
+		expected string
+	}{
+		{"claude yolo", "claude", "yolo", []string{"--model", "opus"},
+			`/bin/claude --dangerously-skip-permissions --model opus`},
+		{"claude resume", "claude", "resume", nil, `/bin/claude --continue`},
+		{"codex full-auto before the dir flag", "codex", "full-auto", []string{"--ignored"},
+			`/bin/codex --full-auto --cd /project`},
+		{"no profile", "codex", aitools.NoProfile, nil, `/bin/codex --cd /project`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, ok := aitools.Defaults().Get(tt.tool).Profile(tt.profile)
+			if !ok {
+				t.Fatalf("%s has no profile %q", tt.tool, tt.profile)
+			}
+			result := util.BuildAILaunchCmd(tt.tool, "/bin/"+tt.tool, "/project", profile, tt.args)
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildAILaunchCmd_QuotesArgs(t *testing.T) {
+	profile := aitools.Profile{Name: "review", Args: []string{"--append-system-prompt", "Review; don't edit $FILES"}}
+	result := util.BuildAILaunchCmd("opencode", "/bin/opencode", "/project", profile, nil)
+	expected := `/bin/opencode --append-system-prompt 'Review; don'\''t edit $FILES' /project`
+	if result != expected {
+		t.Errorf("got %q, want %q", result, expected)
+	}
+
+	result = util.BuildAILaunchCmd("claude", "/bin/claude", "", aitools.Profile{}, []string{"`id`", "a b"})
+	if result != "/bin/claude '`id`' 'a b'" {
+		t.Errorf("got %q", result)
+	}
+}
@@ -8,6 +8,19 @@ import (
 	"github.com/jackuait/ghost-tab/internal/util"
 )
 
+func TestConfigDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got := util.ConfigDir(); got != "/xdg/ghost-tab" {
+		t.Errorf("ConfigDir() = %q, want /xdg/ghost-tab", got)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "/home/me")
+	if got := util.ConfigDir(); got != "/home/me/.config/ghost-tab" {
+		t.Errorf("ConfigDir() = %q, want /home/me/.config/ghost-tab", got)
+	}
+}
+
 func TestExpandPath(t *testing.T) {
 	home := os.Getenv("HOME")
 
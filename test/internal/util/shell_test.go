@@ -0,0 +1,65 @@
+package util_test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/jackuait/ghost-tab/internal/util"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"--continue", "--continue"},
+		{"/usr/bin/claude", "/usr/bin/claude"},
+		{"model=o3,fast", "model=o3,fast"},
+		{"", "''"},
+		{"two words", "'two words'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+		{"a;b", "'a;b'"},
+		{"~/x", "'~/x'"},
+		{"=ls", "'=ls'"},
+		{"=", "'='"},
+	}
+	for _, tt := range tests {
+		if got := util.ShellQuote(tt.in); got != tt.want {
+			t.Errorf("ShellQuote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestShellQuote_RoundTrip(t *testing.T) {
+	for _, s := range []string{"plain", "two words", "it's", `"$(rm -rf /)"`, "`id`", "a\nb", "\\", "é 👻", "*"} {
+		out, err := exec.Command("sh", "-c", "printf %s "+util.ShellQuote(s)).Output()
+		if err != nil {
+			t.Fatalf("sh: %v", err)
+		}
+		if string(out) != s {
+			t.Errorf("sh read %q back as %q", s, out)
+		}
+	}
+}
+
+func TestShellQuote_ZshEqualsExpansion(t *testing.T) {
+	if _, err := exec.LookPath("zsh"); err != nil {
+		t.Skip("zsh not installed")
+	}
+	out, err := exec.Command("zsh", "-c", "printf %s "+util.ShellQuote("=sh")).Output()
+	if err != nil {
+		t.Fatalf("zsh: %v", err)
+	}
+	if string(out) != "=sh" {
+		t.Errorf("zsh read =sh back as %q", out)
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	got := util.ShellJoin([]string{"/bin/codex", "--cd", `/my "app"`, ""})
+	want := `/bin/codex --cd '/my "app"' ''`
+	if got != want {
+		t.Errorf("ShellJoin = %q, want %q", got, want)
+	}
+}